@@ -4,7 +4,7 @@
 
 import ProductCard from '@/components/ProductCard'
 import { apiRequest } from '@/lib/api'
-import { Product } from '@/types/product'
+import { Product, ProductListResponse } from '@/types/product'
 
 // ===========================
 // メインコンポーネント: トップページ（商品一覧）
@@ -24,7 +24,8 @@ export default async function Home() {
   let error: unknown = null
 
   try {
-    products = await apiRequest<Product[]>('/products') || []
+    const res = await apiRequest<ProductListResponse>('/products?per_page=100')
+    products = res?.products || []
   } catch (e) {
     error = e
     console.error('Error fetching products:', e)
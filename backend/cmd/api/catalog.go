@@ -0,0 +1,116 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+
+	domainrepository "github.com/sotaheavymetal21/rabbit-cart/backend/internal/domain/repository"
+	"github.com/sotaheavymetal21/rabbit-cart/backend/internal/infrastructure/repository"
+	"github.com/sotaheavymetal21/rabbit-cart/backend/internal/infrastructure/search"
+	"github.com/sotaheavymetal21/rabbit-cart/backend/internal/usecase"
+	"gorm.io/gorm"
+)
+
+// runImportProducts は CSV / JSONL ファイルから商品を一括で取り込みます
+//
+//	main import-products -file products.csv [-format csv|jsonl] [-dry-run] [-allow-partial]
+//
+// 取り込み結果の報告は JSON で標準出力に書き出し、エラーのある行があれば終了コード 1 で終了します
+func runImportProducts(db *gorm.DB, args []string) {
+	fs := flag.NewFlagSet("import-products", flag.ExitOnError)
+	file := fs.String("file", "", "取り込むファイルのパス (- で標準入力)")
+	format := fs.String("format", "", "ファイル形式 (csv または jsonl、省略時は拡張子から判定)")
+	dryRun := fs.Bool("dry-run", false, "検証のみ行い、データベースには書き込まない")
+	allowPartial := fs.Bool("allow-partial", false, "エラーの無い行だけを取り込む")
+	fs.Parse(args)
+
+	if *file == "" {
+		log.Fatal("-file を指定してください")
+	}
+	if *format == "" {
+		*format = strings.TrimPrefix(filepath.Ext(*file), ".")
+	}
+	catalogFormat, err := usecase.ParseCatalogFormat(*format)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	var r io.Reader = os.Stdin
+	if *file != "-" {
+		f, err := os.Open(*file)
+		if err != nil {
+			log.Fatalf("ファイルを開けませんでした: %v", err)
+		}
+		defer f.Close()
+		r = f
+	}
+
+	report, err := newCatalogUseCase(db).Import(context.Background(), catalogFormat, r, usecase.CatalogImportOptions{
+		DryRun:       *dryRun,
+		AllowPartial: *allowPartial,
+	})
+	if err != nil {
+		log.Fatalf("商品データの取り込みに失敗しました: %v", err)
+	}
+
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(report); err != nil {
+		log.Fatal(err)
+	}
+	if report.Failed > 0 {
+		os.Exit(1)
+	}
+}
+
+// runExportProducts は全商品を CSV / JSONL で書き出します
+//
+//	main export-products [-format csv|jsonl] [-out products.csv]
+func runExportProducts(db *gorm.DB, args []string) {
+	fs := flag.NewFlagSet("export-products", flag.ExitOnError)
+	format := fs.String("format", "csv", "出力形式 (csv または jsonl)")
+	out := fs.String("out", "-", "出力先のパス (- で標準出力)")
+	fs.Parse(args)
+
+	catalogFormat, err := usecase.ParseCatalogFormat(*format)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	var w io.Writer = os.Stdout
+	if *out != "-" {
+		f, err := os.Create(*out)
+		if err != nil {
+			log.Fatalf("ファイルを作成できませんでした: %v", err)
+		}
+		defer f.Close()
+		w = f
+	}
+
+	if err := newCatalogUseCase(db).Export(context.Background(), catalogFormat, w); err != nil {
+		log.Fatalf("商品データの書き出しに失敗しました: %v", err)
+	}
+}
+
+// reindexSearch は全商品で検索インデックスを作り直します
+// トークンの分割方法を変更した後や、インデックスが商品データとずれた場合に実行します
+//
+//	main reindex-search
+func reindexSearch(searcher domainrepository.ProductSearcher) {
+	log.Println("検索インデックスを作り直しています...")
+	if err := searcher.Reindex(context.Background()); err != nil {
+		log.Fatalf("検索インデックスの構築に失敗しました: %v", err)
+	}
+	log.Println("検索インデックスを作り直しました")
+}
+
+func newCatalogUseCase(db *gorm.DB) usecase.CatalogUseCase {
+	return usecase.NewCatalogUseCase(repository.NewProductRepository(db), search.NewPostgresProductSearcher(db))
+}
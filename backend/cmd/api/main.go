@@ -1,15 +1,28 @@
 package main
 
 import (
+	"context"
 	"log"
+	"os"
+	"time"
 
+	"github.com/gin-gonic/gin"
+	"github.com/sotaheavymetal21/rabbit-cart/backend/internal/domain/entity"
+	domainrepository "github.com/sotaheavymetal21/rabbit-cart/backend/internal/domain/repository"
 	"github.com/sotaheavymetal21/rabbit-cart/backend/internal/infrastructure/database"
+	"github.com/sotaheavymetal21/rabbit-cart/backend/internal/infrastructure/jwt"
+	"github.com/sotaheavymetal21/rabbit-cart/backend/internal/infrastructure/mail"
 	"github.com/sotaheavymetal21/rabbit-cart/backend/internal/infrastructure/middleware"
+	"github.com/sotaheavymetal21/rabbit-cart/backend/internal/infrastructure/oidc"
+	"github.com/sotaheavymetal21/rabbit-cart/backend/internal/infrastructure/payment"
 	"github.com/sotaheavymetal21/rabbit-cart/backend/internal/infrastructure/repository"
+	"github.com/sotaheavymetal21/rabbit-cart/backend/internal/infrastructure/search"
+	"github.com/sotaheavymetal21/rabbit-cart/backend/internal/infrastructure/throttle"
 	"github.com/sotaheavymetal21/rabbit-cart/backend/internal/interface/handler"
 	"github.com/sotaheavymetal21/rabbit-cart/backend/internal/interface/router"
 	"github.com/sotaheavymetal21/rabbit-cart/backend/internal/usecase"
 	"github.com/sotaheavymetal21/rabbit-cart/backend/pkg/config"
+	"gorm.io/gorm"
 )
 
 func main() {
@@ -22,30 +35,260 @@ func main() {
 	}
 	log.Println("データベースへの接続に成功しました！")
 
+	// サブコマンドの振り分け (指定が無い場合は API サーバーを起動)
+	command := "serve"
+	if len(os.Args) > 1 {
+		command = os.Args[1]
+	}
+	switch command {
+	case "serve":
+		if cfg.MigrateOnStartup {
+			applyMigrations(db)
+		}
+		runServer(cfg, db)
+	case "migrate":
+		runMigrate(db, os.Args[2:])
+	case "import-products":
+		runImportProducts(db, os.Args[2:])
+	case "export-products":
+		runExportProducts(db, os.Args[2:])
+	case "reindex-search":
+		reindexSearch(search.NewPostgresProductSearcher(db))
+	default:
+		log.Fatalf("不明なサブコマンドです: %s (serve, migrate, import-products, export-products, reindex-search)", command)
+	}
+}
+
+// runServer は API サーバーを起動します
+func runServer(cfg *config.Config, db *gorm.DB) {
 	// 依存関係の注入 (Dependency Injection)
 	// Repository
 	productRepo := repository.NewProductRepository(db)
 	userRepo := repository.NewUserRepository(db)
 	orderRepo := repository.NewOrderRepository(db)
+	cartRepo := repository.NewCartRepository(db)
+	tokenRepo := repository.NewTokenRepository(db)
+	passwordResetRepo := repository.NewPasswordResetRepository(db)
+	emailVerificationRepo := repository.NewEmailVerificationRepository(db)
+	loginLockoutRepo := repository.NewLoginLockoutRepository(db)
+	mfaRepo := repository.NewMFARepository(db)
+	sessionRepo := repository.NewSessionRepository(db)
+	identityRepo := repository.NewUserIdentityRepository(db)
+	addressRepo := repository.NewAddressRepository(db)
+	idempotencyRepo := repository.NewIdempotencyKeyRepository(db)
+	paymentRepo := repository.NewPaymentRepository(db)
+	paymentEventRepo := repository.NewPaymentEventRepository(db)
+	refundRepo := repository.NewRefundRepository(db)
+	promotionRepo := repository.NewPromotionRepository(db)
+	productSearcher := search.NewPostgresProductSearcher(db)
+
+	// 商品数が多いと時間がかかるため、起動時の作り直しは明示的に有効にした場合のみ行う
+	if cfg.SearchReindexOnStartup {
+		reindexSearch(productSearcher)
+	}
 
 	// UseCase
-	productUseCase := usecase.NewProductUseCase(productRepo)
-	authUseCase := usecase.NewAuthUseCase(userRepo)
-	orderUseCase := usecase.NewOrderUseCase(orderRepo, productRepo)
+	mailer := newMailer(cfg)
+	productUseCase := usecase.NewProductUseCase(productRepo, productSearcher)
+	authUseCase := usecase.NewAuthUseCase(userRepo, passwordResetRepo, emailVerificationRepo, mfaRepo, identityRepo, mailer, newOIDCProviders(cfg), usecase.AuthConfig{
+		PasswordResetURL:           cfg.PasswordResetURL,
+		PasswordResetTTL:           cfg.PasswordResetTTL,
+		VerificationURL:            cfg.VerificationURL,
+		VerificationTTL:            cfg.VerificationTTL,
+		VerificationResendInterval: cfg.VerificationResendInterval,
+	})
+	paymentGateway := newPaymentGateway(cfg)
+	orderUseCase := usecase.NewOrderUseCase(orderRepo, productRepo, cartRepo, userRepo, addressRepo, paymentRepo, promotionRepo, paymentGateway, usecase.OrderPolicy{
+		RequireVerifiedEmail: cfg.RequireVerifiedEmailForOrder,
+	})
+	if cfg.PaymentWebhookSecret == "" {
+		log.Println("PAYMENT_WEBHOOK_SECRET が設定されていないため、決済の Webhook は全て拒否します")
+	}
+	refundUseCase := usecase.NewRefundUseCase(orderRepo, paymentRepo, refundRepo, paymentGateway, orderUseCase)
+	paymentWebhookUseCase := usecase.NewPaymentWebhookUseCase(paymentEventRepo, payment.NewHMACWebhookVerifier(paymentGateway.Name(), cfg.PaymentWebhookSecret, cfg.PaymentWebhookTolerance), orderUseCase, refundUseCase)
+	promotionUseCase := usecase.NewPromotionUseCase(promotionRepo)
+	catalogUseCase := usecase.NewCatalogUseCase(productRepo, productSearcher)
+	sessionUseCase := usecase.NewSessionUseCase(sessionRepo)
+	addressUseCase := usecase.NewAddressUseCase(addressRepo)
+	idempotencyUseCase := usecase.NewIdempotencyUseCase(idempotencyRepo, usecase.IdempotencyConfig{
+		TTL:         cfg.IdempotencyKeyTTL,
+		LockTimeout: cfg.IdempotencyLockTimeout,
+	})
+	// 期限切れの冪等キー (ロックの期限が切れた処理中のものを含む) を定期的に掃除する
+	go purgePeriodically("冪等キー", cfg.IdempotencyPurgeInterval, idempotencyRepo.PurgeExpired)
+	userUseCase := usecase.NewUserUseCase(userRepo, emailVerificationRepo, mailer, usecase.UserConfig{
+		VerificationURL: cfg.VerificationURL,
+		VerificationTTL: cfg.VerificationTTL,
+	})
+	cartUseCase, err := usecase.NewCartUseCase(cartRepo, productRepo, usecase.CartMergePolicy{
+		Strategy:     entity.CartMergeStrategy(cfg.CartMergeStrategy),
+		ClampToStock: cfg.CartMergeClampToStock,
+	})
+	if err != nil {
+		log.Fatalf("CART_MERGE_STRATEGY が正しくありません: %v", err)
+	}
+
+	loginGuardPolicy := usecase.DefaultLoginGuardPolicy
+	loginGuardPolicy.AccountLockoutThreshold = cfg.LoginLockoutThreshold
+	loginGuardPolicy.IPLockoutThreshold = cfg.LoginIPLockoutThreshold
+	loginGuardPolicy.LockoutDuration = cfg.LoginLockoutDuration
+	loginGuard := usecase.NewLoginGuard(newLoginAttemptStore(cfg), loginLockoutRepo, loginGuardPolicy)
+
+	// 認証方式に応じてトークンの発行とミドルウェアの Authenticator を組み立てる
+	authMode := entity.AuthMode(cfg.AuthMode)
+	var tokenUseCase usecase.TokenUseCase
+	var authenticators []middleware.Authenticator
+	if authMode.UsesJWT() {
+		keys, err := jwt.ParseKeys(cfg.JWTKeys)
+		if err != nil {
+			log.Fatalf("JWT_KEYS が正しくありません: %v", err)
+		}
+		activeKID := cfg.JWTActiveKID
+		if activeKID == "" && len(keys) == 1 {
+			for kid := range keys {
+				activeKID = kid
+			}
+		}
+		signer, err := jwt.NewHMACSigner(keys, activeKID, cfg.JWTIssuer)
+		if err != nil {
+			log.Fatalf("JWT の署名鍵の設定に失敗しました: %v", err)
+		}
+		tokenUseCase = usecase.NewTokenUseCase(tokenRepo, userRepo, signer, usecase.TokenConfig{
+			AccessTTL:  cfg.AccessTokenTTL,
+			RefreshTTL: cfg.RefreshTokenTTL,
+		})
+		authenticators = append(authenticators, middleware.BearerAuthenticator(tokenUseCase))
+
+		// 期限切れのリフレッシュトークンと失効リストを掃除する
+		if err := tokenRepo.PurgeExpired(context.Background(), time.Now()); err != nil {
+			log.Printf("期限切れトークンの削除に失敗しました: %v", err)
+		}
+	}
+	if authMode.UsesSession() {
+		authenticators = append(authenticators, middleware.SessionAuthenticator())
+	}
 
 	// Handler
 	productHandler := handler.NewProductHandler(productUseCase)
-	authHandler := handler.NewAuthHandler(authUseCase)
+	authHandler := handler.NewAuthHandler(authUseCase, cartUseCase, tokenUseCase, sessionUseCase, loginGuard, authMode)
+	sessionHandler := handler.NewSessionHandler(sessionUseCase)
+	userHandler := handler.NewUserHandler(userUseCase, tokenUseCase, authMode)
+	addressHandler := handler.NewAddressHandler(addressUseCase)
 	orderHandler := handler.NewOrderHandler(orderUseCase)
+	cartHandler := handler.NewCartHandler(cartUseCase)
+	adminProductHandler := handler.NewAdminProductHandler(productUseCase, catalogUseCase)
+	adminOrderHandler := handler.NewAdminOrderHandler(orderUseCase, refundUseCase)
+	adminPaymentHandler := handler.NewAdminPaymentHandler(paymentWebhookUseCase)
+	paymentWebhookHandler := handler.NewPaymentWebhookHandler(paymentWebhookUseCase)
+	adminPromotionHandler := handler.NewAdminPromotionHandler(promotionUseCase)
 
 	// Middleware
-	authMiddleware := middleware.AuthMiddleware(userRepo)
+	authMiddleware := middleware.AuthMiddleware(userRepo, sessionUseCase, authenticators...)
+	errorMiddleware := middleware.ErrorHandler()
+	localeMiddleware := middleware.LocaleMiddleware()
+	idempotencyMiddleware := middleware.IdempotencyMiddleware(idempotencyUseCase)
+	adminMiddleware := middleware.AdminMiddleware(cfg.RequireAdminMFA, entity.UserRoleAdmin, entity.UserRoleStaff)
+	// adminMiddleware の後に重ねて使うため、MFA の確認は adminMiddleware に任せる
+	adminOnlyMiddleware := middleware.AdminMiddleware(false, entity.UserRoleAdmin)
 
 	// ルーターのセットアップ
-	r := router.SetupRouter(productHandler, authHandler, orderHandler, cfg.RedisURL, cfg.SessionSecret, authMiddleware)
+	r := router.SetupRouter(productHandler, authHandler, sessionHandler, userHandler, addressHandler, orderHandler, cartHandler, adminProductHandler, adminOrderHandler, adminPaymentHandler, adminPromotionHandler, paymentWebhookHandler, cfg.RedisURL, cfg.SessionSecret, errorMiddleware, localeMiddleware, authMiddleware, adminMiddleware, adminOnlyMiddleware, idempotencyMiddleware)
 
 	log.Printf("サーバーをポート %s で起動しています...", cfg.Port)
 	if err := r.Run(":" + cfg.Port); err != nil {
 		log.Fatalf("サーバーの起動に失敗しました: %v", err)
 	}
 }
+
+// newMailer は設定に応じた Mailer を生成します
+func newMailer(cfg *config.Config) domainrepository.Mailer {
+	switch cfg.MailDriver {
+	case "smtp":
+		if cfg.SMTPAddr == "" {
+			log.Fatal("MAIL_DRIVER=smtp の場合は SMTP_ADDR を設定してください")
+		}
+		return mail.NewSMTPMailer(cfg.SMTPAddr, cfg.SMTPUsername, cfg.SMTPPassword, cfg.MailFrom)
+	case "memory":
+		return mail.NewMemoryMailer()
+	case "file":
+		mailer, err := mail.NewFileMailer(cfg.MailDir, cfg.MailFrom)
+		if err != nil {
+			log.Fatalf("メールの出力先の作成に失敗しました: %v", err)
+		}
+		return mailer
+	default:
+		log.Fatalf("不明な MAIL_DRIVER です: %s (smtp, file, memory)", cfg.MailDriver)
+		return nil
+	}
+}
+
+// newPaymentGateway は設定に応じた決済ゲートウェイを生成します
+// フェイクゲートウェイは実際には課金しないため、本番モード (GIN_MODE=release) では起動を中止します
+func newPaymentGateway(cfg *config.Config) domainrepository.PaymentGateway {
+	switch cfg.PaymentGateway {
+	case "":
+		log.Fatal("PAYMENT_GATEWAY が設定されていません (fake)")
+		return nil
+	case "fake":
+		// .env から読み込んだ GIN_MODE も対象にするため、gin.Mode() ではなく環境変数を確認する
+		if os.Getenv(gin.EnvGinMode) == gin.ReleaseMode {
+			log.Fatal("GIN_MODE=release の場合は PAYMENT_GATEWAY=fake を使用できません")
+		}
+		gateway, err := payment.NewFakeGateway(payment.FakeScenario(cfg.FakePaymentScenario), cfg.FakePaymentChallengeURL)
+		if err != nil {
+			log.Fatalf("FAKE_PAYMENT_SCENARIO が正しくありません: %v", err)
+		}
+		return gateway
+	default:
+		log.Fatalf("不明な PAYMENT_GATEWAY です: %s (fake)", cfg.PaymentGateway)
+		return nil
+	}
+}
+
+// newOIDCProviders は設定された外部 ID プロバイダーを生成します
+func newOIDCProviders(cfg *config.Config) []domainrepository.OIDCProvider {
+	providers := make([]domainrepository.OIDCProvider, 0, len(cfg.OIDCProviders))
+	for _, pc := range cfg.OIDCProviders {
+		p, err := oidc.NewProvider(oidc.Config{
+			Name:         pc.Name,
+			Issuer:       pc.Issuer,
+			ClientID:     pc.ClientID,
+			ClientSecret: pc.ClientSecret,
+			RedirectURL:  pc.RedirectURL,
+			Scopes:       pc.Scopes,
+		})
+		if err != nil {
+			log.Fatalf("OIDC_PROVIDERS の設定が正しくありません: %v", err)
+		}
+		providers = append(providers, p)
+	}
+	return providers
+}
+
+// newLoginAttemptStore は設定に応じたログイン失敗回数の保存先を生成します
+// memory はプロセスごとに数えるため、複数台で動かす場合は redis を使用してください
+func newLoginAttemptStore(cfg *config.Config) domainrepository.LoginAttemptStore {
+	switch cfg.LoginGuardBackend {
+	case "redis":
+		return throttle.NewRedisAttemptStore(throttle.NewRedisPool(cfg.RedisURL))
+	case "memory":
+		return throttle.NewMemoryAttemptStore()
+	default:
+		log.Fatalf("不明な LOGIN_GUARD_BACKEND です: %s (redis, memory)", cfg.LoginGuardBackend)
+		return nil
+	}
+}
+
+// purgePeriodically は起動時と interval ごとに purge で期限切れのデータを削除します
+// interval が 0 以下の場合は起動時のみ削除します
+func purgePeriodically(name string, interval time.Duration, purge func(ctx context.Context, now time.Time) error) {
+	for {
+		if err := purge(context.Background(), time.Now()); err != nil {
+			log.Printf("期限切れの%sの削除に失敗しました: %v", name, err)
+		}
+		if interval <= 0 {
+			return
+		}
+		time.Sleep(interval)
+	}
+}
@@ -0,0 +1,81 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/sotaheavymetal21/rabbit-cart/backend/internal/infrastructure/database"
+	"github.com/sotaheavymetal21/rabbit-cart/backend/migrations"
+	"gorm.io/gorm"
+)
+
+// runMigrate はマイグレーションを操作します
+//
+//	main migrate up                 未適用のマイグレーションを適用する
+//	main migrate status             適用状況を表示する
+//	main migrate baseline <version> version までを適用済みとして記録する (AutoMigrate で作成済みの DB 向け)
+//	main migrate verify             エンティティとデータベースのスキーマの差分を表示する
+func runMigrate(db *gorm.DB, args []string) {
+	ctx := context.Background()
+	migrator := database.NewMigrator(db, migrations.FS)
+
+	command := "up"
+	if len(args) > 0 {
+		command = args[0]
+	}
+	switch command {
+	case "up":
+		applyMigrations(db)
+	case "status":
+		statuses, err := migrator.Status(ctx)
+		if err != nil {
+			log.Fatalf("マイグレーションの状況の取得に失敗しました: %v", err)
+		}
+		for _, s := range statuses {
+			state := "未適用"
+			if s.AppliedAt != nil {
+				state = "適用済み " + s.AppliedAt.Format("2006-01-02 15:04:05")
+			}
+			if s.Modified {
+				state += " (ファイルが変更されています)"
+			}
+			fmt.Printf("%s_%s\t%s\n", s.Version, s.Name, state)
+		}
+	case "baseline":
+		if len(args) < 2 {
+			log.Fatal("baseline にはバージョンを指定してください")
+		}
+		if err := migrator.Baseline(ctx, args[1]); err != nil {
+			log.Fatalf("ベースラインの記録に失敗しました: %v", err)
+		}
+		log.Printf("バージョン %s までを適用済みとして記録しました", args[1])
+	case "verify":
+		diffs, err := migrator.Verify(ctx, database.Models()...)
+		if err != nil {
+			log.Fatalf("スキーマの検証に失敗しました: %v", err)
+		}
+		if len(diffs) > 0 {
+			for _, d := range diffs {
+				fmt.Println(d)
+			}
+			os.Exit(1)
+		}
+		log.Println("エンティティとデータベースのスキーマは一致しています")
+	default:
+		log.Fatalf("不明な migrate コマンドです: %s (up, status, baseline, verify)", command)
+	}
+}
+
+// applyMigrations は未適用のマイグレーションを全て適用します
+func applyMigrations(db *gorm.DB) {
+	migrator := database.NewMigrator(db, migrations.FS)
+	applied, err := migrator.Up(context.Background())
+	for _, m := range applied {
+		log.Printf("マイグレーション %s_%s を適用しました", m.Version, m.Name)
+	}
+	if err != nil {
+		log.Fatalf("マイグレーションの適用に失敗しました: %v", err)
+	}
+}
@@ -0,0 +1,137 @@
+package entity
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// postalCodePattern は日本の郵便番号 (7桁、ハイフンは任意) の形式です
+var postalCodePattern = regexp.MustCompile(`^(\d{3})-?(\d{4})$`)
+
+// addressPhonePattern は配送先の電話番号として受け付ける形式です
+var addressPhonePattern = regexp.MustCompile(`^\+?[0-9()\- ]{6,20}$`)
+
+// Prefectures は都道府県の一覧です (JIS X 0401 の順)
+var Prefectures = []string{
+	"北海道", "青森県", "岩手県", "宮城県", "秋田県", "山形県", "福島県",
+	"茨城県", "栃木県", "群馬県", "埼玉県", "千葉県", "東京都", "神奈川県",
+	"新潟県", "富山県", "石川県", "福井県", "山梨県", "長野県", "岐阜県",
+	"静岡県", "愛知県", "三重県", "滋賀県", "京都府", "大阪府", "兵庫県",
+	"奈良県", "和歌山県", "鳥取県", "島根県", "岡山県", "広島県", "山口県",
+	"徳島県", "香川県", "愛媛県", "高知県", "福岡県", "佐賀県", "長崎県",
+	"熊本県", "大分県", "宮崎県", "鹿児島県", "沖縄県",
+}
+
+// invalidAddress は住所の項目 field が正しくないことを表す *ValidationError を返します
+// code は理由の種類で、reason は args を埋め込む理由のメッセージです
+func invalidAddress(field, code, reason string, args ...any) *ValidationError {
+	return NewValidationError("invalid_address", "住所の入力内容が正しくありません", FieldError{
+		Field:   "address." + field,
+		Code:    code,
+		Message: fmt.Sprintf(reason, args...),
+		Args:    args,
+	})
+}
+
+// Address は配送先の住所です
+// 注文には作成時点の住所をそのまま保存するため、住所録を後から変更しても注文の配送先は変わりません
+type Address struct {
+	Name       string `json:"name" gorm:"type:varchar(100);not null"` // 宛名
+	PostalCode string `json:"postal_code" gorm:"type:varchar(8);not null"`
+	Prefecture string `json:"prefecture" gorm:"type:varchar(10);not null"`
+	City       string `json:"city" gorm:"type:varchar(100);not null"`             // 市区町村
+	Line1      string `json:"line1" gorm:"type:varchar(200);not null"`            // 町名・番地
+	Line2      string `json:"line2" gorm:"type:varchar(200);not null;default:''"` // 建物名・部屋番号
+	Phone      string `json:"phone" gorm:"type:varchar(20);not null"`
+}
+
+// Normalize は前後の空白を取り除き、郵便番号を "123-4567" の形式に揃えます
+func (a *Address) Normalize() {
+	a.Name = strings.TrimSpace(a.Name)
+	a.PostalCode = strings.TrimSpace(a.PostalCode)
+	a.Prefecture = strings.TrimSpace(a.Prefecture)
+	a.City = strings.TrimSpace(a.City)
+	a.Line1 = strings.TrimSpace(a.Line1)
+	a.Line2 = strings.TrimSpace(a.Line2)
+	a.Phone = strings.TrimSpace(a.Phone)
+	if m := postalCodePattern.FindStringSubmatch(a.PostalCode); m != nil {
+		a.PostalCode = m[1] + "-" + m[2]
+	}
+}
+
+// Validate は住所の各項目を検証します (Normalize の後に呼び出してください)
+// 不正な項目がある場合は *ValidationError を返します
+func (a *Address) Validate() error {
+	required := []struct {
+		field, value string
+		max          int
+	}{
+		{"name", a.Name, 100},
+		{"city", a.City, 100},
+		{"line1", a.Line1, 200},
+	}
+	for _, r := range required {
+		if r.value == "" {
+			return invalidAddress(r.field, "required", "入力してください")
+		}
+		if utf8.RuneCountInString(r.value) > r.max {
+			return invalidAddress(r.field, "max_length", "%d 文字以内で入力してください", r.max)
+		}
+	}
+	if utf8.RuneCountInString(a.Line2) > 200 {
+		return invalidAddress("line2", "max_length", "%d 文字以内で入力してください", 200)
+	}
+	if !postalCodePattern.MatchString(a.PostalCode) {
+		return invalidAddress("postal_code", "postal_code", "7桁の郵便番号 (例: 123-4567) を入力してください")
+	}
+	if !isPrefecture(a.Prefecture) {
+		return invalidAddress("prefecture", "prefecture", "都道府県名を入力してください")
+	}
+	if !addressPhonePattern.MatchString(a.Phone) {
+		return invalidAddress("phone", "phone", "電話番号を入力してください")
+	}
+	return nil
+}
+
+func isPrefecture(s string) bool {
+	for _, p := range Prefectures {
+		if p == s {
+			return true
+		}
+	}
+	return false
+}
+
+// UnmarshalJSON は住所を読み込みます
+// 構造化される前の注文は住所を JSON 文字列として保存していたため、文字列の場合は中身を JSON として読み込み、
+// JSON として読めない場合は Line1 に入れます
+func (a *Address) UnmarshalJSON(b []byte) error {
+	type plain Address
+	var s string
+	if err := json.Unmarshal(b, &s); err == nil {
+		if err := json.Unmarshal([]byte(s), (*plain)(a)); err != nil {
+			*a = Address{Line1: s}
+		}
+		return nil
+	}
+	return json.Unmarshal(b, (*plain)(a))
+}
+
+// UserAddress はユーザーの住所録に保存された配送先を表すエンティティです
+type UserAddress struct {
+	ID        string    `json:"id" gorm:"primaryKey;type:uuid;default:uuid_generate_v4()"`
+	UserID    string    `json:"-" gorm:"type:uuid;not null;index;uniqueIndex:idx_addresses_user_default,where:is_default"` // 既定の配送先はユーザーごとに1件
+	Address   Address   `json:"address" gorm:"embedded"`
+	IsDefault bool      `json:"is_default" gorm:"not null;default:false"` // 注文時に住所を指定しなかった場合に使う配送先
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// TableName はテーブル名を指定します
+func (UserAddress) TableName() string {
+	return "addresses"
+}
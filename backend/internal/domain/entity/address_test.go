@@ -0,0 +1,96 @@
+package entity_test
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/sotaheavymetal21/rabbit-cart/backend/internal/domain/entity"
+)
+
+func validAddress() entity.Address {
+	return entity.Address{
+		Name:       "山田 太郎",
+		PostalCode: "100-0001",
+		Prefecture: "東京都",
+		City:       "千代田区",
+		Line1:      "千代田1-1",
+		Phone:      "03-1234-5678",
+	}
+}
+
+func TestAddressNormalize(t *testing.T) {
+	a := entity.Address{
+		Name:       " 山田 太郎 ",
+		PostalCode: " 1000001 ",
+		Prefecture: "東京都 ",
+		City:       " 千代田区",
+		Line1:      "千代田1-1 ",
+		Line2:      "  ",
+		Phone:      " 03-1234-5678",
+	}
+	a.Normalize()
+	if a != validAddress() {
+		t.Errorf("Normalize = %+v, want %+v", a, validAddress())
+	}
+}
+
+func TestAddressValidate(t *testing.T) {
+	tests := []struct {
+		name      string
+		modify    func(a *entity.Address)
+		wantField string // 空文字は正しい住所
+	}{
+		{"正しい住所", func(a *entity.Address) {}, ""},
+		{"建物名は任意", func(a *entity.Address) { a.Line2 = "" }, ""},
+		{"宛名が無い", func(a *entity.Address) { a.Name = "" }, "name"},
+		{"市区町村が長すぎる", func(a *entity.Address) { a.City = strings.Repeat("区", 101) }, "city"},
+		{"番地が無い", func(a *entity.Address) { a.Line1 = "" }, "line1"},
+		{"建物名が長すぎる", func(a *entity.Address) { a.Line2 = strings.Repeat("棟", 201) }, "line2"},
+		{"郵便番号の桁数が足りない", func(a *entity.Address) { a.PostalCode = "100-001" }, "postal_code"},
+		{"都道府県ではない", func(a *entity.Address) { a.Prefecture = "東京" }, "prefecture"},
+		{"電話番号の形式が正しくない", func(a *entity.Address) { a.Phone = "電話なし" }, "phone"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a := validAddress()
+			tt.modify(&a)
+			err := a.Validate()
+			if tt.wantField == "" {
+				if err != nil {
+					t.Errorf("Validate = %v, want nil", err)
+				}
+				return
+			}
+			var validationErr *entity.ValidationError
+			if !errors.As(err, &validationErr) || len(validationErr.Fields) != 1 || validationErr.Fields[0].Field != "address."+tt.wantField {
+				t.Errorf("Validate = %#v, want address.%s の ValidationError", err, tt.wantField)
+			}
+		})
+	}
+}
+
+// 構造化される前の注文は住所を JSON 文字列として保存していたため、その形式も読み込めることを確認する
+func TestAddressUnmarshalLegacy(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+		want entity.Address
+	}{
+		{"構造化された住所", `{"name":"山田 太郎","city":"千代田区"}`, entity.Address{Name: "山田 太郎", City: "千代田区"}},
+		{"JSON 文字列の住所", `"{\"name\":\"山田 太郎\",\"city\":\"千代田区\"}"`, entity.Address{Name: "山田 太郎", City: "千代田区"}},
+		{"自由入力の住所", `"東京都千代田区千代田1-1"`, entity.Address{Line1: "東京都千代田区千代田1-1"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got entity.Address
+			if err := json.Unmarshal([]byte(tt.data), &got); err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want {
+				t.Errorf("Unmarshal = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
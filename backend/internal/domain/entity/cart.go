@@ -0,0 +1,39 @@
+package entity
+
+import (
+	"time"
+)
+
+// Cart はユーザーごとのショッピングカートを表すエンティティです
+type Cart struct {
+	ID          string     `json:"id" gorm:"primaryKey;type:uuid;default:uuid_generate_v4()"`
+	UserID      string     `json:"user_id" gorm:"type:uuid;not null;uniqueIndex"`
+	Items       []CartItem `json:"items" gorm:"foreignKey:CartID"`
+	TotalAmount int        `json:"total_amount" gorm:"-"` // 現在の商品価格から算出する合計金額（保存しない）
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+}
+
+// TableName はテーブル名を指定します
+func (Cart) TableName() string {
+	return "carts"
+}
+
+// FindItem は指定された商品のカート明細を返します。存在しない場合は nil を返します
+func (c *Cart) FindItem(productID string) *CartItem {
+	for i := range c.Items {
+		if c.Items[i].ProductID == productID {
+			return &c.Items[i]
+		}
+	}
+	return nil
+}
+
+// CalculateTotal は各明細の商品価格と数量から TotalAmount を計算します
+func (c *Cart) CalculateTotal() {
+	total := 0
+	for _, item := range c.Items {
+		total += item.Product.Price * item.Quantity
+	}
+	c.TotalAmount = total
+}
@@ -0,0 +1,21 @@
+package entity
+
+import (
+	"time"
+)
+
+// CartItem はカート明細を表すエンティティです
+type CartItem struct {
+	ID        string    `json:"id" gorm:"primaryKey;type:uuid;default:uuid_generate_v4()"`
+	CartID    string    `json:"cart_id" gorm:"type:uuid;not null;uniqueIndex:idx_cart_items_cart_product"`
+	ProductID string    `json:"product_id" gorm:"type:uuid;not null;uniqueIndex:idx_cart_items_cart_product"`
+	Quantity  int       `json:"quantity" gorm:"not null"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+	Product   Product   `json:"product" gorm:"foreignKey:ProductID"`
+}
+
+// TableName はテーブル名を指定します
+func (CartItem) TableName() string {
+	return "cart_items"
+}
@@ -0,0 +1,23 @@
+package entity
+
+import (
+	"time"
+)
+
+// EmailVerificationToken はメールアドレス確認用の使い捨てトークンを表すエンティティです
+// トークン自体は保存せず、SHA-256 のハッシュのみを保存します
+// NewEmail が空でない場合はメールアドレス変更の確認用で、確認が済むとユーザーのメールアドレスを NewEmail に変更します
+type EmailVerificationToken struct {
+	ID        string     `json:"id" gorm:"primaryKey;type:uuid;default:uuid_generate_v4()"`
+	UserID    string     `json:"user_id" gorm:"type:uuid;not null;index"`
+	NewEmail  string     `json:"new_email" gorm:"not null;default:''"`
+	TokenHash string     `json:"-" gorm:"type:varchar(64);not null;uniqueIndex"`
+	ExpiresAt time.Time  `json:"expires_at" gorm:"not null"`
+	UsedAt    *time.Time `json:"used_at"`
+	CreatedAt time.Time  `json:"created_at"`
+}
+
+// TableName はテーブル名を指定します
+func (EmailVerificationToken) TableName() string {
+	return "email_verification_tokens"
+}
@@ -0,0 +1,139 @@
+package entity
+
+// ErrUnauthenticated はログインが必要な操作でログインしていない場合のエラーです
+var ErrUnauthenticated = NewUnauthorizedError("unauthenticated", "ログインが必要です")
+
+// FieldError は入力エラーの項目と理由です
+type FieldError struct {
+	Field   string `json:"field"` // JSON のフィールド名 (入れ子の場合は "address.postal_code" の形式)
+	Code    string `json:"code"`  // 理由の種類 ("required"、"max_length" など)
+	Message string `json:"message"`
+	Args    []any  `json:"-"` // メッセージに埋め込む値 (文字数の上限など)
+}
+
+// ValidationError は入力内容が正しくない場合のエラーです
+type ValidationError struct {
+	Code    string // API のエラーコード
+	Message string
+	Fields  []FieldError // 項目ごとのエラー (項目を特定できない場合は空)
+	Args    []any        // メッセージに埋め込む値 (件数の上限など)
+}
+
+// NewValidationError は ValidationError を生成します
+func NewValidationError(code, message string, fields ...FieldError) *ValidationError {
+	return &ValidationError{Code: code, Message: message, Fields: fields}
+}
+
+// NewFieldError は1つの項目についての ValidationError を生成します
+func NewFieldError(code, field, message string) *ValidationError {
+	return NewValidationError(code, message, FieldError{Field: field, Code: code, Message: message})
+}
+
+func (e *ValidationError) Error() string {
+	return e.Message
+}
+
+// UnauthorizedError は認証情報が無い・正しくない場合のエラーです
+type UnauthorizedError struct {
+	Code    string
+	Message string
+}
+
+// NewUnauthorizedError は UnauthorizedError を生成します
+func NewUnauthorizedError(code, message string) *UnauthorizedError {
+	return &UnauthorizedError{Code: code, Message: message}
+}
+
+func (e *UnauthorizedError) Error() string {
+	return e.Message
+}
+
+// ForbiddenError は操作する権限が無い場合のエラーです
+type ForbiddenError struct {
+	Code    string
+	Message string
+}
+
+// NewForbiddenError は ForbiddenError を生成します
+func NewForbiddenError(code, message string) *ForbiddenError {
+	return &ForbiddenError{Code: code, Message: message}
+}
+
+func (e *ForbiddenError) Error() string {
+	return e.Message
+}
+
+// NotFoundError は対象のリソースが存在しない場合のエラーです
+type NotFoundError struct {
+	Code    string
+	Message string
+}
+
+// NewNotFoundError は NotFoundError を生成します
+func NewNotFoundError(code, message string) *NotFoundError {
+	return &NotFoundError{Code: code, Message: message}
+}
+
+func (e *NotFoundError) Error() string {
+	return e.Message
+}
+
+// ConflictError は現在の状態と矛盾する操作 (重複登録・同時更新など) の場合のエラーです
+type ConflictError struct {
+	Code    string
+	Message string
+}
+
+// NewConflictError は ConflictError を生成します
+func NewConflictError(code, message string) *ConflictError {
+	return &ConflictError{Code: code, Message: message}
+}
+
+func (e *ConflictError) Error() string {
+	return e.Message
+}
+
+// UnprocessableError は形式は正しいものの、以前のリクエストとの関係などから処理できない場合のエラーです
+type UnprocessableError struct {
+	Code    string
+	Message string
+}
+
+// NewUnprocessableError は UnprocessableError を生成します
+func NewUnprocessableError(code, message string) *UnprocessableError {
+	return &UnprocessableError{Code: code, Message: message}
+}
+
+func (e *UnprocessableError) Error() string {
+	return e.Message
+}
+
+// PaymentRequiredError は決済が完了できなかった場合のエラーです
+type PaymentRequiredError struct {
+	Code    string
+	Message string
+}
+
+// NewPaymentRequiredError は PaymentRequiredError を生成します
+func NewPaymentRequiredError(code, message string) *PaymentRequiredError {
+	return &PaymentRequiredError{Code: code, Message: message}
+}
+
+func (e *PaymentRequiredError) Error() string {
+	return e.Message
+}
+
+// ServiceUnavailableError は外部サービスの障害などで一時的に処理できない場合のエラーです
+type ServiceUnavailableError struct {
+	Code    string
+	Message string
+}
+
+// NewServiceUnavailableError は ServiceUnavailableError を生成します
+func NewServiceUnavailableError(code, message string) *ServiceUnavailableError {
+	return &ServiceUnavailableError{Code: code, Message: message}
+}
+
+func (e *ServiceUnavailableError) Error() string {
+	return e.Message
+}
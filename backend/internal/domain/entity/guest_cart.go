@@ -0,0 +1,42 @@
+package entity
+
+// GuestCartItem は未ログインユーザーのセッションに保存するカート明細です
+type GuestCartItem struct {
+	ProductID string `json:"product_id"`
+	Quantity  int    `json:"quantity"`
+}
+
+// CartMergeStrategy はゲストカートとユーザーのカートに同じ商品がある場合の数量の決め方です
+type CartMergeStrategy string
+
+const (
+	// CartMergeStrategySum は両方の数量を合算します
+	CartMergeStrategySum CartMergeStrategy = "sum"
+	// CartMergeStrategyMax は多い方の数量を採用します
+	CartMergeStrategyMax CartMergeStrategy = "max"
+)
+
+// IsValid は定義済みのマージ方法かを返します
+func (s CartMergeStrategy) IsValid() bool {
+	return s == CartMergeStrategySum || s == CartMergeStrategyMax
+}
+
+// CartMergeReason はマージ時に明細が調整された理由です
+type CartMergeReason string
+
+const (
+	CartMergeReasonConflict       CartMergeReason = "conflict"         // 両方のカートに同じ商品があった
+	CartMergeReasonClampedToStock CartMergeReason = "clamped_to_stock" // 在庫数に合わせて数量を減らした
+	CartMergeReasonOutOfStock     CartMergeReason = "out_of_stock"     // 在庫切れのため追加しなかった
+	CartMergeReasonNotFound       CartMergeReason = "not_found"        // 商品が存在しないため追加しなかった
+)
+
+// CartMergeAdjustment はマージによって数量が変わった明細の情報です
+type CartMergeAdjustment struct {
+	ProductID     string            `json:"product_id"`
+	Name          string            `json:"name,omitempty"`
+	GuestQuantity int               `json:"guest_quantity"`
+	UserQuantity  int               `json:"user_quantity"`
+	Quantity      int               `json:"quantity"` // マージ後の数量 (0 の場合は追加されていない)
+	Reasons       []CartMergeReason `json:"reasons"`
+}
@@ -0,0 +1,28 @@
+package entity
+
+import (
+	"time"
+)
+
+// IdempotencyKey はクライアントが Idempotency-Key ヘッダーで指定したキーと、そのリクエストの処理結果を表すエンティティです
+// 同じキーで再送されたリクエストには保存したレスポンスを返し、注文などが二重に作成されないようにします
+type IdempotencyKey struct {
+	ID             string    `gorm:"primaryKey;type:uuid;default:uuid_generate_v4()"`
+	UserID         string    `gorm:"type:uuid;not null;uniqueIndex:idx_idempotency_keys_user_key"`
+	Key            string    `gorm:"type:varchar(255);not null;uniqueIndex:idx_idempotency_keys_user_key"`
+	RequestHash    string    `gorm:"type:varchar(64);not null"` // メソッド・パス・ボディの SHA-256 (16進数)
+	ResponseStatus int       `gorm:"not null;default:0"`        // 処理中は 0
+	ResponseBody   []byte    // 処理が完了したリクエストのレスポンスボディ
+	ExpiresAt      time.Time `gorm:"not null;index"` // 処理中はロックの期限、完了後はレスポンスを保持する期限
+	CreatedAt      time.Time
+}
+
+// IsCompleted はリクエストの処理が完了し、レスポンスが保存されているかを返します
+func (k *IdempotencyKey) IsCompleted() bool {
+	return k.ResponseStatus != 0
+}
+
+// TableName はテーブル名を指定します
+func (IdempotencyKey) TableName() string {
+	return "idempotency_keys"
+}
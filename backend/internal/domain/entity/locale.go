@@ -0,0 +1,21 @@
+package entity
+
+// Locale は API のメッセージの言語を表します
+type Locale string
+
+const (
+	LocaleJapanese Locale = "ja"
+	LocaleEnglish  Locale = "en"
+)
+
+// DefaultLocale は言語の指定が無い場合に使う言語です
+const DefaultLocale = LocaleJapanese
+
+// IsValid は対応している言語かを返します
+func (l Locale) IsValid() bool {
+	switch l {
+	case LocaleJapanese, LocaleEnglish:
+		return true
+	}
+	return false
+}
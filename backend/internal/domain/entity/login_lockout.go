@@ -0,0 +1,29 @@
+package entity
+
+import (
+	"time"
+)
+
+// LoginLockoutScope はログインのロックアウトの対象の種類です
+type LoginLockoutScope string
+
+const (
+	LoginLockoutScopeAccount LoginLockoutScope = "account" // メールアドレス単位
+	LoginLockoutScopeIP      LoginLockoutScope = "ip"      // 接続元 IP アドレス単位
+)
+
+// LoginLockout はログイン失敗が続いたことによるロックアウトの監査記録を表すエンティティです
+type LoginLockout struct {
+	ID          string            `json:"id" gorm:"primaryKey;type:uuid;default:uuid_generate_v4()"`
+	Scope       LoginLockoutScope `json:"scope" gorm:"type:varchar(20);not null"`
+	Email       string            `json:"email" gorm:"not null;index"`
+	IPAddress   string            `json:"ip_address" gorm:"type:varchar(45);not null;index"`
+	Failures    int               `json:"failures" gorm:"not null"` // ロックアウト時点の失敗回数
+	LockedUntil time.Time         `json:"locked_until" gorm:"not null"`
+	CreatedAt   time.Time         `json:"created_at"`
+}
+
+// TableName はテーブル名を指定します
+func (LoginLockout) TableName() string {
+	return "login_lockouts"
+}
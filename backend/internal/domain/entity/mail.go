@@ -0,0 +1,8 @@
+package entity
+
+// Mail は送信するメールを表します
+type Mail struct {
+	To      string
+	Subject string
+	Body    string // プレーンテキスト
+}
@@ -0,0 +1,36 @@
+package entity
+
+import (
+	"time"
+)
+
+// MFARecoveryCode は認証アプリを使えなくなった場合に使う使い捨てのリカバリーコードを表すエンティティです
+// コード自体は保存せず、SHA-256 のハッシュのみを保存します
+type MFARecoveryCode struct {
+	ID        string     `json:"id" gorm:"primaryKey;type:uuid;default:uuid_generate_v4()"`
+	UserID    string     `json:"user_id" gorm:"type:uuid;not null;index"`
+	CodeHash  string     `json:"-" gorm:"type:varchar(64);not null"`
+	UsedAt    *time.Time `json:"used_at"`
+	CreatedAt time.Time  `json:"created_at"`
+}
+
+// TableName はテーブル名を指定します
+func (MFARecoveryCode) TableName() string {
+	return "mfa_recovery_codes"
+}
+
+// MFAChallenge はパスワード認証に成功し、二要素認証のコード入力を待っている状態を表すエンティティです
+type MFAChallenge struct {
+	ID        string     `json:"id" gorm:"primaryKey;type:uuid;default:uuid_generate_v4()"`
+	UserID    string     `json:"user_id" gorm:"type:uuid;not null;index"`
+	TokenHash string     `json:"-" gorm:"type:varchar(64);not null;uniqueIndex"`
+	Attempts  int        `json:"attempts" gorm:"not null;default:0"` // コードの入力に失敗した回数
+	ExpiresAt time.Time  `json:"expires_at" gorm:"not null"`
+	UsedAt    *time.Time `json:"used_at"`
+	CreatedAt time.Time  `json:"created_at"`
+}
+
+// TableName はテーブル名を指定します
+func (MFAChallenge) TableName() string {
+	return "mfa_challenges"
+}
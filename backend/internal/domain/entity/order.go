@@ -1,6 +1,7 @@
 package entity
 
 import (
+	"fmt"
 	"time"
 )
 
@@ -8,20 +9,68 @@ type OrderStatus string
 
 const (
 	OrderStatusPending   OrderStatus = "pending"
+	OrderStatusPaid      OrderStatus = "paid"
+	OrderStatusShipped   OrderStatus = "shipped"
+	OrderStatusDelivered OrderStatus = "delivered"
 	OrderStatusCompleted OrderStatus = "completed"
 	OrderStatusCancelled OrderStatus = "cancelled"
+	OrderStatusRefunded  OrderStatus = "refunded"
 )
 
+// orderStatusTransitions は各ステータスから遷移可能なステータスの一覧です
+// ここに定義されていない遷移は全て不正な遷移として扱います
+var orderStatusTransitions = map[OrderStatus][]OrderStatus{
+	OrderStatusPending:   {OrderStatusPaid, OrderStatusCancelled},
+	OrderStatusPaid:      {OrderStatusShipped, OrderStatusCancelled},
+	OrderStatusShipped:   {OrderStatusDelivered, OrderStatusRefunded},
+	OrderStatusDelivered: {OrderStatusCompleted, OrderStatusRefunded},
+	OrderStatusCompleted: {OrderStatusRefunded},
+}
+
+// CanTransitionTo は現在のステータスから next へ遷移できるかどうかを返します
+func (s OrderStatus) CanTransitionTo(next OrderStatus) bool {
+	for _, allowed := range orderStatusTransitions[s] {
+		if allowed == next {
+			return true
+		}
+	}
+	return false
+}
+
+// ErrOrderStatusConflict は更新中に別の処理で注文ステータスが変更されていた場合のエラーです
+var ErrOrderStatusConflict = NewConflictError("order_status_conflict", "注文ステータスが他の処理によって変更されました")
+
+// InvalidStatusTransitionError は許可されていないステータス遷移を要求された場合のエラーです
+type InvalidStatusTransitionError struct {
+	From OrderStatus
+	To   OrderStatus
+}
+
+func (e *InvalidStatusTransitionError) Error() string {
+	return fmt.Sprintf("注文ステータスを %s から %s に変更することはできません", e.From, e.To)
+}
+
 // Order は注文を表すエンティティです
 type Order struct {
-	ID          string      `json:"id" gorm:"primaryKey;type:uuid;default:uuid_generate_v4()"`
-	UserID      string      `json:"user_id" gorm:"type:uuid;not null"`
-	TotalAmount int         `json:"total_amount" gorm:"not null"`
-	Status      OrderStatus `json:"status" gorm:"type:varchar(20);default:'pending';not null"`
-	Address     string      `json:"address" gorm:"type:jsonb;not null"` // JSON serialized string
-	CreatedAt   time.Time   `json:"created_at"`
-	UpdatedAt   time.Time   `json:"updated_at"`
-	OrderItems  []OrderItem `json:"order_items" gorm:"foreignKey:OrderID"`
+	ID             string               `json:"id" gorm:"primaryKey;type:uuid;default:uuid_generate_v4()"`
+	UserID         *string              `json:"user_id" gorm:"type:uuid;index"`            // 退会したユーザーの注文は nil
+	SubtotalAmount int                  `json:"subtotal_amount" gorm:"not null;default:0"` // 割引前の商品の合計
+	DiscountAmount int                  `json:"discount_amount" gorm:"not null;default:0"`
+	TotalAmount    int                  `json:"total_amount" gorm:"not null"` // 支払い金額 (SubtotalAmount - DiscountAmount)
+	Status         OrderStatus          `json:"status" gorm:"type:varchar(20);default:'pending';not null"`
+	Address        Address              `json:"address" gorm:"type:jsonb;serializer:json;not null"` // 注文時点の配送先
+	CreatedAt      time.Time            `json:"created_at"`
+	UpdatedAt      time.Time            `json:"updated_at"`
+	OrderItems     []OrderItem          `json:"order_items" gorm:"foreignKey:OrderID"`
+	StatusHistory  []OrderStatusHistory `json:"status_history,omitempty" gorm:"foreignKey:OrderID"`
+	Payments       []Payment            `json:"payments,omitempty" gorm:"foreignKey:OrderID"`
+	Refunds        []Refund             `json:"refunds,omitempty" gorm:"foreignKey:OrderID"`
+	Discounts      []OrderDiscount      `json:"discounts,omitempty" gorm:"foreignKey:OrderID"`
+}
+
+// IsOwnedBy は userID のユーザーの注文かを返します
+func (o *Order) IsOwnedBy(userID string) bool {
+	return o.UserID != nil && *o.UserID == userID
 }
 
 // TableName はテーブル名を指定します
@@ -6,13 +6,38 @@ import (
 
 // OrderItem は注文明細を表すエンティティです
 type OrderItem struct {
-	ID        string    `json:"id" gorm:"primaryKey;type:uuid;default:uuid_generate_v4()"`
-	OrderID   string    `json:"order_id" gorm:"type:uuid;not null"`
-	ProductID string    `json:"product_id" gorm:"type:uuid;not null"`
-	Quantity  int       `json:"quantity" gorm:"not null"`
-	Price     int       `json:"price" gorm:"not null"`
-	CreatedAt time.Time `json:"created_at"`
-	Product   Product   `json:"product" gorm:"foreignKey:ProductID"`
+	ID               string    `json:"id" gorm:"primaryKey;type:uuid;default:uuid_generate_v4()"`
+	OrderID          string    `json:"order_id" gorm:"type:uuid;not null"`
+	ProductID        string    `json:"product_id" gorm:"type:uuid;not null"`
+	Quantity         int       `json:"quantity" gorm:"not null"`
+	Price            int       `json:"price" gorm:"not null"`
+	DiscountAmount   int       `json:"discount_amount" gorm:"not null;default:0"`   // 注文の割引のうちこの明細に按分した額
+	RefundedQuantity int       `json:"refunded_quantity" gorm:"not null;default:0"` // 返金済みの数量
+	RefundedAmount   int       `json:"refunded_amount" gorm:"not null;default:0"`   // 返金済みの金額 (返品を伴わない減額を含む)
+	CreatedAt        time.Time `json:"created_at"`
+	Product          Product   `json:"product" gorm:"foreignKey:ProductID"`
+}
+
+// RefundableQuantity はまだ返金していない数量を返します
+func (i *OrderItem) RefundableQuantity() int {
+	return i.Quantity - i.RefundedQuantity
+}
+
+// RefundableAmount はまだ返金していない金額 (割引後) を返します
+func (i *OrderItem) RefundableAmount() int {
+	return i.Price*i.Quantity - i.DiscountAmount - i.RefundedAmount
+}
+
+// RefundAmountFor は quantity 個を返品する場合の返金額 (割引を按分した後の額) を返します
+// 未返金の数量を全て返品する場合は、端数が残らないように未返金の金額の全てを返します
+func (i *OrderItem) RefundAmountFor(quantity int) int {
+	if quantity <= 0 {
+		return 0
+	}
+	if quantity >= i.RefundableQuantity() {
+		return i.RefundableAmount()
+	}
+	return (i.Price*i.Quantity - i.DiscountAmount) * quantity / i.Quantity
 }
 
 // TableName はテーブル名を指定します
@@ -0,0 +1,24 @@
+package entity
+
+import (
+	"time"
+)
+
+// SystemActorID はユーザー以外 (決済サービスからの通知など) による変更を記録する際の ChangedBy です
+const SystemActorID = "00000000-0000-0000-0000-000000000000"
+
+// OrderStatusHistory は注文ステータスの変更履歴を表すエンティティです
+type OrderStatusHistory struct {
+	ID         string      `json:"id" gorm:"primaryKey;type:uuid;default:uuid_generate_v4()"`
+	OrderID    string      `json:"order_id" gorm:"type:uuid;not null;index"`
+	FromStatus OrderStatus `json:"from_status" gorm:"type:varchar(20);not null"`
+	ToStatus   OrderStatus `json:"to_status" gorm:"type:varchar(20);not null"`
+	ChangedBy  string      `json:"changed_by" gorm:"type:uuid;not null"` // 変更を行ったユーザーのID
+	Reason     string      `json:"reason"`
+	CreatedAt  time.Time   `json:"created_at"`
+}
+
+// TableName はテーブル名を指定します
+func (OrderStatusHistory) TableName() string {
+	return "order_status_history"
+}
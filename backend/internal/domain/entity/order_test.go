@@ -0,0 +1,35 @@
+package entity_test
+
+import (
+	"testing"
+
+	"github.com/sotaheavymetal21/rabbit-cart/backend/internal/domain/entity"
+)
+
+func TestOrderStatusCanTransitionTo(t *testing.T) {
+	statuses := []entity.OrderStatus{
+		entity.OrderStatusPending,
+		entity.OrderStatusPaid,
+		entity.OrderStatusShipped,
+		entity.OrderStatusDelivered,
+		entity.OrderStatusCompleted,
+		entity.OrderStatusCancelled,
+	}
+	// 許可する遷移の一覧 (ここにない組み合わせは全て不正)
+	allowed := map[[2]entity.OrderStatus]bool{
+		{entity.OrderStatusPending, entity.OrderStatusPaid}:        true,
+		{entity.OrderStatusPending, entity.OrderStatusCancelled}:   true,
+		{entity.OrderStatusPaid, entity.OrderStatusShipped}:        true,
+		{entity.OrderStatusPaid, entity.OrderStatusCancelled}:      true,
+		{entity.OrderStatusShipped, entity.OrderStatusDelivered}:   true,
+		{entity.OrderStatusDelivered, entity.OrderStatusCompleted}: true,
+	}
+	for _, from := range statuses {
+		for _, to := range statuses {
+			want := allowed[[2]entity.OrderStatus{from, to}]
+			if got := from.CanTransitionTo(to); got != want {
+				t.Errorf("%s → %s: CanTransitionTo = %v, want %v", from, to, got, want)
+			}
+		}
+	}
+}
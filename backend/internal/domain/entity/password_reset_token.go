@@ -0,0 +1,21 @@
+package entity
+
+import (
+	"time"
+)
+
+// PasswordResetToken はパスワードリセット用の使い捨てトークンを表すエンティティです
+// トークン自体は保存せず、SHA-256 のハッシュのみを保存します
+type PasswordResetToken struct {
+	ID        string     `json:"id" gorm:"primaryKey;type:uuid;default:uuid_generate_v4()"`
+	UserID    string     `json:"user_id" gorm:"type:uuid;not null;index"`
+	TokenHash string     `json:"-" gorm:"type:varchar(64);not null;uniqueIndex"`
+	ExpiresAt time.Time  `json:"expires_at" gorm:"not null"`
+	UsedAt    *time.Time `json:"used_at"`
+	CreatedAt time.Time  `json:"created_at"`
+}
+
+// TableName はテーブル名を指定します
+func (PasswordResetToken) TableName() string {
+	return "password_reset_tokens"
+}
@@ -0,0 +1,95 @@
+package entity
+
+import (
+	"time"
+)
+
+// PaymentStatus は決済の状態を表します
+type PaymentStatus string
+
+const (
+	PaymentStatusPending        PaymentStatus = "pending"         // ゲートウェイへの与信の依頼前・依頼中
+	PaymentStatusRequiresAction PaymentStatus = "requires_action" // 3D セキュアなどの本人認証が必要
+	PaymentStatusAuthorized     PaymentStatus = "authorized"      // 与信済み (売上は未確定)
+	PaymentStatusCaptured       PaymentStatus = "captured"        // 売上確定済み
+	PaymentStatusVoided         PaymentStatus = "voided"          // 与信を取り消し済み
+	PaymentStatusRefunded       PaymentStatus = "refunded"        // 全額を返金済み
+	PaymentStatusFailed         PaymentStatus = "failed"          // 与信が拒否された、またはゲートウェイでエラーが発生した
+)
+
+// DefaultCurrency は決済の通貨です
+const DefaultCurrency = "JPY"
+
+var (
+	// ErrPaymentDeclined は決済がカード会社などに拒否された場合のエラーです
+	ErrPaymentDeclined = NewPaymentRequiredError("payment_declined", "決済が承認されませんでした。別のお支払い方法をお試しください")
+	// ErrPaymentUnavailable は決済サービスとの通信に失敗した場合のエラーです
+	ErrPaymentUnavailable = NewServiceUnavailableError("payment_unavailable", "決済サービスに接続できませんでした。時間をおいて再度お試しください")
+)
+
+// Payment は注文の決済を表すエンティティです
+// 注文の作成時に与信を行い、発送時に売上を確定します。与信の失敗後に再度決済した場合は1つの注文に複数の決済が記録されます
+type Payment struct {
+	ID                string        `json:"id" gorm:"primaryKey;type:uuid;default:uuid_generate_v4()"`
+	OrderID           string        `json:"order_id" gorm:"type:uuid;not null;index"`
+	Provider          string        `json:"provider" gorm:"type:varchar(50);not null"`
+	ProviderPaymentID string        `json:"-" gorm:"type:varchar(255);not null;default:'';index"` // ゲートウェイでの決済ID
+	Amount            int           `json:"amount" gorm:"not null"`
+	RefundedAmount    int           `json:"refunded_amount" gorm:"not null;default:0"`
+	Currency          string        `json:"currency" gorm:"type:varchar(3);not null;default:'JPY'"`
+	Status            PaymentStatus `json:"status" gorm:"type:varchar(20);not null"`
+	NextActionURL     string        `json:"next_action_url,omitempty" gorm:"not null;default:''"` // 本人認証の画面の URL (requires_action の場合)
+	FailureCode       string        `json:"failure_code,omitempty" gorm:"type:varchar(50);not null;default:''"`
+	AuthorizedAt      *time.Time    `json:"authorized_at"`
+	CapturedAt        *time.Time    `json:"captured_at"`
+	VoidedAt          *time.Time    `json:"voided_at"`
+	CreatedAt         time.Time     `json:"created_at"`
+	UpdatedAt         time.Time     `json:"updated_at"`
+}
+
+// ApplyResult はゲートウェイの処理結果を決済に反映します
+func (p *Payment) ApplyResult(result *PaymentResult, at time.Time) {
+	if result.ProviderPaymentID != "" {
+		p.ProviderPaymentID = result.ProviderPaymentID
+	}
+	p.Status = result.Status
+	p.NextActionURL = result.NextActionURL
+	p.FailureCode = result.FailureCode
+	switch result.Status {
+	case PaymentStatusAuthorized:
+		p.AuthorizedAt = &at
+	case PaymentStatusCaptured:
+		p.CapturedAt = &at
+	case PaymentStatusVoided:
+		p.VoidedAt = &at
+	}
+}
+
+// RefundableAmount はまだ返金していない売上の確定額を返します (売上が確定していない場合は 0)
+func (p *Payment) RefundableAmount() int {
+	if p.Status != PaymentStatusCaptured {
+		return 0
+	}
+	return p.Amount - p.RefundedAmount
+}
+
+// TableName はテーブル名を指定します
+func (Payment) TableName() string {
+	return "payments"
+}
+
+// PaymentAuthorization は決済ゲートウェイに与信を依頼する内容です
+type PaymentAuthorization struct {
+	OrderID       string
+	Amount        int
+	Currency      string
+	PaymentMethod string // クライアントがゲートウェイから取得した支払い方法のトークン
+}
+
+// PaymentResult は決済ゲートウェイの処理結果です
+type PaymentResult struct {
+	ProviderPaymentID string
+	Status            PaymentStatus
+	NextActionURL     string // 本人認証が必要な場合の認証画面の URL
+	FailureCode       string // 拒否された場合の理由 (ゲートウェイのコード)
+}
@@ -0,0 +1,67 @@
+package entity
+
+import (
+	"time"
+)
+
+// PaymentEventType は決済サービスから通知されるイベントの種類です
+type PaymentEventType string
+
+const (
+	PaymentEventAuthorized PaymentEventType = "payment.authorized" // 本人認証の完了などにより与信が承認された
+	PaymentEventFailed     PaymentEventType = "payment.failed"     // 与信が拒否された
+	PaymentEventRefunded   PaymentEventType = "payment.refunded"   // 決済サービスの管理画面などで返金された (全額または一部)
+)
+
+var (
+	// ErrInvalidWebhookSignature は Webhook の署名が正しくない場合のエラーです
+	ErrInvalidWebhookSignature = NewUnauthorizedError("invalid_webhook_signature", "Webhook の署名が正しくありません")
+	// ErrWebhookTimestampOutOfRange は Webhook の送信時刻が許容範囲外の場合のエラーです (再送攻撃の防止)
+	ErrWebhookTimestampOutOfRange = NewUnauthorizedError("webhook_timestamp_out_of_range", "Webhook の送信時刻が許容範囲外です")
+	// ErrInvalidWebhookPayload は Webhook のボディを解釈できない場合のエラーです
+	ErrInvalidWebhookPayload = NewValidationError("invalid_webhook_payload", "Webhook の内容が正しくありません")
+)
+
+// PaymentEvent は決済サービスから Webhook で受け取ったイベントです
+// 決済サービスは同じイベントを再送することがあるため、(Provider, EventID) で重複を除いて一度だけ処理します
+type PaymentEvent struct {
+	ID                string           `json:"id" gorm:"primaryKey;type:uuid;default:uuid_generate_v4()"`
+	Provider          string           `json:"provider" gorm:"type:varchar(50);not null;uniqueIndex:idx_payment_events_provider_event"`
+	EventID           string           `json:"event_id" gorm:"type:varchar(255);not null;uniqueIndex:idx_payment_events_provider_event"` // 決済サービスでのイベントID
+	Type              PaymentEventType `json:"type" gorm:"type:varchar(50);not null"`
+	ProviderPaymentID string           `json:"provider_payment_id" gorm:"type:varchar(255);not null"`
+	FailureCode       string           `json:"failure_code,omitempty" gorm:"type:varchar(50);not null;default:''"`
+	Amount            int              `json:"amount,omitempty" gorm:"not null;default:0"` // 返金のイベントで返金された金額
+	Payload           string           `json:"payload" gorm:"type:text;not null"`          // 受け取ったボディ (調査・再処理用)
+	ProcessedAt       *time.Time       `json:"processed_at"`
+	ClaimedAt         time.Time        `json:"-" gorm:"not null"` // 最後に処理を開始した日時 (処理中のイベントの再送を二重に処理しないために使う)
+	CreatedAt         time.Time        `json:"created_at"`
+}
+
+// TableName はテーブル名を指定します
+func (PaymentEvent) TableName() string {
+	return "payment_events"
+}
+
+// PaymentDeadLetter は処理に失敗した決済イベントを表すエンティティです
+// 管理者が原因を解消した後に再処理 (re-drive) できるように、イベントごとに1件記録します
+type PaymentDeadLetter struct {
+	ID             string       `json:"id" gorm:"primaryKey;type:uuid;default:uuid_generate_v4()"`
+	PaymentEventID string       `json:"payment_event_id" gorm:"type:uuid;not null;uniqueIndex"`
+	Event          PaymentEvent `json:"event" gorm:"foreignKey:PaymentEventID"`
+	LastError      string       `json:"last_error" gorm:"type:text;not null"`
+	Attempts       int          `json:"attempts" gorm:"not null;default:1"`
+	ResolvedAt     *time.Time   `json:"resolved_at"` // 再処理に成功した日時
+	CreatedAt      time.Time    `json:"created_at"`
+	UpdatedAt      time.Time    `json:"updated_at"`
+}
+
+// IsResolved は再処理に成功したかを返します
+func (d *PaymentDeadLetter) IsResolved() bool {
+	return d.ResolvedAt != nil
+}
+
+// TableName はテーブル名を指定します
+func (PaymentDeadLetter) TableName() string {
+	return "payment_dead_letters"
+}
@@ -7,6 +7,7 @@ import (
 // Product は商品を表すエンティティです
 type Product struct {
 	ID          string    `json:"id" gorm:"primaryKey;type:uuid;default:uuid_generate_v4()"`
+	SKU         string    `json:"sku" gorm:"type:varchar(64);not null;default:'';uniqueIndex:idx_products_sku,where:sku <> ''"` // 在庫管理用の商品コード（未設定の場合は空文字）
 	Name        string    `json:"name"`
 	Description string    `json:"description"`
 	Price       int       `json:"price"`
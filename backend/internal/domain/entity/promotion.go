@@ -0,0 +1,205 @@
+package entity
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// DiscountType は割引の種類です
+type DiscountType string
+
+const (
+	DiscountTypePercentage  DiscountType = "percentage"   // 対象商品の小計の DiscountValue % を割り引く
+	DiscountTypeFixedAmount DiscountType = "fixed_amount" // DiscountValue 円を割り引く
+)
+
+// PromotionScope は割引の対象となる商品の範囲です
+type PromotionScope string
+
+const (
+	PromotionScopeAll      PromotionScope = "all"      // 全ての商品
+	PromotionScopeCategory PromotionScope = "category" // Targets のカテゴリの商品
+	PromotionScopeProduct  PromotionScope = "product"  // Targets の商品IDの商品
+)
+
+var (
+	// ErrPromotionUsageLimitReached はクーポンの利用回数が上限に達している場合のエラーです
+	ErrPromotionUsageLimitReached = NewFieldError("coupon_usage_limit_reached", "coupon_code", "このクーポンは利用回数の上限に達しました")
+	// ErrPromotionPerUserLimitReached はユーザーごとのクーポンの利用回数が上限に達している場合のエラーです
+	ErrPromotionPerUserLimitReached = NewFieldError("coupon_per_user_limit_reached", "coupon_code", "このクーポンは既に利用済みです")
+)
+
+// promotionCodePattern はクーポンコードの形式です (大文字に揃えてから確認します)
+var promotionCodePattern = regexp.MustCompile(`^[A-Z0-9_-]{1,50}$`)
+
+// Promotion はチェックアウト時にクーポンコードで適用する割引を表すエンティティです
+type Promotion struct {
+	ID            string         `json:"id" gorm:"primaryKey;type:uuid;default:uuid_generate_v4()"`
+	Code          string         `json:"code" gorm:"type:varchar(50);not null;uniqueIndex"` // クーポンコード (大文字)
+	Name          string         `json:"name" gorm:"type:varchar(100);not null"`
+	DiscountType  DiscountType   `json:"discount_type" gorm:"type:varchar(20);not null"`
+	DiscountValue int            `json:"discount_value" gorm:"not null"`                       // 割引率 (%) または割引額 (円)
+	MaxDiscount   int            `json:"max_discount" gorm:"not null;default:0"`               // 割引額の上限 (0 は上限なし)
+	MinSpend      int            `json:"min_spend" gorm:"not null;default:0"`                  // 対象商品の小計の下限
+	Scope         PromotionScope `json:"scope" gorm:"type:varchar(20);not null;default:'all'"` // 対象商品の範囲
+	Targets       []string       `json:"targets" gorm:"type:jsonb;serializer:json;not null"`   // 対象のカテゴリまたは商品ID
+	UsageLimit    int            `json:"usage_limit" gorm:"not null;default:0"`                // 全体の利用回数の上限 (0 は上限なし)
+	PerUserLimit  int            `json:"per_user_limit" gorm:"not null;default:0"`             // ユーザーごとの利用回数の上限 (0 は上限なし)
+	UsedCount     int            `json:"used_count" gorm:"not null;default:0"`                 // キャンセルされていない注文での利用回数
+	StartsAt      *time.Time     `json:"starts_at"`                                            // 利用開始日時 (nil は制限なし)
+	EndsAt        *time.Time     `json:"ends_at"`                                              // 利用終了日時 (nil は制限なし)
+	Active        bool           `json:"active" gorm:"not null;default:true"`                  // false の場合は期間内でも利用できない
+	CreatedAt     time.Time      `json:"created_at"`
+	UpdatedAt     time.Time      `json:"updated_at"`
+}
+
+// TableName はテーブル名を指定します
+func (Promotion) TableName() string {
+	return "promotions"
+}
+
+// NormalizePromotionCode はクーポンコードの前後の空白を除いて大文字に揃えます
+func NormalizePromotionCode(code string) string {
+	return strings.ToUpper(strings.TrimSpace(code))
+}
+
+// Normalize は入力の表記ゆれを揃えます
+func (p *Promotion) Normalize() {
+	p.Code = NormalizePromotionCode(p.Code)
+	p.Name = strings.TrimSpace(p.Name)
+	if p.Scope == "" {
+		p.Scope = PromotionScopeAll
+	}
+	targets := make([]string, 0, len(p.Targets))
+	seen := make(map[string]bool, len(p.Targets))
+	for _, t := range p.Targets {
+		t = strings.TrimSpace(t)
+		if t == "" || seen[t] {
+			continue
+		}
+		seen[t] = true
+		targets = append(targets, t)
+	}
+	p.Targets = targets
+}
+
+// invalidPromotion はクーポンの項目 field が正しくないことを表す *ValidationError を返します
+func invalidPromotion(field, code, reason string, args ...any) *ValidationError {
+	return NewValidationError("invalid_promotion", "クーポンの入力内容が正しくありません", FieldError{
+		Field:   field,
+		Code:    code,
+		Message: fmt.Sprintf(reason, args...),
+		Args:    args,
+	})
+}
+
+// Validate はクーポンの設定が正しいかを確認します (Normalize の後に呼び出してください)
+func (p *Promotion) Validate() error {
+	if !promotionCodePattern.MatchString(p.Code) {
+		return invalidPromotion("code", "promotion_code", "クーポンコードは英数字・ハイフン・アンダースコアの50文字以内で指定してください")
+	}
+	if p.Name == "" {
+		return invalidPromotion("name", "required", "入力してください")
+	}
+	if utf8.RuneCountInString(p.Name) > 100 {
+		return invalidPromotion("name", "max_length", "%d 文字以内で入力してください", 100)
+	}
+	switch p.DiscountType {
+	case DiscountTypePercentage:
+		if p.DiscountValue < 1 {
+			return invalidPromotion("discount_value", "min", "%v 以上を指定してください", 1)
+		}
+		if p.DiscountValue > 100 {
+			return invalidPromotion("discount_value", "max", "%v 以下を指定してください", 100)
+		}
+	case DiscountTypeFixedAmount:
+		if p.DiscountValue < 1 {
+			return invalidPromotion("discount_value", "min", "%v 以上を指定してください", 1)
+		}
+	default:
+		return invalidPromotion("discount_type", "oneof", "%v のいずれかを指定してください", "percentage fixed_amount")
+	}
+	for _, f := range []struct {
+		field string
+		value int
+	}{
+		{"max_discount", p.MaxDiscount},
+		{"min_spend", p.MinSpend},
+		{"usage_limit", p.UsageLimit},
+		{"per_user_limit", p.PerUserLimit},
+	} {
+		if f.value < 0 {
+			return invalidPromotion(f.field, "min", "%v 以上を指定してください", 0)
+		}
+	}
+	switch p.Scope {
+	case PromotionScopeAll:
+		p.Targets = []string{}
+	case PromotionScopeCategory, PromotionScopeProduct:
+		if len(p.Targets) == 0 {
+			return invalidPromotion("targets", "required", "入力してください")
+		}
+	default:
+		return invalidPromotion("scope", "oneof", "%v のいずれかを指定してください", "all category product")
+	}
+	if p.StartsAt != nil && p.EndsAt != nil && !p.EndsAt.After(*p.StartsAt) {
+		return invalidPromotion("ends_at", "after_starts_at", "開始日時より後の日時を指定してください")
+	}
+	return nil
+}
+
+// IsAvailableAt は now の時点でクーポンが有効期間内かを返します (利用回数の上限は含みません)
+func (p *Promotion) IsAvailableAt(now time.Time) bool {
+	if !p.Active {
+		return false
+	}
+	if p.StartsAt != nil && now.Before(*p.StartsAt) {
+		return false
+	}
+	return p.EndsAt == nil || now.Before(*p.EndsAt)
+}
+
+// AppliesTo は商品が割引の対象かを返します
+func (p *Promotion) AppliesTo(product *Product) bool {
+	switch p.Scope {
+	case PromotionScopeCategory:
+		return slices.Contains(p.Targets, product.Category)
+	case PromotionScopeProduct:
+		return slices.Contains(p.Targets, product.ID)
+	default:
+		return true
+	}
+}
+
+// DiscountFor は対象商品の小計 subtotal に対する割引額を返します (小計を超えることはありません)
+func (p *Promotion) DiscountFor(subtotal int) int {
+	discount := p.DiscountValue
+	if p.DiscountType == DiscountTypePercentage {
+		discount = subtotal * p.DiscountValue / 100
+	}
+	if p.MaxDiscount > 0 && discount > p.MaxDiscount {
+		discount = p.MaxDiscount
+	}
+	return min(discount, subtotal)
+}
+
+// OrderDiscount は注文に適用した割引の内訳を表すエンティティです
+// クーポンの設定が後から変更されても注文時点の内容が分かるように、コードと名前も保存します
+type OrderDiscount struct {
+	ID          string    `json:"id" gorm:"primaryKey;type:uuid;default:uuid_generate_v4()"`
+	OrderID     string    `json:"order_id" gorm:"type:uuid;not null;index"`
+	PromotionID string    `json:"promotion_id" gorm:"type:uuid;not null;index"`
+	Code        string    `json:"code" gorm:"type:varchar(50);not null"`
+	Name        string    `json:"name" gorm:"type:varchar(100);not null"`
+	Amount      int       `json:"amount" gorm:"not null"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+// TableName はテーブル名を指定します
+func (OrderDiscount) TableName() string {
+	return "order_discounts"
+}
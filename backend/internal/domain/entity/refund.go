@@ -0,0 +1,58 @@
+package entity
+
+import (
+	"time"
+)
+
+// RefundStatus は返金の状態を表します
+type RefundStatus string
+
+const (
+	RefundStatusPending   RefundStatus = "pending"   // 返金額を確保し、決済サービスに返金を依頼中
+	RefundStatusSucceeded RefundStatus = "succeeded" // 返金済み
+	RefundStatusFailed    RefundStatus = "failed"    // 決済サービスで返金できなかった (確保した返金額は戻します)
+)
+
+var (
+	// ErrRefundExceedsCaptured は返金額の合計が売上の確定額を超える場合のエラーです
+	ErrRefundExceedsCaptured = NewUnprocessableError("refund_exceeds_captured", "返金額の合計が決済額を超えています")
+	// ErrRefundExceedsOrderItem は返金する数量・金額が注文明細の未返金の分を超える場合のエラーです
+	ErrRefundExceedsOrderItem = NewUnprocessableError("refund_exceeds_order_item", "返金する数量・金額が注文明細の未返金の分を超えています")
+)
+
+// Refund は注文の返金を表すエンティティです
+// 1回の返金で1つ以上の注文明細の全部または一部を返金し、明細ごとの返金額を RefundItem に記録します
+type Refund struct {
+	ID        string       `json:"id" gorm:"primaryKey;type:uuid;default:uuid_generate_v4()"`
+	OrderID   string       `json:"order_id" gorm:"type:uuid;not null;index"`
+	PaymentID string       `json:"payment_id" gorm:"type:uuid;not null;index"`
+	Amount    int          `json:"amount" gorm:"not null"`
+	Reason    string       `json:"reason" gorm:"not null;default:''"`
+	Restock   bool         `json:"restock" gorm:"not null;default:false"` // 返品された数量を在庫に戻すか
+	Status    RefundStatus `json:"status" gorm:"type:varchar(20);not null"`
+	CreatedBy string       `json:"created_by" gorm:"type:uuid;not null"` // 返金を行った管理者のID (決済サービスで行われた返金は SystemActorID)
+	CreatedAt time.Time    `json:"created_at"`
+	UpdatedAt time.Time    `json:"updated_at"`
+	Items     []RefundItem `json:"items" gorm:"foreignKey:RefundID"`
+}
+
+// TableName はテーブル名を指定します
+func (Refund) TableName() string {
+	return "refunds"
+}
+
+// RefundItem は返金の注文明細ごとの内訳です
+// 数量が 0 の場合は返品を伴わない減額 (値引き) を表します
+type RefundItem struct {
+	ID          string    `json:"id" gorm:"primaryKey;type:uuid;default:uuid_generate_v4()"`
+	RefundID    string    `json:"refund_id" gorm:"type:uuid;not null;index"`
+	OrderItemID string    `json:"order_item_id" gorm:"type:uuid;not null;index"`
+	Quantity    int       `json:"quantity" gorm:"not null"`
+	Amount      int       `json:"amount" gorm:"not null"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+// TableName はテーブル名を指定します
+func (RefundItem) TableName() string {
+	return "refund_items"
+}
@@ -0,0 +1,27 @@
+package entity
+
+import (
+	"strings"
+)
+
+// StockShortage は在庫が不足している商品の情報を表します
+type StockShortage struct {
+	ProductID string `json:"product_id"`
+	Name      string `json:"name"`
+	Requested int    `json:"requested"`
+	Available int    `json:"available"`
+}
+
+// InsufficientStockError は注文に在庫不足の商品が含まれている場合のエラーです
+// 不足している全ての商品を Shortages に保持します
+type InsufficientStockError struct {
+	Shortages []StockShortage
+}
+
+func (e *InsufficientStockError) Error() string {
+	names := make([]string, 0, len(e.Shortages))
+	for _, s := range e.Shortages {
+		names = append(names, s.Name)
+	}
+	return "在庫不足の商品があります: " + strings.Join(names, ", ")
+}
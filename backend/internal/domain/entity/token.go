@@ -0,0 +1,88 @@
+package entity
+
+import (
+	"time"
+)
+
+var (
+	// ErrInvalidToken はトークンの形式や署名が正しくない場合のエラーです
+	ErrInvalidToken = NewUnauthorizedError("invalid_token", "トークンが正しくありません")
+	// ErrTokenExpired はトークンの有効期限が切れている場合のエラーです
+	ErrTokenExpired = NewUnauthorizedError("token_expired", "トークンの有効期限が切れています")
+	// ErrTokenRevoked はトークンが失効している場合のエラーです
+	ErrTokenRevoked = NewUnauthorizedError("token_revoked", "トークンは失効しています")
+	// ErrRefreshTokenReused は使用済みのリフレッシュトークンが再利用された場合のエラーです
+	ErrRefreshTokenReused = NewUnauthorizedError("refresh_token_reused", "リフレッシュトークンは既に使用されています")
+)
+
+// AuthMode はログイン時に発行する認証情報の種類です
+type AuthMode string
+
+const (
+	AuthModeSession AuthMode = "session" // Redis セッション (Cookie) のみ
+	AuthModeJWT     AuthMode = "jwt"     // JWT アクセストークン・リフレッシュトークンのみ
+	AuthModeBoth    AuthMode = "both"    // セッションと JWT の両方
+)
+
+// UsesSession はセッションによる認証を行うかを返します
+func (m AuthMode) UsesSession() bool {
+	return m != AuthModeJWT
+}
+
+// UsesJWT は JWT による認証を行うかを返します
+func (m AuthMode) UsesJWT() bool {
+	return m == AuthModeJWT || m == AuthModeBoth
+}
+
+// AccessTokenClaims は JWT アクセストークンのクレームです
+type AccessTokenClaims struct {
+	Issuer    string   `json:"iss"`
+	Subject   string   `json:"sub"` // ユーザーID
+	ID        string   `json:"jti"`
+	SessionID string   `json:"sid"` // ログインセッションID (UserSession.ID)
+	Role      UserRole `json:"role"`
+	IssuedAt  int64    `json:"iat"`
+	ExpiresAt int64    `json:"exp"`
+}
+
+// ExpiresAtTime は有効期限を time.Time で返します
+func (c *AccessTokenClaims) ExpiresAtTime() time.Time {
+	return time.Unix(c.ExpiresAt, 0)
+}
+
+// TokenPair はログイン・リフレッシュ時に発行するトークンの組です
+type TokenPair struct {
+	AccessToken  string `json:"access_token"`
+	RefreshToken string `json:"refresh_token"`
+	TokenType    string `json:"token_type"`
+	ExpiresIn    int    `json:"expires_in"` // アクセストークンの有効期間 (秒)
+}
+
+// RefreshToken はサーバー側で管理するリフレッシュトークンを表すエンティティです
+// トークン自体は保存せず、SHA-256 のハッシュのみを保存します
+type RefreshToken struct {
+	ID        string     `json:"id" gorm:"primaryKey;type:uuid;default:uuid_generate_v4()"`
+	UserID    string     `json:"user_id" gorm:"type:uuid;not null;index"`
+	FamilyID  string     `json:"family_id" gorm:"type:varchar(64);not null;index"` // 同じログインから発行されたトークンの系列 (UserSession.ID)
+	TokenHash string     `json:"-" gorm:"type:varchar(64);not null;uniqueIndex"`
+	ExpiresAt time.Time  `json:"expires_at" gorm:"not null"`
+	RevokedAt *time.Time `json:"revoked_at"`
+	CreatedAt time.Time  `json:"created_at"`
+}
+
+// TableName はテーブル名を指定します
+func (RefreshToken) TableName() string {
+	return "refresh_tokens"
+}
+
+// RevokedToken は有効期限前に失効させたアクセストークンを表すエンティティです
+type RevokedToken struct {
+	JTI       string    `json:"jti" gorm:"primaryKey;type:varchar(64)"`
+	ExpiresAt time.Time `json:"expires_at" gorm:"not null;index"` // 元のトークンの有効期限 (これ以降は削除してよい)
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// TableName はテーブル名を指定します
+func (RevokedToken) TableName() string {
+	return "revoked_tokens"
+}
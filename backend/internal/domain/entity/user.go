@@ -4,13 +4,66 @@ import (
 	"time"
 )
 
+// UserRole はユーザーの権限を表します
+type UserRole string
+
+const (
+	UserRoleCustomer UserRole = "customer"
+	UserRoleStaff    UserRole = "staff"
+	UserRoleAdmin    UserRole = "admin"
+)
+
 // User はユーザーを表すエンティティです
 type User struct {
-	ID           string    `json:"id" gorm:"primaryKey;type:uuid;default:uuid_generate_v4()"`
-	Email        string    `json:"email" gorm:"unique;not null"`
-	PasswordHash string    `json:"-" gorm:"not null"` // パスワードハッシュはJSON出力しない
-	CreatedAt    time.Time `json:"created_at"`
-	UpdatedAt    time.Time `json:"updated_at"`
+	ID                string     `json:"id" gorm:"primaryKey;type:uuid;default:uuid_generate_v4()"`
+	Email             string     `json:"email" gorm:"unique;not null"`
+	PasswordHash      *string    `json:"-"` // パスワードハッシュはJSON出力しない (外部 ID プロバイダーのみでログインするユーザーは nil)
+	Role              UserRole   `json:"role" gorm:"type:varchar(20);default:'customer';not null"`
+	DisplayName       string     `json:"display_name" gorm:"type:varchar(50);not null;default:''"`
+	Phone             string     `json:"phone" gorm:"type:varchar(20);not null;default:''"`
+	Locale            Locale     `json:"locale" gorm:"type:varchar(5);not null;default:''"` // API のメッセージの言語 (空の場合は Accept-Language に従います)
+	PasswordChangedAt *time.Time `json:"-"`                                                 // パスワードを最後に変更した日時 (これより前のログインは無効)
+	VerifiedAt        *time.Time `json:"verified_at"`                                       // メールアドレスを確認した日時 (未確認の場合は nil)
+	// TOTP による二要素認証の設定 (TOTPEnabledAt が nil の間は登録途中で、ログインには使用しません)
+	TOTPSecret    string     `json:"-" gorm:"not null;default:''"`
+	TOTPEnabledAt *time.Time `json:"totp_enabled_at"`
+	TOTPLastStep  int64      `json:"-" gorm:"not null;default:0"` // 最後に使用したコードの時間ステップ (再利用の防止)
+	CreatedAt     time.Time  `json:"created_at"`
+	UpdatedAt     time.Time  `json:"updated_at"`
+}
+
+// HasRole はユーザーが roles のいずれかの権限を持っているかを返します
+func (u *User) HasRole(roles ...UserRole) bool {
+	for _, r := range roles {
+		if u.Role == r {
+			return true
+		}
+	}
+	return false
+}
+
+// HasPassword はパスワードが設定されているかを返します
+func (u *User) HasPassword() bool {
+	return u.PasswordHash != nil && *u.PasswordHash != ""
+}
+
+// IsVerified はメールアドレスが確認済みかを返します
+func (u *User) IsVerified() bool {
+	return u.VerifiedAt != nil
+}
+
+// MFAEnabled は二要素認証が有効かを返します
+func (u *User) MFAEnabled() bool {
+	return u.TOTPEnabledAt != nil
+}
+
+// CredentialsValidAt は authenticatedAt にログインしたセッション・トークンが現在も有効かを返します
+// セッションやトークンの日時は秒単位のため、パスワード変更日時も秒単位に切り捨てて比較します
+func (u *User) CredentialsValidAt(authenticatedAt time.Time) bool {
+	if u.PasswordChangedAt == nil {
+		return true
+	}
+	return !authenticatedAt.Before(u.PasswordChangedAt.Truncate(time.Second))
 }
 
 // TableName はテーブル名を指定します
@@ -0,0 +1,29 @@
+package entity
+
+import (
+	"time"
+)
+
+// UserIdentity は外部 ID プロバイダー (OpenID Connect) のアカウントとユーザーの紐付けを表すエンティティです
+type UserIdentity struct {
+	ID        string    `json:"id" gorm:"primaryKey;type:uuid;default:uuid_generate_v4()"`
+	UserID    string    `json:"-" gorm:"type:uuid;not null;index"`
+	Provider  string    `json:"provider" gorm:"type:varchar(50);not null;uniqueIndex:idx_user_identities_provider_subject"`
+	Subject   string    `json:"-" gorm:"type:varchar(255);not null;uniqueIndex:idx_user_identities_provider_subject"` // ID トークンの sub
+	Email     string    `json:"email" gorm:"not null"`                                                                // 紐付けた時点のプロバイダーのメールアドレス
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// TableName はテーブル名を指定します
+func (UserIdentity) TableName() string {
+	return "user_identities"
+}
+
+// OIDCClaims は検証済みの ID トークンから取り出したユーザー情報です
+type OIDCClaims struct {
+	Subject       string
+	Email         string
+	EmailVerified bool
+	Nonce         string
+}
@@ -0,0 +1,27 @@
+package entity
+
+import (
+	"time"
+)
+
+// ErrSessionRevoked はセッションがログアウト・リモートでの失効により無効になっている場合のエラーです
+var ErrSessionRevoked = NewUnauthorizedError("session_revoked", "セッションは無効になりました。再度ログインしてください")
+
+// UserSession はログインごとに作成されるセッションを表すエンティティです
+// Cookie のセッションと JWT のリフレッシュトークンの系列 (FamilyID) はどちらもこの ID で識別します
+type UserSession struct {
+	ID         string     `json:"id" gorm:"primaryKey;type:uuid;default:uuid_generate_v4()"`
+	UserID     string     `json:"-" gorm:"type:uuid;not null;index"`
+	Device     string     `json:"device" gorm:"not null"` // User-Agent から判定したブラウザと OS
+	IPAddress  string     `json:"ip_address" gorm:"type:varchar(45);not null"`
+	UserAgent  string     `json:"user_agent" gorm:"not null"`
+	LastSeenAt time.Time  `json:"last_seen_at" gorm:"not null"`
+	RevokedAt  *time.Time `json:"-"`
+	CreatedAt  time.Time  `json:"created_at"`
+	Current    bool       `json:"current" gorm:"-"` // リクエストを送ったセッションかどうか
+}
+
+// TableName はテーブル名を指定します
+func (UserSession) TableName() string {
+	return "user_sessions"
+}
@@ -0,0 +1,25 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/sotaheavymetal21/rabbit-cart/backend/internal/domain/entity"
+)
+
+// AddressRepository はユーザーの住所録へのアクセスを抽象化するインターフェースです
+type AddressRepository interface {
+	// FindAllByUserID はユーザーの住所を既定の配送先・登録日時の新しい順に取得します
+	FindAllByUserID(ctx context.Context, userID string) ([]*entity.UserAddress, error)
+	// FindByID はユーザーの住所をIDで取得します (他のユーザーの住所は gorm.ErrRecordNotFound)
+	FindByID(ctx context.Context, userID, id string) (*entity.UserAddress, error)
+	// FindDefault はユーザーの既定の配送先を取得します
+	FindDefault(ctx context.Context, userID string) (*entity.UserAddress, error)
+	// Create は住所を保存します
+	// IsDefault が true の場合、またはユーザーの最初の住所の場合は既定の配送先にします
+	Create(ctx context.Context, address *entity.UserAddress) error
+	// Update は住所を更新します (IsDefault が true の場合は既定の配送先にします)
+	Update(ctx context.Context, address *entity.UserAddress) error
+	// Delete はユーザーの住所を削除します
+	// 既定の配送先を削除した場合は、最も新しい住所を既定の配送先にします
+	Delete(ctx context.Context, userID, id string) error
+}
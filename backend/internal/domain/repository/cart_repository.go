@@ -0,0 +1,19 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/sotaheavymetal21/rabbit-cart/backend/internal/domain/entity"
+)
+
+// CartRepository はカートデータへのアクセスを抽象化するインターフェースです
+type CartRepository interface {
+	// FindOrCreateByUserID は指定されたユーザーのカートを取得します（存在しない場合は空のカートを作成します）
+	FindOrCreateByUserID(ctx context.Context, userID string) (*entity.Cart, error)
+	// SetItemQuantity はカート内の商品の数量を設定します（明細が無い場合は追加します）
+	SetItemQuantity(ctx context.Context, cartID, productID string, quantity int) error
+	// RemoveItem はカートから指定された商品を削除します
+	RemoveItem(ctx context.Context, cartID, productID string) error
+	// Clear はカート内の全ての明細を削除します
+	Clear(ctx context.Context, cartID string) error
+}
@@ -0,0 +1,21 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/sotaheavymetal21/rabbit-cart/backend/internal/domain/entity"
+)
+
+// EmailVerificationRepository はメールアドレス確認用トークンへのアクセスを抽象化するインターフェースです
+type EmailVerificationRepository interface {
+	// Create はトークンを保存します
+	Create(ctx context.Context, token *entity.EmailVerificationToken) error
+	// FindByHash はハッシュ値でトークンを検索します
+	FindByHash(ctx context.Context, tokenHash string) (*entity.EmailVerificationToken, error)
+	// FindLatestByUserID はユーザーに最後に発行したトークンを取得します
+	FindLatestByUserID(ctx context.Context, userID string) (*entity.EmailVerificationToken, error)
+	// Consume はトークンを使用済みにしてユーザーを確認済みにします
+	// メールアドレス変更の確認用のトークンの場合は、ユーザーのメールアドレスも変更します
+	// トークンが使用済みまたは期限切れの場合は gorm.ErrRecordNotFound を返します
+	Consume(ctx context.Context, token *entity.EmailVerificationToken) error
+}
@@ -0,0 +1,11 @@
+package repository
+
+import "github.com/sotaheavymetal21/rabbit-cart/backend/internal/domain/entity"
+
+var (
+	// ErrNotFound は条件に一致するレコードが無い場合のエラーです
+	// リポジトリの実装はデータベースのエラーをこのエラーに変換して返します
+	ErrNotFound = entity.NewNotFoundError("not_found", "対象が見つかりません")
+	// ErrDuplicate は一意制約に違反する登録・更新の場合のエラーです
+	ErrDuplicate = entity.NewConflictError("duplicate", "既に登録されています")
+)
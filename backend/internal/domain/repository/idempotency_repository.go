@@ -0,0 +1,23 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/sotaheavymetal21/rabbit-cart/backend/internal/domain/entity"
+)
+
+// IdempotencyKeyRepository は冪等キーへのアクセスを抽象化するインターフェースです
+type IdempotencyKeyRepository interface {
+	// Create は冪等キーを保存します (同じユーザー・キーの期限切れのもの・ロックの期限が切れた処理中のものは置き換えます)
+	// 有効な同じキーが既にある場合は ErrDuplicate を返します
+	Create(ctx context.Context, key *entity.IdempotencyKey) error
+	// Find はユーザーの冪等キーを取得します
+	Find(ctx context.Context, userID, key string) (*entity.IdempotencyKey, error)
+	// Complete は冪等キーにリクエストの処理結果のレスポンスを保存し、有効期限を expiresAt に延長します
+	Complete(ctx context.Context, id string, status int, body []byte, expiresAt time.Time) error
+	// Delete は冪等キーを削除します
+	Delete(ctx context.Context, id string) error
+	// PurgeExpired は有効期限を過ぎた冪等キーを削除します
+	PurgeExpired(ctx context.Context, now time.Time) error
+}
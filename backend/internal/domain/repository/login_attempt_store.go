@@ -0,0 +1,26 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/sotaheavymetal21/rabbit-cart/backend/internal/domain/entity"
+)
+
+// LoginAttemptStore はログイン失敗回数とロックの保存先を抽象化するインターフェースです
+type LoginAttemptStore interface {
+	// RecordFailure は key の失敗を記録し、直近 window 内の失敗回数を返します
+	RecordFailure(ctx context.Context, key string, window time.Duration) (int, error)
+	// Reset は key の失敗回数とロックを削除します
+	Reset(ctx context.Context, key string) error
+	// Lock は key を d の間ロックします
+	Lock(ctx context.Context, key string, d time.Duration) error
+	// LockedFor は key のロックの残り時間を返します (ロックされていない場合は 0)
+	LockedFor(ctx context.Context, key string) (time.Duration, error)
+}
+
+// LoginLockoutRepository はロックアウトの監査記録へのアクセスを抽象化するインターフェースです
+type LoginLockoutRepository interface {
+	// Create は監査記録を保存します
+	Create(ctx context.Context, lockout *entity.LoginLockout) error
+}
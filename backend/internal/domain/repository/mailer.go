@@ -0,0 +1,13 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/sotaheavymetal21/rabbit-cart/backend/internal/domain/entity"
+)
+
+// Mailer はメール送信を抽象化するインターフェースです
+type Mailer interface {
+	// Send はメールを送信します
+	Send(ctx context.Context, mail *entity.Mail) error
+}
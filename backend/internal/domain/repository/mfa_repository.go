@@ -0,0 +1,33 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/sotaheavymetal21/rabbit-cart/backend/internal/domain/entity"
+)
+
+// MFARepository は二要素認証の設定・リカバリーコード・ログイン途中の状態へのアクセスを抽象化するインターフェースです
+type MFARepository interface {
+	// SetPendingTOTPSecret は登録途中の TOTP シークレットを保存します (二要素認証は無効のまま)
+	SetPendingTOTPSecret(ctx context.Context, userID, secret string) error
+	// EnableTOTP は二要素認証を有効にし、リカバリーコードを codes で置き換えます
+	EnableTOTP(ctx context.Context, userID string, step int64, codes []entity.MFARecoveryCode) error
+	// DisableTOTP は二要素認証を無効にし、シークレットとリカバリーコードを削除します
+	DisableTOTP(ctx context.Context, userID string) error
+	// AdvanceTOTPStep は最後に使用したコードの時間ステップを step に進めます
+	// step が記録済みの値以下の場合 (コードの再利用) は false を返します
+	AdvanceTOTPStep(ctx context.Context, userID string, step int64) (bool, error)
+	// UseRecoveryCode は未使用のリカバリーコードを使用済みにします (該当するコードが無い場合は false)
+	UseRecoveryCode(ctx context.Context, userID, codeHash string) (bool, error)
+	// CountUnusedRecoveryCodes は未使用のリカバリーコードの数を返します
+	CountUnusedRecoveryCodes(ctx context.Context, userID string) (int64, error)
+
+	// CreateChallenge はログイン途中の状態を保存します
+	CreateChallenge(ctx context.Context, challenge *entity.MFAChallenge) error
+	// FindChallenge はハッシュ値でログイン途中の状態を検索します
+	FindChallenge(ctx context.Context, tokenHash string) (*entity.MFAChallenge, error)
+	// RecordChallengeFailure はコードの入力失敗を記録し、失敗回数を返します
+	RecordChallengeFailure(ctx context.Context, id string) (int, error)
+	// ConsumeChallenge はログイン途中の状態を使用済みにします (既に使用済みの場合は gorm.ErrRecordNotFound)
+	ConsumeChallenge(ctx context.Context, id string) error
+}
@@ -0,0 +1,18 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/sotaheavymetal21/rabbit-cart/backend/internal/domain/entity"
+)
+
+// OIDCProvider は OpenID Connect の ID プロバイダーとの通信を抽象化するインターフェースです
+type OIDCProvider interface {
+	// Name は設定で指定したプロバイダー名を返します
+	Name() string
+	// AuthCodeURL は認可コードフロー (PKCE の S256) でユーザーをリダイレクトする URL を返します
+	AuthCodeURL(ctx context.Context, state, nonce, codeChallenge string) (string, error)
+	// Exchange は認可コードをトークンに交換し、署名・発行者・対象者・有効期限を検証した ID トークンのクレームを返します
+	// nonce の照合は呼び出し側で行います
+	Exchange(ctx context.Context, code, codeVerifier string) (*entity.OIDCClaims, error)
+}
@@ -10,8 +10,12 @@ import (
 type OrderRepository interface {
 	// FindAllByUserID は指定されたユーザーの注文を全て取得します
 	FindAllByUserID(ctx context.Context, userID string) ([]*entity.Order, error)
-	// FindByID は指定されたIDの注文を取得します（注文明細を含む）
+	// FindByID は指定されたIDの注文を取得します（注文明細・ステータス履歴・決済・返金・割引の内訳を含む）
 	FindByID(ctx context.Context, id string) (*entity.Order, error)
-	// Create は注文を作成します（注文明細も含む）
+	// Create は注文を作成します（注文明細・割引の内訳も含む）
+	// クーポンの利用回数が上限に達している場合は entity.ErrPromotionUsageLimitReached / entity.ErrPromotionPerUserLimitReached を返します
 	Create(ctx context.Context, order *entity.Order) error
+	// UpdateStatus は history.FromStatus から history.ToStatus へ注文ステータスを更新し、変更履歴を記録します
+	// restoreStock が true の場合は注文明細の数量分の在庫と、注文で使用したクーポンの利用回数を同一トランザクション内で戻します
+	UpdateStatus(ctx context.Context, history *entity.OrderStatusHistory, restoreStock bool) error
 }
@@ -0,0 +1,19 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/sotaheavymetal21/rabbit-cart/backend/internal/domain/entity"
+)
+
+// PasswordResetRepository はパスワードリセット用トークンへのアクセスを抽象化するインターフェースです
+type PasswordResetRepository interface {
+	// Create はトークンを保存します
+	Create(ctx context.Context, token *entity.PasswordResetToken) error
+	// FindByHash はハッシュ値でトークンを検索します
+	FindByHash(ctx context.Context, tokenHash string) (*entity.PasswordResetToken, error)
+	// Consume はトークンを使用済みにしてユーザーのパスワードを更新します
+	// 同じユーザーの他の未使用トークン・ログインセッション・リフレッシュトークンも全て失効させます
+	// トークンが使用済みまたは期限切れの場合は gorm.ErrRecordNotFound を返します
+	Consume(ctx context.Context, token *entity.PasswordResetToken, passwordHash string) error
+}
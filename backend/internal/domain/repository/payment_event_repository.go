@@ -0,0 +1,31 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/sotaheavymetal21/rabbit-cart/backend/internal/domain/entity"
+)
+
+// PaymentEventRepository は Webhook で受け取った決済イベントと、処理に失敗したイベント (デッドレター) へのアクセスを抽象化するインターフェースです
+type PaymentEventRepository interface {
+	// Create はイベントを保存します (同じ決済サービス・イベントIDのものが保存済みの場合は ErrDuplicate を返します)
+	Create(ctx context.Context, event *entity.PaymentEvent) error
+	// FindByEventID は決済サービスとイベントIDでイベントを取得します
+	FindByEventID(ctx context.Context, provider, eventID string) (*entity.PaymentEvent, error)
+	// Claim は処理済みでないイベントの処理を開始した日時を claimedAt から now に更新します
+	// 他の処理が先に更新していた場合は false を返します
+	Claim(ctx context.Context, id string, claimedAt, now time.Time) (bool, error)
+	// MarkProcessed はイベントを処理済みにします
+	MarkProcessed(ctx context.Context, id string, at time.Time) error
+	// CreateDeadLetter は処理に失敗したイベントを記録します
+	CreateDeadLetter(ctx context.Context, deadLetter *entity.PaymentDeadLetter) error
+	// UpdateDeadLetter はデッドレターの再処理の結果を更新します
+	UpdateDeadLetter(ctx context.Context, deadLetter *entity.PaymentDeadLetter) error
+	// FindDeadLetter はデッドレターをイベントと共に取得します
+	FindDeadLetter(ctx context.Context, id string) (*entity.PaymentDeadLetter, error)
+	// FindDeadLetterByEventID はイベントのデッドレターを取得します
+	FindDeadLetterByEventID(ctx context.Context, paymentEventID string) (*entity.PaymentDeadLetter, error)
+	// ListUnresolvedDeadLetters は再処理に成功していないデッドレターを古い順に取得します
+	ListUnresolvedDeadLetters(ctx context.Context) ([]*entity.PaymentDeadLetter, error)
+}
@@ -0,0 +1,31 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/sotaheavymetal21/rabbit-cart/backend/internal/domain/entity"
+)
+
+// PaymentGateway は決済サービスとの通信を抽象化するインターフェースです
+// 決済が拒否された場合はエラーではなく Status が failed の結果を返し、通信の失敗などの場合はエラーを返します
+type PaymentGateway interface {
+	// Name は決済サービスの名前を返します (決済の記録に保存します)
+	Name() string
+	// Authorize は支払い方法に対して金額の与信を行います
+	// 本人認証が必要な場合は Status が requires_action の結果を返します
+	Authorize(ctx context.Context, req *entity.PaymentAuthorization) (*entity.PaymentResult, error)
+	// Capture は与信済みの決済の売上を amount で確定します
+	Capture(ctx context.Context, providerPaymentID string, amount int) (*entity.PaymentResult, error)
+	// Void は売上確定前の与信を取り消します
+	Void(ctx context.Context, providerPaymentID string) (*entity.PaymentResult, error)
+	// Refund は売上確定済みの決済から amount を返金します
+	Refund(ctx context.Context, providerPaymentID string, amount int) (*entity.PaymentResult, error)
+}
+
+// PaymentWebhookVerifier は決済サービスからの Webhook の署名を検証し、通知されたイベントを取り出すインターフェースです
+type PaymentWebhookVerifier interface {
+	// Verify は payload の署名 signature と送信時刻を now と比べて検証し、イベントを返します
+	// 署名が正しくない場合は entity.ErrInvalidWebhookSignature、送信時刻が許容範囲外の場合は entity.ErrWebhookTimestampOutOfRange を返します
+	Verify(payload []byte, signature string, now time.Time) (*entity.PaymentEvent, error)
+}
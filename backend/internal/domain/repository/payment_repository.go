@@ -0,0 +1,19 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/sotaheavymetal21/rabbit-cart/backend/internal/domain/entity"
+)
+
+// PaymentRepository は決済の記録へのアクセスを抽象化するインターフェースです
+type PaymentRepository interface {
+	// Create は決済を保存します
+	Create(ctx context.Context, payment *entity.Payment) error
+	// Update は決済の状態を更新します
+	Update(ctx context.Context, payment *entity.Payment) error
+	// FindLatestByOrderID は注文の最新の決済を取得します
+	FindLatestByOrderID(ctx context.Context, orderID string) (*entity.Payment, error)
+	// FindByProviderPaymentID は決済サービスでの決済IDから決済を取得します
+	FindByProviderPaymentID(ctx context.Context, provider, providerPaymentID string) (*entity.Payment, error)
+}
@@ -6,10 +6,67 @@ import (
 	"github.com/sotaheavymetal21/rabbit-cart/backend/internal/domain/entity"
 )
 
+var (
+	// ErrInvalidCursor はページングのカーソルが解釈できない場合のエラーです
+	ErrInvalidCursor = entity.NewValidationError("invalid_cursor", "カーソルが不正です", entity.FieldError{Field: "cursor", Code: "next_cursor", Message: "前回の結果の next_cursor を指定してください"})
+	// ErrProductInUse は注文で参照されている商品を削除しようとした場合のエラーです
+	ErrProductInUse = entity.NewConflictError("product_in_use", "注文履歴がある商品は削除できません")
+	// ErrDuplicateSKU は商品コードが他の商品と重複している場合のエラーです
+	ErrDuplicateSKU = entity.NewConflictError("duplicate_sku", "商品コードが他の商品と重複しています")
+)
+
+// ProductSort は商品一覧の並び順です
+type ProductSort string
+
+const (
+	ProductSortNewest    ProductSort = "newest"
+	ProductSortPriceAsc  ProductSort = "price_asc"
+	ProductSortPriceDesc ProductSort = "price_desc"
+	ProductSortName      ProductSort = "name"
+)
+
+// ProductQuery は商品の検索条件です
+// Cursor が指定された場合は Page を無視し、カーソル位置の続きから取得します
+type ProductQuery struct {
+	Keyword     string // 商品名・説明文の部分一致
+	Category    string
+	MinPrice    *int
+	MaxPrice    *int
+	InStockOnly bool
+	Sort        ProductSort
+	Page        int
+	PerPage     int
+	Cursor      string
+}
+
+// ProductPage は商品検索の結果です
+type ProductPage struct {
+	Products   []*entity.Product
+	Total      int64 // 条件に一致する商品の総数
+	Page       int   // カーソル指定時は 0
+	PerPage    int
+	NextCursor string // 次のページが無い場合は空文字
+}
+
 // ProductRepository は商品データへのアクセスを抽象化するインターフェースです
 type ProductRepository interface {
 	// FindAll は全ての商品を取得します
 	FindAll(ctx context.Context) ([]*entity.Product, error)
 	// FindByID は指定されたIDの商品を取得します
 	FindByID(ctx context.Context, id string) (*entity.Product, error)
+	// Search は条件に一致する商品を1ページ分取得します
+	Search(ctx context.Context, query ProductQuery) (*ProductPage, error)
+	// Create は商品を作成します
+	Create(ctx context.Context, product *entity.Product) error
+	// Update は商品情報を更新します (在庫は更新しません)
+	Update(ctx context.Context, product *entity.Product) error
+	// Delete は商品を削除します（注文で参照されている場合は ErrProductInUse を返します）
+	Delete(ctx context.Context, id string) error
+	// Restock は商品の在庫を quantity だけ増やし、更新後の商品を返します
+	Restock(ctx context.Context, id string, quantity int) (*entity.Product, error)
+	// UpsertBySKU は商品コードをキーに商品を一括で作成・更新します (既存の商品の在庫は更新しません)
+	// 全件を1つのトランザクションで batchSize 件ずつ書き込み、新規作成した件数を返します
+	UpsertBySKU(ctx context.Context, products []*entity.Product, batchSize int) (int, error)
+	// FindInBatches は全ての商品を batchSize 件ずつ fn に渡します
+	FindInBatches(ctx context.Context, batchSize int, fn func(products []*entity.Product) error) error
 }
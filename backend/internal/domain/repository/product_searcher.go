@@ -0,0 +1,27 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/sotaheavymetal21/rabbit-cart/backend/internal/domain/entity"
+)
+
+// ProductSearchHit は全文検索でヒットした商品と関連度です
+type ProductSearchHit struct {
+	Product *entity.Product `json:"product"`
+	Score   float64         `json:"score"`
+	// Highlights はフィールド名 (name, description, category) ごとの一致箇所を <mark> で囲んだ抜粋です
+	Highlights map[string]string `json:"highlights"`
+}
+
+// ProductSearcher は商品の全文検索インデックスを抽象化するインターフェースです
+type ProductSearcher interface {
+	// Index は商品をインデックスに登録します（登録済みの場合は更新します）
+	Index(ctx context.Context, product *entity.Product) error
+	// Remove は商品をインデックスから削除します
+	Remove(ctx context.Context, productID string) error
+	// Reindex は全ての商品でインデックスを作り直します
+	Reindex(ctx context.Context) error
+	// Search はキーワードに一致する商品を関連度の高い順に最大 limit 件返します
+	Search(ctx context.Context, keyword string, limit int) ([]ProductSearchHit, error)
+}
@@ -0,0 +1,26 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/sotaheavymetal21/rabbit-cart/backend/internal/domain/entity"
+)
+
+// ErrPromotionInUse は注文で使用されたクーポンを削除しようとした場合のエラーです
+var ErrPromotionInUse = entity.NewConflictError("promotion_in_use", "注文で使用されたクーポンは削除できません。無効にしてください")
+
+// PromotionRepository はクーポンへのアクセスを抽象化するインターフェースです
+type PromotionRepository interface {
+	// FindAll は全てのクーポンを登録日時の新しい順に取得します
+	FindAll(ctx context.Context) ([]*entity.Promotion, error)
+	// FindByID は指定されたIDのクーポンを取得します
+	FindByID(ctx context.Context, id string) (*entity.Promotion, error)
+	// FindByCode はクーポンコードからクーポンを取得します
+	FindByCode(ctx context.Context, code string) (*entity.Promotion, error)
+	// Create はクーポンを作成します (コードが重複する場合は ErrDuplicate を返します)
+	Create(ctx context.Context, promotion *entity.Promotion) error
+	// Update はクーポンの設定を更新します (利用回数は変更しません)
+	Update(ctx context.Context, promotion *entity.Promotion) error
+	// Delete はクーポンを削除します (注文で使用されている場合は ErrPromotionInUse を返します)
+	Delete(ctx context.Context, id string) error
+}
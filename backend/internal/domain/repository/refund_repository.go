@@ -0,0 +1,19 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/sotaheavymetal21/rabbit-cart/backend/internal/domain/entity"
+)
+
+// RefundRepository は返金データへのアクセスを抽象化するインターフェースです
+type RefundRepository interface {
+	// Create は返金を pending として保存し、決済と注文明細の返金済みの金額・数量に加算して返金額を確保します
+	// 決済の売上の確定額を超える場合は entity.ErrRefundExceedsCaptured、明細の未返金の分を超える場合は entity.ErrRefundExceedsOrderItem を返します
+	Create(ctx context.Context, refund *entity.Refund) error
+	// Complete は返金を succeeded にし、決済のステータスを paymentStatus に更新します
+	// refund.Restock が true の場合は返品された数量を同一トランザクション内で在庫に戻します
+	Complete(ctx context.Context, refund *entity.Refund, paymentStatus entity.PaymentStatus) error
+	// Fail は返金を failed にし、Create で確保した返金額を決済と注文明細から差し引きます
+	Fail(ctx context.Context, refund *entity.Refund) error
+}
@@ -0,0 +1,25 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/sotaheavymetal21/rabbit-cart/backend/internal/domain/entity"
+)
+
+// SessionRepository はログインセッションへのアクセスを抽象化するインターフェースです
+type SessionRepository interface {
+	// Create はセッションを保存します
+	Create(ctx context.Context, session *entity.UserSession) error
+	// FindByID はIDでセッションを検索します
+	FindByID(ctx context.Context, id string) (*entity.UserSession, error)
+	// FindActiveByUserID はユーザーの有効なセッションを最終利用日時の新しい順に取得します
+	FindActiveByUserID(ctx context.Context, userID string) ([]*entity.UserSession, error)
+	// Touch はセッションの最終利用日時と IP アドレスを更新します
+	Touch(ctx context.Context, id, ip string, at time.Time) error
+	// Revoke はユーザーのセッションを失効させ、同じ系列のリフレッシュトークンも失効させます
+	// 該当する有効なセッションが無い場合は false を返します
+	Revoke(ctx context.Context, userID, id string) (bool, error)
+	// RevokeAllByUserID はユーザーの全てのセッションとリフレッシュトークンを失効させ、失効させたセッション数を返します
+	RevokeAllByUserID(ctx context.Context, userID string) (int64, error)
+}
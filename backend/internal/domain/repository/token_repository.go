@@ -0,0 +1,36 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/sotaheavymetal21/rabbit-cart/backend/internal/domain/entity"
+)
+
+// TokenRepository はリフレッシュトークンとアクセストークンの失効リストへのアクセスを抽象化するインターフェースです
+type TokenRepository interface {
+	// CreateRefreshToken はリフレッシュトークンを保存します
+	CreateRefreshToken(ctx context.Context, token *entity.RefreshToken) error
+	// FindRefreshToken はハッシュ値でリフレッシュトークンを検索します
+	FindRefreshToken(ctx context.Context, tokenHash string) (*entity.RefreshToken, error)
+	// RotateRefreshToken は old を失効させて next を保存します
+	// old が既に失効している場合は entity.ErrRefreshTokenReused を返します
+	RotateRefreshToken(ctx context.Context, old, next *entity.RefreshToken) error
+	// RevokeRefreshTokenFamily は同じ系列のリフレッシュトークンを全て失効させます
+	RevokeRefreshTokenFamily(ctx context.Context, familyID string) error
+	// RevokeAccessToken はアクセストークンを失効リストに追加します
+	RevokeAccessToken(ctx context.Context, jti string, expiresAt time.Time) error
+	// IsAccessTokenRevoked はアクセストークンが失効リストにあるかを返します
+	IsAccessTokenRevoked(ctx context.Context, jti string) (bool, error)
+	// PurgeExpired は有効期限を過ぎたリフレッシュトークンと失効リストのエントリを削除します
+	PurgeExpired(ctx context.Context, now time.Time) error
+}
+
+// TokenSigner はアクセストークンの署名と検証を抽象化するインターフェースです
+type TokenSigner interface {
+	// Sign はクレームに署名したトークンを返します
+	Sign(claims *entity.AccessTokenClaims) (string, error)
+	// Verify はトークンの署名と有効期限を検証し、クレームを返します
+	// 不正なトークンは entity.ErrInvalidToken、期限切れは entity.ErrTokenExpired を返します
+	Verify(token string) (*entity.AccessTokenClaims, error)
+}
@@ -0,0 +1,17 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/sotaheavymetal21/rabbit-cart/backend/internal/domain/entity"
+)
+
+// UserIdentityRepository は外部 ID プロバイダーのアカウントの紐付けへのアクセスを抽象化するインターフェースです
+type UserIdentityRepository interface {
+	// FindByProviderSubject はプロバイダー名と sub で紐付けを検索します
+	FindByProviderSubject(ctx context.Context, provider, subject string) (*entity.UserIdentity, error)
+	// Create は既存のユーザーに紐付けを追加します
+	Create(ctx context.Context, identity *entity.UserIdentity) error
+	// CreateWithUser はユーザーと紐付けを同一トランザクションで作成します
+	CreateWithUser(ctx context.Context, user *entity.User, identity *entity.UserIdentity) error
+}
@@ -14,4 +14,12 @@ type UserRepository interface {
 	FindByEmail(ctx context.Context, email string) (*entity.User, error)
 	// FindByID はIDでユーザーを検索します
 	FindByID(ctx context.Context, id string) (*entity.User, error)
+	// UpdateProfile はユーザーの表示名・電話番号・言語を更新します
+	UpdateProfile(ctx context.Context, id, displayName, phone string, locale entity.Locale) error
+	// UpdatePassword はユーザーのパスワードを変更します
+	// keepSessionID 以外のログインセッションと全てのリフレッシュトークンも失効させます
+	UpdatePassword(ctx context.Context, id, passwordHash, keepSessionID string) error
+	// Delete はユーザーを削除します
+	// 注文は売上の記録として残し、ユーザーとの紐付けと配送先を削除して匿名化します
+	Delete(ctx context.Context, id string) error
 }
@@ -0,0 +1,88 @@
+// Package dbtest はデータベースを使うテストに使い捨てのデータベースを提供します
+// 環境変数 TEST_DB_URL に PostgreSQL の接続先 (データベースを作成できるユーザー) を設定した場合のみ実行し、未設定の場合はテストをスキップします
+package dbtest
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"net/url"
+	"os"
+	"testing"
+
+	"github.com/sotaheavymetal21/rabbit-cart/backend/internal/infrastructure/database"
+	"github.com/sotaheavymetal21/rabbit-cart/backend/migrations"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// EnvURL はテスト用の PostgreSQL の接続先を指定する環境変数の名前です
+const EnvURL = "TEST_DB_URL"
+
+// Open は空のデータベースを新しく作成して接続します
+// 作成したデータベースはテストの終了時に削除します
+func Open(t testing.TB) *gorm.DB {
+	t.Helper()
+
+	baseURL := os.Getenv(EnvURL)
+	if baseURL == "" {
+		t.Skipf("%s が設定されていないためスキップします", EnvURL)
+	}
+	u, err := url.Parse(baseURL)
+	if err != nil {
+		t.Fatalf("%s を解析できません: %v", EnvURL, err)
+	}
+
+	admin, err := gorm.Open(postgres.Open(baseURL), &gorm.Config{Logger: logger.Discard})
+	if err != nil {
+		t.Fatalf("テスト用のデータベースサーバーに接続できません: %v", err)
+	}
+	adminDB, err := admin.DB()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	name := "rabbit_cart_test_" + randomSuffix(t)
+	if err := admin.Exec(fmt.Sprintf("CREATE DATABASE %q", name)).Error; err != nil {
+		adminDB.Close()
+		t.Fatalf("テスト用のデータベースを作成できません: %v", err)
+	}
+
+	u.Path = "/" + name
+	db, err := gorm.Open(postgres.Open(u.String()), &gorm.Config{Logger: logger.Discard})
+	if err != nil {
+		t.Fatalf("テスト用のデータベースに接続できません: %v", err)
+	}
+
+	t.Cleanup(func() {
+		if sqlDB, err := db.DB(); err == nil {
+			sqlDB.Close()
+		}
+		if err := admin.Exec(fmt.Sprintf("DROP DATABASE IF EXISTS %q WITH (FORCE)", name)).Error; err != nil {
+			t.Logf("テスト用のデータベース %s を削除できません: %v", name, err)
+		}
+		adminDB.Close()
+	})
+	return db
+}
+
+// New は Open で作成したデータベースに全てのマイグレーションを適用して返します
+func New(t testing.TB) *gorm.DB {
+	t.Helper()
+
+	db := Open(t)
+	if _, err := database.NewMigrator(db, migrations.FS).Up(context.Background()); err != nil {
+		t.Fatalf("マイグレーションの適用に失敗しました: %v", err)
+	}
+	return db
+}
+
+func randomSuffix(t testing.TB) string {
+	b := make([]byte, 6)
+	if _, err := rand.Read(b); err != nil {
+		t.Fatal(err)
+	}
+	return hex.EncodeToString(b)
+}
@@ -0,0 +1,311 @@
+package database
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/schema"
+)
+
+// migrationLockID は複数のプロセスが同時にマイグレーションを実行しないための advisory lock のキーです
+const migrationLockID = 7426105
+
+// migrationFilePattern はマイグレーションファイル名の形式 (<バージョン>_<名前>.sql) です
+var migrationFilePattern = regexp.MustCompile(`^(\d+)_([^.]+)\.sql$`)
+
+// ErrMigrationModified は適用済みのマイグレーションファイルが書き換えられている場合のエラーです
+var ErrMigrationModified = errors.New("適用済みのマイグレーションファイルが変更されています")
+
+// Migration は1つのマイグレーションファイルです
+type Migration struct {
+	Version  string
+	Name     string
+	SQL      string
+	Checksum string
+}
+
+// MigrationStatus はマイグレーションの適用状況です
+type MigrationStatus struct {
+	Migration
+	AppliedAt *time.Time
+	Modified  bool // 適用後にファイルの内容が変わっている
+}
+
+// SchemaMigration は適用済みのマイグレーションを記録するテーブルのレコードです
+type SchemaMigration struct {
+	Version   string    `gorm:"primaryKey;type:varchar(32)"`
+	Name      string    `gorm:"not null"`
+	Checksum  string    `gorm:"type:varchar(64);not null"`
+	AppliedAt time.Time `gorm:"not null"`
+}
+
+// TableName はテーブル名を指定します
+func (SchemaMigration) TableName() string {
+	return "schema_migrations"
+}
+
+// Migrator は埋め込まれた SQL ファイルをバージョン順に適用します
+type Migrator struct {
+	db   *gorm.DB
+	fsys fs.FS
+}
+
+// NewMigrator は Migrator を生成します
+func NewMigrator(db *gorm.DB, fsys fs.FS) *Migrator {
+	return &Migrator{db: db, fsys: fsys}
+}
+
+// Load はマイグレーションファイルをバージョン順に読み込みます
+func (m *Migrator) Load() ([]Migration, error) {
+	entries, err := fs.ReadDir(m.fsys, ".")
+	if err != nil {
+		return nil, err
+	}
+
+	var migrations []Migration
+	for _, e := range entries {
+		matches := migrationFilePattern.FindStringSubmatch(e.Name())
+		if e.IsDir() || matches == nil {
+			continue
+		}
+		content, err := fs.ReadFile(m.fsys, path.Join(".", e.Name()))
+		if err != nil {
+			return nil, err
+		}
+		sum := sha256.Sum256(content)
+		migrations = append(migrations, Migration{
+			Version:  matches[1],
+			Name:     matches[2],
+			SQL:      string(content),
+			Checksum: hex.EncodeToString(sum[:]),
+		})
+	}
+	sort.Slice(migrations, func(i, j int) bool { return migrations[i].Version < migrations[j].Version })
+	return migrations, nil
+}
+
+// Status は全てのマイグレーションの適用状況を返します
+func (m *Migrator) Status(ctx context.Context) ([]MigrationStatus, error) {
+	migrations, err := m.Load()
+	if err != nil {
+		return nil, err
+	}
+	applied, err := m.applied(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	statuses := make([]MigrationStatus, 0, len(migrations))
+	for _, mig := range migrations {
+		status := MigrationStatus{Migration: mig}
+		if rec, ok := applied[mig.Version]; ok {
+			appliedAt := rec.AppliedAt
+			status.AppliedAt = &appliedAt
+			status.Modified = rec.Checksum != mig.Checksum
+		}
+		statuses = append(statuses, status)
+	}
+	return statuses, nil
+}
+
+// Up は未適用のマイグレーションをバージョン順に適用し、適用したものを返します
+// 各マイグレーションは1つのトランザクションで実行し、失敗した時点で中断します
+func (m *Migrator) Up(ctx context.Context) ([]Migration, error) {
+	statuses, err := m.Status(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var done []Migration
+	for _, s := range statuses {
+		if s.Modified {
+			return done, fmt.Errorf("%w: %s_%s", ErrMigrationModified, s.Version, s.Name)
+		}
+		if s.AppliedAt != nil {
+			continue
+		}
+
+		mig := s.Migration
+		applied := false
+		err := m.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+			if err := tx.Exec("SELECT pg_advisory_xact_lock(?)", migrationLockID).Error; err != nil {
+				return err
+			}
+			// ロック待ちの間に他のプロセスが適用している場合は何もしない
+			var count int64
+			if err := tx.Model(&SchemaMigration{}).Where("version = ?", mig.Version).Count(&count).Error; err != nil {
+				return err
+			}
+			if count > 0 {
+				return nil
+			}
+			if err := tx.Exec(mig.SQL).Error; err != nil {
+				return err
+			}
+			applied = true
+			return tx.Create(&SchemaMigration{
+				Version:   mig.Version,
+				Name:      mig.Name,
+				Checksum:  mig.Checksum,
+				AppliedAt: time.Now(),
+			}).Error
+		})
+		if err != nil {
+			return done, fmt.Errorf("マイグレーション %s_%s の適用に失敗しました: %w", mig.Version, mig.Name, err)
+		}
+		if applied {
+			done = append(done, mig)
+		}
+	}
+	return done, nil
+}
+
+// Baseline は version 以前のマイグレーションを実行せずに適用済みとして記録します
+// AutoMigrate で構築済みのデータベースをマイグレーション管理に移行する際に使用します
+func (m *Migrator) Baseline(ctx context.Context, version string) error {
+	migrations, err := m.Load()
+	if err != nil {
+		return err
+	}
+	if err := m.ensureTable(ctx); err != nil {
+		return err
+	}
+
+	found := false
+	for _, mig := range migrations {
+		if mig.Version > version {
+			break
+		}
+		found = found || mig.Version == version
+		if err := m.db.WithContext(ctx).
+			Where(SchemaMigration{Version: mig.Version}).
+			FirstOrCreate(&SchemaMigration{
+				Version:   mig.Version,
+				Name:      mig.Name,
+				Checksum:  mig.Checksum,
+				AppliedAt: time.Now(),
+			}).Error; err != nil {
+			return err
+		}
+	}
+	if !found {
+		return fmt.Errorf("バージョン %s のマイグレーションが見つかりません", version)
+	}
+	return nil
+}
+
+// Verify はエンティティの定義とデータベースのスキーマを比較し、差分を返します
+// テーブルやカラムの不足、型の不一致、エンティティに無いカラムを検出します
+func (m *Migrator) Verify(ctx context.Context, models ...interface{}) ([]string, error) {
+	db := m.db.WithContext(ctx)
+	migrator := db.Migrator()
+
+	var diffs []string
+	for _, model := range models {
+		stmt := &gorm.Statement{DB: db}
+		if err := stmt.Parse(model); err != nil {
+			return nil, err
+		}
+		table := stmt.Schema.Table
+
+		if !migrator.HasTable(model) {
+			diffs = append(diffs, fmt.Sprintf("テーブル %s がありません", table))
+			continue
+		}
+		columnTypes, err := migrator.ColumnTypes(model)
+		if err != nil {
+			return nil, err
+		}
+		columns := make(map[string]gorm.ColumnType, len(columnTypes))
+		for _, ct := range columnTypes {
+			columns[ct.Name()] = ct
+		}
+
+		for _, field := range stmt.Schema.Fields {
+			if field.DBName == "" || field.IgnoreMigration {
+				continue
+			}
+			ct, ok := columns[field.DBName]
+			if !ok {
+				diffs = append(diffs, fmt.Sprintf("%s.%s: カラムがありません", table, field.DBName))
+				continue
+			}
+			delete(columns, field.DBName)
+
+			want := normalizeColumnType(migrator.FullDataTypeOf(field).SQL)
+			got := normalizeColumnType(ct.DatabaseTypeName())
+			if want != got {
+				diffs = append(diffs, fmt.Sprintf("%s.%s: 型が異なります (エンティティ: %s, データベース: %s)", table, field.DBName, want, got))
+			}
+			if nullable, ok := ct.Nullable(); ok && !nullable && !field.NotNull && !field.PrimaryKey && field.DataType != schema.Time {
+				diffs = append(diffs, fmt.Sprintf("%s.%s: データベースでは NOT NULL ですがエンティティに not null がありません", table, field.DBName))
+			}
+		}
+		for name := range columns {
+			diffs = append(diffs, fmt.Sprintf("%s.%s: エンティティに無いカラムです", table, name))
+		}
+	}
+	sort.Strings(diffs)
+	return diffs, nil
+}
+
+func (m *Migrator) ensureTable(ctx context.Context) error {
+	return m.db.WithContext(ctx).Exec(`CREATE TABLE IF NOT EXISTS schema_migrations (
+  version varchar(32) PRIMARY KEY,
+  name text NOT NULL,
+  checksum varchar(64) NOT NULL,
+  applied_at timestamptz NOT NULL
+)`).Error
+}
+
+func (m *Migrator) applied(ctx context.Context) (map[string]SchemaMigration, error) {
+	if err := m.ensureTable(ctx); err != nil {
+		return nil, err
+	}
+	var records []SchemaMigration
+	if err := m.db.WithContext(ctx).Find(&records).Error; err != nil {
+		return nil, err
+	}
+	applied := make(map[string]SchemaMigration, len(records))
+	for _, r := range records {
+		applied[r.Version] = r
+	}
+	return applied, nil
+}
+
+// normalizeColumnType は GORM の型名と PostgreSQL の型名を比較できる形に揃えます
+func normalizeColumnType(t string) string {
+	t = strings.ToLower(strings.TrimSpace(t))
+	// 型名の後ろの制約 (NOT NULL, DEFAULT など) とサイズ指定を取り除く
+	if i := strings.IndexAny(t, " ("); i >= 0 {
+		t = t[:i]
+	}
+	switch t {
+	case "bigint", "int8", "bigserial":
+		return "int8"
+	case "integer", "int", "int4", "serial":
+		return "int4"
+	case "smallint", "int2":
+		return "int2"
+	case "boolean", "bool":
+		return "bool"
+	case "character", "varchar", "character varying":
+		return "varchar"
+	case "timestamp with time zone", "timestamptz":
+		return "timestamptz"
+	case "double precision", "float8":
+		return "float8"
+	default:
+		return t
+	}
+}
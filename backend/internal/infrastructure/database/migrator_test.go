@@ -0,0 +1,69 @@
+package database_test
+
+import (
+	"context"
+	"testing"
+	"testing/fstest"
+
+	"github.com/sotaheavymetal21/rabbit-cart/backend/internal/infrastructure/database"
+	"github.com/sotaheavymetal21/rabbit-cart/backend/internal/infrastructure/database/dbtest"
+	"github.com/sotaheavymetal21/rabbit-cart/backend/migrations"
+)
+
+func TestMigratorLoad(t *testing.T) {
+	fsys := fstest.MapFS{
+		"20240102000000_second.sql": {Data: []byte("SELECT 2;")},
+		"20240101000000_first.sql":  {Data: []byte("SELECT 1;")},
+		"atlas.sum":                 {Data: []byte("h1:...")},
+		"README.md":                 {Data: []byte("# migrations")},
+	}
+	got, err := database.NewMigrator(nil, fsys).Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 2 {
+		t.Fatalf("len(Load()) = %d, want 2", len(got))
+	}
+	if got[0].Version != "20240101000000" || got[0].Name != "first" || got[1].Version != "20240102000000" {
+		t.Errorf("Load() = %+v, want first, second の順", got)
+	}
+	if got[0].Checksum == got[1].Checksum {
+		t.Error("内容の異なるファイルのチェックサムが一致しています")
+	}
+}
+
+// 全てのマイグレーションを適用したスキーマがエンティティの定義と一致することを確認する
+func TestMigratorUpMatchesModels(t *testing.T) {
+	db := dbtest.Open(t)
+	ctx := context.Background()
+	migrator := database.NewMigrator(db, migrations.FS)
+
+	all, err := migrator.Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	applied, err := migrator.Up(ctx)
+	if err != nil {
+		t.Fatalf("Up: %v", err)
+	}
+	if len(applied) != len(all) {
+		t.Errorf("適用したマイグレーション = %d 件, want %d 件", len(applied), len(all))
+	}
+
+	diffs, err := migrator.Verify(ctx, database.Models()...)
+	if err != nil {
+		t.Fatalf("Verify: %v", err)
+	}
+	for _, d := range diffs {
+		t.Errorf("スキーマの差分: %s", d)
+	}
+
+	// 2回目は何も適用しない
+	applied, err = migrator.Up(ctx)
+	if err != nil {
+		t.Fatalf("2回目の Up: %v", err)
+	}
+	if len(applied) != 0 {
+		t.Errorf("2回目の Up で %d 件適用しました, want 0 件", len(applied))
+	}
+}
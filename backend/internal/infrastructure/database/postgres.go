@@ -8,25 +8,44 @@ import (
 	"gorm.io/gorm"
 )
 
+// NewPostgresDB はデータベースに接続します
+// スキーマの作成・変更は Migrator (migrate サブコマンド) で行います
 func NewPostgresDB(dbUrl string) (*gorm.DB, error) {
 	if dbUrl == "" {
 		log.Fatal("DB_URL が設定されていません")
 	}
 
-	db, err := gorm.Open(postgres.Open(dbUrl), &gorm.Config{})
-	if err != nil {
-		return nil, err
-	}
+	return gorm.Open(postgres.Open(dbUrl), &gorm.Config{})
+}
 
-	// Auto Migration
-	if err := db.AutoMigrate(
+// Models はマイグレーション後のスキーマと突き合わせるエンティティの一覧です
+// エンティティを追加した場合はここにも追加してください
+func Models() []interface{} {
+	return []interface{}{
 		&entity.User{},
 		&entity.Product{},
 		&entity.Order{},
 		&entity.OrderItem{},
-	); err != nil {
-		return nil, err
+		&entity.OrderStatusHistory{},
+		&entity.Cart{},
+		&entity.CartItem{},
+		&entity.RefreshToken{},
+		&entity.RevokedToken{},
+		&entity.PasswordResetToken{},
+		&entity.EmailVerificationToken{},
+		&entity.LoginLockout{},
+		&entity.MFARecoveryCode{},
+		&entity.MFAChallenge{},
+		&entity.UserSession{},
+		&entity.UserIdentity{},
+		&entity.UserAddress{},
+		&entity.IdempotencyKey{},
+		&entity.Payment{},
+		&entity.PaymentEvent{},
+		&entity.PaymentDeadLetter{},
+		&entity.Refund{},
+		&entity.RefundItem{},
+		&entity.Promotion{},
+		&entity.OrderDiscount{},
 	}
-
-	return db, nil
 }
@@ -0,0 +1,56 @@
+// Package i18n は API のメッセージカタログ (日本語・英語) とリクエストの言語の判定を提供します
+package i18n
+
+import (
+	"fmt"
+
+	"github.com/sotaheavymetal21/rabbit-cart/backend/internal/domain/entity"
+	"golang.org/x/text/language"
+)
+
+// catalogs は言語ごとのメッセージです
+// キーはエラーコード・入力エラーの理由の種類・成功時のメッセージの識別子で、値は fmt の書式です
+var catalogs = map[entity.Locale]map[string]string{
+	entity.LocaleJapanese: ja,
+	entity.LocaleEnglish:  en,
+}
+
+// matcher は Accept-Language の言語から対応している言語を選びます (先頭が既定の言語です)
+var matcher = language.NewMatcher([]language.Tag{language.Japanese, language.English})
+
+// matcherLocales は matcher の言語の順に並べた Locale です
+var matcherLocales = []entity.Locale{entity.LocaleJapanese, entity.LocaleEnglish}
+
+// Lookup は locale のメッセージ key に args を埋め込んで返します
+// locale に無い場合は既定の言語のメッセージを使い、どちらにも無い場合は false を返します
+func Lookup(locale entity.Locale, key string, args ...any) (string, bool) {
+	format, ok := catalogs[locale][key]
+	if !ok {
+		format, ok = catalogs[entity.DefaultLocale][key]
+	}
+	if !ok {
+		return "", false
+	}
+	if len(args) == 0 {
+		return format, true
+	}
+	return fmt.Sprintf(format, args...), true
+}
+
+// T は locale のメッセージ key に args を埋め込んで返します (カタログに無い場合は key をそのまま返します)
+func T(locale entity.Locale, key string, args ...any) string {
+	if msg, ok := Lookup(locale, key, args...); ok {
+		return msg
+	}
+	return key
+}
+
+// Negotiate は Accept-Language ヘッダーの値から対応している言語を選びます
+// ヘッダーが無い場合や対応している言語が含まれない場合は既定の言語を返します
+func Negotiate(acceptLanguage string) entity.Locale {
+	if acceptLanguage == "" {
+		return entity.DefaultLocale
+	}
+	_, index := language.MatchStrings(matcher, acceptLanguage)
+	return matcherLocales[index]
+}
@@ -0,0 +1,83 @@
+package i18n
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/sotaheavymetal21/rabbit-cart/backend/internal/domain/entity"
+)
+
+// verbPattern は fmt の書式の埋め込み位置です ("%%" は除きます)
+var verbPattern = regexp.MustCompile(`%[-+# 0-9.]*[a-zA-Z]`)
+
+// 翻訳漏れや埋め込む値の数の食い違いが無いよう、日本語と英語のカタログを突き合わせる
+func TestCatalogsHaveSameKeysAndVerbs(t *testing.T) {
+	for key, jaFormat := range ja {
+		enFormat, ok := en[key]
+		if !ok {
+			t.Errorf("%s: 英語のメッセージがありません", key)
+			continue
+		}
+		if j, e := len(verbPattern.FindAllString(jaFormat, -1)), len(verbPattern.FindAllString(enFormat, -1)); j != e {
+			t.Errorf("%s: 埋め込み位置の数が異なります (ja=%d, en=%d)", key, j, e)
+		}
+	}
+	for key := range en {
+		if _, ok := ja[key]; !ok {
+			t.Errorf("%s: 日本語のメッセージがありません", key)
+		}
+	}
+}
+
+func TestLookup(t *testing.T) {
+	// 英語に無いキーは日本語のメッセージを使う
+	ja["test_only_ja"] = "日本語だけのメッセージ (%d)"
+	t.Cleanup(func() { delete(ja, "test_only_ja") })
+
+	tests := []struct {
+		name   string
+		locale entity.Locale
+		key    string
+		args   []any
+		want   string
+		wantOK bool
+	}{
+		{"英語", entity.LocaleEnglish, "not_found", nil, "The requested resource was not found", true},
+		{"値の埋め込み", entity.LocaleEnglish, "too_many_requests", []any{30}, "Too many attempts. Please try again in 30 seconds", true},
+		{"英語に無いキー", entity.LocaleEnglish, "test_only_ja", []any{1}, "日本語だけのメッセージ (1)", true},
+		{"対応していない言語", entity.Locale("fr"), "not_found", nil, ja["not_found"], true},
+		{"カタログに無いキー", entity.LocaleEnglish, "no_such_key", nil, "", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := Lookup(tt.locale, tt.key, tt.args...)
+			if got != tt.want || ok != tt.wantOK {
+				t.Errorf("Lookup = (%q, %v), want (%q, %v)", got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+
+	if got := T(entity.LocaleEnglish, "no_such_key"); got != "no_such_key" {
+		t.Errorf("T = %q, want キーそのまま", got)
+	}
+}
+
+func TestNegotiate(t *testing.T) {
+	tests := []struct {
+		acceptLanguage string
+		want           entity.Locale
+	}{
+		{"", entity.LocaleJapanese},
+		{"en", entity.LocaleEnglish},
+		{"en-US,en;q=0.9", entity.LocaleEnglish},
+		{"ja-JP,ja;q=0.9,en;q=0.8", entity.LocaleJapanese},
+		{"fr-FR,en;q=0.5", entity.LocaleEnglish},
+		{"fr-FR,de;q=0.5", entity.LocaleJapanese},
+		{"not a language", entity.LocaleJapanese},
+	}
+	for _, tt := range tests {
+		if got := Negotiate(tt.acceptLanguage); got != tt.want {
+			t.Errorf("Negotiate(%q) = %s, want %s", tt.acceptLanguage, got, tt.want)
+		}
+	}
+}
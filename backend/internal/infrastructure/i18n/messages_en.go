@@ -0,0 +1,142 @@
+package i18n
+
+// en は英語のメッセージです (無いキーは日本語のメッセージを使います)
+var en = map[string]string{
+	// 共通のエラー
+	"invalid_request":   "The request contains invalid values",
+	"unauthenticated":   "You need to log in",
+	"forbidden":         "You do not have permission to perform this action",
+	"not_found":         "The requested resource was not found",
+	"duplicate":         "It is already registered",
+	"too_many_requests": "Too many attempts. Please try again in %d seconds",
+	"payload_too_large": "The request is too large",
+	"internal_error":    "Something went wrong on our side. Please try again later",
+
+	// 認証
+	"invalid_credentials":        "The email address or password is incorrect",
+	"invalid_token":              "The token is invalid",
+	"token_expired":              "The token has expired",
+	"token_revoked":              "The token has been revoked",
+	"refresh_token_reused":       "The refresh token has already been used",
+	"token_auth_disabled":        "Token authentication is not enabled",
+	"session_revoked":            "Your session is no longer valid. Please log in again",
+	"session_not_found":          "The session was not found",
+	"user_not_found":             "The user was not found",
+	"credentials_changed":        "Your password has been changed. Please log in again",
+	"email_already_in_use":       "This email address is already registered",
+	"invalid_reset_token":        "The password reset link is invalid or has expired",
+	"invalid_verification_token": "The email verification link is invalid or has expired",
+	"already_verified":           "Your email address is already verified",
+	"email_not_verified":         "Please verify your email address before placing an order",
+	"invalid_mfa_code":           "The authentication code is incorrect",
+	"invalid_mfa_token":          "Your login has expired. Please log in again",
+	"mfa_already_enabled":        "Two-factor authentication is already enabled",
+	"mfa_not_enabled":            "Two-factor authentication is not enabled",
+	"mfa_setup_not_started":      "Please start setting up two-factor authentication first",
+	"mfa_setup_required":         "Enable two-factor authentication to use the admin features",
+	"oidc_provider_not_found":    "This login method is not available",
+	"invalid_oidc_state":         "Your login has expired. Please try again",
+	"oidc_login_failed":          "Login with the external service failed",
+	"oidc_email_required":        "Could not get an email address from the external service",
+	"oidc_account_exists":        "This email address is already registered. Please log in with your email address and password",
+
+	// アカウント
+	"incorrect_password": "The current password is incorrect",
+	"same_email":         "This is your current email address",
+	"invalid_profile":    "The profile contains invalid values",
+	"invalid_locale":     "Unsupported language (specify ja or en)",
+	"invalid_address":    "The address contains invalid values",
+	"address_not_found":  "The address was not found",
+	"address_required":   "Please specify a shipping address",
+
+	// 商品・カタログ
+	"product_not_found":          "The product was not found",
+	"product_in_use":             "Products with order history cannot be deleted",
+	"duplicate_sku":              "The SKU is already used by another product",
+	"invalid_sku":                "The SKU must be up to 64 letters, digits, hyphens or underscores",
+	"invalid_image_url":          "The image URL is invalid",
+	"invalid_price_range":        "The minimum price is greater than the maximum price",
+	"empty_keyword":              "Please enter a search keyword",
+	"invalid_cursor":             "The cursor is invalid",
+	"unsupported_catalog_format": "Unsupported format (specify csv or jsonl)",
+	"catalog_too_large":          "Up to %d products can be imported at once",
+	"invalid_catalog_header":     "The CSV header row is missing required columns",
+
+	// カート・注文
+	"invalid_quantity":               "The quantity must be 1 or more",
+	"insufficient_stock":             "Some products are out of stock: %s",
+	"empty_order":                    "The order has no items",
+	"order_not_found":                "The order was not found",
+	"order_forbidden":                "You do not have permission to access this order",
+	"order_status_conflict":          "The order status was changed by another process",
+	"invalid_idempotency_key":        "The Idempotency-Key must be up to 255 printable ASCII characters",
+	"idempotency_key_in_use":         "A request with the same Idempotency-Key is still being processed. Please try again shortly",
+	"idempotency_key_mismatch":       "This Idempotency-Key was already used for a request with different content",
+	"invalid_status_transition":      "The order status cannot be changed from %s to %s",
+	"payment_declined":               "Your payment was declined. Please try another payment method",
+	"payment_unavailable":            "Could not connect to the payment service. Please try again later",
+	"payment_not_authorized":         "Orders whose payment has not been authorized cannot be shipped",
+	"payment_not_found":              "The payment was not found",
+	"payment_status_conflict":        "The payment status conflicts with the notification",
+	"invalid_webhook_signature":      "The webhook signature is invalid",
+	"webhook_timestamp_out_of_range": "The webhook timestamp is outside the allowed range",
+	"invalid_webhook_payload":        "The webhook payload is invalid",
+	"dead_letter_not_found":          "The dead letter was not found",
+	"webhook_event_in_progress":      "This event is being processed. Please resend it later",
+	"dead_letter_resolved":           "This event has already been reprocessed",
+	"payment_not_captured":           "Orders whose payment has not been captured cannot be refunded. Cancel the order instead if it has not shipped",
+	"empty_refund":                   "There is nothing to refund",
+	"refund_item_not_found":          "The order item was not found",
+	"refund_failed":                  "The payment service could not process the refund",
+	"refund_amount_mismatch":         "The refunded amount does not match the remaining captured amount",
+	"refund_exceeds_captured":        "The total refund exceeds the captured amount",
+	"refund_exceeds_order_item":      "The refund exceeds the remaining quantity or amount of the order item",
+
+	// クーポン
+	"invalid_promotion":             "The coupon contains invalid values",
+	"promotion_not_found":           "The coupon was not found",
+	"promotion_in_use":              "Coupons used in orders cannot be deleted. Deactivate the coupon instead",
+	"duplicate_promotion_code":      "The coupon code is already used by another coupon",
+	"invalid_coupon":                "The coupon code is invalid",
+	"coupon_not_available":          "This coupon is not available right now",
+	"coupon_not_applicable":         "The order contains no items eligible for this coupon",
+	"coupon_min_spend":              "This coupon requires a total of at least %d yen for eligible items",
+	"coupon_usage_limit_reached":    "This coupon has reached its usage limit",
+	"coupon_per_user_limit_reached": "You have already used this coupon",
+
+	// 入力エラーの理由 (FieldError の code)
+	"required":        "This field is required",
+	"email":           "Enter a valid email address",
+	"min_length":      "Must be at least %v characters",
+	"max_length":      "Must be %v characters or fewer",
+	"min":             "Must be %v or greater",
+	"max":             "Must be %v or less",
+	"oneof":           "Must be one of %v",
+	"invalid_type":    "The value has the wrong type",
+	"invalid":         "The value is invalid",
+	"postal_code":     "Enter a 7-digit postal code (e.g. 123-4567)",
+	"prefecture":      "Enter a prefecture name",
+	"phone":           "Enter a phone number",
+	"next_cursor":     "Specify the next_cursor from the previous response",
+	"promotion_code":  "Use up to 50 letters, digits, hyphens or underscores",
+	"after_starts_at": "Must be later than the start date and time",
+
+	// 成功時のメッセージ
+	"registered":               "Your account has been created",
+	"logged_in":                "You are now logged in",
+	"logged_out":               "You have been logged out",
+	"mfa_code_required":        "Enter the code shown in your authenticator app",
+	"mfa_enabled":              "Two-factor authentication is enabled. Keep your recovery codes in a safe place",
+	"mfa_disabled":             "Two-factor authentication is disabled",
+	"password_reset_requested": "If the email address is registered, we have sent a password reset link",
+	"password_reset":           "Your password has been reset. Please log in with your new password",
+	"email_verified":           "Your email address has been verified",
+	"verification_sent":        "We have sent a verification link",
+	"profile_updated":          "Your profile has been updated",
+	"password_changed":         "Your password has been changed",
+	"email_change_requested":   "We have sent a verification link to your new email address. Your email address will change once it is verified",
+	"account_deleted":          "Your account has been deleted. Thank you for shopping with us",
+	"address_deleted":          "The address has been deleted",
+	"session_logged_out":       "The session has been logged out",
+	"all_sessions_logged_out":  "You have been logged out from all devices",
+}
@@ -0,0 +1,142 @@
+package i18n
+
+// ja は日本語のメッセージです (既定の言語のため、全てのキーを含めてください)
+var ja = map[string]string{
+	// 共通のエラー
+	"invalid_request":   "入力内容が正しくありません",
+	"unauthenticated":   "ログインが必要です",
+	"forbidden":         "この操作を行う権限がありません",
+	"not_found":         "対象が見つかりません",
+	"duplicate":         "既に登録されています",
+	"too_many_requests": "しばらく時間をおいてから再度お試しください (%d 秒後に再試行できます)",
+	"payload_too_large": "リクエストのサイズが大きすぎます",
+	"internal_error":    "サーバーでエラーが発生しました。時間をおいて再度お試しください",
+
+	// 認証
+	"invalid_credentials":        "メールアドレスまたはパスワードが正しくありません",
+	"invalid_token":              "トークンが正しくありません",
+	"token_expired":              "トークンの有効期限が切れています",
+	"token_revoked":              "トークンは失効しています",
+	"refresh_token_reused":       "リフレッシュトークンは既に使用されています",
+	"token_auth_disabled":        "トークン認証は有効になっていません",
+	"session_revoked":            "セッションは無効になりました。再度ログインしてください",
+	"session_not_found":          "セッションが見つかりません",
+	"user_not_found":             "ユーザーが見つかりません",
+	"credentials_changed":        "パスワードが変更されました。再度ログインしてください",
+	"email_already_in_use":       "このメールアドレスは既に登録されています",
+	"invalid_reset_token":        "パスワードリセット用のリンクが無効か、有効期限が切れています",
+	"invalid_verification_token": "メールアドレス確認用のリンクが無効か、有効期限が切れています",
+	"already_verified":           "メールアドレスは既に確認済みです",
+	"email_not_verified":         "注文するにはメールアドレスの確認を完了してください",
+	"invalid_mfa_code":           "認証コードが正しくありません",
+	"invalid_mfa_token":          "認証の有効期限が切れました。もう一度ログインしてください",
+	"mfa_already_enabled":        "二要素認証は既に有効です",
+	"mfa_not_enabled":            "二要素認証は有効になっていません",
+	"mfa_setup_not_started":      "先に二要素認証の設定を開始してください",
+	"mfa_setup_required":         "管理機能を利用するには二要素認証を有効にしてください",
+	"oidc_provider_not_found":    "指定されたログイン方法は利用できません",
+	"invalid_oidc_state":         "ログインの有効期限が切れました。もう一度お試しください",
+	"oidc_login_failed":          "外部サービスでのログインに失敗しました",
+	"oidc_email_required":        "外部サービスからメールアドレスを取得できませんでした",
+	"oidc_account_exists":        "このメールアドレスは既に登録されています。メールアドレスとパスワードでログインしてください",
+
+	// アカウント
+	"incorrect_password": "現在のパスワードが正しくありません",
+	"same_email":         "現在と同じメールアドレスです",
+	"invalid_profile":    "プロフィールの入力内容が正しくありません",
+	"invalid_locale":     "対応していない言語です (ja または en を指定してください)",
+	"invalid_address":    "住所の入力内容が正しくありません",
+	"address_not_found":  "住所が見つかりません",
+	"address_required":   "配送先の住所を指定してください",
+
+	// 商品・カタログ
+	"product_not_found":          "商品が見つかりません",
+	"product_in_use":             "注文履歴がある商品は削除できません",
+	"duplicate_sku":              "商品コードが他の商品と重複しています",
+	"invalid_sku":                "商品コードは英数字・ハイフン・アンダースコアの64文字以内で指定してください",
+	"invalid_image_url":          "画像URLの形式が正しくありません",
+	"invalid_price_range":        "価格の下限が上限を上回っています",
+	"empty_keyword":              "検索キーワードを入力してください",
+	"invalid_cursor":             "カーソルが不正です",
+	"unsupported_catalog_format": "対応していない形式です (csv または jsonl を指定してください)",
+	"catalog_too_large":          "一度に取り込める商品は %d 件までです",
+	"invalid_catalog_header":     "CSV のヘッダー行に必須の列がありません",
+
+	// カート・注文
+	"invalid_quantity":               "数量は1以上を指定してください",
+	"insufficient_stock":             "在庫不足の商品があります: %s",
+	"empty_order":                    "注文商品が含まれていません",
+	"order_not_found":                "注文が見つかりません",
+	"order_forbidden":                "この注文を操作する権限がありません",
+	"order_status_conflict":          "注文ステータスが他の処理によって変更されました",
+	"invalid_idempotency_key":        "Idempotency-Key は255文字以内の英数字・記号で指定してください",
+	"idempotency_key_in_use":         "同じ Idempotency-Key のリクエストを処理中です。しばらくしてから再度お試しください",
+	"idempotency_key_mismatch":       "この Idempotency-Key は異なる内容のリクエストで使用済みです",
+	"invalid_status_transition":      "注文ステータスを %s から %s に変更することはできません",
+	"payment_declined":               "決済が承認されませんでした。別のお支払い方法をお試しください",
+	"payment_unavailable":            "決済サービスに接続できませんでした。時間をおいて再度お試しください",
+	"payment_not_authorized":         "決済の与信が完了していない注文は発送できません",
+	"payment_not_found":              "決済が見つかりません",
+	"payment_status_conflict":        "決済の状態が通知の内容と矛盾しています",
+	"invalid_webhook_signature":      "Webhook の署名が正しくありません",
+	"webhook_timestamp_out_of_range": "Webhook の送信時刻が許容範囲外です",
+	"invalid_webhook_payload":        "Webhook の内容が正しくありません",
+	"dead_letter_not_found":          "デッドレターが見つかりません",
+	"webhook_event_in_progress":      "このイベントは処理中です。しばらくしてから再送してください",
+	"dead_letter_resolved":           "このイベントは再処理済みです",
+	"payment_not_captured":           "売上が確定していない注文は返金できません。発送前の注文はキャンセルしてください",
+	"empty_refund":                   "返金する明細がありません",
+	"refund_item_not_found":          "注文明細が見つかりません",
+	"refund_failed":                  "決済サービスで返金できませんでした",
+	"refund_amount_mismatch":         "返金額が未返金の決済額と一致しません",
+	"refund_exceeds_captured":        "返金額の合計が決済額を超えています",
+	"refund_exceeds_order_item":      "返金する数量・金額が注文明細の未返金の分を超えています",
+
+	// クーポン
+	"invalid_promotion":             "クーポンの入力内容が正しくありません",
+	"promotion_not_found":           "クーポンが見つかりません",
+	"promotion_in_use":              "注文で使用されたクーポンは削除できません。無効にしてください",
+	"duplicate_promotion_code":      "クーポンコードが他のクーポンと重複しています",
+	"invalid_coupon":                "クーポンコードが正しくありません",
+	"coupon_not_available":          "このクーポンは現在利用できません",
+	"coupon_not_applicable":         "クーポンの対象となる商品が含まれていません",
+	"coupon_min_spend":              "このクーポンは対象商品の合計が %d 円以上の場合に利用できます",
+	"coupon_usage_limit_reached":    "このクーポンは利用回数の上限に達しました",
+	"coupon_per_user_limit_reached": "このクーポンは既に利用済みです",
+
+	// 入力エラーの理由 (FieldError の code)
+	"required":        "入力してください",
+	"email":           "メールアドレスの形式で入力してください",
+	"min_length":      "%v 文字以上で入力してください",
+	"max_length":      "%v 文字以内で入力してください",
+	"min":             "%v 以上を指定してください",
+	"max":             "%v 以下を指定してください",
+	"oneof":           "%v のいずれかを指定してください",
+	"invalid_type":    "値の型が正しくありません",
+	"invalid":         "値が正しくありません",
+	"postal_code":     "7桁の郵便番号 (例: 123-4567) を入力してください",
+	"prefecture":      "都道府県名を入力してください",
+	"phone":           "電話番号を入力してください",
+	"next_cursor":     "前回の結果の next_cursor を指定してください",
+	"promotion_code":  "クーポンコードは英数字・ハイフン・アンダースコアの50文字以内で指定してください",
+	"after_starts_at": "開始日時より後の日時を指定してください",
+
+	// 成功時のメッセージ
+	"registered":               "登録が完了しました",
+	"logged_in":                "ログインに成功しました",
+	"logged_out":               "ログアウトしました",
+	"mfa_code_required":        "認証アプリに表示されているコードを入力してください",
+	"mfa_enabled":              "二要素認証を有効にしました。リカバリーコードを安全な場所に保管してください",
+	"mfa_disabled":             "二要素認証を無効にしました",
+	"password_reset_requested": "登録されているメールアドレスの場合、パスワード再設定用のリンクを送信しました",
+	"password_reset":           "パスワードを再設定しました。新しいパスワードでログインしてください",
+	"email_verified":           "メールアドレスを確認しました",
+	"verification_sent":        "確認用のリンクを送信しました",
+	"profile_updated":          "プロフィールを更新しました",
+	"password_changed":         "パスワードを変更しました",
+	"email_change_requested":   "新しいメールアドレスに確認用のリンクを送信しました。確認が完了するとメールアドレスが変更されます",
+	"account_deleted":          "退会しました。ご利用ありがとうございました",
+	"address_deleted":          "住所を削除しました",
+	"session_logged_out":       "セッションをログアウトしました",
+	"all_sessions_logged_out":  "全ての端末からログアウトしました",
+}
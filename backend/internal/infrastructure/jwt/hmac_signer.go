@@ -0,0 +1,146 @@
+// Package jwt は HS256 で署名する JWT の発行と検証を実装します
+package jwt
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/sotaheavymetal21/rabbit-cart/backend/internal/domain/entity"
+	"github.com/sotaheavymetal21/rabbit-cart/backend/internal/domain/repository"
+)
+
+// minKeyLength は HS256 の鍵として受け付ける最小のバイト数です
+const minKeyLength = 32
+
+// leeway はサーバー間の時刻のずれとして許容する時間です
+const leeway = 30 * time.Second
+
+type header struct {
+	Alg string `json:"alg"`
+	Typ string `json:"typ"`
+	Kid string `json:"kid"`
+}
+
+// hmacSigner は HS256 で署名する TokenSigner の実装です
+// 複数の鍵を kid で区別して保持し、署名には activeKID の鍵、検証にはトークンの kid に対応する鍵を使います
+type hmacSigner struct {
+	keys      map[string][]byte
+	activeKID string
+	issuer    string
+	now       func() time.Time
+}
+
+// NewHMACSigner は HS256 の TokenSigner を生成します
+// 鍵をローテーションする場合は新しい鍵を追加して activeKID を切り替え、古い鍵はアクセストークンの有効期間が過ぎてから削除してください
+func NewHMACSigner(keys map[string][]byte, activeKID, issuer string) (repository.TokenSigner, error) {
+	if _, ok := keys[activeKID]; !ok {
+		return nil, fmt.Errorf("署名に使う鍵 (kid=%s) がありません", activeKID)
+	}
+	for kid, key := range keys {
+		if len(key) < minKeyLength {
+			return nil, fmt.Errorf("鍵 (kid=%s) は %d バイト以上にしてください", kid, minKeyLength)
+		}
+	}
+	return &hmacSigner{keys: keys, activeKID: activeKID, issuer: issuer, now: time.Now}, nil
+}
+
+// ParseKeys は "kid1:secret1,kid2:secret2" 形式の文字列を kid ごとの鍵に変換します
+func ParseKeys(s string) (map[string][]byte, error) {
+	keys := make(map[string][]byte)
+	for _, pair := range strings.Split(s, ",") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+		kid, secret, ok := strings.Cut(pair, ":")
+		if !ok || kid == "" || secret == "" {
+			return nil, errors.New("鍵は kid:secret の形式で指定してください")
+		}
+		keys[kid] = []byte(secret)
+	}
+	if len(keys) == 0 {
+		return nil, errors.New("鍵が指定されていません")
+	}
+	return keys, nil
+}
+
+// Sign はクレームに署名したトークンを返します
+func (s *hmacSigner) Sign(claims *entity.AccessTokenClaims) (string, error) {
+	claims.Issuer = s.issuer
+	h, err := json.Marshal(header{Alg: "HS256", Typ: "JWT", Kid: s.activeKID})
+	if err != nil {
+		return "", err
+	}
+	p, err := json.Marshal(claims)
+	if err != nil {
+		return "", err
+	}
+	signingInput := encodeSegment(h) + "." + encodeSegment(p)
+	return signingInput + "." + encodeSegment(sign(s.keys[s.activeKID], signingInput)), nil
+}
+
+// Verify はトークンの署名と有効期限を検証し、クレームを返します
+func (s *hmacSigner) Verify(token string) (*entity.AccessTokenClaims, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil, entity.ErrInvalidToken
+	}
+
+	var h header
+	if err := decodeSegment(parts[0], &h); err != nil {
+		return nil, entity.ErrInvalidToken
+	}
+	// alg は固定し、"none" や別アルゴリズムへのすり替えを受け付けない
+	if h.Alg != "HS256" {
+		return nil, entity.ErrInvalidToken
+	}
+	key, ok := s.keys[h.Kid]
+	if !ok {
+		return nil, entity.ErrInvalidToken
+	}
+
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil || !hmac.Equal(signature, sign(key, parts[0]+"."+parts[1])) {
+		return nil, entity.ErrInvalidToken
+	}
+
+	var claims entity.AccessTokenClaims
+	if err := decodeSegment(parts[1], &claims); err != nil {
+		return nil, entity.ErrInvalidToken
+	}
+	if claims.Issuer != s.issuer || claims.Subject == "" || claims.ID == "" {
+		return nil, entity.ErrInvalidToken
+	}
+	now := s.now()
+	if now.Add(-leeway).After(claims.ExpiresAtTime()) {
+		return nil, entity.ErrTokenExpired
+	}
+	if time.Unix(claims.IssuedAt, 0).After(now.Add(leeway)) {
+		return nil, entity.ErrInvalidToken
+	}
+	return &claims, nil
+}
+
+func sign(key []byte, signingInput string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(signingInput))
+	return mac.Sum(nil)
+}
+
+func encodeSegment(b []byte) string {
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+func decodeSegment(seg string, v interface{}) error {
+	b, err := base64.RawURLEncoding.DecodeString(seg)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(b, v)
+}
@@ -0,0 +1,246 @@
+package jwt
+
+import (
+	"crypto/hmac"
+	"crypto/sha512"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sotaheavymetal21/rabbit-cart/backend/internal/domain/entity"
+)
+
+const testIssuer = "rabbit-cart"
+
+var (
+	testNow    = time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC)
+	oldKey     = []byte("old-secret-0123456789abcdefghijklmnop")
+	currentKey = []byte("current-secret-0123456789abcdefghijkl")
+)
+
+func newTestSigner(t *testing.T, keys map[string][]byte, activeKID string, now time.Time) *hmacSigner {
+	t.Helper()
+	signer, err := NewHMACSigner(keys, activeKID, testIssuer)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := signer.(*hmacSigner)
+	s.now = func() time.Time { return now }
+	return s
+}
+
+func testClaims(issuedAt time.Time, ttl time.Duration) *entity.AccessTokenClaims {
+	return &entity.AccessTokenClaims{
+		Subject:   "user-1",
+		ID:        "jti-1",
+		SessionID: "session-1",
+		Role:      entity.UserRoleCustomer,
+		IssuedAt:  issuedAt.Unix(),
+		ExpiresAt: issuedAt.Add(ttl).Unix(),
+	}
+}
+
+// forge は任意のヘッダーで署名したトークンを組み立てます
+func forge(t *testing.T, h header, claims *entity.AccessTokenClaims, signature func(signingInput string) []byte) string {
+	t.Helper()
+	hb, err := json.Marshal(h)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pb, err := json.Marshal(claims)
+	if err != nil {
+		t.Fatal(err)
+	}
+	signingInput := encodeSegment(hb) + "." + encodeSegment(pb)
+	return signingInput + "." + encodeSegment(signature(signingInput))
+}
+
+func TestHMACSignerSignAndVerify(t *testing.T) {
+	s := newTestSigner(t, map[string][]byte{"k1": currentKey}, "k1", testNow)
+
+	token, err := s.Sign(testClaims(testNow, 15*time.Minute))
+	if err != nil {
+		t.Fatal(err)
+	}
+	claims, err := s.Verify(token)
+	if err != nil {
+		t.Fatalf("Verify: %v", err)
+	}
+	if claims.Issuer != testIssuer || claims.Subject != "user-1" || claims.SessionID != "session-1" || claims.Role != entity.UserRoleCustomer {
+		t.Errorf("claims = %+v", claims)
+	}
+}
+
+func TestHMACSignerRejectsOtherAlgorithms(t *testing.T) {
+	s := newTestSigner(t, map[string][]byte{"k1": currentKey}, "k1", testNow)
+	claims := testClaims(testNow, 15*time.Minute)
+	claims.Issuer = testIssuer
+
+	tests := []struct {
+		name  string
+		token string
+	}{
+		{"alg=none で署名なし", forge(t, header{Alg: "none", Typ: "JWT", Kid: "k1"}, claims, func(string) []byte { return nil })},
+		{"alg=none で HS256 の署名", forge(t, header{Alg: "none", Typ: "JWT", Kid: "k1"}, claims, func(in string) []byte { return sign(currentKey, in) })},
+		{"alg=HS512", forge(t, header{Alg: "HS512", Typ: "JWT", Kid: "k1"}, claims, func(in string) []byte {
+			mac := hmac.New(sha512.New, currentKey)
+			mac.Write([]byte(in))
+			return mac.Sum(nil)
+		})},
+		{"alg=RS256", forge(t, header{Alg: "RS256", Typ: "JWT", Kid: "k1"}, claims, func(in string) []byte { return sign(currentKey, in) })},
+		{"alg の大文字・小文字違い", forge(t, header{Alg: "hs256", Typ: "JWT", Kid: "k1"}, claims, func(in string) []byte { return sign(currentKey, in) })},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := s.Verify(tt.token); !errors.Is(err, entity.ErrInvalidToken) {
+				t.Errorf("Verify = %v, want ErrInvalidToken", err)
+			}
+		})
+	}
+}
+
+func TestHMACSignerRejectsUnknownKID(t *testing.T) {
+	s := newTestSigner(t, map[string][]byte{"k1": currentKey}, "k1", testNow)
+	claims := testClaims(testNow, 15*time.Minute)
+	claims.Issuer = testIssuer
+
+	for _, kid := range []string{"k2", ""} {
+		token := forge(t, header{Alg: "HS256", Typ: "JWT", Kid: kid}, claims, func(in string) []byte { return sign(currentKey, in) })
+		if _, err := s.Verify(token); !errors.Is(err, entity.ErrInvalidToken) {
+			t.Errorf("kid=%q: Verify = %v, want ErrInvalidToken", kid, err)
+		}
+	}
+}
+
+func TestHMACSignerExpiry(t *testing.T) {
+	issuer := newTestSigner(t, map[string][]byte{"k1": currentKey}, "k1", testNow)
+	token, err := issuer.Sign(testClaims(testNow, 15*time.Minute))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name string
+		now  time.Time
+		want error
+	}{
+		{"有効期限内", testNow.Add(14 * time.Minute), nil},
+		{"有効期限後でも許容範囲内", testNow.Add(15*time.Minute + leeway), nil},
+		{"許容範囲を超えて期限切れ", testNow.Add(15*time.Minute + leeway + time.Second), entity.ErrTokenExpired},
+		{"発行時刻が未来", testNow.Add(-leeway - time.Second), entity.ErrInvalidToken},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			verifier := newTestSigner(t, map[string][]byte{"k1": currentKey}, "k1", tt.now)
+			if _, err := verifier.Verify(token); !errors.Is(err, tt.want) {
+				t.Errorf("Verify = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestHMACSignerRejectsTampering(t *testing.T) {
+	s := newTestSigner(t, map[string][]byte{"k1": currentKey}, "k1", testNow)
+	token, err := s.Sign(testClaims(testNow, 15*time.Minute))
+	if err != nil {
+		t.Fatal(err)
+	}
+	parts := strings.Split(token, ".")
+
+	// 権限を書き換えたクレーム
+	escalated := testClaims(testNow, 15*time.Minute)
+	escalated.Issuer = testIssuer
+	escalated.Role = entity.UserRoleAdmin
+	pb, err := json.Marshal(escalated)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// 署名の先頭の1文字を変える (末尾の文字は下位ビットが捨てられるため先頭を変える)
+	sig := []byte(parts[2])
+	if sig[0] == 'A' {
+		sig[0] = 'B'
+	} else {
+		sig[0] = 'A'
+	}
+
+	other := newTestSigner(t, map[string][]byte{"k1": []byte("another-secret-0123456789abcdefghijk")}, "k1", testNow)
+	forged, err := other.Sign(testClaims(testNow, 15*time.Minute))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name  string
+		token string
+	}{
+		{"クレームの改ざん", parts[0] + "." + encodeSegment(pb) + "." + parts[2]},
+		{"署名の改ざん", parts[0] + "." + parts[1] + "." + string(sig)},
+		{"署名の削除", parts[0] + "." + parts[1] + "."},
+		{"別の鍵で署名", forged},
+		{"セグメント不足", parts[0] + "." + parts[1]},
+		{"base64 でない署名", parts[0] + "." + parts[1] + ".!!!"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := s.Verify(tt.token); !errors.Is(err, entity.ErrInvalidToken) {
+				t.Errorf("Verify = %v, want ErrInvalidToken", err)
+			}
+		})
+	}
+}
+
+func TestHMACSignerRejectsOtherIssuer(t *testing.T) {
+	s := newTestSigner(t, map[string][]byte{"k1": currentKey}, "k1", testNow)
+	claims := testClaims(testNow, 15*time.Minute)
+	claims.Issuer = "other-service"
+	token := forge(t, header{Alg: "HS256", Typ: "JWT", Kid: "k1"}, claims, func(in string) []byte { return sign(currentKey, in) })
+	if _, err := s.Verify(token); !errors.Is(err, entity.ErrInvalidToken) {
+		t.Errorf("Verify = %v, want ErrInvalidToken", err)
+	}
+}
+
+func TestHMACSignerKeyRotation(t *testing.T) {
+	before := newTestSigner(t, map[string][]byte{"old": oldKey}, "old", testNow)
+	oldToken, err := before.Sign(testClaims(testNow, 15*time.Minute))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// ローテーション中: 新しい鍵で署名し、古い鍵で署名されたトークンも受け付ける
+	during := newTestSigner(t, map[string][]byte{"old": oldKey, "new": currentKey}, "new", testNow.Add(5*time.Minute))
+	if _, err := during.Verify(oldToken); err != nil {
+		t.Errorf("ローテーション中に古い鍵のトークンを拒否しました: %v", err)
+	}
+	newToken, err := during.Sign(testClaims(testNow.Add(5*time.Minute), 15*time.Minute))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var h header
+	if err := decodeSegment(strings.Split(newToken, ".")[0], &h); err != nil {
+		t.Fatal(err)
+	}
+	if h.Kid != "new" {
+		t.Errorf("kid = %q, want new", h.Kid)
+	}
+
+	// 古い鍵を削除した後は古い鍵のトークンを拒否する
+	after := newTestSigner(t, map[string][]byte{"new": currentKey}, "new", testNow.Add(10*time.Minute))
+	if _, err := after.Verify(oldToken); !errors.Is(err, entity.ErrInvalidToken) {
+		t.Errorf("削除した鍵のトークン: Verify = %v, want ErrInvalidToken", err)
+	}
+	if _, err := after.Verify(newToken); err != nil {
+		t.Errorf("新しい鍵のトークンを拒否しました: %v", err)
+	}
+}
+
+func TestNewHMACSignerValidatesKeys(t *testing.T) {
+	if _, err := NewHMACSigner(map[string][]byte{"k1": currentKey}, "k2", testIssuer); err == nil {
+		t.Error("存在しない activeKID を受け付けました")
+	}
+	if _, err := NewHMACSigner(map[string][]byte{"k1": currentKey, "short": []byte("too-short")}, "k1", testIssuer); err == nil {
+		t.Error("短すぎる鍵を受け付けました")
+	}
+}
@@ -0,0 +1,34 @@
+package mail
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/sotaheavymetal21/rabbit-cart/backend/internal/domain/entity"
+	"github.com/sotaheavymetal21/rabbit-cart/backend/internal/domain/repository"
+)
+
+// fileMailer はメールを送信せずに .eml ファイルとして書き出す Mailer の実装です
+// ローカル開発で送信内容 (リセット用リンクなど) を確認するために使用します
+type fileMailer struct {
+	dir  string
+	from string
+}
+
+// NewFileMailer はファイル出力の Mailer を生成します
+func NewFileMailer(dir, from string) (repository.Mailer, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+	return &fileMailer{dir: dir, from: from}, nil
+}
+
+// Send はメールを dir に書き出します
+func (m *fileMailer) Send(ctx context.Context, mail *entity.Mail) error {
+	now := time.Now()
+	name := fmt.Sprintf("%s_%d.eml", now.Format("20060102T150405"), now.UnixNano())
+	return os.WriteFile(filepath.Join(m.dir, name), buildMessage(m.from, mail, now), 0o600)
+}
@@ -0,0 +1,34 @@
+package mail
+
+import (
+	"context"
+	"sync"
+
+	"github.com/sotaheavymetal21/rabbit-cart/backend/internal/domain/entity"
+)
+
+// MemoryMailer は送信したメールをメモリに保持する Mailer の実装です
+type MemoryMailer struct {
+	mu   sync.Mutex
+	sent []entity.Mail
+}
+
+// NewMemoryMailer はメモリ保持の Mailer を生成します
+func NewMemoryMailer() *MemoryMailer {
+	return &MemoryMailer{}
+}
+
+// Send はメールを保持します
+func (m *MemoryMailer) Send(ctx context.Context, mail *entity.Mail) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.sent = append(m.sent, *mail)
+	return nil
+}
+
+// Sent は保持しているメールを送信順に返します
+func (m *MemoryMailer) Sent() []entity.Mail {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]entity.Mail(nil), m.sent...)
+}
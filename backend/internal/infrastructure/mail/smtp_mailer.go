@@ -0,0 +1,37 @@
+package mail
+
+import (
+	"context"
+	"net"
+	"net/smtp"
+	"time"
+
+	"github.com/sotaheavymetal21/rabbit-cart/backend/internal/domain/entity"
+	"github.com/sotaheavymetal21/rabbit-cart/backend/internal/domain/repository"
+)
+
+// smtpMailer は SMTP サーバー経由でメールを送信する Mailer の実装です
+type smtpMailer struct {
+	addr string
+	auth smtp.Auth
+	from string
+}
+
+// NewSMTPMailer は SMTP の Mailer を生成します
+// addr は "host:port" 形式で、username が空の場合は認証を行いません
+func NewSMTPMailer(addr, username, password, from string) repository.Mailer {
+	var auth smtp.Auth
+	if username != "" {
+		host, _, _ := net.SplitHostPort(addr)
+		auth = smtp.PlainAuth("", username, password, host)
+	}
+	return &smtpMailer{addr: addr, auth: auth, from: from}
+}
+
+// Send はメールを送信します
+func (m *smtpMailer) Send(ctx context.Context, mail *entity.Mail) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	return smtp.SendMail(m.addr, m.auth, m.from, []string{mail.To}, buildMessage(m.from, mail, time.Now()))
+}
@@ -0,0 +1,39 @@
+package middleware
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/sotaheavymetal21/rabbit-cart/backend/internal/domain/entity"
+)
+
+var (
+	// errAdminForbidden は管理機能の権限が無い場合のエラーです
+	errAdminForbidden = entity.NewForbiddenError("forbidden", "この操作を行う権限がありません")
+	// errMFASetupRequired は管理機能の利用に必要な二要素認証が有効になっていない場合のエラーです
+	errMFASetupRequired = entity.NewForbiddenError("mfa_setup_required", "管理機能を利用するには二要素認証を有効にしてください")
+)
+
+// AdminMiddleware は管理画面向けのエンドポイントで使用するミドルウェアです
+// AuthMiddleware の後に配置し、roles のいずれかの権限を持つユーザーのみを通します
+// requireMFA が true の場合は、二要素認証を有効にしていないユーザーも拒否します
+func AdminMiddleware(requireMFA bool, roles ...entity.UserRole) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		value, exists := c.Get("user")
+		if !exists {
+			abortWithError(c, entity.ErrUnauthenticated)
+			return
+		}
+
+		user, ok := value.(*entity.User)
+		if !ok || !user.HasRole(roles...) {
+			abortWithError(c, errAdminForbidden)
+			return
+		}
+
+		if requireMFA && !user.MFAEnabled() {
+			abortWithError(c, errMFASetupRequired)
+			return
+		}
+
+		c.Next()
+	}
+}
@@ -0,0 +1,50 @@
+package middleware_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sotaheavymetal21/rabbit-cart/backend/internal/domain/entity"
+	"github.com/sotaheavymetal21/rabbit-cart/backend/internal/infrastructure/middleware"
+)
+
+func TestAdminMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	enabledAt := time.Now()
+	tests := []struct {
+		name       string
+		requireMFA bool
+		user       *entity.User // nil は未ログイン
+		want       int
+	}{
+		{"未ログイン", false, nil, http.StatusUnauthorized},
+		{"一般ユーザー", false, &entity.User{Role: entity.UserRoleCustomer}, http.StatusForbidden},
+		{"スタッフ", false, &entity.User{Role: entity.UserRoleStaff}, http.StatusOK},
+		{"管理者", false, &entity.User{Role: entity.UserRoleAdmin}, http.StatusOK},
+		{"二要素認証が必須で未設定", true, &entity.User{Role: entity.UserRoleAdmin}, http.StatusForbidden},
+		{"二要素認証が必須で設定済み", true, &entity.User{Role: entity.UserRoleAdmin, TOTPEnabledAt: &enabledAt}, http.StatusOK},
+		{"二要素認証が必須で一般ユーザー", true, &entity.User{Role: entity.UserRoleCustomer, TOTPEnabledAt: &enabledAt}, http.StatusForbidden},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := gin.New()
+			r.Use(middleware.ErrorHandler(), func(c *gin.Context) {
+				if tt.user != nil {
+					c.Set("user", tt.user)
+				}
+			})
+			r.GET("/admin", middleware.AdminMiddleware(tt.requireMFA, entity.UserRoleStaff, entity.UserRoleAdmin), func(c *gin.Context) {
+				c.Status(http.StatusOK)
+			})
+
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/admin", nil))
+			if w.Code != tt.want {
+				t.Errorf("ステータス = %d, want %d", w.Code, tt.want)
+			}
+		})
+	}
+}
@@ -1,36 +1,88 @@
 package middleware
 
 import (
-	"net/http"
+	"errors"
 
-	"github.com/gin-contrib/sessions"
 	"github.com/gin-gonic/gin"
+	"github.com/sotaheavymetal21/rabbit-cart/backend/internal/domain/entity"
 	"github.com/sotaheavymetal21/rabbit-cart/backend/internal/domain/repository"
+	"github.com/sotaheavymetal21/rabbit-cart/backend/internal/usecase"
+)
+
+var (
+	// errUserNotFound は認証情報のユーザーが削除されている場合のエラーです
+	errUserNotFound = entity.NewUnauthorizedError("user_not_found", "ユーザーが見つかりません")
+	// errCredentialsChanged は認証後にパスワードが変更された場合のエラーです
+	errCredentialsChanged = entity.NewUnauthorizedError("credentials_changed", "パスワードが変更されました。再度ログインしてください")
 )
 
 // AuthMiddleware は認証が必要なエンドポイントで使用するミドルウェアです
-func AuthMiddleware(userRepo repository.UserRepository) gin.HandlerFunc {
+// authenticators を順に試し、最初に認証情報が見つかったもので認証します
+// ログアウトやリモートでの失効により無効になったログインセッションは sessionUseCase で拒否します
+func AuthMiddleware(userRepo repository.UserRepository, sessionUseCase usecase.SessionUseCase, authenticators ...Authenticator) gin.HandlerFunc {
 	return func(c *gin.Context) {
-		session := sessions.Default(c)
-		userID := session.Get("user_id")
-
-		// セッションにユーザーIDが存在しない場合
-		if userID == nil {
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "ログインが必要です"})
-			c.Abort()
+		principal, err := authenticate(c, authenticators)
+		if err != nil {
+			// 認証情報が無い場合
+			if errors.Is(err, ErrNoCredentials) {
+				abortWithError(c, entity.ErrUnauthenticated)
+				return
+			}
+			// トークンの期限切れはクライアントがリフレッシュできるよう区別して返す
+			if errors.Is(err, entity.ErrTokenExpired) {
+				c.Header("WWW-Authenticate", `Bearer error="invalid_token", error_description="expired"`)
+			}
+			abortWithError(c, err)
 			return
 		}
 
 		// ユーザー情報を取得
-		user, err := userRepo.FindByID(c.Request.Context(), userID.(string))
+		user, err := userRepo.FindByID(c.Request.Context(), principal.UserID)
 		if err != nil {
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "ユーザーが見つかりません"})
-			c.Abort()
+			if errors.Is(err, repository.ErrNotFound) {
+				err = errUserNotFound
+			}
+			abortWithError(c, err)
+			return
+		}
+
+		// パスワード変更前にログインしたセッション・トークンは無効にする
+		if !user.CredentialsValidAt(principal.AuthenticatedAt) {
+			abortWithError(c, errCredentialsChanged)
+			return
+		}
+
+		// 失効したログインセッションを拒否し、最終利用日時を記録する
+		if err := sessionUseCase.Validate(c.Request.Context(), principal.SessionID, user.ID, c.ClientIP()); err != nil {
+			abortWithError(c, err)
 			return
 		}
 
 		// コンテキストにユーザー情報をセット
 		c.Set("user", user)
+		c.Set("userID", user.ID)
+		c.Set("sessionID", principal.SessionID)
+		if user.Locale.IsValid() {
+			// ユーザーが設定した言語を Accept-Language より優先する
+			c.Set("locale", user.Locale)
+		}
 		c.Next()
 	}
 }
+
+// abortWithError は後続のハンドラーを実行せず、err を ErrorHandler でレスポンスに変換させます
+func abortWithError(c *gin.Context, err error) {
+	_ = c.Error(err)
+	c.Abort()
+}
+
+func authenticate(c *gin.Context, authenticators []Authenticator) (*Principal, error) {
+	for _, a := range authenticators {
+		principal, err := a.Authenticate(c)
+		if errors.Is(err, ErrNoCredentials) {
+			continue
+		}
+		return principal, err
+	}
+	return nil, ErrNoCredentials
+}
@@ -0,0 +1,81 @@
+package middleware
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/gin-contrib/sessions"
+	"github.com/gin-gonic/gin"
+	"github.com/sotaheavymetal21/rabbit-cart/backend/internal/usecase"
+)
+
+// ErrNoCredentials はリクエストに認証情報が含まれていない場合のエラーです
+var ErrNoCredentials = errors.New("認証情報がありません")
+
+// Principal は認証で得られたユーザーIDと、そのユーザーがログインした日時・ログインセッションIDです
+type Principal struct {
+	UserID          string
+	SessionID       string
+	AuthenticatedAt time.Time
+}
+
+// Authenticator はリクエストから認証済みのユーザーを取り出すインターフェースです
+type Authenticator interface {
+	// Authenticate は認証済みのユーザーを返します
+	// 認証情報が無い場合は ErrNoCredentials、認証情報が不正な場合はそれ以外のエラーを返します
+	Authenticate(c *gin.Context) (*Principal, error)
+}
+
+type sessionAuthenticator struct{}
+
+// SessionAuthenticator は Redis セッションに保存されたユーザーIDで認証する Authenticator を生成します
+func SessionAuthenticator() Authenticator {
+	return sessionAuthenticator{}
+}
+
+// Authenticate はセッションのユーザーIDを返します
+func (sessionAuthenticator) Authenticate(c *gin.Context) (*Principal, error) {
+	session := sessions.Default(c)
+	userID, ok := session.Get("user_id").(string)
+	if !ok || userID == "" {
+		return nil, ErrNoCredentials
+	}
+	// ログイン日時が無いセッションはゼロ値とし、パスワード変更前のセッションとして扱う
+	authenticatedAt, _ := session.Get("authenticated_at").(int64)
+	sessionID, _ := session.Get("session_id").(string)
+	return &Principal{UserID: userID, SessionID: sessionID, AuthenticatedAt: time.Unix(authenticatedAt, 0)}, nil
+}
+
+type bearerAuthenticator struct {
+	tokenUseCase usecase.TokenUseCase
+}
+
+// BearerAuthenticator は Authorization ヘッダーの JWT アクセストークンで認証する Authenticator を生成します
+func BearerAuthenticator(tokenUseCase usecase.TokenUseCase) Authenticator {
+	return &bearerAuthenticator{tokenUseCase: tokenUseCase}
+}
+
+// Authenticate はアクセストークンのユーザーIDを返します
+// 検証済みのクレームはログアウト時の失効に使うためコンテキストに "accessClaims" としてセットします
+func (a *bearerAuthenticator) Authenticate(c *gin.Context) (*Principal, error) {
+	token := BearerToken(c)
+	if token == "" {
+		return nil, ErrNoCredentials
+	}
+	claims, err := a.tokenUseCase.Authenticate(c.Request.Context(), token)
+	if err != nil {
+		return nil, err
+	}
+	c.Set("accessClaims", claims)
+	return &Principal{UserID: claims.Subject, SessionID: claims.SessionID, AuthenticatedAt: time.Unix(claims.IssuedAt, 0)}, nil
+}
+
+// BearerToken は Authorization ヘッダーの Bearer トークンを返します (無い場合は空文字)
+func BearerToken(c *gin.Context) string {
+	scheme, token, ok := strings.Cut(c.GetHeader("Authorization"), " ")
+	if !ok || !strings.EqualFold(scheme, "Bearer") {
+		return ""
+	}
+	return strings.TrimSpace(token)
+}
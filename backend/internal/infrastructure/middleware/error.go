@@ -0,0 +1,128 @@
+package middleware
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sotaheavymetal21/rabbit-cart/backend/internal/domain/entity"
+	"github.com/sotaheavymetal21/rabbit-cart/backend/internal/infrastructure/i18n"
+	"github.com/sotaheavymetal21/rabbit-cart/backend/internal/usecase"
+)
+
+// ErrorResponse は API のエラーレスポンスの形式です
+// error は利用者に表示するメッセージ、code はクライアントが処理を分岐するための機械可読な値で、どのエラーでも必ず返します
+type ErrorResponse struct {
+	Error     string                 `json:"error"`
+	Code      string                 `json:"code"`
+	Fields    []entity.FieldError    `json:"fields,omitempty"`    // 入力エラーの項目
+	Shortages []entity.StockShortage `json:"shortages,omitempty"` // 在庫不足の商品
+}
+
+// ErrorHandler はハンドラー・ミドルウェアが c.Error で登録したエラーをレスポンスに変換するミドルウェアです
+// ドメインのエラーの種類から HTTP ステータスを決め、メッセージはエラーコードからリクエストの言語で返します
+// 想定外のエラーは内容を記録して 500 を返します
+// 既にレスポンスが書き込まれている場合は何もしません
+func ErrorHandler() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Next()
+
+		if len(c.Errors) == 0 || c.Writer.Written() {
+			return
+		}
+		err := c.Errors.Last().Err
+		status, res := renderError(err, requestLocale(c))
+		if status >= http.StatusInternalServerError {
+			log.Printf("%s %s の処理に失敗しました: %v", c.Request.Method, c.FullPath(), err)
+		}
+		var throttled *usecase.ThrottledError
+		if errors.As(err, &throttled) {
+			c.Header("Retry-After", strconv.Itoa(throttled.RetryAfterSeconds()))
+		}
+		c.AbortWithStatusJSON(status, res)
+	}
+}
+
+// renderError はエラーを HTTP ステータスと locale の言語のレスポンスに変換します
+func renderError(err error, locale entity.Locale) (int, ErrorResponse) {
+	var (
+		validationErr   *entity.ValidationError
+		unauthorizedErr *entity.UnauthorizedError
+		forbiddenErr    *entity.ForbiddenError
+		notFoundErr     *entity.NotFoundError
+		conflictErr     *entity.ConflictError
+		unprocessable   *entity.UnprocessableError
+		paymentErr      *entity.PaymentRequiredError
+		unavailableErr  *entity.ServiceUnavailableError
+		stockErr        *entity.InsufficientStockError
+		transitionErr   *entity.InvalidStatusTransitionError
+		throttled       *usecase.ThrottledError
+		maxBytesErr     *http.MaxBytesError
+	)
+	switch {
+	case errors.As(err, &validationErr):
+		return http.StatusBadRequest, ErrorResponse{
+			Error:  localize(locale, validationErr.Code, validationErr.Message, validationErr.Args...),
+			Code:   validationErr.Code,
+			Fields: localizeFields(locale, validationErr.Fields),
+		}
+	case errors.As(err, &unauthorizedErr):
+		return http.StatusUnauthorized, newErrorResponse(locale, unauthorizedErr.Code, unauthorizedErr.Message)
+	case errors.As(err, &forbiddenErr):
+		return http.StatusForbidden, newErrorResponse(locale, forbiddenErr.Code, forbiddenErr.Message)
+	case errors.As(err, &notFoundErr):
+		return http.StatusNotFound, newErrorResponse(locale, notFoundErr.Code, notFoundErr.Message)
+	case errors.As(err, &conflictErr):
+		return http.StatusConflict, newErrorResponse(locale, conflictErr.Code, conflictErr.Message)
+	case errors.As(err, &unprocessable):
+		return http.StatusUnprocessableEntity, newErrorResponse(locale, unprocessable.Code, unprocessable.Message)
+	case errors.As(err, &paymentErr):
+		return http.StatusPaymentRequired, newErrorResponse(locale, paymentErr.Code, paymentErr.Message)
+	case errors.As(err, &unavailableErr):
+		return http.StatusServiceUnavailable, newErrorResponse(locale, unavailableErr.Code, unavailableErr.Message)
+	case errors.As(err, &stockErr):
+		names := make([]string, 0, len(stockErr.Shortages))
+		for _, s := range stockErr.Shortages {
+			names = append(names, s.Name)
+		}
+		res := newErrorResponse(locale, "insufficient_stock", stockErr.Error(), strings.Join(names, ", "))
+		res.Shortages = stockErr.Shortages
+		return http.StatusConflict, res
+	case errors.As(err, &transitionErr):
+		return http.StatusConflict, newErrorResponse(locale, "invalid_status_transition", transitionErr.Error(), transitionErr.From, transitionErr.To)
+	case errors.As(err, &throttled):
+		return http.StatusTooManyRequests, newErrorResponse(locale, "too_many_requests", throttled.Error(), throttled.RetryAfterSeconds())
+	case errors.As(err, &maxBytesErr):
+		return http.StatusRequestEntityTooLarge, newErrorResponse(locale, "payload_too_large", "")
+	}
+	return http.StatusInternalServerError, newErrorResponse(locale, "internal_error", "")
+}
+
+// newErrorResponse は code のメッセージを locale の言語で埋めた ErrorResponse を返します
+func newErrorResponse(locale entity.Locale, code, fallback string, args ...any) ErrorResponse {
+	return ErrorResponse{Error: localize(locale, code, fallback, args...), Code: code}
+}
+
+// localize は code のメッセージを locale の言語で返します (カタログに無い場合は fallback を返します)
+func localize(locale entity.Locale, code, fallback string, args ...any) string {
+	if msg, ok := i18n.Lookup(locale, code, args...); ok {
+		return msg
+	}
+	return fallback
+}
+
+// localizeFields は入力エラーの各項目の理由を locale の言語に置き換えます
+func localizeFields(locale entity.Locale, fields []entity.FieldError) []entity.FieldError {
+	if len(fields) == 0 {
+		return nil
+	}
+	localized := make([]entity.FieldError, len(fields))
+	for i, f := range fields {
+		f.Message = localize(locale, f.Code, f.Message, f.Args...)
+		localized[i] = f
+	}
+	return localized
+}
@@ -0,0 +1,142 @@
+package middleware_test
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sotaheavymetal21/rabbit-cart/backend/internal/domain/entity"
+	"github.com/sotaheavymetal21/rabbit-cart/backend/internal/domain/repository"
+	"github.com/sotaheavymetal21/rabbit-cart/backend/internal/infrastructure/middleware"
+	"github.com/sotaheavymetal21/rabbit-cart/backend/internal/usecase"
+)
+
+// serveError は handler が登録したエラーを ErrorHandler で変換したレスポンスを返します
+func serveError(t *testing.T, handler gin.HandlerFunc) (*httptest.ResponseRecorder, middleware.ErrorResponse) {
+	t.Helper()
+	return serveErrorIn(t, "", handler)
+}
+
+// serveErrorIn は Accept-Language を指定して serveError を行います
+func serveErrorIn(t *testing.T, acceptLanguage string, handler gin.HandlerFunc) (*httptest.ResponseRecorder, middleware.ErrorResponse) {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(middleware.ErrorHandler(), middleware.LocaleMiddleware())
+	r.GET("/", handler)
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	if acceptLanguage != "" {
+		req.Header.Set("Accept-Language", acceptLanguage)
+	}
+	r.ServeHTTP(w, req)
+	var res middleware.ErrorResponse
+	if w.Body.Len() > 0 {
+		if err := json.Unmarshal(w.Body.Bytes(), &res); err != nil {
+			t.Fatalf("レスポンスを読み込めません: %v (%s)", err, w.Body.String())
+		}
+	}
+	return w, res
+}
+
+func TestErrorHandlerMapsDomainErrors(t *testing.T) {
+	tests := []struct {
+		name       string
+		err        error
+		wantStatus int
+		wantCode   string
+	}{
+		{"入力エラー", entity.NewFieldError("invalid_price", "price", "価格が正しくありません"), http.StatusBadRequest, "invalid_price"},
+		{"未ログイン", entity.ErrUnauthenticated, http.StatusUnauthorized, "unauthenticated"},
+		{"権限が無い", entity.NewForbiddenError("forbidden", "権限がありません"), http.StatusForbidden, "forbidden"},
+		{"見つからない", repository.ErrNotFound, http.StatusNotFound, "not_found"},
+		{"重複", repository.ErrDuplicate, http.StatusConflict, "duplicate"},
+		{"ラップされたエラー", fmt.Errorf("商品の取得に失敗しました: %w", repository.ErrNotFound), http.StatusNotFound, "not_found"},
+		{"在庫不足", &entity.InsufficientStockError{Shortages: []entity.StockShortage{{ProductID: "p1", Name: "T シャツ", Requested: 3, Available: 1}}}, http.StatusConflict, "insufficient_stock"},
+		{"ステータスの遷移", &entity.InvalidStatusTransitionError{From: entity.OrderStatusDelivered, To: entity.OrderStatusCancelled}, http.StatusConflict, "invalid_status_transition"},
+		{"回数制限", &usecase.ThrottledError{RetryAfter: 1500 * time.Millisecond}, http.StatusTooManyRequests, "too_many_requests"},
+		{"サイズ超過", &http.MaxBytesError{Limit: 1024}, http.StatusRequestEntityTooLarge, "payload_too_large"},
+		{"想定外のエラー", errors.New("connection refused"), http.StatusInternalServerError, "internal_error"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w, res := serveError(t, func(c *gin.Context) { c.Error(tt.err) })
+			if w.Code != tt.wantStatus || res.Code != tt.wantCode {
+				t.Errorf("レスポンス = %d %q, want %d %q", w.Code, res.Code, tt.wantStatus, tt.wantCode)
+			}
+			if res.Error == "" {
+				t.Error("error のメッセージが空です")
+			}
+		})
+	}
+}
+
+func TestErrorHandlerDetails(t *testing.T) {
+	t.Run("入力エラーの項目", func(t *testing.T) {
+		_, res := serveError(t, func(c *gin.Context) {
+			c.Error(entity.NewFieldError("invalid_address", "address.postal_code", "7桁の郵便番号を入力してください"))
+		})
+		if len(res.Fields) != 1 || res.Fields[0].Field != "address.postal_code" {
+			t.Errorf("fields = %+v, want address.postal_code", res.Fields)
+		}
+	})
+	t.Run("在庫不足の商品", func(t *testing.T) {
+		_, res := serveError(t, func(c *gin.Context) {
+			c.Error(&entity.InsufficientStockError{Shortages: []entity.StockShortage{{ProductID: "p1", Requested: 3, Available: 1}}})
+		})
+		if len(res.Shortages) != 1 || res.Shortages[0].ProductID != "p1" || res.Shortages[0].Available != 1 {
+			t.Errorf("shortages = %+v, want p1 の在庫 1", res.Shortages)
+		}
+	})
+	t.Run("Retry-After", func(t *testing.T) {
+		w, _ := serveError(t, func(c *gin.Context) { c.Error(&usecase.ThrottledError{RetryAfter: 1500 * time.Millisecond}) })
+		if got := w.Header().Get("Retry-After"); got != "2" {
+			t.Errorf("Retry-After = %q, want 2", got)
+		}
+	})
+	t.Run("想定外のエラーの内容は返さない", func(t *testing.T) {
+		w, _ := serveError(t, func(c *gin.Context) { c.Error(errors.New("password=secret")) })
+		if body := w.Body.String(); strings.Contains(body, "secret") {
+			t.Errorf("レスポンスにエラーの内容が含まれています: %s", body)
+		}
+	})
+	t.Run("書き込み済みのレスポンスはそのまま", func(t *testing.T) {
+		w, _ := serveError(t, func(c *gin.Context) {
+			c.Error(repository.ErrNotFound)
+			c.JSON(http.StatusAccepted, gin.H{"message": "受け付けました"})
+		})
+		if w.Code != http.StatusAccepted {
+			t.Errorf("ステータス = %d, want %d", w.Code, http.StatusAccepted)
+		}
+	})
+}
+
+func TestErrorHandlerLocalizesMessages(t *testing.T) {
+	address := entity.Address{Name: "山田 太郎", City: strings.Repeat("区", 101), Line1: "千代田1-1"}
+	handler := func(c *gin.Context) { c.Error(address.Validate()) }
+
+	tests := []struct {
+		acceptLanguage string
+		wantError      string
+		wantField      string
+	}{
+		{"", "住所の入力内容が正しくありません", "100 文字以内で入力してください"},
+		{"en-US,en;q=0.9", "The address contains invalid values", "Must be 100 characters or fewer"},
+	}
+	for _, tt := range tests {
+		_, res := serveErrorIn(t, tt.acceptLanguage, handler)
+		if res.Error != tt.wantError || res.Code != "invalid_address" {
+			t.Errorf("%q: error = %q (%s), want %q", tt.acceptLanguage, res.Error, res.Code, tt.wantError)
+		}
+		if len(res.Fields) != 1 || res.Fields[0].Field != "address.city" || res.Fields[0].Code != "max_length" || res.Fields[0].Message != tt.wantField {
+			t.Errorf("%q: fields = %+v, want address.city の %q", tt.acceptLanguage, res.Fields, tt.wantField)
+		}
+	}
+}
@@ -0,0 +1,112 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"log"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sotaheavymetal21/rabbit-cart/backend/internal/domain/entity"
+	"github.com/sotaheavymetal21/rabbit-cart/backend/internal/usecase"
+)
+
+// maxIdempotentRequestSize は冪等キーの照合のために読み込むリクエストボディの最大のバイト数です
+const maxIdempotentRequestSize = 1 << 20
+
+// IdempotencyMiddleware は Idempotency-Key ヘッダーが指定されたリクエストを二重に処理しないようにするミドルウェアです
+// 成功したリクエストのレスポンスを保存し、同じキー・同じ内容で再送されたリクエストには処理を行わずに保存したレスポンスを返します
+// ヘッダーが無いリクエストはそのまま処理します。AuthMiddleware の後に登録してください
+func IdempotencyMiddleware(idempotencyUseCase usecase.IdempotencyUseCase) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		key := c.GetHeader("Idempotency-Key")
+		if key == "" {
+			c.Next()
+			return
+		}
+		userID, exists := c.Get("userID")
+		if !exists {
+			abortWithError(c, entity.ErrUnauthenticated)
+			return
+		}
+
+		// ボディは全てメモリに読み込むため、大きすぎるリクエストは 413 で拒否する
+		body, err := io.ReadAll(http.MaxBytesReader(c.Writer, c.Request.Body, maxIdempotentRequestSize))
+		if err != nil {
+			abortWithError(c, err)
+			return
+		}
+		c.Request.Body = io.NopCloser(bytes.NewReader(body))
+
+		ctx := c.Request.Context()
+		record, err := idempotencyUseCase.Begin(ctx, userID.(string), key, requestFingerprint(c.Request, body))
+		if err != nil {
+			abortWithError(c, err)
+			return
+		}
+		if record.IsCompleted() {
+			c.Header("Idempotent-Replayed", "true")
+			c.Data(record.ResponseStatus, "application/json; charset=utf-8", record.ResponseBody)
+			c.Abort()
+			return
+		}
+
+		// 処理の結果はクライアントが切断した後も冪等キーに反映するため、キャンセルされないコンテキストで保存する
+		saveCtx := context.WithoutCancel(ctx)
+		release := func() {
+			if err := idempotencyUseCase.Release(saveCtx, record); err != nil {
+				log.Printf("冪等キーの削除に失敗しました (user_id=%s): %v", userID, err)
+			}
+		}
+		// ハンドラーが panic した場合もキーを削除し、ロックの期限切れを待たずに再試行できるようにする
+		defer func() {
+			if r := recover(); r != nil {
+				release()
+				panic(r)
+			}
+		}()
+
+		recorder := &responseRecorder{ResponseWriter: c.Writer}
+		c.Writer = recorder
+		c.Next()
+
+		// 失敗したリクエストは結果を保存せず、同じキーで再試行できるようにする
+		status := c.Writer.Status()
+		if len(c.Errors) > 0 || status < http.StatusOK || status >= http.StatusMultipleChoices {
+			release()
+			return
+		}
+		if err := idempotencyUseCase.Complete(saveCtx, record, status, recorder.body.Bytes()); err != nil {
+			log.Printf("冪等キーのレスポンスの保存に失敗しました (user_id=%s): %v", userID, err)
+		}
+	}
+}
+
+// requestFingerprint は同じ冪等キーで再送されたリクエストが同じ内容かを確認するための値を返します
+// JSON のボディは空白の違いを無視するため、余分な空白を取り除いて比較します
+func requestFingerprint(r *http.Request, body []byte) []byte {
+	var compacted bytes.Buffer
+	if err := json.Compact(&compacted, body); err == nil {
+		body = compacted.Bytes()
+	}
+	fingerprint := []byte(r.Method + " " + r.URL.Path + "\n")
+	return append(fingerprint, body...)
+}
+
+// responseRecorder はレスポンスボディを書き込みながら保持する gin.ResponseWriter です
+type responseRecorder struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+func (w *responseRecorder) Write(b []byte) (int, error) {
+	w.body.Write(b)
+	return w.ResponseWriter.Write(b)
+}
+
+func (w *responseRecorder) WriteString(s string) (int, error) {
+	w.body.WriteString(s)
+	return w.ResponseWriter.WriteString(s)
+}
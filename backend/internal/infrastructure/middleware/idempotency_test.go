@@ -0,0 +1,192 @@
+package middleware_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sotaheavymetal21/rabbit-cart/backend/internal/domain/entity"
+	"github.com/sotaheavymetal21/rabbit-cart/backend/internal/domain/repository"
+	"github.com/sotaheavymetal21/rabbit-cart/backend/internal/infrastructure/middleware"
+	"github.com/sotaheavymetal21/rabbit-cart/backend/internal/usecase"
+)
+
+// memoryIdempotencyKeyRepository はテスト用の冪等キーのリポジトリです
+// 実際のデータベースと同じように、キャンセルされたコンテキストでは保存に失敗します
+type memoryIdempotencyKeyRepository struct {
+	mu   sync.Mutex
+	keys map[string]*entity.IdempotencyKey
+}
+
+func newMemoryIdempotencyKeyRepository() *memoryIdempotencyKeyRepository {
+	return &memoryIdempotencyKeyRepository{keys: map[string]*entity.IdempotencyKey{}}
+}
+
+func (r *memoryIdempotencyKeyRepository) Create(ctx context.Context, key *entity.IdempotencyKey) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, k := range r.keys {
+		if k.UserID == key.UserID && k.Key == key.Key {
+			return repository.ErrDuplicate
+		}
+	}
+	key.ID = key.UserID + "/" + key.Key
+	stored := *key
+	r.keys[key.ID] = &stored
+	return nil
+}
+
+func (r *memoryIdempotencyKeyRepository) Find(ctx context.Context, userID, key string) (*entity.IdempotencyKey, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	k, ok := r.keys[userID+"/"+key]
+	if !ok {
+		return nil, repository.ErrNotFound
+	}
+	found := *k
+	return &found, nil
+}
+
+func (r *memoryIdempotencyKeyRepository) Complete(ctx context.Context, id string, status int, body []byte, expiresAt time.Time) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	k, ok := r.keys[id]
+	if !ok {
+		return repository.ErrNotFound
+	}
+	k.ResponseStatus, k.ResponseBody, k.ExpiresAt = status, body, expiresAt
+	return nil
+}
+
+func (r *memoryIdempotencyKeyRepository) Delete(ctx context.Context, id string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.keys, id)
+	return nil
+}
+
+func (r *memoryIdempotencyKeyRepository) PurgeExpired(ctx context.Context, now time.Time) error {
+	return nil
+}
+
+// newIdempotencyRouter は POST /orders を handler で処理する、ログイン済みのユーザー u1 のルーターを返します
+func newIdempotencyRouter(repo repository.IdempotencyKeyRepository, handler gin.HandlerFunc) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(gin.CustomRecovery(func(c *gin.Context, _ any) {
+		c.AbortWithStatus(http.StatusInternalServerError)
+	}), middleware.ErrorHandler(), func(c *gin.Context) {
+		c.Set("userID", "u1")
+	})
+	idempotencyUseCase := usecase.NewIdempotencyUseCase(repo, usecase.IdempotencyConfig{})
+	r.POST("/orders", middleware.IdempotencyMiddleware(idempotencyUseCase), handler)
+	return r
+}
+
+func postOrder(r *gin.Engine, key string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPost, "/orders", strings.NewReader(`{"cart": true}`))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Idempotency-Key", key)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	return w
+}
+
+func TestIdempotencyMiddlewareReplaysCompletedRequest(t *testing.T) {
+	calls := 0
+	r := newIdempotencyRouter(newMemoryIdempotencyKeyRepository(), func(c *gin.Context) {
+		calls++
+		c.JSON(http.StatusCreated, gin.H{"id": "order-1"})
+	})
+
+	first := postOrder(r, "key-1")
+	second := postOrder(r, "key-1")
+	if calls != 1 {
+		t.Fatalf("ハンドラーの呼び出し回数 = %d, want 1", calls)
+	}
+	if second.Code != http.StatusCreated || second.Body.String() != first.Body.String() {
+		t.Errorf("再送のレスポンス = %d %s, want %d %s", second.Code, second.Body.String(), first.Code, first.Body.String())
+	}
+	if second.Header().Get("Idempotent-Replayed") != "true" {
+		t.Error("再送のレスポンスに Idempotent-Replayed ヘッダーがありません")
+	}
+}
+
+func TestIdempotencyMiddlewareCompletesAfterClientDisconnect(t *testing.T) {
+	repo := newMemoryIdempotencyKeyRepository()
+	calls := 0
+	r := newIdempotencyRouter(repo, func(c *gin.Context) {
+		calls++
+		c.JSON(http.StatusCreated, gin.H{"id": "order-1"})
+	})
+
+	// 注文の作成後、レスポンスを受け取る前にクライアントが切断した場合
+	ctx, cancel := context.WithCancel(context.Background())
+	req := httptest.NewRequest(http.MethodPost, "/orders", strings.NewReader(`{"cart": true}`)).WithContext(ctx)
+	req.Header.Set("Idempotency-Key", "key-1")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(&cancelOnWrite{ResponseRecorder: w, cancel: cancel}, req)
+
+	record, err := repo.Find(context.Background(), "u1", "key-1")
+	if err != nil {
+		t.Fatalf("冪等キーが保存されていません: %v", err)
+	}
+	if !record.IsCompleted() {
+		t.Fatal("切断されたリクエストの冪等キーが処理中のままです")
+	}
+	if retry := postOrder(r, "key-1"); retry.Code != http.StatusCreated || calls != 1 {
+		t.Errorf("再試行 = %d (ハンドラーの呼び出し %d 回), want %d (1 回)", retry.Code, calls, http.StatusCreated)
+	}
+}
+
+func TestIdempotencyMiddlewareReleasesKeyOnPanic(t *testing.T) {
+	repo := newMemoryIdempotencyKeyRepository()
+	panics := true
+	r := newIdempotencyRouter(repo, func(c *gin.Context) {
+		if panics {
+			panic("注文の作成中に予期しないエラー")
+		}
+		c.JSON(http.StatusCreated, gin.H{"id": "order-1"})
+	})
+
+	if w := postOrder(r, "key-1"); w.Code != http.StatusInternalServerError {
+		t.Fatalf("ステータス = %d, want %d", w.Code, http.StatusInternalServerError)
+	}
+	if _, err := repo.Find(context.Background(), "u1", "key-1"); !errors.Is(err, repository.ErrNotFound) {
+		t.Fatalf("panic したリクエストの冪等キーが残っています: %v", err)
+	}
+	// ロックの期限切れを待たずに同じキーで再試行できる
+	panics = false
+	if w := postOrder(r, "key-1"); w.Code != http.StatusCreated {
+		t.Errorf("再試行のステータス = %d, want %d", w.Code, http.StatusCreated)
+	}
+}
+
+// cancelOnWrite はレスポンスの書き込み時にリクエストのコンテキストをキャンセルし、クライアントの切断を再現します
+type cancelOnWrite struct {
+	*httptest.ResponseRecorder
+	cancel context.CancelFunc
+}
+
+func (w *cancelOnWrite) Write(b []byte) (int, error) {
+	w.cancel()
+	return w.ResponseRecorder.Write(b)
+}
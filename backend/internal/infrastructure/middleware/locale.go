@@ -0,0 +1,26 @@
+package middleware
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/sotaheavymetal21/rabbit-cart/backend/internal/domain/entity"
+	"github.com/sotaheavymetal21/rabbit-cart/backend/internal/infrastructure/i18n"
+)
+
+// LocaleMiddleware は Accept-Language ヘッダーからメッセージの言語を決めてコンテキストに設定するミドルウェアです
+// ログインユーザーが言語を設定している場合は AuthMiddleware がその言語で上書きします
+func LocaleMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Set("locale", i18n.Negotiate(c.GetHeader("Accept-Language")))
+		c.Next()
+	}
+}
+
+// requestLocale はコンテキストに設定されたメッセージの言語を返します (設定されていない場合は既定の言語)
+func requestLocale(c *gin.Context) entity.Locale {
+	if value, exists := c.Get("locale"); exists {
+		if locale, ok := value.(entity.Locale); ok {
+			return locale
+		}
+	}
+	return entity.DefaultLocale
+}
@@ -0,0 +1,170 @@
+package oidc
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/sotaheavymetal21/rabbit-cart/backend/internal/domain/entity"
+)
+
+// leeway は ID プロバイダーとの時刻のずれとして許容する時間です
+const leeway = time.Minute
+
+type idTokenHeader struct {
+	Alg string `json:"alg"`
+	Kid string `json:"kid"`
+}
+
+type idTokenClaims struct {
+	Issuer        string   `json:"iss"`
+	Subject       string   `json:"sub"`
+	Audience      audience `json:"aud"`
+	AuthorizedBy  string   `json:"azp"`
+	ExpiresAt     int64    `json:"exp"`
+	IssuedAt      int64    `json:"iat"`
+	Nonce         string   `json:"nonce"`
+	Email         string   `json:"email"`
+	EmailVerified boolish  `json:"email_verified"`
+}
+
+// audience は文字列と文字列の配列のどちらでも表される aud クレームです
+type audience []string
+
+func (a *audience) UnmarshalJSON(b []byte) error {
+	var s string
+	if err := json.Unmarshal(b, &s); err == nil {
+		*a = audience{s}
+		return nil
+	}
+	var list []string
+	if err := json.Unmarshal(b, &list); err != nil {
+		return err
+	}
+	*a = list
+	return nil
+}
+
+func (a audience) contains(v string) bool {
+	for _, s := range a {
+		if s == v {
+			return true
+		}
+	}
+	return false
+}
+
+// boolish は真偽値と文字列 ("true") のどちらでも表される email_verified クレームです
+type boolish bool
+
+func (b *boolish) UnmarshalJSON(data []byte) error {
+	switch strings.Trim(string(data), `"`) {
+	case "true":
+		*b = true
+	default:
+		*b = false
+	}
+	return nil
+}
+
+// verifyIDToken は ID トークンの署名 (RS256・ES256) と iss・aud・azp・exp・iat を検証してクレームを返します
+func verifyIDToken(ctx context.Context, token string, keys *keySet, issuer, clientID string, now time.Time) (*entity.OIDCClaims, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil, errors.New("ID トークンの形式が正しくありません")
+	}
+
+	var h idTokenHeader
+	if err := decodeSegment(parts[0], &h); err != nil {
+		return nil, errors.New("ID トークンのヘッダーが正しくありません")
+	}
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return nil, errors.New("ID トークンの署名が正しくありません")
+	}
+	key, err := keys.key(ctx, h.Kid)
+	if err != nil {
+		return nil, err
+	}
+	if err := verifySignature(h.Alg, key, parts[0]+"."+parts[1], signature); err != nil {
+		return nil, err
+	}
+
+	var claims idTokenClaims
+	if err := decodeSegment(parts[1], &claims); err != nil {
+		return nil, errors.New("ID トークンのクレームが正しくありません")
+	}
+	if claims.Issuer != issuer {
+		return nil, fmt.Errorf("ID トークンの発行者 (%s) が一致しません", claims.Issuer)
+	}
+	if !claims.Audience.contains(clientID) {
+		return nil, errors.New("ID トークンの対象者にクライアントIDが含まれていません")
+	}
+	if len(claims.Audience) > 1 && claims.AuthorizedBy != clientID {
+		return nil, errors.New("ID トークンの azp がクライアントIDと一致しません")
+	}
+	if claims.Subject == "" {
+		return nil, errors.New("ID トークンに sub がありません")
+	}
+	if now.Add(-leeway).After(time.Unix(claims.ExpiresAt, 0)) {
+		return nil, errors.New("ID トークンの有効期限が切れています")
+	}
+	if time.Unix(claims.IssuedAt, 0).After(now.Add(leeway)) {
+		return nil, errors.New("ID トークンの発行日時が未来です")
+	}
+
+	return &entity.OIDCClaims{
+		Subject:       claims.Subject,
+		Email:         claims.Email,
+		EmailVerified: bool(claims.EmailVerified),
+		Nonce:         claims.Nonce,
+	}, nil
+}
+
+// verifySignature は alg に従って署名を検証します
+// alg と鍵の種類が一致しない場合 ("none" や HS256 へのすり替えを含む) は拒否します
+func verifySignature(alg string, key crypto.PublicKey, signingInput string, signature []byte) error {
+	digest := sha256.Sum256([]byte(signingInput))
+	switch alg {
+	case "RS256":
+		pub, ok := key.(*rsa.PublicKey)
+		if !ok {
+			return errors.New("ID トークンの alg と鍵の種類が一致しません")
+		}
+		if err := rsa.VerifyPKCS1v15(pub, crypto.SHA256, digest[:], signature); err != nil {
+			return errors.New("ID トークンの署名が正しくありません")
+		}
+		return nil
+	case "ES256":
+		pub, ok := key.(*ecdsa.PublicKey)
+		if !ok || len(signature) != 64 {
+			return errors.New("ID トークンの alg と鍵の種類が一致しません")
+		}
+		// JWS の ES256 署名は ASN.1 ではなく r と s を 32 バイトずつ連結した形式
+		r := new(big.Int).SetBytes(signature[:32])
+		s := new(big.Int).SetBytes(signature[32:])
+		if !ecdsa.Verify(pub, digest[:], r, s) {
+			return errors.New("ID トークンの署名が正しくありません")
+		}
+		return nil
+	default:
+		return fmt.Errorf("対応していない署名アルゴリズムです: %s", alg)
+	}
+}
+
+func decodeSegment(seg string, v interface{}) error {
+	b, err := base64.RawURLEncoding.DecodeString(seg)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(b, v)
+}
@@ -0,0 +1,323 @@
+// Package oidctest はテスト用の OpenID Connect プロバイダー (httptest) を提供します
+// ディスカバリー・認可・トークン (PKCE の検証を含む)・JWKS の各エンドポイントを実装し、
+// 発行する ID トークンのヘッダーやクレームをテストから書き換えられます
+package oidctest
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"testing"
+	"time"
+)
+
+const (
+	// RSAKeyID と ECKeyID は JWKS で公開する鍵の kid です
+	RSAKeyID = "rsa-1"
+	ECKeyID  = "ec-1"
+
+	// RedirectURL はクライアントに設定するリダイレクト先です
+	RedirectURL = "https://shop.example.com/api/v1/auth/oidc/mock/callback"
+)
+
+// Provider は httptest で動作する ID プロバイダーです
+type Provider struct {
+	Issuer   string
+	ClientID string
+
+	// Header と Claims は次に発行する ID トークンのヘッダーとクレームを書き換えます
+	Header func(header map[string]interface{})
+	Claims func(claims map[string]interface{})
+	// Forge が true の場合は JWKS で公開していない鍵で署名します (kid は公開している鍵のもの)
+	Forge bool
+
+	server *httptest.Server
+	rsaKey *rsa.PrivateKey
+	ecKey  *ecdsa.PrivateKey
+	forged *rsa.PrivateKey
+
+	mu          sync.Mutex
+	codes       map[string]authRequest
+	jwksFetches int
+}
+
+// authRequest は認可コードの発行時に受け取ったパラメーターです
+type authRequest struct {
+	clientID      string
+	redirectURI   string
+	nonce         string
+	codeChallenge string
+}
+
+// New は ID プロバイダーを起動します (テストの終了時に停止します)
+func New(t testing.TB, clientID string) *Provider {
+	t.Helper()
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	forged, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	p := &Provider{
+		ClientID: clientID,
+		rsaKey:   rsaKey,
+		ecKey:    ecKey,
+		forged:   forged,
+		codes:    make(map[string]authRequest),
+	}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", p.handleDiscovery)
+	mux.HandleFunc("/authorize", p.handleAuthorize)
+	mux.HandleFunc("/token", p.handleToken)
+	mux.HandleFunc("/jwks", p.handleJWKS)
+	p.server = httptest.NewServer(mux)
+	p.Issuer = p.server.URL
+	t.Cleanup(p.server.Close)
+	return p
+}
+
+// Authorize は利用者が認可エンドポイントで同意した後のリダイレクトを再現し、認可コードと state を返します
+func (p *Provider) Authorize(t testing.TB, authURL string) (code, state string) {
+	t.Helper()
+	client := &http.Client{
+		CheckRedirect: func(req *http.Request, via []*http.Request) error { return http.ErrUseLastResponse },
+	}
+	res, err := client.Get(authURL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusFound {
+		t.Fatalf("認可エンドポイントのステータス = %d, want %d", res.StatusCode, http.StatusFound)
+	}
+	location, err := url.Parse(res.Header.Get("Location"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return location.Query().Get("code"), location.Query().Get("state")
+}
+
+// JWKSFetches は JWKS エンドポイントが呼び出された回数を返します
+func (p *Provider) JWKSFetches() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.jwksFetches
+}
+
+func (p *Provider) handleDiscovery(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"issuer":                 p.Issuer,
+		"authorization_endpoint": p.Issuer + "/authorize",
+		"token_endpoint":         p.Issuer + "/token",
+		"jwks_uri":               p.Issuer + "/jwks",
+	})
+}
+
+func (p *Provider) handleAuthorize(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	if q.Get("response_type") != "code" || q.Get("client_id") != p.ClientID || q.Get("redirect_uri") == "" {
+		http.Error(w, "invalid_request", http.StatusBadRequest)
+		return
+	}
+	// PKCE は S256 のみ受け付ける
+	if q.Get("code_challenge") == "" || q.Get("code_challenge_method") != "S256" {
+		http.Error(w, "invalid_request: code_challenge", http.StatusBadRequest)
+		return
+	}
+
+	code := randomString()
+	p.mu.Lock()
+	p.codes[code] = authRequest{
+		clientID:      q.Get("client_id"),
+		redirectURI:   q.Get("redirect_uri"),
+		nonce:         q.Get("nonce"),
+		codeChallenge: q.Get("code_challenge"),
+	}
+	p.mu.Unlock()
+
+	redirect, err := url.Parse(q.Get("redirect_uri"))
+	if err != nil {
+		http.Error(w, "invalid_request: redirect_uri", http.StatusBadRequest)
+		return
+	}
+	v := redirect.Query()
+	v.Set("code", code)
+	v.Set("state", q.Get("state"))
+	redirect.RawQuery = v.Encode()
+	http.Redirect(w, r, redirect.String(), http.StatusFound)
+}
+
+func (p *Provider) handleToken(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if err := r.ParseForm(); err != nil || r.PostForm.Get("grant_type") != "authorization_code" {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid_request"})
+		return
+	}
+
+	// 認可コードは1回しか使えない
+	p.mu.Lock()
+	req, ok := p.codes[r.PostForm.Get("code")]
+	delete(p.codes, r.PostForm.Get("code"))
+	p.mu.Unlock()
+	if !ok || req.redirectURI != r.PostForm.Get("redirect_uri") || req.clientID != clientID(r) {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid_grant"})
+		return
+	}
+	digest := sha256.Sum256([]byte(r.PostForm.Get("code_verifier")))
+	if base64.RawURLEncoding.EncodeToString(digest[:]) != req.codeChallenge {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid_grant", "error_description": "PKCE verification failed"})
+		return
+	}
+
+	token, err := p.idToken(req.nonce)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"access_token": randomString(),
+		"token_type":   "Bearer",
+		"expires_in":   3600,
+		"id_token":     token,
+	})
+}
+
+func (p *Provider) handleJWKS(w http.ResponseWriter, r *http.Request) {
+	p.mu.Lock()
+	p.jwksFetches++
+	p.mu.Unlock()
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"keys": []map[string]string{
+			{
+				"kty": "RSA",
+				"kid": RSAKeyID,
+				"use": "sig",
+				"alg": "RS256",
+				"n":   encodeSegment(p.rsaKey.N.Bytes()),
+				"e":   encodeSegment(big.NewInt(int64(p.rsaKey.E)).Bytes()),
+			},
+			{
+				"kty": "EC",
+				"kid": ECKeyID,
+				"use": "sig",
+				"alg": "ES256",
+				"crv": "P-256",
+				"x":   encodeSegment(p.ecKey.X.FillBytes(make([]byte, 32))),
+				"y":   encodeSegment(p.ecKey.Y.FillBytes(make([]byte, 32))),
+			},
+		},
+	})
+}
+
+// idToken は ID トークンを発行します
+// alg に応じた鍵で署名し、"none" の場合は署名を付けず、HS256 の場合は RSA の公開鍵 (n) を HMAC の鍵に使います
+func (p *Provider) idToken(nonce string) (string, error) {
+	now := time.Now()
+	header := map[string]interface{}{"alg": "RS256", "typ": "JWT", "kid": RSAKeyID}
+	claims := map[string]interface{}{
+		"iss":            p.Issuer,
+		"sub":            "mock-subject-1",
+		"aud":            p.ClientID,
+		"exp":            now.Add(5 * time.Minute).Unix(),
+		"iat":            now.Unix(),
+		"nonce":          nonce,
+		"email":          "alice@example.com",
+		"email_verified": true,
+	}
+	if p.Header != nil {
+		p.Header(header)
+	}
+	if p.Claims != nil {
+		p.Claims(claims)
+	}
+
+	hb, err := json.Marshal(header)
+	if err != nil {
+		return "", err
+	}
+	cb, err := json.Marshal(claims)
+	if err != nil {
+		return "", err
+	}
+	signingInput := encodeSegment(hb) + "." + encodeSegment(cb)
+	digest := sha256.Sum256([]byte(signingInput))
+
+	var signature []byte
+	switch header["alg"] {
+	case "RS256":
+		key := p.rsaKey
+		if p.Forge {
+			key = p.forged
+		}
+		signature, err = rsa.SignPKCS1v15(rand.Reader, key, crypto.SHA256, digest[:])
+	case "ES256":
+		var r, s *big.Int
+		r, s, err = ecdsa.Sign(rand.Reader, p.ecKey, digest[:])
+		if err == nil {
+			signature = append(r.FillBytes(make([]byte, 32)), s.FillBytes(make([]byte, 32))...)
+		}
+	case "HS256":
+		mac := hmac.New(sha256.New, p.rsaKey.N.Bytes())
+		mac.Write([]byte(signingInput))
+		signature = mac.Sum(nil)
+	case "none":
+	default:
+		return "", fmt.Errorf("未対応の alg です: %v", header["alg"])
+	}
+	if err != nil {
+		return "", err
+	}
+	return signingInput + "." + encodeSegment(signature), nil
+}
+
+// clientID は Basic 認証またはフォームからクライアントIDを取り出します
+func clientID(r *http.Request) string {
+	if id, _, ok := r.BasicAuth(); ok {
+		if unescaped, err := url.QueryUnescape(id); err == nil {
+			return unescaped
+		}
+		return id
+	}
+	return r.PostForm.Get("client_id")
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func encodeSegment(b []byte) string {
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+func randomString() string {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		panic(err)
+	}
+	return encodeSegment(b)
+}
@@ -0,0 +1,186 @@
+// Package oidc は OpenID Connect の認可コードフロー (PKCE) と ID トークンの検証を実装します
+package oidc
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sotaheavymetal21/rabbit-cart/backend/internal/domain/entity"
+	"github.com/sotaheavymetal21/rabbit-cart/backend/internal/domain/repository"
+)
+
+// httpTimeout は ID プロバイダーへのリクエストのタイムアウトです
+const httpTimeout = 10 * time.Second
+
+// maxResponseSize は ID プロバイダーのレスポンスとして読み込む最大のバイト数です
+const maxResponseSize = 1 << 20
+
+// Config は ID プロバイダーごとの設定です
+type Config struct {
+	Name         string // API の URL で使うプロバイダー名 (例: google)
+	Issuer       string // ディスカバリー (/.well-known/openid-configuration) の取得元
+	ClientID     string
+	ClientSecret string // 公開クライアントの場合は空
+	RedirectURL  string
+	Scopes       []string
+}
+
+// discovery は OpenID Provider Metadata のうち使用する項目です
+type discovery struct {
+	Issuer                string `json:"issuer"`
+	AuthorizationEndpoint string `json:"authorization_endpoint"`
+	TokenEndpoint         string `json:"token_endpoint"`
+	JWKSURI               string `json:"jwks_uri"`
+}
+
+type provider struct {
+	config Config
+	client *http.Client
+
+	mu       sync.Mutex
+	metadata *discovery
+	keys     *keySet
+}
+
+// NewProvider は OIDCProvider の実装を生成します
+// エンドポイントはディスカバリーで取得し、最初に使用した時点で読み込みます
+func NewProvider(config Config) (repository.OIDCProvider, error) {
+	if config.Name == "" || config.Issuer == "" || config.ClientID == "" || config.RedirectURL == "" {
+		return nil, fmt.Errorf("ID プロバイダー %q の設定が不足しています (issuer, client_id, redirect_url は必須です)", config.Name)
+	}
+	if len(config.Scopes) == 0 {
+		config.Scopes = []string{"openid", "email", "profile"}
+	}
+	config.Issuer = strings.TrimSuffix(config.Issuer, "/")
+	return &provider{config: config, client: &http.Client{Timeout: httpTimeout}}, nil
+}
+
+// Name は設定で指定したプロバイダー名を返します
+func (p *provider) Name() string {
+	return p.config.Name
+}
+
+// AuthCodeURL は認可エンドポイントの URL を返します
+func (p *provider) AuthCodeURL(ctx context.Context, state, nonce, codeChallenge string) (string, error) {
+	metadata, err := p.discover(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	v := url.Values{}
+	v.Set("response_type", "code")
+	v.Set("client_id", p.config.ClientID)
+	v.Set("redirect_uri", p.config.RedirectURL)
+	v.Set("scope", strings.Join(p.config.Scopes, " "))
+	v.Set("state", state)
+	v.Set("nonce", nonce)
+	v.Set("code_challenge", codeChallenge)
+	v.Set("code_challenge_method", "S256")
+
+	sep := "?"
+	if strings.Contains(metadata.AuthorizationEndpoint, "?") {
+		sep = "&"
+	}
+	return metadata.AuthorizationEndpoint + sep + v.Encode(), nil
+}
+
+// Exchange は認可コードをトークンに交換し、検証済みの ID トークンのクレームを返します
+func (p *provider) Exchange(ctx context.Context, code, codeVerifier string) (*entity.OIDCClaims, error) {
+	metadata, err := p.discover(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	form := url.Values{}
+	form.Set("grant_type", "authorization_code")
+	form.Set("code", code)
+	form.Set("redirect_uri", p.config.RedirectURL)
+	form.Set("code_verifier", codeVerifier)
+	if p.config.ClientSecret == "" {
+		form.Set("client_id", p.config.ClientID)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, metadata.TokenEndpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+	if p.config.ClientSecret != "" {
+		// client_secret_basic (RFC 6749 2.3.1 に従い、ID とシークレットは URL エンコードする)
+		req.SetBasicAuth(url.QueryEscape(p.config.ClientID), url.QueryEscape(p.config.ClientSecret))
+	}
+
+	var token struct {
+		IDToken string `json:"id_token"`
+	}
+	if err := p.do(req, &token); err != nil {
+		return nil, fmt.Errorf("トークンエンドポイントの呼び出しに失敗しました: %w", err)
+	}
+	if token.IDToken == "" {
+		return nil, errors.New("トークンレスポンスに id_token がありません")
+	}
+
+	return verifyIDToken(ctx, token.IDToken, p.keySet(metadata), metadata.Issuer, p.config.ClientID, time.Now())
+}
+
+// discover はディスカバリーを取得します (成功した結果はキャッシュします)
+func (p *provider) discover(ctx context.Context) (*discovery, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.metadata != nil {
+		return p.metadata, nil
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, p.config.Issuer+"/.well-known/openid-configuration", nil)
+	if err != nil {
+		return nil, err
+	}
+	var metadata discovery
+	if err := p.do(req, &metadata); err != nil {
+		return nil, fmt.Errorf("ディスカバリーの取得に失敗しました: %w", err)
+	}
+	// なりすましを防ぐため、ディスカバリーの issuer は設定と一致しなければならない
+	if strings.TrimSuffix(metadata.Issuer, "/") != p.config.Issuer {
+		return nil, fmt.Errorf("ディスカバリーの issuer (%s) が設定 (%s) と一致しません", metadata.Issuer, p.config.Issuer)
+	}
+	if metadata.AuthorizationEndpoint == "" || metadata.TokenEndpoint == "" || metadata.JWKSURI == "" {
+		return nil, errors.New("ディスカバリーに必要なエンドポイントがありません")
+	}
+	p.metadata = &metadata
+	return p.metadata, nil
+}
+
+func (p *provider) keySet(metadata *discovery) *keySet {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.keys == nil {
+		p.keys = newKeySet(metadata.JWKSURI, p.do)
+	}
+	return p.keys
+}
+
+// do はリクエストを送信し、JSON のレスポンスを v に読み込みます
+func (p *provider) do(req *http.Request, v interface{}) error {
+	res, err := p.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(res.Body, maxResponseSize))
+	if err != nil {
+		return err
+	}
+	if res.StatusCode != http.StatusOK {
+		return fmt.Errorf("%s %s: ステータス %d: %s", req.Method, req.URL.Redacted(), res.StatusCode, strings.TrimSpace(string(body)))
+	}
+	return json.Unmarshal(body, v)
+}
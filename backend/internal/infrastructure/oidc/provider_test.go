@@ -0,0 +1,186 @@
+package oidc_test
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sotaheavymetal21/rabbit-cart/backend/internal/domain/entity"
+	"github.com/sotaheavymetal21/rabbit-cart/backend/internal/domain/repository"
+	"github.com/sotaheavymetal21/rabbit-cart/backend/internal/infrastructure/oidc"
+	"github.com/sotaheavymetal21/rabbit-cart/backend/internal/infrastructure/oidc/oidctest"
+)
+
+const (
+	testClientID = "rabbit-cart-web"
+	testVerifier = "dBjftJeZ4CVP-mB92K27uhbUJU1p1r_wW1gFWFOEjXk"
+)
+
+func newTestProvider(t *testing.T, idp *oidctest.Provider) repository.OIDCProvider {
+	t.Helper()
+	p, err := oidc.NewProvider(oidc.Config{
+		Name:        "mock",
+		Issuer:      idp.Issuer,
+		ClientID:    testClientID,
+		RedirectURL: oidctest.RedirectURL,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return p
+}
+
+func challenge(verifier string) string {
+	sum := sha256.Sum256([]byte(verifier))
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
+
+// login は認可エンドポイントへのリダイレクトから認可コードの交換までを行います
+func login(t *testing.T, idp *oidctest.Provider, p repository.OIDCProvider, verifier string) (*entity.OIDCClaims, error) {
+	t.Helper()
+	ctx := context.Background()
+	authURL, err := p.AuthCodeURL(ctx, "state-1", "nonce-1", challenge(testVerifier))
+	if err != nil {
+		t.Fatalf("AuthCodeURL: %v", err)
+	}
+	code, state := idp.Authorize(t, authURL)
+	if state != "state-1" {
+		t.Fatalf("state = %q, want state-1", state)
+	}
+	return p.Exchange(ctx, code, verifier)
+}
+
+func TestProviderExchange(t *testing.T) {
+	idp := oidctest.New(t, testClientID)
+	claims, err := login(t, idp, newTestProvider(t, idp), testVerifier)
+	if err != nil {
+		t.Fatalf("Exchange: %v", err)
+	}
+	want := entity.OIDCClaims{Subject: "mock-subject-1", Email: "alice@example.com", EmailVerified: true, Nonce: "nonce-1"}
+	if *claims != want {
+		t.Errorf("claims = %+v, want %+v", *claims, want)
+	}
+}
+
+func TestProviderExchangeValidatesIDToken(t *testing.T) {
+	now := time.Now()
+	tests := []struct {
+		name    string
+		header  func(h map[string]interface{})
+		claims  func(c map[string]interface{})
+		forge   bool
+		wantErr bool
+	}{
+		{name: "ES256", header: func(h map[string]interface{}) { h["alg"], h["kid"] = "ES256", oidctest.ECKeyID }},
+		{name: "aud が配列で azp が一致", claims: func(c map[string]interface{}) {
+			c["aud"], c["azp"] = []string{testClientID, "other-client"}, testClientID
+		}},
+		{name: "有効期限切れだが許容範囲内", claims: func(c map[string]interface{}) { c["exp"] = now.Add(-30 * time.Second).Unix() }},
+
+		{name: "公開していない鍵で署名", forge: true, wantErr: true},
+		{name: "alg=none", header: func(h map[string]interface{}) { h["alg"] = "none" }, wantErr: true},
+		{name: "alg=HS256 (公開鍵を HMAC の鍵に使う)", header: func(h map[string]interface{}) { h["alg"] = "HS256" }, wantErr: true},
+		{name: "alg と鍵の種類が不一致", header: func(h map[string]interface{}) { h["alg"], h["kid"] = "ES256", oidctest.RSAKeyID }, wantErr: true},
+		{name: "未知の kid", header: func(h map[string]interface{}) { h["kid"] = "rsa-unknown" }, wantErr: true},
+		{name: "別のクライアント向け", claims: func(c map[string]interface{}) { c["aud"] = "other-client" }, wantErr: true},
+		{name: "aud が配列で azp なし", claims: func(c map[string]interface{}) { c["aud"] = []string{testClientID, "other-client"} }, wantErr: true},
+		{name: "別の発行者", claims: func(c map[string]interface{}) { c["iss"] = "https://evil.example.com" }, wantErr: true},
+		{name: "sub なし", claims: func(c map[string]interface{}) { delete(c, "sub") }, wantErr: true},
+		{name: "有効期限切れ", claims: func(c map[string]interface{}) { c["exp"] = now.Add(-2 * time.Minute).Unix() }, wantErr: true},
+		{name: "発行日時が未来", claims: func(c map[string]interface{}) { c["iat"] = now.Add(2 * time.Minute).Unix() }, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			idp := oidctest.New(t, testClientID)
+			idp.Header, idp.Claims, idp.Forge = tt.header, tt.claims, tt.forge
+
+			claims, err := login(t, idp, newTestProvider(t, idp), testVerifier)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("Exchange が成功しました: %+v", claims)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Exchange: %v", err)
+			}
+			if claims.Subject != "mock-subject-1" || claims.Nonce != "nonce-1" {
+				t.Errorf("claims = %+v", claims)
+			}
+		})
+	}
+}
+
+func TestProviderExchangeRejectsPKCEMismatch(t *testing.T) {
+	idp := oidctest.New(t, testClientID)
+	p := newTestProvider(t, idp)
+	if _, err := login(t, idp, p, "another-verifier-0123456789abcdefghijklmnopq"); err == nil {
+		t.Error("コード検証値が一致しないのに Exchange が成功しました")
+	}
+
+	// 認可コードは1回しか使えない
+	ctx := context.Background()
+	authURL, err := p.AuthCodeURL(ctx, "state-1", "nonce-1", challenge(testVerifier))
+	if err != nil {
+		t.Fatal(err)
+	}
+	code, _ := idp.Authorize(t, authURL)
+	if _, err := p.Exchange(ctx, code, testVerifier); err != nil {
+		t.Fatalf("Exchange: %v", err)
+	}
+	if _, err := p.Exchange(ctx, code, testVerifier); err == nil {
+		t.Error("使用済みの認可コードで Exchange が成功しました")
+	}
+}
+
+func TestProviderAuthCodeURL(t *testing.T) {
+	idp := oidctest.New(t, testClientID)
+	authURL, err := newTestProvider(t, idp).AuthCodeURL(context.Background(), "state-1", "nonce-1", challenge(testVerifier))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, param := range []string{"state=state-1", "nonce=nonce-1", "code_challenge=" + challenge(testVerifier), "code_challenge_method=S256"} {
+		if !strings.Contains(authURL, param) {
+			t.Errorf("認可 URL に %s がありません: %s", param, authURL)
+		}
+	}
+}
+
+func TestProviderRejectsDiscoveryIssuerMismatch(t *testing.T) {
+	idp := oidctest.New(t, testClientID)
+	// 同じサーバーを別のホスト名で設定すると、ディスカバリーの issuer と一致しない
+	p, err := oidc.NewProvider(oidc.Config{
+		Name:        "mock",
+		Issuer:      strings.Replace(idp.Issuer, "127.0.0.1", "localhost", 1),
+		ClientID:    testClientID,
+		RedirectURL: oidctest.RedirectURL,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := p.AuthCodeURL(context.Background(), "state-1", "nonce-1", challenge(testVerifier)); err == nil {
+		t.Error("issuer が一致しないディスカバリーを受け付けました")
+	}
+}
+
+func TestProviderJWKSRefetchIsRateLimited(t *testing.T) {
+	idp := oidctest.New(t, testClientID)
+	p := newTestProvider(t, idp)
+	if _, err := login(t, idp, p, testVerifier); err != nil {
+		t.Fatal(err)
+	}
+
+	// 未知の kid のトークンを続けて受け取っても JWKS を取得し直さない
+	idp.Header = func(h map[string]interface{}) { h["kid"] = "rsa-unknown" }
+	for range 3 {
+		if _, err := login(t, idp, p, testVerifier); err == nil {
+			t.Fatal("未知の kid のトークンを受け付けました")
+		}
+	}
+	if got := idp.JWKSFetches(); got != 1 {
+		t.Errorf("JWKS の取得回数 = %d, want 1", got)
+	}
+}
@@ -0,0 +1,172 @@
+// Package payment は PaymentGateway の実装を提供します
+package payment
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"sync"
+
+	"github.com/sotaheavymetal21/rabbit-cart/backend/internal/domain/entity"
+)
+
+// FakeScenario はフェイクゲートウェイが与信の依頼にどう応答するかを表します
+type FakeScenario string
+
+const (
+	FakeScenarioApprove   FakeScenario = "approve"   // 与信を承認する
+	FakeScenarioDecline   FakeScenario = "decline"   // カード会社に拒否されたとして failed を返す
+	FakeScenarioChallenge FakeScenario = "challenge" // 3D セキュアの本人認証が必要として requires_action を返す
+	FakeScenarioError     FakeScenario = "error"     // 通信に失敗したとしてエラーを返す
+)
+
+// fakePaymentMethodPrefix は支払い方法のトークンでシナリオを指定する場合の接頭辞です (例: "fake_decline")
+const fakePaymentMethodPrefix = "fake_"
+
+// ErrFakeGatewayUnavailable はシナリオ error で返すエラーです
+var ErrFakeGatewayUnavailable = errors.New("フェイク決済ゲートウェイ: 接続できませんでした")
+
+// fakePayment はフェイクゲートウェイが保持する決済の状態です
+type fakePayment struct {
+	status   entity.PaymentStatus
+	amount   int
+	captured int
+	refunded int
+}
+
+// FakeGateway は外部と通信せずにメモリ上で決済を処理する PaymentGateway の実装です (開発・テスト用)
+// 既定のシナリオで応答し、支払い方法のトークンに "fake_decline" などを指定した場合はそのシナリオで応答します
+type FakeGateway struct {
+	scenario     FakeScenario
+	challengeURL string
+
+	mu       sync.Mutex
+	payments map[string]*fakePayment
+}
+
+// NewFakeGateway はフェイクの決済ゲートウェイを生成します
+// challengeURL は本人認証が必要な場合に返す認証画面の URL です (決済IDをクエリパラメータ payment_id に付けます)
+func NewFakeGateway(scenario FakeScenario, challengeURL string) (*FakeGateway, error) {
+	if !scenario.isValid() {
+		return nil, fmt.Errorf("不明なシナリオです: %s (approve, decline, challenge, error)", scenario)
+	}
+	return &FakeGateway{
+		scenario:     scenario,
+		challengeURL: challengeURL,
+		payments:     make(map[string]*fakePayment),
+	}, nil
+}
+
+// Name は決済サービスの名前を返します
+func (g *FakeGateway) Name() string {
+	return "fake"
+}
+
+// Authorize はシナリオに従って与信の依頼に応答します
+func (g *FakeGateway) Authorize(ctx context.Context, req *entity.PaymentAuthorization) (*entity.PaymentResult, error) {
+	scenario := g.scenario
+	if name, ok := strings.CutPrefix(req.PaymentMethod, fakePaymentMethodPrefix); ok && FakeScenario(name).isValid() {
+		scenario = FakeScenario(name)
+	}
+	if scenario == FakeScenarioError {
+		return nil, ErrFakeGatewayUnavailable
+	}
+
+	id, err := newFakePaymentID()
+	if err != nil {
+		return nil, err
+	}
+	result := &entity.PaymentResult{ProviderPaymentID: id}
+	switch scenario {
+	case FakeScenarioDecline:
+		result.Status = entity.PaymentStatusFailed
+		result.FailureCode = "card_declined"
+	case FakeScenarioChallenge:
+		result.Status = entity.PaymentStatusRequiresAction
+		result.NextActionURL = g.challengeURL + "?payment_id=" + url.QueryEscape(id)
+	default:
+		result.Status = entity.PaymentStatusAuthorized
+	}
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.payments[id] = &fakePayment{status: result.Status, amount: req.Amount}
+	return result, nil
+}
+
+// Capture は与信済みの決済の売上を確定します
+func (g *FakeGateway) Capture(ctx context.Context, providerPaymentID string, amount int) (*entity.PaymentResult, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	p := g.lookup(providerPaymentID, fakePayment{status: entity.PaymentStatusAuthorized, amount: amount})
+	if p.status != entity.PaymentStatusAuthorized {
+		return nil, fmt.Errorf("フェイク決済ゲートウェイ: %s の決済は売上を確定できません", p.status)
+	}
+	if amount <= 0 || amount > p.amount {
+		return nil, fmt.Errorf("フェイク決済ゲートウェイ: 与信額 %d を超えて売上を確定できません (%d)", p.amount, amount)
+	}
+	p.status, p.captured = entity.PaymentStatusCaptured, amount
+	return &entity.PaymentResult{ProviderPaymentID: providerPaymentID, Status: p.status}, nil
+}
+
+// Void は売上確定前の与信を取り消します
+func (g *FakeGateway) Void(ctx context.Context, providerPaymentID string) (*entity.PaymentResult, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	p := g.lookup(providerPaymentID, fakePayment{status: entity.PaymentStatusAuthorized})
+	if p.status != entity.PaymentStatusAuthorized && p.status != entity.PaymentStatusRequiresAction {
+		return nil, fmt.Errorf("フェイク決済ゲートウェイ: %s の決済は取り消せません", p.status)
+	}
+	p.status = entity.PaymentStatusVoided
+	return &entity.PaymentResult{ProviderPaymentID: providerPaymentID, Status: p.status}, nil
+}
+
+// Refund は売上確定済みの決済から返金します (全額を返金すると refunded になります)
+func (g *FakeGateway) Refund(ctx context.Context, providerPaymentID string, amount int) (*entity.PaymentResult, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	p := g.lookup(providerPaymentID, fakePayment{status: entity.PaymentStatusCaptured, amount: amount, captured: amount})
+	if p.status != entity.PaymentStatusCaptured {
+		return nil, fmt.Errorf("フェイク決済ゲートウェイ: %s の決済は返金できません", p.status)
+	}
+	if amount <= 0 || p.refunded+amount > p.captured {
+		return nil, fmt.Errorf("フェイク決済ゲートウェイ: 返金額が売上額を超えています (売上 %d, 返金済み %d, 返金 %d)", p.captured, p.refunded, amount)
+	}
+	p.refunded += amount
+	if p.refunded == p.captured {
+		p.status = entity.PaymentStatusRefunded
+	}
+	return &entity.PaymentResult{ProviderPaymentID: providerPaymentID, Status: p.status}, nil
+}
+
+// lookup は決済の状態を返します (g.mu を取得してから呼び出してください)
+// プロセスの再起動で状態が失われた決済は、依頼された操作ができる状態 (fallback) として扱います
+func (g *FakeGateway) lookup(providerPaymentID string, fallback fakePayment) *fakePayment {
+	p, ok := g.payments[providerPaymentID]
+	if !ok {
+		p = &fallback
+		g.payments[providerPaymentID] = p
+	}
+	return p
+}
+
+func (s FakeScenario) isValid() bool {
+	switch s {
+	case FakeScenarioApprove, FakeScenarioDecline, FakeScenarioChallenge, FakeScenarioError:
+		return true
+	}
+	return false
+}
+
+// newFakePaymentID はゲートウェイでの決済IDを生成します
+func newFakePaymentID() (string, error) {
+	b := make([]byte, 12)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return "fakepay_" + hex.EncodeToString(b), nil
+}
@@ -0,0 +1,113 @@
+package payment
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/sotaheavymetal21/rabbit-cart/backend/internal/domain/entity"
+)
+
+// SignatureHeader は Webhook の署名を送るヘッダーです
+// 値は "t=<送信時刻の UNIX 秒>,v1=<署名>" の形式で、署名は "<送信時刻>.<ボディ>" の HMAC-SHA256 (16進数) です
+// 署名鍵の切り替え中は v1 を複数含むことがあります
+const SignatureHeader = "Rabbit-Signature"
+
+// webhookPayload は Webhook のボディの形式です
+type webhookPayload struct {
+	ID   string `json:"id"`
+	Type string `json:"type"`
+	Data struct {
+		PaymentID   string `json:"payment_id"`
+		FailureCode string `json:"failure_code"`
+		Amount      int    `json:"amount"` // 返金のイベントで返金された金額
+	} `json:"data"`
+}
+
+// HMACWebhookVerifier は共有の秘密鍵による HMAC-SHA256 の署名を検証する PaymentWebhookVerifier の実装です
+type HMACWebhookVerifier struct {
+	provider  string
+	secret    []byte
+	tolerance time.Duration
+}
+
+// NewHMACWebhookVerifier は provider の Webhook を検証する HMACWebhookVerifier を生成します
+// tolerance は送信時刻と受信時刻のずれの許容範囲で、これより古い Webhook は再送攻撃として拒否します
+// secret が空の場合は全ての Webhook を拒否します
+func NewHMACWebhookVerifier(provider, secret string, tolerance time.Duration) *HMACWebhookVerifier {
+	return &HMACWebhookVerifier{provider: provider, secret: []byte(secret), tolerance: tolerance}
+}
+
+// Verify は署名と送信時刻を検証し、イベントを返します
+func (v *HMACWebhookVerifier) Verify(payload []byte, signature string, now time.Time) (*entity.PaymentEvent, error) {
+	if len(v.secret) == 0 {
+		return nil, entity.ErrInvalidWebhookSignature
+	}
+	timestamp, signatures := parseSignatureHeader(signature)
+	if timestamp == "" || len(signatures) == 0 {
+		return nil, entity.ErrInvalidWebhookSignature
+	}
+
+	expected := v.sign(timestamp, payload)
+	valid := false
+	for _, s := range signatures {
+		if decoded, err := hex.DecodeString(s); err == nil && hmac.Equal(decoded, expected) {
+			valid = true
+			break
+		}
+	}
+	if !valid {
+		return nil, entity.ErrInvalidWebhookSignature
+	}
+
+	// 署名済みの時刻を確認するため、署名の検証後に判定する
+	sec, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return nil, entity.ErrInvalidWebhookSignature
+	}
+	if skew := now.Sub(time.Unix(sec, 0)); skew > v.tolerance || skew < -v.tolerance {
+		return nil, entity.ErrWebhookTimestampOutOfRange
+	}
+
+	var body webhookPayload
+	if err := json.Unmarshal(payload, &body); err != nil || body.ID == "" || body.Type == "" || body.Data.PaymentID == "" {
+		return nil, entity.ErrInvalidWebhookPayload
+	}
+	return &entity.PaymentEvent{
+		Provider:          v.provider,
+		EventID:           body.ID,
+		Type:              entity.PaymentEventType(body.Type),
+		ProviderPaymentID: body.Data.PaymentID,
+		FailureCode:       body.Data.FailureCode,
+		Amount:            body.Data.Amount,
+		Payload:           string(payload),
+	}, nil
+}
+
+func (v *HMACWebhookVerifier) sign(timestamp string, payload []byte) []byte {
+	mac := hmac.New(sha256.New, v.secret)
+	mac.Write([]byte(timestamp + "."))
+	mac.Write(payload)
+	return mac.Sum(nil)
+}
+
+// parseSignatureHeader は SignatureHeader の値から送信時刻と署名を取り出します
+func parseSignatureHeader(header string) (timestamp string, signatures []string) {
+	for _, part := range strings.Split(header, ",") {
+		key, value, ok := strings.Cut(strings.TrimSpace(part), "=")
+		if !ok {
+			continue
+		}
+		switch key {
+		case "t":
+			timestamp = value
+		case "v1":
+			signatures = append(signatures, value)
+		}
+	}
+	return timestamp, signatures
+}
@@ -0,0 +1,60 @@
+package payment
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/sotaheavymetal21/rabbit-cart/backend/internal/domain/entity"
+)
+
+// signWebhook は secrets のそれぞれで署名した SignatureHeader の値を返します
+func signWebhook(at time.Time, payload string, secrets ...string) string {
+	timestamp := strconv.FormatInt(at.Unix(), 10)
+	header := "t=" + timestamp
+	for _, secret := range secrets {
+		mac := hmac.New(sha256.New, []byte(secret))
+		mac.Write([]byte(timestamp + "." + payload))
+		header += ",v1=" + hex.EncodeToString(mac.Sum(nil))
+	}
+	return header
+}
+
+func TestHMACWebhookVerifier(t *testing.T) {
+	now := time.Now()
+	verifier := NewHMACWebhookVerifier("fake", "whsec_test", 5*time.Minute)
+	refunded := `{"id":"evt_1","type":"payment.refunded","data":{"payment_id":"pay_1","amount":3000}}`
+	noPayment := `{"id":"evt_1","type":"payment.refunded","data":{}}`
+
+	tests := []struct {
+		name      string
+		payload   string
+		signature string
+		want      error
+	}{
+		{"正しい署名", refunded, signWebhook(now, refunded, "whsec_test"), nil},
+		{"鍵の切り替え中", refunded, signWebhook(now, refunded, "whsec_old", "whsec_test"), nil},
+		{"異なる鍵の署名", refunded, signWebhook(now, refunded, "whsec_other"), entity.ErrInvalidWebhookSignature},
+		{"署名が無い", refunded, "", entity.ErrInvalidWebhookSignature},
+		{"古い送信時刻", refunded, signWebhook(now.Add(-10*time.Minute), refunded, "whsec_test"), entity.ErrWebhookTimestampOutOfRange},
+		{"決済IDが無い", noPayment, signWebhook(now, noPayment, "whsec_test"), entity.ErrInvalidWebhookPayload},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			event, err := verifier.Verify([]byte(tt.payload), tt.signature, now)
+			if !errors.Is(err, tt.want) {
+				t.Fatalf("Verify = %v, want %v", err, tt.want)
+			}
+			if err != nil {
+				return
+			}
+			if event.EventID != "evt_1" || event.Type != entity.PaymentEventRefunded || event.ProviderPaymentID != "pay_1" || event.Amount != 3000 {
+				t.Errorf("イベント = %+v", event)
+			}
+		})
+	}
+}
@@ -0,0 +1,123 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"github.com/sotaheavymetal21/rabbit-cart/backend/internal/domain/entity"
+	"github.com/sotaheavymetal21/rabbit-cart/backend/internal/domain/repository"
+	"gorm.io/gorm"
+)
+
+type addressRepository struct {
+	db *gorm.DB
+}
+
+// NewAddressRepository は AddressRepository の実装を生成します
+func NewAddressRepository(db *gorm.DB) repository.AddressRepository {
+	return &addressRepository{db: db}
+}
+
+// FindAllByUserID はユーザーの住所を既定の配送先・登録日時の新しい順に取得します
+func (r *addressRepository) FindAllByUserID(ctx context.Context, userID string) ([]*entity.UserAddress, error) {
+	var addresses []*entity.UserAddress
+	if err := r.db.WithContext(ctx).
+		Where("user_id = ?", userID).
+		Order("is_default desc, created_at desc").
+		Find(&addresses).Error; err != nil {
+		return nil, err
+	}
+	return addresses, nil
+}
+
+// FindByID はユーザーの住所をIDで取得します
+func (r *addressRepository) FindByID(ctx context.Context, userID, id string) (*entity.UserAddress, error) {
+	var address entity.UserAddress
+	if err := r.db.WithContext(ctx).First(&address, "id = ? AND user_id = ?", id, userID).Error; err != nil {
+		return nil, translateError(err)
+	}
+	return &address, nil
+}
+
+// FindDefault はユーザーの既定の配送先を取得します
+func (r *addressRepository) FindDefault(ctx context.Context, userID string) (*entity.UserAddress, error) {
+	var address entity.UserAddress
+	if err := r.db.WithContext(ctx).First(&address, "user_id = ? AND is_default", userID).Error; err != nil {
+		return nil, translateError(err)
+	}
+	return &address, nil
+}
+
+// Create は住所を保存します
+func (r *addressRepository) Create(ctx context.Context, address *entity.UserAddress) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if !address.IsDefault {
+			var count int64
+			if err := tx.Model(&entity.UserAddress{}).Where("user_id = ?", address.UserID).Count(&count).Error; err != nil {
+				return err
+			}
+			address.IsDefault = count == 0
+		}
+		if address.IsDefault {
+			if err := clearDefaultAddress(tx, address.UserID); err != nil {
+				return err
+			}
+		}
+		return tx.Create(address).Error
+	})
+}
+
+// Update は住所を更新します
+func (r *addressRepository) Update(ctx context.Context, address *entity.UserAddress) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if address.IsDefault {
+			if err := clearDefaultAddress(tx, address.UserID); err != nil {
+				return err
+			}
+		}
+		result := tx.Model(&entity.UserAddress{}).
+			Where("id = ? AND user_id = ?", address.ID, address.UserID).
+			Select("name", "postal_code", "prefecture", "city", "line1", "line2", "phone", "is_default").
+			Updates(address)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return repository.ErrNotFound
+		}
+		return nil
+	})
+}
+
+// Delete はユーザーの住所を削除します
+func (r *addressRepository) Delete(ctx context.Context, userID, id string) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var address entity.UserAddress
+		if err := tx.First(&address, "id = ? AND user_id = ?", id, userID).Error; err != nil {
+			return translateError(err)
+		}
+		if err := tx.Delete(&address).Error; err != nil {
+			return err
+		}
+		if !address.IsDefault {
+			return nil
+		}
+
+		var next entity.UserAddress
+		if err := tx.Where("user_id = ?", userID).Order("created_at desc").First(&next).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return nil
+			}
+			return err
+		}
+		return tx.Model(&next).Update("is_default", true).Error
+	})
+}
+
+// clearDefaultAddress はユーザーの既定の配送先を解除します
+// 既定の配送先はユーザーごとに1件 (部分ユニークインデックス) のため、新しい既定を設定する前に呼び出します
+func clearDefaultAddress(tx *gorm.DB, userID string) error {
+	return tx.Model(&entity.UserAddress{}).
+		Where("user_id = ? AND is_default", userID).
+		Update("is_default", false).Error
+}
@@ -0,0 +1,70 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/sotaheavymetal21/rabbit-cart/backend/internal/domain/entity"
+	"github.com/sotaheavymetal21/rabbit-cart/backend/internal/domain/repository"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type cartRepository struct {
+	db *gorm.DB
+}
+
+// NewCartRepository は CartRepository の実装を生成します
+func NewCartRepository(db *gorm.DB) repository.CartRepository {
+	return &cartRepository{db: db}
+}
+
+// FindOrCreateByUserID は指定されたユーザーのカートを取得します（明細と商品情報を含む）
+func (r *cartRepository) FindOrCreateByUserID(ctx context.Context, userID string) (*entity.Cart, error) {
+	db := r.db.WithContext(ctx)
+
+	// 同時リクエストでカートが二重に作成されないよう、user_id の一意制約に任せて作成する
+	if err := db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "user_id"}},
+		DoNothing: true,
+	}).Create(&entity.Cart{UserID: userID}).Error; err != nil {
+		return nil, err
+	}
+
+	var cart entity.Cart
+	if err := db.
+		Preload("Items", func(db *gorm.DB) *gorm.DB { return db.Order("created_at asc") }).
+		Preload("Items.Product").
+		First(&cart, "user_id = ?", userID).Error; err != nil {
+		return nil, translateError(err)
+	}
+	return &cart, nil
+}
+
+// SetItemQuantity はカート内の商品の数量を設定します
+func (r *cartRepository) SetItemQuantity(ctx context.Context, cartID, productID string, quantity int) error {
+	item := &entity.CartItem{
+		CartID:    cartID,
+		ProductID: productID,
+		Quantity:  quantity,
+	}
+	return r.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "cart_id"}, {Name: "product_id"}},
+		DoUpdates: clause.Assignments(map[string]interface{}{
+			"quantity":   quantity,
+			"updated_at": time.Now(),
+		}),
+	}).Create(item).Error
+}
+
+// RemoveItem はカートから指定された商品を削除します
+func (r *cartRepository) RemoveItem(ctx context.Context, cartID, productID string) error {
+	return r.db.WithContext(ctx).
+		Where("cart_id = ? AND product_id = ?", cartID, productID).
+		Delete(&entity.CartItem{}).Error
+}
+
+// Clear はカート内の全ての明細を削除します
+func (r *cartRepository) Clear(ctx context.Context, cartID string) error {
+	return r.db.WithContext(ctx).Where("cart_id = ?", cartID).Delete(&entity.CartItem{}).Error
+}
@@ -0,0 +1,74 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/sotaheavymetal21/rabbit-cart/backend/internal/domain/entity"
+	"github.com/sotaheavymetal21/rabbit-cart/backend/internal/domain/repository"
+	"gorm.io/gorm"
+)
+
+type emailVerificationRepository struct {
+	db *gorm.DB
+}
+
+// NewEmailVerificationRepository は EmailVerificationRepository の実装を生成します
+func NewEmailVerificationRepository(db *gorm.DB) repository.EmailVerificationRepository {
+	return &emailVerificationRepository{db: db}
+}
+
+// Create はトークンを保存します
+func (r *emailVerificationRepository) Create(ctx context.Context, token *entity.EmailVerificationToken) error {
+	return r.db.WithContext(ctx).Create(token).Error
+}
+
+// FindByHash はハッシュ値でトークンを検索します
+func (r *emailVerificationRepository) FindByHash(ctx context.Context, tokenHash string) (*entity.EmailVerificationToken, error) {
+	var token entity.EmailVerificationToken
+	if err := r.db.WithContext(ctx).First(&token, "token_hash = ?", tokenHash).Error; err != nil {
+		return nil, translateError(err)
+	}
+	return &token, nil
+}
+
+// FindLatestByUserID はユーザーに最後に発行したトークンを取得します
+func (r *emailVerificationRepository) FindLatestByUserID(ctx context.Context, userID string) (*entity.EmailVerificationToken, error) {
+	var token entity.EmailVerificationToken
+	if err := r.db.WithContext(ctx).Where("user_id = ?", userID).Order("created_at desc").First(&token).Error; err != nil {
+		return nil, translateError(err)
+	}
+	return &token, nil
+}
+
+// Consume はトークンを使用済みにしてユーザーを確認済みにします (メールアドレス変更の場合はメールアドレスも変更します)
+func (r *emailVerificationRepository) Consume(ctx context.Context, token *entity.EmailVerificationToken) error {
+	now := time.Now()
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&entity.EmailVerificationToken{}).
+			Where("id = ? AND used_at IS NULL AND expires_at > ?", token.ID, now).
+			Update("used_at", now)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return repository.ErrNotFound
+		}
+		// 同じユーザーに発行済みの他のトークンも使えなくする
+		if err := tx.Model(&entity.EmailVerificationToken{}).
+			Where("user_id = ? AND used_at IS NULL", token.UserID).
+			Update("used_at", now).Error; err != nil {
+			return err
+		}
+
+		if token.NewEmail != "" {
+			return tx.Model(&entity.User{}).Where("id = ?", token.UserID).Updates(map[string]interface{}{
+				"email":       token.NewEmail,
+				"verified_at": now,
+			}).Error
+		}
+		return tx.Model(&entity.User{}).
+			Where("id = ? AND verified_at IS NULL", token.UserID).
+			Update("verified_at", now).Error
+	})
+}
@@ -0,0 +1,28 @@
+package repository
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/sotaheavymetal21/rabbit-cart/backend/internal/domain/repository"
+	"gorm.io/gorm"
+)
+
+// translateError は GORM・PostgreSQL のエラーをドメインのエラーに変換します
+// ユースケースがデータベースのエラーに依存しないよう、リポジトリは検索・登録のエラーをこの関数に通して返します
+func translateError(err error) error {
+	var pgErr *pgconn.PgError
+	switch {
+	case err == nil:
+		return nil
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		return repository.ErrNotFound
+	case errors.As(err, &pgErr) && pgErr.Code == "22P02":
+		// UUID の形式でない ID で検索された場合は、該当するレコードが無いものとして扱う
+		return repository.ErrNotFound
+	case errors.As(err, &pgErr) && pgErr.Code == "23505":
+		return fmt.Errorf("%w: %s", repository.ErrDuplicate, pgErr.ConstraintName)
+	}
+	return err
+}
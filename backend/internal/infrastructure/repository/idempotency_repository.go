@@ -0,0 +1,60 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/sotaheavymetal21/rabbit-cart/backend/internal/domain/entity"
+	"github.com/sotaheavymetal21/rabbit-cart/backend/internal/domain/repository"
+	"gorm.io/gorm"
+)
+
+type idempotencyKeyRepository struct {
+	db *gorm.DB
+}
+
+// NewIdempotencyKeyRepository は IdempotencyKeyRepository の実装を生成します
+func NewIdempotencyKeyRepository(db *gorm.DB) repository.IdempotencyKeyRepository {
+	return &idempotencyKeyRepository{db: db}
+}
+
+// Create は冪等キーを保存します (同じユーザー・キーの期限切れのもの・ロックの期限が切れた処理中のものは置き換えます)
+// 同時に同じキーで保存した場合は一意制約により一方が ErrDuplicate になります
+func (r *idempotencyKeyRepository) Create(ctx context.Context, key *entity.IdempotencyKey) error {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("user_id = ? AND key = ? AND expires_at <= ?", key.UserID, key.Key, time.Now()).
+			Delete(&entity.IdempotencyKey{}).Error; err != nil {
+			return err
+		}
+		return tx.Create(key).Error
+	})
+	return translateError(err)
+}
+
+// Find はユーザーの冪等キーを取得します
+func (r *idempotencyKeyRepository) Find(ctx context.Context, userID, key string) (*entity.IdempotencyKey, error) {
+	var record entity.IdempotencyKey
+	if err := r.db.WithContext(ctx).First(&record, "user_id = ? AND key = ?", userID, key).Error; err != nil {
+		return nil, translateError(err)
+	}
+	return &record, nil
+}
+
+// Complete は冪等キーにリクエストの処理結果のレスポンスを保存し、有効期限を expiresAt に延長します
+func (r *idempotencyKeyRepository) Complete(ctx context.Context, id string, status int, body []byte, expiresAt time.Time) error {
+	return r.db.WithContext(ctx).Model(&entity.IdempotencyKey{}).Where("id = ?", id).Updates(map[string]interface{}{
+		"response_status": status,
+		"response_body":   body,
+		"expires_at":      expiresAt,
+	}).Error
+}
+
+// Delete は冪等キーを削除します
+func (r *idempotencyKeyRepository) Delete(ctx context.Context, id string) error {
+	return r.db.WithContext(ctx).Delete(&entity.IdempotencyKey{}, "id = ?", id).Error
+}
+
+// PurgeExpired は有効期限を過ぎた冪等キーを削除します
+func (r *idempotencyKeyRepository) PurgeExpired(ctx context.Context, now time.Time) error {
+	return r.db.WithContext(ctx).Where("expires_at < ?", now).Delete(&entity.IdempotencyKey{}).Error
+}
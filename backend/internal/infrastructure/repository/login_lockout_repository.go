@@ -0,0 +1,23 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/sotaheavymetal21/rabbit-cart/backend/internal/domain/entity"
+	"github.com/sotaheavymetal21/rabbit-cart/backend/internal/domain/repository"
+	"gorm.io/gorm"
+)
+
+type loginLockoutRepository struct {
+	db *gorm.DB
+}
+
+// NewLoginLockoutRepository は LoginLockoutRepository の実装を生成します
+func NewLoginLockoutRepository(db *gorm.DB) repository.LoginLockoutRepository {
+	return &loginLockoutRepository{db: db}
+}
+
+// Create は監査記録を保存します
+func (r *loginLockoutRepository) Create(ctx context.Context, lockout *entity.LoginLockout) error {
+	return r.db.WithContext(ctx).Create(lockout).Error
+}
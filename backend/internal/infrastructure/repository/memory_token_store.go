@@ -0,0 +1,242 @@
+package repository
+
+import (
+	"context"
+	"crypto/rand"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/sotaheavymetal21/rabbit-cart/backend/internal/domain/entity"
+	"github.com/sotaheavymetal21/rabbit-cart/backend/internal/domain/repository"
+)
+
+// memoryTokenStore はメモリ上でリフレッシュトークン・アクセストークンの失効リスト・ログインセッションを保持します
+// TokenRepository と SessionRepository の両方を実装し、データベースを使わないテストやローカル開発で使用します
+type memoryTokenStore struct {
+	mu            sync.Mutex
+	refreshTokens map[string]*entity.RefreshToken // ハッシュ値 → トークン
+	revoked       map[string]time.Time            // jti → 元のトークンの有効期限
+	sessions      map[string]*entity.UserSession  // セッションID → セッション
+}
+
+// NewMemoryTokenStore はメモリ上で動作する TokenRepository と SessionRepository を生成します
+// セッションの失効で同じ系列のリフレッシュトークンも失効させるため、2つは同じデータを共有します
+func NewMemoryTokenStore() (repository.TokenRepository, repository.SessionRepository) {
+	s := &memoryTokenStore{
+		refreshTokens: make(map[string]*entity.RefreshToken),
+		revoked:       make(map[string]time.Time),
+		sessions:      make(map[string]*entity.UserSession),
+	}
+	return s, s
+}
+
+// CreateRefreshToken はリフレッシュトークンを保存します
+func (s *memoryTokenStore) CreateRefreshToken(ctx context.Context, token *entity.RefreshToken) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.createRefreshToken(token)
+}
+
+func (s *memoryTokenStore) createRefreshToken(token *entity.RefreshToken) error {
+	if _, ok := s.refreshTokens[token.TokenHash]; ok {
+		return repository.ErrDuplicate
+	}
+	if token.ID == "" {
+		id, err := newMemoryID()
+		if err != nil {
+			return err
+		}
+		token.ID = id
+	}
+	if token.CreatedAt.IsZero() {
+		token.CreatedAt = time.Now()
+	}
+	copied := *token
+	s.refreshTokens[token.TokenHash] = &copied
+	return nil
+}
+
+// FindRefreshToken はハッシュ値でリフレッシュトークンを検索します
+func (s *memoryTokenStore) FindRefreshToken(ctx context.Context, tokenHash string) (*entity.RefreshToken, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	token, ok := s.refreshTokens[tokenHash]
+	if !ok {
+		return nil, repository.ErrNotFound
+	}
+	copied := *token
+	return &copied, nil
+}
+
+// RotateRefreshToken は old を失効させて next を保存します
+// old が既に失効している場合は entity.ErrRefreshTokenReused を返します
+func (s *memoryTokenStore) RotateRefreshToken(ctx context.Context, old, next *entity.RefreshToken) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	current, ok := s.refreshTokens[old.TokenHash]
+	if !ok || current.ID != old.ID || current.RevokedAt != nil {
+		return entity.ErrRefreshTokenReused
+	}
+	if err := s.createRefreshToken(next); err != nil {
+		return err
+	}
+	now := time.Now()
+	current.RevokedAt = &now
+	return nil
+}
+
+// RevokeRefreshTokenFamily は同じ系列のリフレッシュトークンを全て失効させます
+func (s *memoryTokenStore) RevokeRefreshTokenFamily(ctx context.Context, familyID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.revokeRefreshTokens(func(t *entity.RefreshToken) bool { return t.FamilyID == familyID })
+	return nil
+}
+
+// revokeRefreshTokens は match に一致する有効なリフレッシュトークンを失効させます
+func (s *memoryTokenStore) revokeRefreshTokens(match func(t *entity.RefreshToken) bool) {
+	now := time.Now()
+	for _, t := range s.refreshTokens {
+		if t.RevokedAt == nil && match(t) {
+			t.RevokedAt = &now
+		}
+	}
+}
+
+// RevokeAccessToken はアクセストークンを失効リストに追加します
+func (s *memoryTokenStore) RevokeAccessToken(ctx context.Context, jti string, expiresAt time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.revoked[jti]; !ok {
+		s.revoked[jti] = expiresAt
+	}
+	return nil
+}
+
+// IsAccessTokenRevoked はアクセストークンが失効リストにあるかを返します
+func (s *memoryTokenStore) IsAccessTokenRevoked(ctx context.Context, jti string) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	_, ok := s.revoked[jti]
+	return ok, nil
+}
+
+// PurgeExpired は有効期限を過ぎたリフレッシュトークンと失効リストのエントリを削除します
+func (s *memoryTokenStore) PurgeExpired(ctx context.Context, now time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for hash, t := range s.refreshTokens {
+		if t.ExpiresAt.Before(now) {
+			delete(s.refreshTokens, hash)
+		}
+	}
+	for jti, expiresAt := range s.revoked {
+		if expiresAt.Before(now) {
+			delete(s.revoked, jti)
+		}
+	}
+	return nil
+}
+
+// Create はセッションを保存します
+func (s *memoryTokenStore) Create(ctx context.Context, session *entity.UserSession) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if session.ID == "" {
+		id, err := newMemoryID()
+		if err != nil {
+			return err
+		}
+		session.ID = id
+	}
+	if _, ok := s.sessions[session.ID]; ok {
+		return repository.ErrDuplicate
+	}
+	if session.CreatedAt.IsZero() {
+		session.CreatedAt = time.Now()
+	}
+	copied := *session
+	s.sessions[session.ID] = &copied
+	return nil
+}
+
+// FindByID はIDでセッションを検索します
+func (s *memoryTokenStore) FindByID(ctx context.Context, id string) (*entity.UserSession, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	session, ok := s.sessions[id]
+	if !ok {
+		return nil, repository.ErrNotFound
+	}
+	copied := *session
+	return &copied, nil
+}
+
+// FindActiveByUserID はユーザーの有効なセッションを最終利用日時の新しい順に取得します
+func (s *memoryTokenStore) FindActiveByUserID(ctx context.Context, userID string) ([]*entity.UserSession, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var sessions []*entity.UserSession
+	for _, session := range s.sessions {
+		if session.UserID == userID && session.RevokedAt == nil {
+			copied := *session
+			sessions = append(sessions, &copied)
+		}
+	}
+	sort.Slice(sessions, func(i, j int) bool { return sessions[i].LastSeenAt.After(sessions[j].LastSeenAt) })
+	return sessions, nil
+}
+
+// Touch はセッションの最終利用日時と IP アドレスを更新します
+func (s *memoryTokenStore) Touch(ctx context.Context, id, ip string, at time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if session, ok := s.sessions[id]; ok {
+		session.LastSeenAt = at
+		session.IPAddress = ip
+	}
+	return nil
+}
+
+// Revoke はユーザーのセッションを失効させ、同じ系列のリフレッシュトークンも失効させます
+func (s *memoryTokenStore) Revoke(ctx context.Context, userID, id string) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	revoked := false
+	if session, ok := s.sessions[id]; ok && session.UserID == userID && session.RevokedAt == nil {
+		now := time.Now()
+		session.RevokedAt = &now
+		revoked = true
+	}
+	s.revokeRefreshTokens(func(t *entity.RefreshToken) bool { return t.FamilyID == id && t.UserID == userID })
+	return revoked, nil
+}
+
+// RevokeAllByUserID はユーザーの全てのセッションとリフレッシュトークンを失効させます
+func (s *memoryTokenStore) RevokeAllByUserID(ctx context.Context, userID string) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var count int64
+	now := time.Now()
+	for _, session := range s.sessions {
+		if session.UserID == userID && session.RevokedAt == nil {
+			session.RevokedAt = &now
+			count++
+		}
+	}
+	s.revokeRefreshTokens(func(t *entity.RefreshToken) bool { return t.UserID == userID })
+	return count, nil
+}
+
+// newMemoryID はデータベースの uuid_generate_v4() の代わりに UUID (v4) を生成します
+func newMemoryID() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	b[6] = b[6]&0x0f | 0x40
+	b[8] = b[8]&0x3f | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:16]), nil
+}
@@ -0,0 +1,129 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/sotaheavymetal21/rabbit-cart/backend/internal/domain/entity"
+	"github.com/sotaheavymetal21/rabbit-cart/backend/internal/domain/repository"
+	"gorm.io/gorm"
+)
+
+type mfaRepository struct {
+	db *gorm.DB
+}
+
+// NewMFARepository は MFARepository の実装を生成します
+func NewMFARepository(db *gorm.DB) repository.MFARepository {
+	return &mfaRepository{db: db}
+}
+
+// SetPendingTOTPSecret は登録途中の TOTP シークレットを保存します
+func (r *mfaRepository) SetPendingTOTPSecret(ctx context.Context, userID, secret string) error {
+	return r.db.WithContext(ctx).Model(&entity.User{}).
+		Where("id = ? AND totp_enabled_at IS NULL", userID).
+		Updates(map[string]interface{}{"totp_secret": secret, "totp_last_step": 0}).Error
+}
+
+// EnableTOTP は二要素認証を有効にし、リカバリーコードを codes で置き換えます
+func (r *mfaRepository) EnableTOTP(ctx context.Context, userID string, step int64, codes []entity.MFARecoveryCode) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&entity.User{}).Where("id = ?", userID).Updates(map[string]interface{}{
+			"totp_enabled_at": time.Now(),
+			"totp_last_step":  step,
+		}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("user_id = ?", userID).Delete(&entity.MFARecoveryCode{}).Error; err != nil {
+			return err
+		}
+		return tx.Create(&codes).Error
+	})
+}
+
+// DisableTOTP は二要素認証を無効にし、シークレットとリカバリーコードを削除します
+func (r *mfaRepository) DisableTOTP(ctx context.Context, userID string) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&entity.User{}).Where("id = ?", userID).Updates(map[string]interface{}{
+			"totp_secret":     "",
+			"totp_enabled_at": nil,
+			"totp_last_step":  0,
+		}).Error; err != nil {
+			return err
+		}
+		return tx.Where("user_id = ?", userID).Delete(&entity.MFARecoveryCode{}).Error
+	})
+}
+
+// AdvanceTOTPStep は最後に使用したコードの時間ステップを step に進めます
+// 条件付き UPDATE で判定するため、同じコードで同時にログインされても成功するのは1回だけです
+func (r *mfaRepository) AdvanceTOTPStep(ctx context.Context, userID string, step int64) (bool, error) {
+	result := r.db.WithContext(ctx).Model(&entity.User{}).
+		Where("id = ? AND totp_last_step < ?", userID, step).
+		Update("totp_last_step", step)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
+// UseRecoveryCode は未使用のリカバリーコードを使用済みにします
+func (r *mfaRepository) UseRecoveryCode(ctx context.Context, userID, codeHash string) (bool, error) {
+	result := r.db.WithContext(ctx).Model(&entity.MFARecoveryCode{}).
+		Where("user_id = ? AND code_hash = ? AND used_at IS NULL", userID, codeHash).
+		Update("used_at", time.Now())
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
+// CountUnusedRecoveryCodes は未使用のリカバリーコードの数を返します
+func (r *mfaRepository) CountUnusedRecoveryCodes(ctx context.Context, userID string) (int64, error) {
+	var count int64
+	err := r.db.WithContext(ctx).Model(&entity.MFARecoveryCode{}).
+		Where("user_id = ? AND used_at IS NULL", userID).
+		Count(&count).Error
+	return count, err
+}
+
+// CreateChallenge はログイン途中の状態を保存します
+func (r *mfaRepository) CreateChallenge(ctx context.Context, challenge *entity.MFAChallenge) error {
+	return r.db.WithContext(ctx).Create(challenge).Error
+}
+
+// FindChallenge はハッシュ値でログイン途中の状態を検索します
+func (r *mfaRepository) FindChallenge(ctx context.Context, tokenHash string) (*entity.MFAChallenge, error) {
+	var challenge entity.MFAChallenge
+	if err := r.db.WithContext(ctx).First(&challenge, "token_hash = ?", tokenHash).Error; err != nil {
+		return nil, translateError(err)
+	}
+	return &challenge, nil
+}
+
+// RecordChallengeFailure はコードの入力失敗を記録し、失敗回数を返します
+func (r *mfaRepository) RecordChallengeFailure(ctx context.Context, id string) (int, error) {
+	var challenge entity.MFAChallenge
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&entity.MFAChallenge{}).Where("id = ?", id).
+			Update("attempts", gorm.Expr("attempts + 1")).Error; err != nil {
+			return err
+		}
+		return translateError(tx.Select("attempts").First(&challenge, "id = ?", id).Error)
+	})
+	return challenge.Attempts, err
+}
+
+// ConsumeChallenge はログイン途中の状態を使用済みにします
+func (r *mfaRepository) ConsumeChallenge(ctx context.Context, id string) error {
+	result := r.db.WithContext(ctx).Model(&entity.MFAChallenge{}).
+		Where("id = ? AND used_at IS NULL", id).
+		Update("used_at", time.Now())
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return repository.ErrNotFound
+	}
+	return nil
+}
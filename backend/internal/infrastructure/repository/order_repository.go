@@ -2,6 +2,7 @@ package repository
 
 import (
 	"context"
+	"sort"
 
 	"github.com/sotaheavymetal21/rabbit-cart/backend/internal/domain/entity"
 	"github.com/sotaheavymetal21/rabbit-cart/backend/internal/domain/repository"
@@ -26,21 +27,168 @@ func (r *orderRepository) FindAllByUserID(ctx context.Context, userID string) ([
 	return orders, nil
 }
 
-// FindByID は指定されたIDの注文を取得します（注文明細を含む）
+// FindByID は指定されたIDの注文を取得します（注文明細・ステータス履歴・決済・返金・割引の内訳を含む）
 func (r *orderRepository) FindByID(ctx context.Context, id string) (*entity.Order, error) {
 	var order entity.Order
-	if err := r.db.WithContext(ctx).Preload("OrderItems").Preload("OrderItems.Product").First(&order, "id = ?", id).Error; err != nil {
-		return nil, err
+	if err := r.db.WithContext(ctx).Preload("OrderItems").Preload("OrderItems.Product").
+		Preload("StatusHistory", func(db *gorm.DB) *gorm.DB { return db.Order("created_at asc") }).
+		Preload("Payments", func(db *gorm.DB) *gorm.DB { return db.Order("created_at asc") }).
+		Preload("Refunds", func(db *gorm.DB) *gorm.DB { return db.Order("created_at asc") }).Preload("Refunds.Items").
+		Preload("Discounts").
+		First(&order, "id = ?", id).Error; err != nil {
+		return nil, translateError(err)
 	}
 	return &order, nil
 }
 
-// Create は注文を作成します（注文明細も含む）
+// Create は注文を作成します（注文明細・割引の内訳も含む）
+// 在庫の引き当てとクーポンの利用回数の加算も同一トランザクション内で行い、在庫不足の商品や上限に達したクーポンがあれば注文全体をロールバックします
 func (r *orderRepository) Create(ctx context.Context, order *entity.Order) error {
 	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := decrementStock(tx, order.OrderItems); err != nil {
+			return err
+		}
+		if err := redeemPromotions(tx, order); err != nil {
+			return err
+		}
 		if err := tx.Create(order).Error; err != nil {
 			return err
 		}
 		return nil
 	})
 }
+
+// UpdateStatus は注文ステータスを更新し、変更履歴を記録します
+// 更新時点のステータスが history.FromStatus と異なる場合は entity.ErrOrderStatusConflict を返します
+func (r *orderRepository) UpdateStatus(ctx context.Context, history *entity.OrderStatusHistory, restoreStock bool) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&entity.Order{}).
+			Where("id = ? AND status = ?", history.OrderID, history.FromStatus).
+			Update("status", history.ToStatus)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return entity.ErrOrderStatusConflict
+		}
+
+		if err := tx.Create(history).Error; err != nil {
+			return err
+		}
+
+		if restoreStock {
+			if err := restoreOrderStock(tx, history.OrderID); err != nil {
+				return err
+			}
+			return releasePromotions(tx, history.OrderID)
+		}
+		return nil
+	})
+}
+
+// decrementStock は注文明細の数量分だけ商品の在庫を減らします
+// 条件付き UPDATE (stock >= 数量) で減算するため、同時に注文が入っても在庫がマイナスになることはありません
+func decrementStock(tx *gorm.DB, items []entity.OrderItem) error {
+	// 同じ商品が複数行に分かれていても合算して引き当てる
+	quantities := make(map[string]int)
+	for _, item := range items {
+		quantities[item.ProductID] += item.Quantity
+	}
+
+	// デッドロックを避けるため、常に商品IDの順で行ロックを取得する
+	productIDs := make([]string, 0, len(quantities))
+	for id := range quantities {
+		productIDs = append(productIDs, id)
+	}
+	sort.Strings(productIDs)
+
+	var shortages []entity.StockShortage
+	for _, id := range productIDs {
+		quantity := quantities[id]
+		result := tx.Model(&entity.Product{}).
+			Where("id = ? AND stock >= ?", id, quantity).
+			Update("stock", gorm.Expr("stock - ?", quantity))
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected > 0 {
+			continue
+		}
+
+		// 更新できなかった場合は現在の在庫を取得して不足情報を記録する
+		var product entity.Product
+		if err := tx.Select("id", "name", "stock").First(&product, "id = ?", id).Error; err != nil {
+			return translateError(err)
+		}
+		shortages = append(shortages, entity.StockShortage{
+			ProductID: product.ID,
+			Name:      product.Name,
+			Requested: quantity,
+			Available: product.Stock,
+		})
+	}
+
+	if len(shortages) > 0 {
+		return &entity.InsufficientStockError{Shortages: shortages}
+	}
+	return nil
+}
+
+// restoreOrderStock は注文明細の数量分だけ商品の在庫を戻します
+func restoreOrderStock(tx *gorm.DB, orderID string) error {
+	var items []entity.OrderItem
+	if err := tx.Where("order_id = ?", orderID).Order("product_id").Find(&items).Error; err != nil {
+		return err
+	}
+	for _, item := range items {
+		if err := tx.Model(&entity.Product{}).
+			Where("id = ?", item.ProductID).
+			Update("stock", gorm.Expr("stock + ?", item.Quantity)).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// redeemPromotions は注文で使用するクーポンの利用回数を加算します
+// 条件付き UPDATE で加算するため、同時に注文が入っても利用回数の上限を超えることはありません
+// ユーザーごとの上限は、クーポンの行ロックを取得した後に利用済みの注文 (キャンセルを除く) を数えて確認します
+func redeemPromotions(tx *gorm.DB, order *entity.Order) error {
+	for _, discount := range order.Discounts {
+		result := tx.Model(&entity.Promotion{}).
+			Where("id = ? AND (usage_limit = 0 OR used_count < usage_limit)", discount.PromotionID).
+			Update("used_count", gorm.Expr("used_count + 1"))
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return entity.ErrPromotionUsageLimitReached
+		}
+
+		var promotion entity.Promotion
+		if err := tx.Select("id", "per_user_limit").First(&promotion, "id = ?", discount.PromotionID).Error; err != nil {
+			return translateError(err)
+		}
+		if promotion.PerUserLimit == 0 || order.UserID == nil {
+			continue
+		}
+		var used int64
+		if err := tx.Model(&entity.OrderDiscount{}).
+			Joins("JOIN orders ON orders.id = order_discounts.order_id").
+			Where("order_discounts.promotion_id = ? AND orders.user_id = ? AND orders.status <> ?", discount.PromotionID, *order.UserID, entity.OrderStatusCancelled).
+			Count(&used).Error; err != nil {
+			return err
+		}
+		if int(used) >= promotion.PerUserLimit {
+			return entity.ErrPromotionPerUserLimitReached
+		}
+	}
+	return nil
+}
+
+// releasePromotions はキャンセルした注文で使用したクーポンの利用回数を戻します
+func releasePromotions(tx *gorm.DB, orderID string) error {
+	return tx.Model(&entity.Promotion{}).
+		Where("id IN (?) AND used_count > 0", tx.Model(&entity.OrderDiscount{}).Select("promotion_id").Where("order_id = ?", orderID)).
+		Update("used_count", gorm.Expr("used_count - 1")).Error
+}
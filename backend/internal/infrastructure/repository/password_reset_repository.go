@@ -0,0 +1,72 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/sotaheavymetal21/rabbit-cart/backend/internal/domain/entity"
+	"github.com/sotaheavymetal21/rabbit-cart/backend/internal/domain/repository"
+	"gorm.io/gorm"
+)
+
+type passwordResetRepository struct {
+	db *gorm.DB
+}
+
+// NewPasswordResetRepository は PasswordResetRepository の実装を生成します
+func NewPasswordResetRepository(db *gorm.DB) repository.PasswordResetRepository {
+	return &passwordResetRepository{db: db}
+}
+
+// Create はトークンを保存します
+func (r *passwordResetRepository) Create(ctx context.Context, token *entity.PasswordResetToken) error {
+	return r.db.WithContext(ctx).Create(token).Error
+}
+
+// FindByHash はハッシュ値でトークンを検索します
+func (r *passwordResetRepository) FindByHash(ctx context.Context, tokenHash string) (*entity.PasswordResetToken, error) {
+	var token entity.PasswordResetToken
+	if err := r.db.WithContext(ctx).First(&token, "token_hash = ?", tokenHash).Error; err != nil {
+		return nil, translateError(err)
+	}
+	return &token, nil
+}
+
+// Consume はトークンを使用済みにしてユーザーのパスワードを更新します
+func (r *passwordResetRepository) Consume(ctx context.Context, token *entity.PasswordResetToken, passwordHash string) error {
+	now := time.Now()
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// 条件付き UPDATE で使用済みにするため、同じトークンで同時にリセットされても成功するのは1回だけです
+		result := tx.Model(&entity.PasswordResetToken{}).
+			Where("id = ? AND used_at IS NULL AND expires_at > ?", token.ID, now).
+			Update("used_at", now)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return repository.ErrNotFound
+		}
+		// 同じユーザーに発行済みの他のトークンも使えなくする
+		if err := tx.Model(&entity.PasswordResetToken{}).
+			Where("user_id = ? AND used_at IS NULL", token.UserID).
+			Update("used_at", now).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Model(&entity.User{}).Where("id = ?", token.UserID).Updates(map[string]interface{}{
+			"password_hash":       passwordHash,
+			"password_changed_at": now,
+		}).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Model(&entity.UserSession{}).
+			Where("user_id = ? AND revoked_at IS NULL", token.UserID).
+			Update("revoked_at", now).Error; err != nil {
+			return err
+		}
+		return tx.Model(&entity.RefreshToken{}).
+			Where("user_id = ? AND revoked_at IS NULL", token.UserID).
+			Update("revoked_at", now).Error
+	})
+}
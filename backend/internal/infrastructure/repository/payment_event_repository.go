@@ -0,0 +1,88 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/sotaheavymetal21/rabbit-cart/backend/internal/domain/entity"
+	"github.com/sotaheavymetal21/rabbit-cart/backend/internal/domain/repository"
+	"gorm.io/gorm"
+)
+
+type paymentEventRepository struct {
+	db *gorm.DB
+}
+
+// NewPaymentEventRepository は PaymentEventRepository の実装を生成します
+func NewPaymentEventRepository(db *gorm.DB) repository.PaymentEventRepository {
+	return &paymentEventRepository{db: db}
+}
+
+// Create はイベントを保存します
+// 同じイベントを同時に受け取った場合も一意制約により一方が ErrDuplicate になります
+func (r *paymentEventRepository) Create(ctx context.Context, event *entity.PaymentEvent) error {
+	return translateError(r.db.WithContext(ctx).Create(event).Error)
+}
+
+// FindByEventID は決済サービスとイベントIDでイベントを取得します
+func (r *paymentEventRepository) FindByEventID(ctx context.Context, provider, eventID string) (*entity.PaymentEvent, error) {
+	var event entity.PaymentEvent
+	if err := r.db.WithContext(ctx).First(&event, "provider = ? AND event_id = ?", provider, eventID).Error; err != nil {
+		return nil, translateError(err)
+	}
+	return &event, nil
+}
+
+// Claim は処理済みでないイベントの処理を開始した日時を claimedAt から now に更新します
+// 条件付き UPDATE のため、同じイベントの再送を同時に受け取っても処理を開始できるのは一方だけです
+func (r *paymentEventRepository) Claim(ctx context.Context, id string, claimedAt, now time.Time) (bool, error) {
+	result := r.db.WithContext(ctx).Model(&entity.PaymentEvent{}).
+		Where("id = ? AND claimed_at = ? AND processed_at IS NULL", id, claimedAt).
+		Update("claimed_at", now)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected == 1, nil
+}
+
+// MarkProcessed はイベントを処理済みにします
+func (r *paymentEventRepository) MarkProcessed(ctx context.Context, id string, at time.Time) error {
+	return r.db.WithContext(ctx).Model(&entity.PaymentEvent{}).Where("id = ?", id).Update("processed_at", at).Error
+}
+
+// CreateDeadLetter は処理に失敗したイベントを記録します
+func (r *paymentEventRepository) CreateDeadLetter(ctx context.Context, deadLetter *entity.PaymentDeadLetter) error {
+	return translateError(r.db.WithContext(ctx).Omit("Event").Create(deadLetter).Error)
+}
+
+// UpdateDeadLetter はデッドレターの再処理の結果を更新します
+func (r *paymentEventRepository) UpdateDeadLetter(ctx context.Context, deadLetter *entity.PaymentDeadLetter) error {
+	return r.db.WithContext(ctx).Omit("Event").Save(deadLetter).Error
+}
+
+// FindDeadLetter はデッドレターをイベントと共に取得します
+func (r *paymentEventRepository) FindDeadLetter(ctx context.Context, id string) (*entity.PaymentDeadLetter, error) {
+	var deadLetter entity.PaymentDeadLetter
+	if err := r.db.WithContext(ctx).Preload("Event").First(&deadLetter, "id = ?", id).Error; err != nil {
+		return nil, translateError(err)
+	}
+	return &deadLetter, nil
+}
+
+// FindDeadLetterByEventID はイベントのデッドレターを取得します
+func (r *paymentEventRepository) FindDeadLetterByEventID(ctx context.Context, paymentEventID string) (*entity.PaymentDeadLetter, error) {
+	var deadLetter entity.PaymentDeadLetter
+	if err := r.db.WithContext(ctx).First(&deadLetter, "payment_event_id = ?", paymentEventID).Error; err != nil {
+		return nil, translateError(err)
+	}
+	return &deadLetter, nil
+}
+
+// ListUnresolvedDeadLetters は再処理に成功していないデッドレターを古い順に取得します
+func (r *paymentEventRepository) ListUnresolvedDeadLetters(ctx context.Context) ([]*entity.PaymentDeadLetter, error) {
+	var deadLetters []*entity.PaymentDeadLetter
+	if err := r.db.WithContext(ctx).Preload("Event").Where("resolved_at IS NULL").Order("created_at asc").Find(&deadLetters).Error; err != nil {
+		return nil, err
+	}
+	return deadLetters, nil
+}
@@ -0,0 +1,46 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/sotaheavymetal21/rabbit-cart/backend/internal/domain/entity"
+	"github.com/sotaheavymetal21/rabbit-cart/backend/internal/domain/repository"
+	"gorm.io/gorm"
+)
+
+type paymentRepository struct {
+	db *gorm.DB
+}
+
+// NewPaymentRepository は PaymentRepository の実装を生成します
+func NewPaymentRepository(db *gorm.DB) repository.PaymentRepository {
+	return &paymentRepository{db: db}
+}
+
+// Create は決済を保存します
+func (r *paymentRepository) Create(ctx context.Context, payment *entity.Payment) error {
+	return r.db.WithContext(ctx).Create(payment).Error
+}
+
+// Update は決済の状態を更新します
+func (r *paymentRepository) Update(ctx context.Context, payment *entity.Payment) error {
+	return r.db.WithContext(ctx).Save(payment).Error
+}
+
+// FindLatestByOrderID は注文の最新の決済を取得します
+func (r *paymentRepository) FindLatestByOrderID(ctx context.Context, orderID string) (*entity.Payment, error) {
+	var payment entity.Payment
+	if err := r.db.WithContext(ctx).Where("order_id = ?", orderID).Order("created_at desc").First(&payment).Error; err != nil {
+		return nil, translateError(err)
+	}
+	return &payment, nil
+}
+
+// FindByProviderPaymentID は決済サービスでの決済IDから決済を取得します
+func (r *paymentRepository) FindByProviderPaymentID(ctx context.Context, provider, providerPaymentID string) (*entity.Payment, error) {
+	var payment entity.Payment
+	if err := r.db.WithContext(ctx).Where("provider = ? AND provider_payment_id = ?", provider, providerPaymentID).First(&payment).Error; err != nil {
+		return nil, translateError(err)
+	}
+	return &payment, nil
+}
@@ -2,10 +2,17 @@ package repository
 
 import (
 	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"strings"
+	"time"
 
+	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/sotaheavymetal21/rabbit-cart/backend/internal/domain/entity"
 	"github.com/sotaheavymetal21/rabbit-cart/backend/internal/domain/repository"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type productRepository struct {
@@ -32,7 +39,252 @@ func (r *productRepository) FindByID(ctx context.Context, id string) (*entity.Pr
 	var product entity.Product
 	// GORM を使用してID検索
 	if err := r.db.WithContext(ctx).First(&product, "id = ?", id).Error; err != nil {
+		return nil, translateError(err)
+	}
+	return &product, nil
+}
+
+// Create は商品を作成します
+func (r *productRepository) Create(ctx context.Context, product *entity.Product) error {
+	return translateProductError(r.db.WithContext(ctx).Create(product).Error)
+}
+
+// Update は商品情報を更新します
+func (r *productRepository) Update(ctx context.Context, product *entity.Product) error {
+	result := r.db.WithContext(ctx).Model(product).
+		Select("sku", "name", "description", "price", "image_url", "category").
+		Updates(product)
+	if result.Error != nil {
+		return translateProductError(result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return repository.ErrNotFound
+	}
+	return nil
+}
+
+// Delete は商品を削除します
+// カートに入っている明細は一緒に削除し、注文明細から参照されている場合は削除しません
+func (r *productRepository) Delete(ctx context.Context, id string) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var count int64
+		if err := tx.Model(&entity.OrderItem{}).Where("product_id = ?", id).Count(&count).Error; err != nil {
+			return err
+		}
+		if count > 0 {
+			return repository.ErrProductInUse
+		}
+
+		if err := tx.Where("product_id = ?", id).Delete(&entity.CartItem{}).Error; err != nil {
+			return err
+		}
+		result := tx.Delete(&entity.Product{}, "id = ?", id)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return repository.ErrNotFound
+		}
+		return nil
+	})
+}
+
+// Restock は商品の在庫を quantity だけ増やします
+func (r *productRepository) Restock(ctx context.Context, id string, quantity int) (*entity.Product, error) {
+	var product entity.Product
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&entity.Product{}).Where("id = ?", id).Update("stock", gorm.Expr("stock + ?", quantity))
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return repository.ErrNotFound
+		}
+		return translateError(tx.First(&product, "id = ?", id).Error)
+	})
+	if err != nil {
 		return nil, err
 	}
 	return &product, nil
 }
+
+// UpsertBySKU は商品コードをキーに商品を一括で作成・更新します
+// 既存の商品は商品コードと在庫以外の項目を上書きし、作成・更新した商品の ID を products に反映します
+// 在庫は注文による引き当てを取り込み中の値で打ち消さないよう、新規作成時の初期値としてのみ使います
+func (r *productRepository) UpsertBySKU(ctx context.Context, products []*entity.Product, batchSize int) (int, error) {
+	created := 0
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		for start := 0; start < len(products); start += batchSize {
+			batch := products[start:min(start+batchSize, len(products))]
+
+			skus := make([]string, 0, len(batch))
+			for _, p := range batch {
+				skus = append(skus, p.SKU)
+			}
+			var existing int64
+			if err := tx.Model(&entity.Product{}).Where("sku IN ?", skus).Count(&existing).Error; err != nil {
+				return err
+			}
+			created += len(batch) - int(existing)
+
+			if err := tx.Clauses(clause.OnConflict{
+				Columns:     []clause.Column{{Name: "sku"}},
+				TargetWhere: clause.Where{Exprs: []clause.Expression{clause.Expr{SQL: "sku <> ''"}}},
+				DoUpdates:   clause.AssignmentColumns([]string{"name", "description", "price", "image_url", "category", "updated_at"}),
+			}).Create(batch).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	return created, nil
+}
+
+// FindInBatches は全ての商品を ID 順に batchSize 件ずつ fn に渡します
+func (r *productRepository) FindInBatches(ctx context.Context, batchSize int, fn func(products []*entity.Product) error) error {
+	var products []*entity.Product
+	return r.db.WithContext(ctx).Order("id").FindInBatches(&products, batchSize, func(tx *gorm.DB, batch int) error {
+		return fn(products)
+	}).Error
+}
+
+// translateProductError は商品コードの一意制約違反を ErrDuplicateSKU に変換します
+func translateProductError(err error) error {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Code == "23505" && pgErr.ConstraintName == "idx_products_sku" {
+		return repository.ErrDuplicateSKU
+	}
+	return translateError(err)
+}
+
+// Search は条件に一致する商品を1ページ分取得します
+func (r *productRepository) Search(ctx context.Context, query repository.ProductQuery) (*repository.ProductPage, error) {
+	var total int64
+	if err := applyProductFilters(r.db.WithContext(ctx).Model(&entity.Product{}), query).Count(&total).Error; err != nil {
+		return nil, err
+	}
+
+	column, desc := productSortColumn(query.Sort)
+	direction := "ASC"
+	if desc {
+		direction = "DESC"
+	}
+
+	db := applyProductFilters(r.db.WithContext(ctx), query).
+		Order(column + " " + direction).
+		Order("id " + direction)
+
+	if query.Cursor != "" {
+		cursor, err := decodeProductCursor(query.Cursor)
+		if err != nil {
+			return nil, err
+		}
+		operator := ">"
+		if desc {
+			operator = "<"
+		}
+		db = db.Where("("+column+", id) "+operator+" (?, ?)", cursor.value(query.Sort), cursor.ID)
+	} else if query.Page > 1 {
+		db = db.Offset((query.Page - 1) * query.PerPage)
+	}
+
+	// 次のページの有無を判定するため1件多く取得する
+	var products []*entity.Product
+	if err := db.Limit(query.PerPage + 1).Find(&products).Error; err != nil {
+		return nil, err
+	}
+
+	page := &repository.ProductPage{Products: products, Total: total, PerPage: query.PerPage}
+	if query.Cursor == "" {
+		page.Page = max(query.Page, 1)
+	}
+	if len(products) > query.PerPage {
+		page.Products = products[:query.PerPage]
+		last := page.Products[len(page.Products)-1]
+		page.NextCursor = encodeProductCursor(last)
+	}
+	return page, nil
+}
+
+// applyProductFilters は検索条件を WHERE 句として追加します
+func applyProductFilters(db *gorm.DB, query repository.ProductQuery) *gorm.DB {
+	if query.Keyword != "" {
+		pattern := "%" + likeEscaper.Replace(query.Keyword) + "%"
+		db = db.Where("(name ILIKE ? OR description ILIKE ?)", pattern, pattern)
+	}
+	if query.Category != "" {
+		db = db.Where("category = ?", query.Category)
+	}
+	if query.MinPrice != nil {
+		db = db.Where("price >= ?", *query.MinPrice)
+	}
+	if query.MaxPrice != nil {
+		db = db.Where("price <= ?", *query.MaxPrice)
+	}
+	if query.InStockOnly {
+		db = db.Where("stock > 0")
+	}
+	return db
+}
+
+// likeEscaper は LIKE のワイルドカード文字をエスケープします
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+// productSortColumn は並び順に対応するカラム名と降順かどうかを返します
+func productSortColumn(sort repository.ProductSort) (string, bool) {
+	switch sort {
+	case repository.ProductSortPriceAsc:
+		return "price", false
+	case repository.ProductSortPriceDesc:
+		return "price", true
+	case repository.ProductSortName:
+		return "name", false
+	default:
+		return "created_at", true
+	}
+}
+
+// productCursor はキーセットページングのカーソルです
+// 最後に返した商品の並び替えキーとIDを保持します
+type productCursor struct {
+	Price     int       `json:"p"`
+	Name      string    `json:"n"`
+	CreatedAt time.Time `json:"c"`
+	ID        string    `json:"id"`
+}
+
+func (c productCursor) value(sort repository.ProductSort) interface{} {
+	switch sort {
+	case repository.ProductSortPriceAsc, repository.ProductSortPriceDesc:
+		return c.Price
+	case repository.ProductSortName:
+		return c.Name
+	default:
+		return c.CreatedAt
+	}
+}
+
+func encodeProductCursor(p *entity.Product) string {
+	raw, _ := json.Marshal(productCursor{
+		Price:     p.Price,
+		Name:      p.Name,
+		CreatedAt: p.CreatedAt,
+		ID:        p.ID,
+	})
+	return base64.RawURLEncoding.EncodeToString(raw)
+}
+
+func decodeProductCursor(s string) (*productCursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, repository.ErrInvalidCursor
+	}
+	var cursor productCursor
+	if err := json.Unmarshal(raw, &cursor); err != nil || cursor.ID == "" {
+		return nil, repository.ErrInvalidCursor
+	}
+	return &cursor, nil
+}
@@ -0,0 +1,149 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/sotaheavymetal21/rabbit-cart/backend/internal/domain/entity"
+	"github.com/sotaheavymetal21/rabbit-cart/backend/internal/domain/repository"
+	"github.com/sotaheavymetal21/rabbit-cart/backend/internal/infrastructure/database/dbtest"
+)
+
+func TestProductSortColumn(t *testing.T) {
+	tests := []struct {
+		sort   repository.ProductSort
+		column string
+		desc   bool
+	}{
+		{repository.ProductSortNewest, "created_at", true},
+		{repository.ProductSortPriceAsc, "price", false},
+		{repository.ProductSortPriceDesc, "price", true},
+		{repository.ProductSortName, "name", false},
+		// 一覧にない値は SQL に埋め込まず既定の並び順にする
+		{"", "created_at", true},
+		{"stock; DROP TABLE products", "created_at", true},
+	}
+	for _, tt := range tests {
+		column, desc := productSortColumn(tt.sort)
+		if column != tt.column || desc != tt.desc {
+			t.Errorf("productSortColumn(%q) = (%s, %v), want (%s, %v)", tt.sort, column, desc, tt.column, tt.desc)
+		}
+	}
+}
+
+func TestDecodeProductCursorRejectsInvalid(t *testing.T) {
+	for _, cursor := range []string{"%%%", "bm90LWpzb24", "e30"} { // base64 でない / JSON でない / ID なし
+		if _, err := decodeProductCursor(cursor); !errors.Is(err, repository.ErrInvalidCursor) {
+			t.Errorf("decodeProductCursor(%q) = %v, want ErrInvalidCursor", cursor, err)
+		}
+	}
+
+	product := &entity.Product{ID: "p-1", Name: "Tシャツ", Price: 3000}
+	cursor, err := decodeProductCursor(encodeProductCursor(product))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cursor.ID != product.ID || cursor.value(repository.ProductSortPriceAsc) != 3000 || cursor.value(repository.ProductSortName) != "Tシャツ" {
+		t.Errorf("cursor = %+v", cursor)
+	}
+}
+
+// カーソルでページを辿ると、同じ値の商品があっても重複・欠落なく全件を並び順どおりに取得できることを確認する
+func TestProductSearchCursorPagination(t *testing.T) {
+	db := dbtest.New(t)
+	ctx := context.Background()
+	repo := NewProductRepository(db)
+
+	prices := []int{500, 1200, 1200, 800, 1200, 300, 800}
+	for i, price := range prices {
+		product := &entity.Product{Name: fmt.Sprintf("商品%02d", i), Price: price, Stock: 1, Category: "apparel"}
+		if err := db.Create(product).Error; err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := db.Create(&entity.Product{Name: "対象外", Price: 100, Category: "food"}).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	for _, sort := range []repository.ProductSort{repository.ProductSortNewest, repository.ProductSortPriceAsc, repository.ProductSortPriceDesc, repository.ProductSortName} {
+		t.Run(string(sort), func(t *testing.T) {
+			query := repository.ProductQuery{Category: "apparel", Sort: sort, PerPage: 3}
+			want, err := repo.Search(ctx, repository.ProductQuery{Category: "apparel", Sort: sort, PerPage: len(prices)})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var got []*entity.Product
+			for pages := 0; ; pages++ {
+				if pages > len(prices) {
+					t.Fatal("次のページが終わりません")
+				}
+				page, err := repo.Search(ctx, query)
+				if err != nil {
+					t.Fatalf("Search: %v", err)
+				}
+				if page.Total != int64(len(prices)) {
+					t.Errorf("Total = %d, want %d", page.Total, len(prices))
+				}
+				got = append(got, page.Products...)
+				if page.NextCursor == "" {
+					break
+				}
+				query.Cursor = page.NextCursor
+			}
+
+			if len(got) != len(want.Products) {
+				t.Fatalf("取得件数 = %d, want %d", len(got), len(want.Products))
+			}
+			for i := range got {
+				if got[i].ID != want.Products[i].ID {
+					t.Errorf("%d 件目 = %s, want %s", i, got[i].Name, want.Products[i].Name)
+				}
+			}
+		})
+	}
+
+	if _, err := repo.Search(ctx, repository.ProductQuery{PerPage: 3, Cursor: "e30"}); !errors.Is(err, repository.ErrInvalidCursor) {
+		t.Errorf("不正なカーソル: Search = %v, want ErrInvalidCursor", err)
+	}
+}
+
+// 商品コードで既存の商品を更新する場合は在庫を上書きせず、新規作成する商品にだけ在庫を設定することを確認する
+func TestProductUpsertBySKUKeepsStock(t *testing.T) {
+	db := dbtest.New(t)
+	ctx := context.Background()
+	repo := NewProductRepository(db)
+
+	existing := &entity.Product{SKU: "TEE-001", Name: "Tシャツ", Price: 3000, Stock: 7, Category: "apparel"}
+	if err := db.Create(existing).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	created, err := repo.UpsertBySKU(ctx, []*entity.Product{
+		{SKU: "TEE-001", Name: "Tシャツ (白)", Price: 3200, Stock: 100, Category: "apparel"},
+		{SKU: "MUG-001", Name: "マグカップ", Price: 1500, Stock: 4, Category: "kitchen"},
+	}, 1)
+	if err != nil {
+		t.Fatalf("UpsertBySKU: %v", err)
+	}
+	if created != 1 {
+		t.Errorf("新規作成 = %d 件, want 1 件", created)
+	}
+
+	got, err := repo.FindByID(ctx, existing.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Name != "Tシャツ (白)" || got.Price != 3200 || got.Stock != 7 {
+		t.Errorf("更新後の商品 = %+v, want 名前と価格を更新・在庫 7", got)
+	}
+	var mug entity.Product
+	if err := db.First(&mug, "sku = ?", "MUG-001").Error; err != nil {
+		t.Fatal(err)
+	}
+	if mug.Stock != 4 {
+		t.Errorf("新規作成した商品の在庫 = %d, want 4", mug.Stock)
+	}
+}
@@ -0,0 +1,86 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/sotaheavymetal21/rabbit-cart/backend/internal/domain/entity"
+	"github.com/sotaheavymetal21/rabbit-cart/backend/internal/domain/repository"
+	"gorm.io/gorm"
+)
+
+type promotionRepository struct {
+	db *gorm.DB
+}
+
+// NewPromotionRepository は PromotionRepository の実装を生成します
+func NewPromotionRepository(db *gorm.DB) repository.PromotionRepository {
+	return &promotionRepository{db: db}
+}
+
+// FindAll は全てのクーポンを登録日時の新しい順に取得します
+func (r *promotionRepository) FindAll(ctx context.Context) ([]*entity.Promotion, error) {
+	var promotions []*entity.Promotion
+	if err := r.db.WithContext(ctx).Order("created_at desc").Find(&promotions).Error; err != nil {
+		return nil, err
+	}
+	return promotions, nil
+}
+
+// FindByID は指定されたIDのクーポンを取得します
+func (r *promotionRepository) FindByID(ctx context.Context, id string) (*entity.Promotion, error) {
+	var promotion entity.Promotion
+	if err := r.db.WithContext(ctx).First(&promotion, "id = ?", id).Error; err != nil {
+		return nil, translateError(err)
+	}
+	return &promotion, nil
+}
+
+// FindByCode はクーポンコードからクーポンを取得します
+func (r *promotionRepository) FindByCode(ctx context.Context, code string) (*entity.Promotion, error) {
+	var promotion entity.Promotion
+	if err := r.db.WithContext(ctx).First(&promotion, "code = ?", code).Error; err != nil {
+		return nil, translateError(err)
+	}
+	return &promotion, nil
+}
+
+// Create はクーポンを作成します
+func (r *promotionRepository) Create(ctx context.Context, promotion *entity.Promotion) error {
+	return translateError(r.db.WithContext(ctx).Create(promotion).Error)
+}
+
+// Update はクーポンの設定を更新します
+// 利用回数は注文の作成・キャンセルで加減算するため、ここでは更新しません
+func (r *promotionRepository) Update(ctx context.Context, promotion *entity.Promotion) error {
+	result := r.db.WithContext(ctx).Model(&entity.Promotion{}).Where("id = ?", promotion.ID).
+		Select("*").Omit("id", "used_count", "created_at").Updates(promotion)
+	if result.Error != nil {
+		return translateError(result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return repository.ErrNotFound
+	}
+	return nil
+}
+
+// Delete はクーポンを削除します
+func (r *promotionRepository) Delete(ctx context.Context, id string) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var count int64
+		if err := tx.Model(&entity.OrderDiscount{}).Where("promotion_id = ?", id).Count(&count).Error; err != nil {
+			return translateError(err)
+		}
+		if count > 0 {
+			return repository.ErrPromotionInUse
+		}
+
+		result := tx.Delete(&entity.Promotion{}, "id = ?", id)
+		if result.Error != nil {
+			return translateError(result.Error)
+		}
+		if result.RowsAffected == 0 {
+			return repository.ErrNotFound
+		}
+		return nil
+	})
+}
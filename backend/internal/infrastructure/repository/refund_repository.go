@@ -0,0 +1,127 @@
+package repository
+
+import (
+	"context"
+	"sort"
+
+	"github.com/sotaheavymetal21/rabbit-cart/backend/internal/domain/entity"
+	"github.com/sotaheavymetal21/rabbit-cart/backend/internal/domain/repository"
+	"gorm.io/gorm"
+)
+
+type refundRepository struct {
+	db *gorm.DB
+}
+
+// NewRefundRepository は RefundRepository の実装を生成します
+func NewRefundRepository(db *gorm.DB) repository.RefundRepository {
+	return &refundRepository{db: db}
+}
+
+// Create は返金を pending として保存し、返金額を確保します
+// 条件付き UPDATE で加算するため、同時に返金しても売上の確定額や明細の金額を超えて返金されることはありません
+func (r *refundRepository) Create(ctx context.Context, refund *entity.Refund) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&entity.Payment{}).
+			Where("id = ? AND status = ? AND refunded_amount + ? <= amount", refund.PaymentID, entity.PaymentStatusCaptured, refund.Amount).
+			Update("refunded_amount", gorm.Expr("refunded_amount + ?", refund.Amount))
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return entity.ErrRefundExceedsCaptured
+		}
+
+		// デッドロックを避けるため、常に明細IDの順で行ロックを取得する
+		items := sortedRefundItems(refund.Items)
+		for _, item := range items {
+			result := tx.Model(&entity.OrderItem{}).
+				Where("id = ? AND order_id = ? AND refunded_quantity + ? <= quantity AND refunded_amount + ? <= price * quantity - discount_amount",
+					item.OrderItemID, refund.OrderID, item.Quantity, item.Amount).
+				Updates(map[string]interface{}{
+					"refunded_quantity": gorm.Expr("refunded_quantity + ?", item.Quantity),
+					"refunded_amount":   gorm.Expr("refunded_amount + ?", item.Amount),
+				})
+			if result.Error != nil {
+				return result.Error
+			}
+			if result.RowsAffected == 0 {
+				return entity.ErrRefundExceedsOrderItem
+			}
+		}
+
+		refund.Status = entity.RefundStatusPending
+		return tx.Create(refund).Error
+	})
+}
+
+// Complete は返金を succeeded にし、返品された数量を在庫に戻します
+func (r *refundRepository) Complete(ctx context.Context, refund *entity.Refund, paymentStatus entity.PaymentStatus) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		refund.Status = entity.RefundStatusSucceeded
+		if err := tx.Model(refund).Update("status", refund.Status).Error; err != nil {
+			return err
+		}
+		if err := tx.Model(&entity.Payment{}).Where("id = ?", refund.PaymentID).Update("status", paymentStatus).Error; err != nil {
+			return err
+		}
+		if !refund.Restock {
+			return nil
+		}
+
+		// 返金明細から商品IDを引くため、対象の注文明細を取得する
+		items := sortedRefundItems(refund.Items)
+		orderItemIDs := make([]string, 0, len(items))
+		for _, item := range items {
+			orderItemIDs = append(orderItemIDs, item.OrderItemID)
+		}
+		var orderItems []entity.OrderItem
+		if err := tx.Where("id IN ?", orderItemIDs).Find(&orderItems).Error; err != nil {
+			return err
+		}
+		productIDs := make(map[string]string, len(orderItems))
+		for _, orderItem := range orderItems {
+			productIDs[orderItem.ID] = orderItem.ProductID
+		}
+
+		for _, item := range items {
+			if item.Quantity == 0 {
+				continue
+			}
+			if err := tx.Model(&entity.Product{}).
+				Where("id = ?", productIDs[item.OrderItemID]).
+				Update("stock", gorm.Expr("stock + ?", item.Quantity)).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// Fail は返金を failed にし、確保した返金額を戻します
+func (r *refundRepository) Fail(ctx context.Context, refund *entity.Refund) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&entity.Payment{}).Where("id = ?", refund.PaymentID).
+			Update("refunded_amount", gorm.Expr("refunded_amount - ?", refund.Amount)).Error; err != nil {
+			return err
+		}
+		for _, item := range sortedRefundItems(refund.Items) {
+			if err := tx.Model(&entity.OrderItem{}).Where("id = ?", item.OrderItemID).
+				Updates(map[string]interface{}{
+					"refunded_quantity": gorm.Expr("refunded_quantity - ?", item.Quantity),
+					"refunded_amount":   gorm.Expr("refunded_amount - ?", item.Amount),
+				}).Error; err != nil {
+				return err
+			}
+		}
+		refund.Status = entity.RefundStatusFailed
+		return tx.Model(refund).Update("status", refund.Status).Error
+	})
+}
+
+// sortedRefundItems は返金明細を注文明細IDの順に並べたコピーを返します
+func sortedRefundItems(items []entity.RefundItem) []entity.RefundItem {
+	sorted := append([]entity.RefundItem(nil), items...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].OrderItemID < sorted[j].OrderItemID })
+	return sorted
+}
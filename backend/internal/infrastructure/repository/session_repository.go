@@ -0,0 +1,90 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/sotaheavymetal21/rabbit-cart/backend/internal/domain/entity"
+	"github.com/sotaheavymetal21/rabbit-cart/backend/internal/domain/repository"
+	"gorm.io/gorm"
+)
+
+type sessionRepository struct {
+	db *gorm.DB
+}
+
+// NewSessionRepository は SessionRepository の実装を生成します
+func NewSessionRepository(db *gorm.DB) repository.SessionRepository {
+	return &sessionRepository{db: db}
+}
+
+// Create はセッションを保存します
+func (r *sessionRepository) Create(ctx context.Context, session *entity.UserSession) error {
+	return r.db.WithContext(ctx).Create(session).Error
+}
+
+// FindByID はIDでセッションを検索します
+func (r *sessionRepository) FindByID(ctx context.Context, id string) (*entity.UserSession, error) {
+	var session entity.UserSession
+	if err := r.db.WithContext(ctx).First(&session, "id = ?", id).Error; err != nil {
+		return nil, translateError(err)
+	}
+	return &session, nil
+}
+
+// FindActiveByUserID はユーザーの有効なセッションを最終利用日時の新しい順に取得します
+func (r *sessionRepository) FindActiveByUserID(ctx context.Context, userID string) ([]*entity.UserSession, error) {
+	var sessions []*entity.UserSession
+	if err := r.db.WithContext(ctx).
+		Where("user_id = ? AND revoked_at IS NULL", userID).
+		Order("last_seen_at desc").
+		Find(&sessions).Error; err != nil {
+		return nil, err
+	}
+	return sessions, nil
+}
+
+// Touch はセッションの最終利用日時と IP アドレスを更新します
+func (r *sessionRepository) Touch(ctx context.Context, id, ip string, at time.Time) error {
+	return r.db.WithContext(ctx).Model(&entity.UserSession{}).
+		Where("id = ?", id).
+		Updates(map[string]interface{}{"last_seen_at": at, "ip_address": ip}).Error
+}
+
+// Revoke はユーザーのセッションを失効させ、同じ系列のリフレッシュトークンも失効させます
+func (r *sessionRepository) Revoke(ctx context.Context, userID, id string) (bool, error) {
+	revoked := false
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		now := time.Now()
+		result := tx.Model(&entity.UserSession{}).
+			Where("id = ? AND user_id = ? AND revoked_at IS NULL", id, userID).
+			Update("revoked_at", now)
+		if result.Error != nil {
+			return result.Error
+		}
+		revoked = result.RowsAffected > 0
+		return tx.Model(&entity.RefreshToken{}).
+			Where("family_id = ? AND user_id = ? AND revoked_at IS NULL", id, userID).
+			Update("revoked_at", now).Error
+	})
+	return revoked, err
+}
+
+// RevokeAllByUserID はユーザーの全てのセッションとリフレッシュトークンを失効させます
+func (r *sessionRepository) RevokeAllByUserID(ctx context.Context, userID string) (int64, error) {
+	var count int64
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		now := time.Now()
+		result := tx.Model(&entity.UserSession{}).
+			Where("user_id = ? AND revoked_at IS NULL", userID).
+			Update("revoked_at", now)
+		if result.Error != nil {
+			return result.Error
+		}
+		count = result.RowsAffected
+		return tx.Model(&entity.RefreshToken{}).
+			Where("user_id = ? AND revoked_at IS NULL", userID).
+			Update("revoked_at", now).Error
+	})
+	return count, err
+}
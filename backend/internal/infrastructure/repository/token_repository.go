@@ -0,0 +1,84 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/sotaheavymetal21/rabbit-cart/backend/internal/domain/entity"
+	"github.com/sotaheavymetal21/rabbit-cart/backend/internal/domain/repository"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type tokenRepository struct {
+	db *gorm.DB
+}
+
+// NewTokenRepository は TokenRepository の実装を生成します
+func NewTokenRepository(db *gorm.DB) repository.TokenRepository {
+	return &tokenRepository{db: db}
+}
+
+// CreateRefreshToken はリフレッシュトークンを保存します
+func (r *tokenRepository) CreateRefreshToken(ctx context.Context, token *entity.RefreshToken) error {
+	return r.db.WithContext(ctx).Create(token).Error
+}
+
+// FindRefreshToken はハッシュ値でリフレッシュトークンを検索します
+func (r *tokenRepository) FindRefreshToken(ctx context.Context, tokenHash string) (*entity.RefreshToken, error) {
+	var token entity.RefreshToken
+	if err := r.db.WithContext(ctx).First(&token, "token_hash = ?", tokenHash).Error; err != nil {
+		return nil, translateError(err)
+	}
+	return &token, nil
+}
+
+// RotateRefreshToken は old を失効させて next を保存します
+// 失効済みかどうかを条件付き UPDATE で判定するため、同じトークンで同時にリフレッシュされても成功するのは1回だけです
+func (r *tokenRepository) RotateRefreshToken(ctx context.Context, old, next *entity.RefreshToken) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&entity.RefreshToken{}).
+			Where("id = ? AND revoked_at IS NULL", old.ID).
+			Update("revoked_at", time.Now())
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return entity.ErrRefreshTokenReused
+		}
+		return tx.Create(next).Error
+	})
+}
+
+// RevokeRefreshTokenFamily は同じ系列のリフレッシュトークンを全て失効させます
+func (r *tokenRepository) RevokeRefreshTokenFamily(ctx context.Context, familyID string) error {
+	return r.db.WithContext(ctx).Model(&entity.RefreshToken{}).
+		Where("family_id = ? AND revoked_at IS NULL", familyID).
+		Update("revoked_at", time.Now()).Error
+}
+
+// RevokeAccessToken はアクセストークンを失効リストに追加します
+func (r *tokenRepository) RevokeAccessToken(ctx context.Context, jti string, expiresAt time.Time) error {
+	return r.db.WithContext(ctx).
+		Clauses(clause.OnConflict{DoNothing: true}).
+		Create(&entity.RevokedToken{JTI: jti, ExpiresAt: expiresAt}).Error
+}
+
+// IsAccessTokenRevoked はアクセストークンが失効リストにあるかを返します
+func (r *tokenRepository) IsAccessTokenRevoked(ctx context.Context, jti string) (bool, error) {
+	var count int64
+	if err := r.db.WithContext(ctx).Model(&entity.RevokedToken{}).Where("jti = ?", jti).Count(&count).Error; err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+// PurgeExpired は有効期限を過ぎたリフレッシュトークンと失効リストのエントリを削除します
+func (r *tokenRepository) PurgeExpired(ctx context.Context, now time.Time) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("expires_at < ?", now).Delete(&entity.RefreshToken{}).Error; err != nil {
+			return err
+		}
+		return tx.Where("expires_at < ?", now).Delete(&entity.RevokedToken{}).Error
+	})
+}
@@ -2,6 +2,7 @@ package repository
 
 import (
 	"context"
+	"time"
 
 	"github.com/sotaheavymetal21/rabbit-cart/backend/internal/domain/entity"
 	"github.com/sotaheavymetal21/rabbit-cart/backend/internal/domain/repository"
@@ -19,14 +20,14 @@ func NewUserRepository(db *gorm.DB) repository.UserRepository {
 
 // Create は新しいユーザーを作成します
 func (r *userRepository) Create(ctx context.Context, user *entity.User) error {
-	return r.db.WithContext(ctx).Create(user).Error
+	return translateError(r.db.WithContext(ctx).Create(user).Error)
 }
 
 // FindByEmail はメールアドレスでユーザーを検索します
 func (r *userRepository) FindByEmail(ctx context.Context, email string) (*entity.User, error) {
 	var user entity.User
 	if err := r.db.WithContext(ctx).Where("email = ?", email).First(&user).Error; err != nil {
-		return nil, err
+		return nil, translateError(err)
 	}
 	return &user, nil
 }
@@ -35,7 +36,70 @@ func (r *userRepository) FindByEmail(ctx context.Context, email string) (*entity
 func (r *userRepository) FindByID(ctx context.Context, id string) (*entity.User, error) {
 	var user entity.User
 	if err := r.db.WithContext(ctx).First(&user, "id = ?", id).Error; err != nil {
-		return nil, err
+		return nil, translateError(err)
 	}
 	return &user, nil
 }
+
+// UpdateProfile はユーザーの表示名・電話番号・言語を更新します
+func (r *userRepository) UpdateProfile(ctx context.Context, id, displayName, phone string, locale entity.Locale) error {
+	return r.db.WithContext(ctx).Model(&entity.User{}).Where("id = ?", id).Updates(map[string]interface{}{
+		"display_name": displayName,
+		"phone":        phone,
+		"locale":       locale,
+	}).Error
+}
+
+// UpdatePassword はユーザーのパスワードを変更し、他のログインセッションとリフレッシュトークンを失効させます
+func (r *userRepository) UpdatePassword(ctx context.Context, id, passwordHash, keepSessionID string) error {
+	now := time.Now()
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&entity.User{}).Where("id = ?", id).Updates(map[string]interface{}{
+			"password_hash":       passwordHash,
+			"password_changed_at": now,
+		}).Error; err != nil {
+			return err
+		}
+
+		sessions := tx.Model(&entity.UserSession{}).Where("user_id = ? AND revoked_at IS NULL", id)
+		if keepSessionID != "" {
+			sessions = sessions.Where("id <> ?", keepSessionID)
+		}
+		if err := sessions.Update("revoked_at", now).Error; err != nil {
+			return err
+		}
+
+		return tx.Model(&entity.RefreshToken{}).
+			Where("user_id = ? AND revoked_at IS NULL", id).
+			Update("revoked_at", now).Error
+	})
+}
+
+// Delete はユーザーを削除し、注文を匿名化します
+// セッション・トークン・二要素認証などユーザーに紐付くデータは外部キーの ON DELETE CASCADE で削除されます
+func (r *userRepository) Delete(ctx context.Context, id string) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var user entity.User
+		if err := tx.Select("id", "email").First(&user, "id = ?", id).Error; err != nil {
+			return translateError(err)
+		}
+
+		if err := tx.Model(&entity.Order{}).Where("user_id = ?", id).Updates(map[string]interface{}{
+			"user_id": nil,
+			"address": gorm.Expr("'{}'::jsonb"),
+		}).Error; err != nil {
+			return err
+		}
+
+		// カートの明細は外部キーで削除される
+		if err := tx.Where("user_id = ?", id).Delete(&entity.Cart{}).Error; err != nil {
+			return err
+		}
+		// ログインのロックアウト記録はメールアドレスで保存しているため個別に削除する
+		if err := tx.Where("email = ?", user.Email).Delete(&entity.LoginLockout{}).Error; err != nil {
+			return err
+		}
+
+		return tx.Delete(&entity.User{}, "id = ?", id).Error
+	})
+}
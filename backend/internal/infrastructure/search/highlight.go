@@ -0,0 +1,94 @@
+package search
+
+import (
+	"html"
+	"strings"
+)
+
+const (
+	markOpen  = "<mark>"
+	markClose = "</mark>"
+	ellipsis  = "…"
+)
+
+// Highlight は text の中で terms に一致する箇所を <mark> で囲んだ HTML エスケープ済みの文字列を返します
+// window が正の場合は最初の一致箇所を含むように window 文字まで切り詰めます
+// 一致箇所が無い場合は空文字を返します
+func Highlight(text string, terms []string, window int) string {
+	original := []rune(text)
+	marked := matchRanges(original, terms)
+	if len(marked) == 0 {
+		return ""
+	}
+
+	start, end := 0, len(original)
+	if window > 0 && len(original) > window {
+		first := 0
+		for first < len(original) && !marked[first] {
+			first++
+		}
+		start = max(0, first-window/3)
+		end = min(len(original), start+window)
+	}
+
+	var b strings.Builder
+	if start > 0 {
+		b.WriteString(ellipsis)
+	}
+	inMark := false
+	segment := start
+	for i := start; i <= end; i++ {
+		on := i < end && marked[i]
+		if i < end && on == inMark {
+			continue
+		}
+		b.WriteString(html.EscapeString(string(original[segment:i])))
+		segment = i
+		if i == end {
+			break
+		}
+		if on {
+			b.WriteString(markOpen)
+		} else {
+			b.WriteString(markClose)
+		}
+		inMark = on
+	}
+	if inMark {
+		b.WriteString(markClose)
+	}
+	if end < len(original) {
+		b.WriteString(ellipsis)
+	}
+	return b.String()
+}
+
+// matchRanges は original の各文字が terms のいずれかに一致する範囲に含まれるかを返します
+// 比較は正規化後の文字列で行い、一致箇所を元の文字位置に戻します
+func matchRanges(original []rune, terms []string) map[int]bool {
+	var normalized []rune
+	var origin []int // normalized の各文字に対応する original の位置
+	for i, r := range original {
+		for _, n := range Normalize(string(r)) {
+			normalized = append(normalized, n)
+			origin = append(origin, i)
+		}
+	}
+
+	marked := make(map[int]bool)
+	for _, term := range terms {
+		t := []rune(term)
+		if len(t) == 0 {
+			continue
+		}
+		for i := 0; i+len(t) <= len(normalized); i++ {
+			if string(normalized[i:i+len(t)]) != term {
+				continue
+			}
+			for j := origin[i]; j <= origin[i+len(t)-1]; j++ {
+				marked[j] = true
+			}
+		}
+	}
+	return marked
+}
@@ -0,0 +1,28 @@
+package search
+
+import "testing"
+
+func TestHighlight(t *testing.T) {
+	tests := []struct {
+		name    string
+		text    string
+		keyword string
+		window  int
+		want    string
+	}{
+		{"表記揺れを吸収して元の文字を囲む", "ふわふわウサギのTシャツ", "うさぎ", 0, "ふわふわ<mark>ウサギ</mark>のTシャツ"},
+		{"1文字のキーワード", "本革の靴と靴下", "靴", 0, "本革の<mark>靴</mark>と<mark>靴</mark>下"},
+		{"全角英字", "ＲＡＢＢＩＴ Tee", "rabbit", 0, "<mark>ＲＡＢＢＩＴ</mark> Tee"},
+		{"HTML をエスケープする", "<b>靴</b> & 鞄", "靴", 0, "&lt;b&gt;<mark>靴</mark>&lt;/b&gt; &amp; 鞄"},
+		{"一致しない場合は空文字", "ふわふわウサギ", "くま", 0, ""},
+		{"一致箇所を含むように切り詰める", "あいうえおかきくけこさしすせそ靴たちつてとなにぬ", "靴", 9, "…すせそ<mark>靴</mark>たちつてと…"},
+		{"先頭で一致する場合は末尾のみ省略", "靴あいうえおかきくけこ", "靴", 5, "<mark>靴</mark>あいうえ…"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Highlight(tt.text, QueryTokens(tt.keyword), tt.window); got != tt.want {
+				t.Errorf("Highlight(%q, %q, %d) = %q, want %q", tt.text, tt.keyword, tt.window, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,130 @@
+package search
+
+import (
+	"context"
+	"sort"
+	"sync"
+
+	"github.com/sotaheavymetal21/rabbit-cart/backend/internal/domain/entity"
+	"github.com/sotaheavymetal21/rabbit-cart/backend/internal/domain/repository"
+)
+
+// フィールドごとの重み (PostgreSQL 実装の A/B/C と同じ比率)
+const (
+	nameWeight        = 1.0
+	categoryWeight    = 0.4
+	descriptionWeight = 0.2
+)
+
+// memoryProductSearcher はメモリ上で動作する ProductSearcher の実装です
+// データベースを使わないテストやローカル開発で使用します
+type memoryProductSearcher struct {
+	mu        sync.RWMutex
+	products  map[string]*entity.Product
+	documents map[string]map[string]float64 // 商品ID → トークン → 重みの合計
+	source    func(ctx context.Context) ([]*entity.Product, error)
+}
+
+// NewMemoryProductSearcher はメモリ上で動作する ProductSearcher を生成します
+// source は Reindex で全商品を読み込む関数です（nil の場合は Reindex で何もしません）
+func NewMemoryProductSearcher(source func(ctx context.Context) ([]*entity.Product, error)) repository.ProductSearcher {
+	return &memoryProductSearcher{
+		products:  make(map[string]*entity.Product),
+		documents: make(map[string]map[string]float64),
+		source:    source,
+	}
+}
+
+// Index は商品をインデックスに登録します
+func (s *memoryProductSearcher) Index(ctx context.Context, product *entity.Product) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.index(product)
+	return nil
+}
+
+func (s *memoryProductSearcher) index(product *entity.Product) {
+	document := make(map[string]float64)
+	for _, t := range Tokenize(product.Name) {
+		document[t] += nameWeight
+	}
+	for _, t := range Tokenize(product.Category) {
+		document[t] += categoryWeight
+	}
+	for _, t := range Tokenize(product.Description) {
+		document[t] += descriptionWeight
+	}
+	copied := *product
+	s.products[product.ID] = &copied
+	s.documents[product.ID] = document
+}
+
+// Remove は商品をインデックスから削除します
+func (s *memoryProductSearcher) Remove(ctx context.Context, productID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.products, productID)
+	delete(s.documents, productID)
+	return nil
+}
+
+// Reindex は source から全商品を読み込んでインデックスを作り直します
+func (s *memoryProductSearcher) Reindex(ctx context.Context) error {
+	if s.source == nil {
+		return nil
+	}
+	products, err := s.source(ctx)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.products = make(map[string]*entity.Product)
+	s.documents = make(map[string]map[string]float64)
+	for _, p := range products {
+		s.index(p)
+	}
+	return nil
+}
+
+// Search はキーワードの全てのトークンを含む商品を関連度の高い順に返します
+func (s *memoryProductSearcher) Search(ctx context.Context, keyword string, limit int) ([]repository.ProductSearchHit, error) {
+	terms := QueryTokens(keyword)
+	if len(terms) == 0 {
+		return []repository.ProductSearchHit{}, nil
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	hits := []repository.ProductSearchHit{}
+	for id, document := range s.documents {
+		score := 0.0
+		matched := true
+		for _, t := range terms {
+			weight, ok := document[t]
+			if !ok {
+				matched = false
+				break
+			}
+			score += weight
+		}
+		if !matched {
+			continue
+		}
+		product := *s.products[id]
+		hits = append(hits, buildHit(&product, score/float64(len(terms)), terms))
+	}
+
+	sort.Slice(hits, func(i, j int) bool {
+		if hits[i].Score != hits[j].Score {
+			return hits[i].Score > hits[j].Score
+		}
+		return hits[i].Product.Name < hits[j].Product.Name
+	})
+	if limit > 0 && len(hits) > limit {
+		hits = hits[:limit]
+	}
+	return hits, nil
+}
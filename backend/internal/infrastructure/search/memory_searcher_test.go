@@ -0,0 +1,115 @@
+package search
+
+import (
+	"context"
+	"slices"
+	"testing"
+
+	"github.com/sotaheavymetal21/rabbit-cart/backend/internal/domain/entity"
+	"github.com/sotaheavymetal21/rabbit-cart/backend/internal/domain/repository"
+)
+
+var testProducts = []*entity.Product{
+	{ID: "p1", Name: "本革ビジネス靴", Category: "シューズ", Description: "職人が仕上げた革靴です"},
+	{ID: "p2", Name: "ウサギ柄の靴下", Category: "ファッション小物", Description: "ふわふわのうさぎ柄"},
+	{ID: "p3", Name: "シューズケース", Category: "シューズ", Description: "靴を2足収納できます"},
+	{ID: "p4", Name: "ふわふわうさぎのぬいぐるみ", Category: "おもちゃ", Description: "やわらかい手触り"},
+	{ID: "p5", Name: "Rabbit T-shirt", Category: "ファッション", Description: "綿100%のTシャツ"},
+}
+
+func newTestSearcher(t *testing.T) repository.ProductSearcher {
+	t.Helper()
+	s := NewMemoryProductSearcher(func(ctx context.Context) ([]*entity.Product, error) {
+		return testProducts, nil
+	})
+	if err := s.Reindex(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	return s
+}
+
+func hitIDs(hits []repository.ProductSearchHit) []string {
+	ids := make([]string, 0, len(hits))
+	for _, h := range hits {
+		ids = append(ids, h.Product.ID)
+	}
+	return ids
+}
+
+func TestMemoryProductSearcherSearch(t *testing.T) {
+	s := newTestSearcher(t)
+	tests := []struct {
+		name    string
+		keyword string
+		want    []string
+	}{
+		// 商品名 > カテゴリ > 説明文 の順に重みを付ける
+		{"1文字の漢字", "靴", []string{"p1", "p2", "p3"}},
+		{"カタカナとひらがなを区別しない", "うさぎ", []string{"p2", "p4"}}, // p2 は商品名と説明文の両方に一致
+		{"全てのトークンを含む商品のみ", "うさぎ 靴", []string{"p2"}},
+		{"カテゴリに一致", "シューズ", []string{"p3", "p1"}},
+		{"英字の大文字・小文字を区別しない", "RABBIT", []string{"p5"}},
+		{"一致しない", "くま", []string{}},
+		{"空のキーワード", " ", []string{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hits, err := s.Search(context.Background(), tt.keyword, 0)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := hitIDs(hits); !slices.Equal(got, tt.want) {
+				t.Errorf("Search(%q) = %v, want %v", tt.keyword, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMemoryProductSearcherHighlights(t *testing.T) {
+	s := newTestSearcher(t)
+	hits, err := s.Search(context.Background(), "靴", 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(hits) != 1 {
+		t.Fatalf("len(hits) = %d, want 1", len(hits))
+	}
+	want := map[string]string{
+		"name":        "本革ビジネス<mark>靴</mark>",
+		"description": "職人が仕上げた革<mark>靴</mark>です",
+	}
+	if got := hits[0].Highlights; len(got) != len(want) || got["name"] != want["name"] || got["description"] != want["description"] {
+		t.Errorf("Highlights = %v, want %v", got, want)
+	}
+}
+
+func TestMemoryProductSearcherIndexAndRemove(t *testing.T) {
+	ctx := context.Background()
+	s := NewMemoryProductSearcher(nil)
+
+	product := &entity.Product{ID: "p1", Name: "革靴"}
+	if err := s.Index(ctx, product); err != nil {
+		t.Fatal(err)
+	}
+	// 登録後に呼び出し元が商品を変更してもインデックスには影響しない
+	product.Name = "スニーカー"
+
+	hits, err := s.Search(ctx, "靴", 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := hitIDs(hits); !slices.Equal(got, []string{"p1"}) || hits[0].Product.Name != "革靴" {
+		t.Errorf("Search = %v, want [p1] (革靴)", got)
+	}
+
+	if err := s.Remove(ctx, "p1"); err != nil {
+		t.Fatal(err)
+	}
+	hits, err = s.Search(ctx, "靴", 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(hits) != 0 {
+		t.Errorf("削除後の Search = %v, want []", hitIDs(hits))
+	}
+}
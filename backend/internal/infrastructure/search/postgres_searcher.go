@@ -0,0 +1,104 @@
+package search
+
+import (
+	"context"
+	"strings"
+
+	"github.com/sotaheavymetal21/rabbit-cart/backend/internal/domain/entity"
+	"github.com/sotaheavymetal21/rabbit-cart/backend/internal/domain/repository"
+	"gorm.io/gorm"
+)
+
+// postgresProductSearcher は PostgreSQL の tsvector を使った ProductSearcher の実装です
+//
+// PostgreSQL 標準のパーサーは日本語を単語に分割できないため、アプリケーション側で
+// unigram・bigram に分割したトークンを 'simple' 設定で tsvector に格納します
+// 重みは商品名 A、カテゴリ B、説明文 C とし、ts_rank_cd で関連度順に並べます
+type postgresProductSearcher struct {
+	db *gorm.DB
+}
+
+// NewPostgresProductSearcher は PostgreSQL を使った ProductSearcher を生成します
+func NewPostgresProductSearcher(db *gorm.DB) repository.ProductSearcher {
+	return &postgresProductSearcher{db: db}
+}
+
+const upsertSearchDocumentSQL = `
+INSERT INTO product_search_documents (product_id, document, updated_at)
+VALUES (
+  ?,
+  setweight(to_tsvector('simple', ?), 'A') ||
+  setweight(to_tsvector('simple', ?), 'B') ||
+  setweight(to_tsvector('simple', ?), 'C'),
+  now()
+)
+ON CONFLICT (product_id) DO UPDATE SET document = EXCLUDED.document, updated_at = EXCLUDED.updated_at`
+
+// Index は商品をインデックスに登録します
+func (s *postgresProductSearcher) Index(ctx context.Context, product *entity.Product) error {
+	return s.db.WithContext(ctx).Exec(upsertSearchDocumentSQL,
+		product.ID,
+		joinTokens(product.Name),
+		joinTokens(product.Category),
+		joinTokens(product.Description),
+	).Error
+}
+
+// Remove は商品をインデックスから削除します
+func (s *postgresProductSearcher) Remove(ctx context.Context, productID string) error {
+	return s.db.WithContext(ctx).Exec("DELETE FROM product_search_documents WHERE product_id = ?", productID).Error
+}
+
+// Reindex は全ての商品でインデックスを作り直します
+func (s *postgresProductSearcher) Reindex(ctx context.Context) error {
+	return s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec("DELETE FROM product_search_documents").Error; err != nil {
+			return err
+		}
+		var products []*entity.Product
+		if err := tx.Find(&products).Error; err != nil {
+			return err
+		}
+		txSearcher := &postgresProductSearcher{db: tx}
+		for _, p := range products {
+			if err := txSearcher.Index(ctx, p); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// Search はキーワードに一致する商品を関連度の高い順に返します
+// キーワードの全てのトークンを含む商品のみが対象です
+func (s *postgresProductSearcher) Search(ctx context.Context, keyword string, limit int) ([]repository.ProductSearchHit, error) {
+	terms := QueryTokens(keyword)
+	if len(terms) == 0 {
+		return []repository.ProductSearchHit{}, nil
+	}
+
+	type row struct {
+		entity.Product
+		Score float64
+	}
+	var rows []row
+	err := s.db.WithContext(ctx).
+		Table("products").
+		Select("products.*, ts_rank_cd(d.document, q.query) AS score").
+		Joins("JOIN product_search_documents d ON d.product_id = products.id").
+		Joins("CROSS JOIN plainto_tsquery('simple', ?) AS q(query)", strings.Join(terms, " ")).
+		Where("d.document @@ q.query").
+		Order("score DESC").
+		Order("products.name ASC").
+		Limit(limit).
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	hits := make([]repository.ProductSearchHit, 0, len(rows))
+	for i := range rows {
+		hits = append(hits, buildHit(&rows[i].Product, rows[i].Score, terms))
+	}
+	return hits, nil
+}
@@ -0,0 +1,35 @@
+package search
+
+import (
+	"strings"
+
+	"github.com/sotaheavymetal21/rabbit-cart/backend/internal/domain/entity"
+	"github.com/sotaheavymetal21/rabbit-cart/backend/internal/domain/repository"
+)
+
+// descriptionSnippetLength は説明文のハイライト抜粋の最大文字数です
+const descriptionSnippetLength = 80
+
+// buildHit は商品とスコアから、一致箇所をハイライトした検索結果を組み立てます
+func buildHit(product *entity.Product, score float64, terms []string) repository.ProductSearchHit {
+	highlights := make(map[string]string)
+	if s := Highlight(product.Name, terms, 0); s != "" {
+		highlights["name"] = s
+	}
+	if s := Highlight(product.Description, terms, descriptionSnippetLength); s != "" {
+		highlights["description"] = s
+	}
+	if s := Highlight(product.Category, terms, 0); s != "" {
+		highlights["category"] = s
+	}
+	return repository.ProductSearchHit{
+		Product:    product,
+		Score:      score,
+		Highlights: highlights,
+	}
+}
+
+// joinTokens はトークンを空白区切りの文字列にします（tsvector / tsquery の入力用）
+func joinTokens(s string) string {
+	return strings.Join(Tokenize(s), " ")
+}
@@ -0,0 +1,122 @@
+package search
+
+import (
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// 日本語は単語の区切りが無いため、かな・漢字の連続は2文字ずつの N-gram (bigram) に分割します
+// インデックスには1文字ずつのトークン (unigram) も登録し、「靴」のような1文字のキーワードでも検索できるようにします
+// 英数字は空白や記号で区切られた単語をそのままトークンとします
+//
+// 例: "うさぎ靴 T-shirt"
+//   Tokenize    → ["う", "さ", "ぎ", "靴", "うさ", "さぎ", "ぎ靴", "t", "shirt"]
+//   QueryTokens → ["うさ", "さぎ", "ぎ靴", "t", "shirt"]
+
+type runeClass int
+
+const (
+	classSeparator runeClass = iota
+	classWord
+	classCJK
+)
+
+// Normalize は検索用に文字列を正規化します
+// NFKC で全角英数字・半角カナを揃え、小文字化し、カタカナをひらがなに寄せます
+func Normalize(s string) string {
+	return strings.Map(foldRune, norm.NFKC.String(s))
+}
+
+func foldRune(r rune) rune {
+	// カタカナ (ァ-ヶ) はひらがなに変換して表記揺れを吸収する
+	if r >= 'ァ' && r <= 'ヶ' {
+		return r - 0x60
+	}
+	return unicode.ToLower(r)
+}
+
+func classify(r rune) runeClass {
+	switch {
+	case unicode.In(r, unicode.Han, unicode.Hiragana, unicode.Katakana) || r == 'ー' || r == '々':
+		return classCJK
+	case unicode.IsLetter(r) || unicode.IsDigit(r):
+		return classWord
+	default:
+		return classSeparator
+	}
+}
+
+// Tokenize は文字列を正規化し、インデックスに登録するトークンに分割します
+// かな・漢字の連続は unigram と bigram の両方を返します
+func Tokenize(s string) []string {
+	return tokenize(s, true)
+}
+
+// QueryTokens は検索キーワードを重複の無いトークンに分割します
+// かな・漢字が2文字以上続く場合は bigram のみ、1文字だけの場合はその unigram をトークンとします
+func QueryTokens(keyword string) []string {
+	seen := make(map[string]bool)
+	var tokens []string
+	for _, t := range tokenize(keyword, false) {
+		if !seen[t] {
+			seen[t] = true
+			tokens = append(tokens, t)
+		}
+	}
+	return tokens
+}
+
+func tokenize(s string, unigrams bool) []string {
+	var tokens []string
+	for _, run := range splitRuns([]rune(Normalize(s))) {
+		tokens = append(tokens, run.tokens(unigrams)...)
+	}
+	return tokens
+}
+
+type run struct {
+	class runeClass
+	runes []rune
+}
+
+// tokens は文字の連続をトークンに分割します
+// unigrams が true の場合、かな・漢字は bigram の前に1文字ずつのトークンも返します
+func (r run) tokens(unigrams bool) []string {
+	if r.class == classWord || len(r.runes) == 1 {
+		return []string{string(r.runes)}
+	}
+	tokens := make([]string, 0, 2*len(r.runes)-1)
+	if unigrams {
+		for _, c := range r.runes {
+			tokens = append(tokens, string(c))
+		}
+	}
+	for i := 0; i+1 < len(r.runes); i++ {
+		tokens = append(tokens, string(r.runes[i:i+2]))
+	}
+	return tokens
+}
+
+// splitRuns は文字種の変わり目で文字列を区切り、区切り文字以外の連続を返します
+func splitRuns(runes []rune) []run {
+	var runs []run
+	var current run
+	for _, r := range runes {
+		class := classify(r)
+		if class != current.class && len(current.runes) > 0 {
+			runs = append(runs, current)
+			current = run{}
+		}
+		if class == classSeparator {
+			continue
+		}
+		current.class = class
+		current.runes = append(current.runes, r)
+	}
+	if len(current.runes) > 0 {
+		runs = append(runs, current)
+	}
+	return runs
+}
@@ -0,0 +1,62 @@
+package search
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestTokenize(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		want []string
+	}{
+		{"かな・漢字は unigram と bigram", "うさぎ靴", []string{"う", "さ", "ぎ", "靴", "うさ", "さぎ", "ぎ靴"}},
+		{"1文字の漢字", "靴", []string{"靴"}},
+		{"英数字は単語単位", "T-shirt 2024", []string{"t", "shirt", "2024"}},
+		{"文字種の変わり目で区切る", "Tシャツ", []string{"t", "し", "ゃ", "つ", "しゃ", "ゃつ"}},
+		{"カタカナはひらがなに寄せる", "ウサギ", []string{"う", "さ", "ぎ", "うさ", "さぎ"}},
+		{"全角英数字と半角カナを揃える", "ＲＡＢＢＩＴ ｳｻｷﾞ", []string{"rabbit", "う", "さ", "ぎ", "うさ", "さぎ"}},
+		{"区切り文字のみ", " 、!? ", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Tokenize(tt.in); !slices.Equal(got, tt.want) {
+				t.Errorf("Tokenize(%q) = %q, want %q", tt.in, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestQueryTokens(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		want []string
+	}{
+		{"2文字以上のかな・漢字は bigram のみ", "うさぎ靴", []string{"うさ", "さぎ", "ぎ靴"}},
+		{"1文字の漢字は unigram", "靴", []string{"靴"}},
+		{"重複を取り除く", "ふわふわ ふわふわ", []string{"ふわ", "わふ"}},
+		{"英数字と組み合わせ", "革 Boots", []string{"革", "boots"}},
+		{"空のキーワード", "  ", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := QueryTokens(tt.in); !slices.Equal(got, tt.want) {
+				t.Errorf("QueryTokens(%q) = %q, want %q", tt.in, got, tt.want)
+			}
+		})
+	}
+}
+
+// クエリのトークンは常にインデックスのトークンに含まれる (部分一致で検索できる)
+func TestQueryTokensAreIndexed(t *testing.T) {
+	document := Tokenize("ふわふわウサギの革靴")
+	for _, keyword := range []string{"靴", "革靴", "うさぎ", "ふわ", "の"} {
+		for _, term := range QueryTokens(keyword) {
+			if !slices.Contains(document, term) {
+				t.Errorf("キーワード %q のトークン %q がインデックスにありません", keyword, term)
+			}
+		}
+	}
+}
@@ -0,0 +1,79 @@
+package throttle
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/sotaheavymetal21/rabbit-cart/backend/internal/domain/repository"
+)
+
+// memoryAttemptStore はメモリに失敗時刻を保持する LoginAttemptStore の実装です
+// 単一プロセスでしか共有されないため、ローカル開発や検証用に使用します
+type memoryAttemptStore struct {
+	mu       sync.Mutex
+	failures map[string][]time.Time
+	locks    map[string]time.Time
+	now      func() time.Time
+}
+
+// NewMemoryAttemptStore はメモリの LoginAttemptStore を生成します
+func NewMemoryAttemptStore() repository.LoginAttemptStore {
+	return &memoryAttemptStore{
+		failures: make(map[string][]time.Time),
+		locks:    make(map[string]time.Time),
+		now:      time.Now,
+	}
+}
+
+// RecordFailure は key の失敗を記録し、直近 window 内の失敗回数を返します
+func (s *memoryAttemptStore) RecordFailure(ctx context.Context, key string, window time.Duration) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	threshold := now.Add(-window)
+	recent := s.failures[key][:0]
+	for _, t := range s.failures[key] {
+		if t.After(threshold) {
+			recent = append(recent, t)
+		}
+	}
+	s.failures[key] = append(recent, now)
+	return len(s.failures[key]), nil
+}
+
+// Reset は key の失敗回数とロックを削除します
+func (s *memoryAttemptStore) Reset(ctx context.Context, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.failures, key)
+	delete(s.locks, key)
+	return nil
+}
+
+// Lock は key を d の間ロックします (既にロックされている場合は長い方を残します)
+func (s *memoryAttemptStore) Lock(ctx context.Context, key string, d time.Duration) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	until := s.now().Add(d)
+	if until.After(s.locks[key]) {
+		s.locks[key] = until
+	}
+	return nil
+}
+
+// LockedFor は key のロックの残り時間を返します
+func (s *memoryAttemptStore) LockedFor(ctx context.Context, key string) (time.Duration, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	remaining := s.locks[key].Sub(s.now())
+	if remaining <= 0 {
+		delete(s.locks, key)
+		return 0, nil
+	}
+	return remaining, nil
+}
@@ -0,0 +1,106 @@
+// Package throttle はログイン試行回数の保存先 (Redis、メモリ) を提供します
+package throttle
+
+import (
+	"context"
+	"math/rand/v2"
+	"strconv"
+	"time"
+
+	"github.com/gomodule/redigo/redis"
+	"github.com/sotaheavymetal21/rabbit-cart/backend/internal/domain/repository"
+)
+
+// redisAttemptStore は Redis の sorted set で失敗時刻を保持する LoginAttemptStore の実装です
+// 失敗時刻をスコアとして保存し、window より古いものを削除してから数えることでスライディングウィンドウにしています
+type redisAttemptStore struct {
+	pool   *redis.Pool
+	prefix string
+}
+
+// NewRedisPool は addr ("host:port") の Redis に接続するコネクションプールを生成します
+func NewRedisPool(addr string) *redis.Pool {
+	return &redis.Pool{
+		MaxIdle:     10,
+		IdleTimeout: 240 * time.Second,
+		Dial: func() (redis.Conn, error) {
+			return redis.Dial("tcp", addr)
+		},
+	}
+}
+
+// NewRedisAttemptStore は Redis の LoginAttemptStore を生成します
+func NewRedisAttemptStore(pool *redis.Pool) repository.LoginAttemptStore {
+	return &redisAttemptStore{pool: pool, prefix: "rabbit_cart:login:"}
+}
+
+// RecordFailure は key の失敗を記録し、直近 window 内の失敗回数を返します
+func (s *redisAttemptStore) RecordFailure(ctx context.Context, key string, window time.Duration) (int, error) {
+	conn, err := s.pool.GetContext(ctx)
+	if err != nil {
+		return 0, err
+	}
+	defer conn.Close()
+
+	now := time.Now().UnixNano()
+	k := s.prefix + "failures:" + key
+	conn.Send("MULTI")
+	conn.Send("ZREMRANGEBYSCORE", k, "-inf", now-window.Nanoseconds())
+	// 複数のサーバーで同時に失敗しても別の要素になるよう、メンバーには乱数を付ける
+	conn.Send("ZADD", k, now, strconv.FormatInt(now, 10)+":"+strconv.FormatUint(rand.Uint64(), 36))
+	conn.Send("ZCARD", k)
+	conn.Send("PEXPIRE", k, window.Milliseconds())
+	values, err := redis.Values(conn.Do("EXEC"))
+	if err != nil {
+		return 0, err
+	}
+	return redis.Int(values[2], nil)
+}
+
+// Reset は key の失敗回数とロックを削除します
+func (s *redisAttemptStore) Reset(ctx context.Context, key string) error {
+	conn, err := s.pool.GetContext(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	_, err = conn.Do("DEL", s.prefix+"failures:"+key, s.prefix+"lock:"+key)
+	return err
+}
+
+// Lock は key を d の間ロックします (既にロックされている場合は長い方を残します)
+func (s *redisAttemptStore) Lock(ctx context.Context, key string, d time.Duration) error {
+	remaining, err := s.LockedFor(ctx, key)
+	if err != nil || remaining >= d {
+		return err
+	}
+
+	conn, err := s.pool.GetContext(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	_, err = conn.Do("SET", s.prefix+"lock:"+key, 1, "PX", d.Milliseconds())
+	return err
+}
+
+// LockedFor は key のロックの残り時間を返します
+func (s *redisAttemptStore) LockedFor(ctx context.Context, key string) (time.Duration, error) {
+	conn, err := s.pool.GetContext(ctx)
+	if err != nil {
+		return 0, err
+	}
+	defer conn.Close()
+
+	ms, err := redis.Int64(conn.Do("PTTL", s.prefix+"lock:"+key))
+	if err != nil {
+		return 0, err
+	}
+	// キーが無い場合は -2、有効期限が無い場合は -1 が返る
+	if ms <= 0 {
+		return 0, nil
+	}
+	return time.Duration(ms) * time.Millisecond, nil
+}
@@ -0,0 +1,100 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sotaheavymetal21/rabbit-cart/backend/internal/domain/entity"
+	"github.com/sotaheavymetal21/rabbit-cart/backend/internal/usecase"
+)
+
+type AddressHandler interface {
+	ListAddresses(c *gin.Context)
+	CreateAddress(c *gin.Context)
+	UpdateAddress(c *gin.Context)
+	DeleteAddress(c *gin.Context)
+}
+
+type addressHandler struct {
+	useCase usecase.AddressUseCase
+}
+
+// NewAddressHandler は AddressHandler の実装を生成します
+func NewAddressHandler(u usecase.AddressUseCase) AddressHandler {
+	return &addressHandler{useCase: u}
+}
+
+// ListAddresses は住所録の一覧を返すハンドラーです
+func (h *addressHandler) ListAddresses(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.Error(entity.ErrUnauthenticated)
+		return
+	}
+
+	addresses, err := h.useCase.List(c.Request.Context(), userID.(string))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"addresses": addresses})
+}
+
+// CreateAddress は住所録に住所を追加するハンドラーです
+func (h *addressHandler) CreateAddress(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.Error(entity.ErrUnauthenticated)
+		return
+	}
+
+	var input usecase.AddressInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.Error(bindError(err))
+		return
+	}
+
+	address, err := h.useCase.Create(c.Request.Context(), userID.(string), input)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusCreated, address)
+}
+
+// UpdateAddress は住所録の住所を更新するハンドラーです
+func (h *addressHandler) UpdateAddress(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.Error(entity.ErrUnauthenticated)
+		return
+	}
+
+	var input usecase.AddressInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.Error(bindError(err))
+		return
+	}
+
+	address, err := h.useCase.Update(c.Request.Context(), userID.(string), c.Param("id"), input)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, address)
+}
+
+// DeleteAddress は住所録から住所を削除するハンドラーです
+func (h *addressHandler) DeleteAddress(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.Error(entity.ErrUnauthenticated)
+		return
+	}
+
+	if err := h.useCase.Delete(c.Request.Context(), userID.(string), c.Param("id")); err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": translate(c, "address_deleted")})
+}
@@ -0,0 +1,78 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sotaheavymetal21/rabbit-cart/backend/internal/domain/entity"
+	"github.com/sotaheavymetal21/rabbit-cart/backend/internal/usecase"
+)
+
+type AdminOrderHandler interface {
+	UpdateOrderStatus(c *gin.Context)
+	RefundOrder(c *gin.Context)
+}
+
+type adminOrderHandler struct {
+	useCase       usecase.OrderUseCase
+	refundUseCase usecase.RefundUseCase
+}
+
+// NewAdminOrderHandler は AdminOrderHandler の実装を生成します
+func NewAdminOrderHandler(u usecase.OrderUseCase, refundUseCase usecase.RefundUseCase) AdminOrderHandler {
+	return &adminOrderHandler{useCase: u, refundUseCase: refundUseCase}
+}
+
+// OrderStatusRequest は注文ステータス変更リクエストの構造体です
+type OrderStatusRequest struct {
+	Status string `json:"status" binding:"required,oneof=paid shipped delivered completed cancelled"`
+	Reason string `json:"reason" binding:"max=500"`
+}
+
+// UpdateOrderStatus は管理者が注文ステータスを変更するハンドラーです
+// 発送 (shipped) にすると決済の売上を確定し、キャンセルすると与信を取り消します
+func (h *adminOrderHandler) UpdateOrderStatus(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.Error(entity.ErrUnauthenticated)
+		return
+	}
+
+	var req OrderStatusRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(bindError(err))
+		return
+	}
+
+	order, err := h.useCase.TransitionStatus(c.Request.Context(), c.Param("id"), userID.(string), entity.OrderStatus(req.Status), req.Reason)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, order)
+}
+
+// RefundOrder は注文を返金するハンドラーです
+// ボディが空、または items が空の場合は未返金の全額を返金します
+func (h *adminOrderHandler) RefundOrder(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.Error(entity.ErrUnauthenticated)
+		return
+	}
+
+	var input usecase.RefundInput
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&input); err != nil {
+			c.Error(bindError(err))
+			return
+		}
+	}
+
+	order, err := h.refundUseCase.RefundOrder(c.Request.Context(), c.Param("id"), userID.(string), input)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, order)
+}
@@ -0,0 +1,42 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sotaheavymetal21/rabbit-cart/backend/internal/usecase"
+)
+
+type AdminPaymentHandler interface {
+	ListDeadLetters(c *gin.Context)
+	RedriveDeadLetter(c *gin.Context)
+}
+
+type adminPaymentHandler struct {
+	webhookUseCase usecase.PaymentWebhookUseCase
+}
+
+// NewAdminPaymentHandler は AdminPaymentHandler の実装を生成します
+func NewAdminPaymentHandler(webhookUseCase usecase.PaymentWebhookUseCase) AdminPaymentHandler {
+	return &adminPaymentHandler{webhookUseCase: webhookUseCase}
+}
+
+// ListDeadLetters は処理に失敗した決済イベントの一覧を返すハンドラーです
+func (h *adminPaymentHandler) ListDeadLetters(c *gin.Context) {
+	deadLetters, err := h.webhookUseCase.ListDeadLetters(c.Request.Context())
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, deadLetters)
+}
+
+// RedriveDeadLetter は処理に失敗した決済イベントを再処理するハンドラーです
+func (h *adminPaymentHandler) RedriveDeadLetter(c *gin.Context) {
+	deadLetter, err := h.webhookUseCase.Redrive(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, deadLetter)
+}
@@ -0,0 +1,202 @@
+package handler
+
+import (
+	"io"
+	"log"
+	"net/http"
+	"path/filepath"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sotaheavymetal21/rabbit-cart/backend/internal/usecase"
+)
+
+type AdminProductHandler interface {
+	CreateProduct(c *gin.Context)
+	UpdateProduct(c *gin.Context)
+	DeleteProduct(c *gin.Context)
+	RestockProduct(c *gin.Context)
+	ImportProducts(c *gin.Context)
+	ExportProducts(c *gin.Context)
+}
+
+// maxCatalogUploadSize は商品データ取り込み時のリクエストボディの上限です
+const maxCatalogUploadSize = 20 << 20
+
+type adminProductHandler struct {
+	useCase        usecase.ProductUseCase
+	catalogUseCase usecase.CatalogUseCase
+}
+
+// NewAdminProductHandler は AdminProductHandler の実装を生成します
+func NewAdminProductHandler(u usecase.ProductUseCase, catalogUseCase usecase.CatalogUseCase) AdminProductHandler {
+	return &adminProductHandler{useCase: u, catalogUseCase: catalogUseCase}
+}
+
+// ProductRequest は商品の作成・更新リクエストの構造体です
+// stock は作成時の初期在庫です (省略時は 0)。更新時は無視し、在庫の変更は在庫補充 API で行います
+type ProductRequest struct {
+	SKU         string `json:"sku" binding:"max=64"`
+	Name        string `json:"name" binding:"required,max=200"`
+	Description string `json:"description" binding:"max=5000"`
+	Price       *int   `json:"price" binding:"required,min=0"`
+	Stock       *int   `json:"stock" binding:"omitempty,min=0"`
+	ImageURL    string `json:"image_url" binding:"max=2048"`
+	Category    string `json:"category" binding:"required,max=100"`
+}
+
+func (r *ProductRequest) toInput() usecase.ProductInput {
+	input := usecase.ProductInput{
+		SKU:         r.SKU,
+		Name:        r.Name,
+		Description: r.Description,
+		Price:       *r.Price,
+		ImageURL:    r.ImageURL,
+		Category:    r.Category,
+	}
+	if r.Stock != nil {
+		input.Stock = *r.Stock
+	}
+	return input
+}
+
+// RestockRequest は在庫補充リクエストの構造体です
+type RestockRequest struct {
+	Quantity int `json:"quantity" binding:"required,min=1"`
+}
+
+// CreateProduct は商品を作成するハンドラーです
+func (h *adminProductHandler) CreateProduct(c *gin.Context) {
+	var req ProductRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(bindError(err))
+		return
+	}
+
+	product, err := h.useCase.CreateProduct(c.Request.Context(), req.toInput())
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusCreated, product)
+}
+
+// UpdateProduct は商品情報を更新するハンドラーです
+func (h *adminProductHandler) UpdateProduct(c *gin.Context) {
+	var req ProductRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(bindError(err))
+		return
+	}
+
+	product, err := h.useCase.UpdateProduct(c.Request.Context(), c.Param("id"), req.toInput())
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, product)
+}
+
+// DeleteProduct は商品を削除するハンドラーです
+func (h *adminProductHandler) DeleteProduct(c *gin.Context) {
+	if err := h.useCase.DeleteProduct(c.Request.Context(), c.Param("id")); err != nil {
+		c.Error(err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// RestockProduct は商品の在庫を補充するハンドラーです
+func (h *adminProductHandler) RestockProduct(c *gin.Context) {
+	var req RestockRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(bindError(err))
+		return
+	}
+
+	product, err := h.useCase.RestockProduct(c.Request.Context(), c.Param("id"), req.Quantity)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, product)
+}
+
+// ImportProducts は CSV / JSONL の商品データを一括で取り込むハンドラーです
+// ファイルは multipart の file フィールド、またはリクエストボディそのもので受け取ります
+// 形式は format クエリ、ファイルの拡張子、Content-Type の順に判定します
+func (h *adminProductHandler) ImportProducts(c *gin.Context) {
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxCatalogUploadSize)
+
+	body := io.Reader(c.Request.Body)
+	formatHint := c.Query("format")
+	if file, header, err := c.Request.FormFile("file"); err == nil {
+		defer file.Close()
+		body = file
+		if formatHint == "" {
+			formatHint = strings.TrimPrefix(filepath.Ext(header.Filename), ".")
+		}
+	}
+	if formatHint == "" {
+		formatHint = catalogFormatFromContentType(c.ContentType())
+	}
+
+	format, err := usecase.ParseCatalogFormat(formatHint)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	opts := usecase.CatalogImportOptions{
+		DryRun:       c.Query("dry_run") == "true",
+		AllowPartial: c.Query("allow_partial") == "true",
+	}
+	report, err := h.catalogUseCase.Import(c.Request.Context(), format, body, opts)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	status := http.StatusOK
+	if report.Failed > 0 {
+		status = http.StatusUnprocessableEntity
+		if report.Imported {
+			status = http.StatusMultiStatus
+		}
+	}
+	c.JSON(status, report)
+}
+
+// ExportProducts は全商品を CSV / JSONL で書き出すハンドラーです
+func (h *adminProductHandler) ExportProducts(c *gin.Context) {
+	format, err := usecase.ParseCatalogFormat(c.DefaultQuery("format", string(usecase.CatalogFormatCSV)))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	contentType := "text/csv; charset=utf-8"
+	if format == usecase.CatalogFormatJSONL {
+		contentType = "application/x-ndjson"
+	}
+	c.Header("Content-Type", contentType)
+	c.Header("Content-Disposition", `attachment; filename="products.`+string(format)+`"`)
+	c.Status(http.StatusOK)
+
+	// 書き出しを始めた後はステータスコードを変更できないため、エラーはログに残すだけにする
+	if err := h.catalogUseCase.Export(c.Request.Context(), format, c.Writer); err != nil {
+		log.Printf("商品データの書き出しに失敗しました: %v", err)
+	}
+}
+
+// catalogFormatFromContentType は Content-Type から取り込み形式を推測します
+func catalogFormatFromContentType(contentType string) string {
+	switch contentType {
+	case "text/csv":
+		return string(usecase.CatalogFormatCSV)
+	case "application/x-ndjson", "application/jsonl", "application/json":
+		return string(usecase.CatalogFormatJSONL)
+	default:
+		return ""
+	}
+}
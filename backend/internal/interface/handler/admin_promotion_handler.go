@@ -0,0 +1,86 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sotaheavymetal21/rabbit-cart/backend/internal/usecase"
+)
+
+type AdminPromotionHandler interface {
+	ListPromotions(c *gin.Context)
+	GetPromotion(c *gin.Context)
+	CreatePromotion(c *gin.Context)
+	UpdatePromotion(c *gin.Context)
+	DeletePromotion(c *gin.Context)
+}
+
+type adminPromotionHandler struct {
+	useCase usecase.PromotionUseCase
+}
+
+// NewAdminPromotionHandler は AdminPromotionHandler の実装を生成します
+func NewAdminPromotionHandler(u usecase.PromotionUseCase) AdminPromotionHandler {
+	return &adminPromotionHandler{useCase: u}
+}
+
+// ListPromotions はクーポンの一覧を返すハンドラーです
+func (h *adminPromotionHandler) ListPromotions(c *gin.Context) {
+	promotions, err := h.useCase.ListPromotions(c.Request.Context())
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"promotions": promotions})
+}
+
+// GetPromotion はクーポンを返すハンドラーです
+func (h *adminPromotionHandler) GetPromotion(c *gin.Context) {
+	promotion, err := h.useCase.GetPromotion(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, promotion)
+}
+
+// CreatePromotion はクーポンを作成するハンドラーです
+func (h *adminPromotionHandler) CreatePromotion(c *gin.Context) {
+	var input usecase.PromotionInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.Error(bindError(err))
+		return
+	}
+
+	promotion, err := h.useCase.CreatePromotion(c.Request.Context(), input)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusCreated, promotion)
+}
+
+// UpdatePromotion はクーポンの設定を更新するハンドラーです
+func (h *adminPromotionHandler) UpdatePromotion(c *gin.Context) {
+	var input usecase.PromotionInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.Error(bindError(err))
+		return
+	}
+
+	promotion, err := h.useCase.UpdatePromotion(c.Request.Context(), c.Param("id"), input)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, promotion)
+}
+
+// DeletePromotion はクーポンを削除するハンドラーです
+func (h *adminPromotionHandler) DeletePromotion(c *gin.Context) {
+	if err := h.useCase.DeletePromotion(c.Request.Context(), c.Param("id")); err != nil {
+		c.Error(err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
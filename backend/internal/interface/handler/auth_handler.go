@@ -1,27 +1,55 @@
 package handler
 
 import (
+	"errors"
+	"log"
 	"net/http"
+	"time"
 
 	"github.com/gin-contrib/sessions"
 	"github.com/gin-gonic/gin"
+	"github.com/sotaheavymetal21/rabbit-cart/backend/internal/domain/entity"
+	"github.com/sotaheavymetal21/rabbit-cart/backend/internal/infrastructure/middleware"
 	"github.com/sotaheavymetal21/rabbit-cart/backend/internal/usecase"
 )
 
+var (
+	// errLoginMFACode はログイン時の確認コードの誤りで、パスワードの誤りと同じく 401 として返します
+	errLoginMFACode      = entity.NewUnauthorizedError("invalid_mfa_code", usecase.ErrInvalidMFACode.Message)
+	errTokenAuthDisabled = entity.NewNotFoundError("token_auth_disabled", "トークン認証は有効になっていません")
+)
+
 type AuthHandler interface {
 	Register(c *gin.Context)
 	Login(c *gin.Context)
 	Logout(c *gin.Context)
+	RefreshToken(c *gin.Context)
+	ForgotPassword(c *gin.Context)
+	ResetPassword(c *gin.Context)
+	VerifyEmail(c *gin.Context)
+	ResendVerification(c *gin.Context)
+	VerifyMFA(c *gin.Context)
+	OIDCLogin(c *gin.Context)
+	OIDCCallback(c *gin.Context)
+	SetupTOTP(c *gin.Context)
+	ConfirmTOTP(c *gin.Context)
+	DisableTOTP(c *gin.Context)
 	GetCurrentUser(c *gin.Context)
 }
 
 type authHandler struct {
-	useCase usecase.AuthUseCase
+	useCase        usecase.AuthUseCase
+	cartUseCase    usecase.CartUseCase
+	tokenUseCase   usecase.TokenUseCase
+	sessionUseCase usecase.SessionUseCase
+	loginGuard     usecase.LoginGuard
+	mode           entity.AuthMode
 }
 
 // NewAuthHandler は AuthHandler の実装を生成します
-func NewAuthHandler(u usecase.AuthUseCase) AuthHandler {
-	return &authHandler{useCase: u}
+// mode が JWT を含む場合、ログイン・登録時に tokenUseCase でアクセストークンとリフレッシュトークンを発行します
+func NewAuthHandler(u usecase.AuthUseCase, cartUseCase usecase.CartUseCase, tokenUseCase usecase.TokenUseCase, sessionUseCase usecase.SessionUseCase, loginGuard usecase.LoginGuard, mode entity.AuthMode) AuthHandler {
+	return &authHandler{useCase: u, cartUseCase: cartUseCase, tokenUseCase: tokenUseCase, sessionUseCase: sessionUseCase, loginGuard: loginGuard, mode: mode}
 }
 
 // RegisterRequest は登録リクエストの構造体です
@@ -36,73 +64,410 @@ type LoginRequest struct {
 	Password string `json:"password" binding:"required"`
 }
 
+// RefreshTokenRequest はトークンのリフレッシュ・失効リクエストの構造体です
+type RefreshTokenRequest struct {
+	RefreshToken string `json:"refresh_token"`
+}
+
+// ForgotPasswordRequest はパスワードリセット要求の構造体です
+type ForgotPasswordRequest struct {
+	Email string `json:"email" binding:"required,email"`
+}
+
+// ResetPasswordRequest はパスワード再設定リクエストの構造体です
+type ResetPasswordRequest struct {
+	Token    string `json:"token" binding:"required"`
+	Password string `json:"password" binding:"required,min=6"`
+}
+
+// VerifyEmailRequest はメールアドレス確認リクエストの構造体です
+type VerifyEmailRequest struct {
+	Token string `json:"token" binding:"required"`
+}
+
+// VerifyMFARequest は二要素認証のコード入力リクエストの構造体です
+type VerifyMFARequest struct {
+	MFAToken string `json:"mfa_token" binding:"required"`
+	Code     string `json:"code" binding:"required"` // TOTP コードまたはリカバリーコード
+}
+
+// MFACodeRequest は二要素認証の設定・解除時のコード入力リクエストの構造体です
+type MFACodeRequest struct {
+	Code string `json:"code" binding:"required"`
+}
+
 // Register はユーザー登録のハンドラーです
 func (h *authHandler) Register(c *gin.Context) {
 	var req RegisterRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "入力内容が正しくありません"})
+		c.Error(bindError(err))
 		return
 	}
 
 	user, err := h.useCase.Register(c.Request.Context(), req.Email, req.Password)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
-		return
-	}
-
-	// セッションにユーザーIDを保存
-	session := sessions.Default(c)
-	session.Set("user_id", user.ID)
-	if err := session.Save(); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "セッションの保存に失敗しました"})
+		c.Error(err)
 		return
 	}
 
-	c.JSON(http.StatusCreated, gin.H{
-		"message": "登録が完了しました",
-		"user":    user,
-	})
+	h.respondLogin(c, user, http.StatusCreated, "registered")
 }
 
 // Login はログインのハンドラーです
 func (h *authHandler) Login(c *gin.Context) {
 	var req LoginRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "入力内容が正しくありません"})
+		c.Error(bindError(err))
+		return
+	}
+
+	// 失敗が続いているメールアドレス・IP からの試行はパスワードを検証せずに拒否する
+	ctx := c.Request.Context()
+	ip := c.ClientIP()
+	if err := h.loginGuard.Check(ctx, req.Email, ip); err != nil {
+		c.Error(err)
 		return
 	}
 
-	user, err := h.useCase.Login(c.Request.Context(), req.Email, req.Password)
+	result, err := h.useCase.Login(ctx, req.Email, req.Password)
 	if err != nil {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		if errors.Is(err, usecase.ErrInvalidCredentials) {
+			// 失敗回数が上限に達した場合は *usecase.ThrottledError が返る
+			if err := h.loginGuard.RecordFailure(ctx, req.Email, ip); err != nil {
+				c.Error(err)
+				return
+			}
+		}
+		c.Error(err)
 		return
 	}
 
-	// セッションにユーザーIDを保存
+	// 二要素認証が有効な場合はコードの入力を求める (ログインはまだ完了していない)
+	if result.User == nil {
+		c.JSON(http.StatusOK, gin.H{
+			"message":        translate(c, "mfa_code_required"),
+			"mfa_required":   true,
+			"mfa_token":      result.MFAToken,
+			"mfa_expires_in": result.MFAExpiresIn,
+		})
+		return
+	}
+
+	h.completeLogin(c, result.User, ip)
+}
+
+// VerifyMFA は二要素認証のコードを検証してログインを完了するハンドラーです
+func (h *authHandler) VerifyMFA(c *gin.Context) {
+	var req VerifyMFARequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(bindError(err))
+		return
+	}
+
+	ctx := c.Request.Context()
+	ip := c.ClientIP()
+	user, err := h.useCase.VerifyMFA(ctx, req.MFAToken, req.Code)
+	if err != nil {
+		var codeErr *usecase.InvalidMFACodeError
+		if errors.As(err, &codeErr) {
+			// コードの総当たりもパスワードと同じくログイン失敗として数える
+			if err := h.loginGuard.RecordFailure(ctx, codeErr.Email, ip); err != nil {
+				c.Error(err)
+				return
+			}
+			err = errLoginMFACode
+		}
+		c.Error(err)
+		return
+	}
+
+	h.completeLogin(c, user, ip)
+}
+
+// OIDCLogin は外部 ID プロバイダーの認可エンドポイントへリダイレクトするハンドラーです
+// state・nonce・PKCE のコード検証値はこのブラウザのセッションに保存し、OIDCCallback で照合します
+func (h *authHandler) OIDCLogin(c *gin.Context) {
+	login, err := h.useCase.BeginOIDCLogin(c.Request.Context(), c.Param("provider"))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	session := sessions.Default(c)
+	session.Set("oidc_provider", login.State.Provider)
+	session.Set("oidc_state", login.State.State)
+	session.Set("oidc_nonce", login.State.Nonce)
+	session.Set("oidc_code_verifier", login.State.CodeVerifier)
+	session.Set("oidc_expires_at", login.State.ExpiresAt.Unix())
+	if err := session.Save(); err != nil {
+		c.Error(err)
+		return
+	}
+	c.Redirect(http.StatusFound, login.URL)
+}
+
+// OIDCCallback は外部 ID プロバイダーから戻ってきた認可コードでログインを完了するハンドラーです
+// プロバイダーの redirect_url にはこのエンドポイント、またはこのエンドポイントへクエリを引き継ぐフロントエンドの画面を指定します
+func (h *authHandler) OIDCCallback(c *gin.Context) {
 	session := sessions.Default(c)
-	session.Set("user_id", user.ID)
+	pending := loadOIDCLoginState(session)
+	// state は一度しか使えないよう、結果に関わらず削除する
+	for _, key := range []string{"oidc_provider", "oidc_state", "oidc_nonce", "oidc_code_verifier", "oidc_expires_at"} {
+		session.Delete(key)
+	}
 	if err := session.Save(); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "セッションの保存に失敗しました"})
+		c.Error(err)
 		return
 	}
 
+	if pending != nil && pending.Provider != c.Param("provider") {
+		pending = nil
+	}
+	// ユーザーがプロバイダーでの同意を拒否した場合など
+	if errorCode := c.Query("error"); errorCode != "" {
+		log.Printf("ID プロバイダー %s でのログインが中断されました: %s", c.Param("provider"), errorCode)
+		c.Error(usecase.ErrOIDCLoginFailed)
+		return
+	}
+
+	result, err := h.useCase.CompleteOIDCLogin(c.Request.Context(), pending, c.Query("state"), c.Query("code"))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	if result.User == nil {
+		c.JSON(http.StatusOK, gin.H{
+			"message":        translate(c, "mfa_code_required"),
+			"mfa_required":   true,
+			"mfa_token":      result.MFAToken,
+			"mfa_expires_in": result.MFAExpiresIn,
+		})
+		return
+	}
+
+	// パスワードによるログインではないため、ログイン失敗回数はリセットしない
+	h.respondLogin(c, result.User, http.StatusOK, "logged_in")
+}
+
+// SetupTOTP は二要素認証の設定を開始し、認証アプリに登録する情報を返すハンドラーです
+func (h *authHandler) SetupTOTP(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.Error(entity.ErrUnauthenticated)
+		return
+	}
+
+	setup, err := h.useCase.SetupTOTP(c.Request.Context(), userID.(string))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, setup)
+}
+
+// ConfirmTOTP は認証アプリのコードを確認して二要素認証を有効にするハンドラーです
+// リカバリーコードはこのレスポンスでしか表示できません
+func (h *authHandler) ConfirmTOTP(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.Error(entity.ErrUnauthenticated)
+		return
+	}
+
+	var req MFACodeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(bindError(err))
+		return
+	}
+
+	codes, err := h.useCase.ConfirmTOTP(c.Request.Context(), userID.(string), req.Code)
+	if err != nil {
+		c.Error(err)
+		return
+	}
 	c.JSON(http.StatusOK, gin.H{
-		"message": "ログインに成功しました",
-		"user":    user,
+		"message":        translate(c, "mfa_enabled"),
+		"recovery_codes": codes,
 	})
 }
 
+// DisableTOTP はコードを確認して二要素認証を無効にするハンドラーです
+// セッションを奪われた場合にコードの総当たりで無効にされないよう、ログインと同じ失敗回数の制限を課します
+func (h *authHandler) DisableTOTP(c *gin.Context) {
+	value, exists := c.Get("user")
+	if !exists {
+		c.Error(entity.ErrUnauthenticated)
+		return
+	}
+	user := value.(*entity.User)
+
+	var req MFACodeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(bindError(err))
+		return
+	}
+
+	ctx := c.Request.Context()
+	ip := c.ClientIP()
+	if err := h.loginGuard.Check(ctx, user.Email, ip); err != nil {
+		c.Error(err)
+		return
+	}
+	if err := h.useCase.DisableTOTP(ctx, user.ID, req.Code); err != nil {
+		var codeErr *usecase.InvalidMFACodeError
+		if errors.As(err, &codeErr) {
+			if err := h.loginGuard.RecordFailure(ctx, codeErr.Email, ip); err != nil {
+				c.Error(err)
+				return
+			}
+		}
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": translate(c, "mfa_disabled")})
+}
+
 // Logout はログアウトのハンドラーです
+// 現在のログインセッションを失効させ、
+// JWT を使用している場合は Authorization ヘッダーのアクセストークンと、ボディで指定されたリフレッシュトークンも失効させます
 func (h *authHandler) Logout(c *gin.Context) {
 	session := sessions.Default(c)
+	userID, _ := session.Get("user_id").(string)
+	sessionID, _ := session.Get("session_id").(string)
+
+	if h.mode.UsesJWT() {
+		claims, err := h.revokeTokens(c)
+		if err != nil {
+			c.Error(err)
+			return
+		}
+		if claims != nil && sessionID == "" {
+			userID, sessionID = claims.Subject, claims.SessionID
+		}
+	}
+
+	if userID != "" && sessionID != "" {
+		if err := h.sessionUseCase.Revoke(c.Request.Context(), userID, sessionID); err != nil && !errors.Is(err, usecase.ErrSessionNotFound) {
+			c.Error(err)
+			return
+		}
+	}
+
 	session.Clear()
 	if err := session.Save(); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "ログアウトに失敗しました"})
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": translate(c, "logged_out"),
+	})
+}
+
+// RefreshToken はリフレッシュトークンで新しいトークンの組を発行するハンドラーです
+func (h *authHandler) RefreshToken(c *gin.Context) {
+	if !h.mode.UsesJWT() {
+		c.Error(errTokenAuthDisabled)
+		return
+	}
+
+	var req RefreshTokenRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(bindError(err))
+		return
+	}
+	if req.RefreshToken == "" {
+		c.Error(entity.NewFieldError("invalid_request", "refresh_token", "リフレッシュトークンを指定してください"))
+		return
+	}
+
+	tokens, err := h.tokenUseCase.Refresh(c.Request.Context(), req.RefreshToken)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"tokens": tokens})
+}
+
+// ForgotPassword はパスワードリセット用のリンクをメールで送信するハンドラーです
+// メールアドレスの登録有無に関わらず同じレスポンスを返します
+func (h *authHandler) ForgotPassword(c *gin.Context) {
+	var req ForgotPasswordRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(bindError(err))
+		return
+	}
+
+	if err := h.useCase.RequestPasswordReset(c.Request.Context(), req.Email); err != nil {
+		log.Printf("パスワードリセットメールの送信に失敗しました: %v", err)
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusAccepted, gin.H{
+		"message": translate(c, "password_reset_requested"),
+	})
+}
+
+// ResetPassword はトークンを検証して新しいパスワードを設定するハンドラーです
+func (h *authHandler) ResetPassword(c *gin.Context) {
+	var req ResetPasswordRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(bindError(err))
+		return
+	}
+
+	if err := h.useCase.ResetPassword(c.Request.Context(), req.Token, req.Password); err != nil {
+		c.Error(err)
+		return
+	}
+
+	// このリクエストのセッションも古いログインのため破棄する
+	session := sessions.Default(c)
+	forgetLogin(session)
+	_ = session.Save()
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": translate(c, "password_reset"),
+	})
+}
+
+// VerifyEmail はトークンを検証してメールアドレスを確認済みにするハンドラーです
+func (h *authHandler) VerifyEmail(c *gin.Context) {
+	var req VerifyEmailRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(bindError(err))
+		return
+	}
+
+	user, err := h.useCase.VerifyEmail(c.Request.Context(), req.Token)
+	if err != nil {
+		c.Error(err)
 		return
 	}
 
 	c.JSON(http.StatusOK, gin.H{
-		"message": "ログアウトしました",
+		"message": translate(c, "email_verified"),
+		"user":    user,
+	})
+}
+
+// ResendVerification はログインユーザーにメールアドレス確認用のリンクを再送するハンドラーです
+func (h *authHandler) ResendVerification(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.Error(entity.ErrUnauthenticated)
+		return
+	}
+
+	if err := h.useCase.ResendVerification(c.Request.Context(), userID.(string)); err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusAccepted, gin.H{
+		"message": translate(c, "verification_sent"),
 	})
 }
 
@@ -111,7 +476,7 @@ func (h *authHandler) GetCurrentUser(c *gin.Context) {
 	// ミドルウェアでセットされたユーザー情報を取得
 	user, exists := c.Get("user")
 	if !exists {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "ログインしていません"})
+		c.Error(entity.ErrUnauthenticated)
 		return
 	}
 
@@ -119,3 +484,113 @@ func (h *authHandler) GetCurrentUser(c *gin.Context) {
 		"user": user,
 	})
 }
+
+// completeLogin はログイン失敗回数をリセットし、認証情報を発行してレスポンスを返します
+func (h *authHandler) completeLogin(c *gin.Context, user *entity.User, ip string) {
+	if err := h.loginGuard.RecordSuccess(c.Request.Context(), user.Email, ip); err != nil {
+		log.Printf("ログイン失敗回数のリセットに失敗しました (user_id=%s): %v", user.ID, err)
+	}
+	h.respondLogin(c, user, http.StatusOK, "logged_in")
+}
+
+// loadOIDCLoginState はセッションに保存した外部 ID プロバイダーでのログインの状態を返します (無い場合は nil)
+func loadOIDCLoginState(session sessions.Session) *usecase.OIDCLoginState {
+	provider, _ := session.Get("oidc_provider").(string)
+	state, _ := session.Get("oidc_state").(string)
+	nonce, _ := session.Get("oidc_nonce").(string)
+	verifier, _ := session.Get("oidc_code_verifier").(string)
+	expiresAt, _ := session.Get("oidc_expires_at").(int64)
+	if provider == "" || state == "" || nonce == "" || verifier == "" {
+		return nil
+	}
+	return &usecase.OIDCLoginState{
+		Provider:     provider,
+		State:        state,
+		Nonce:        nonce,
+		CodeVerifier: verifier,
+		ExpiresAt:    time.Unix(expiresAt, 0),
+	}
+}
+
+// respondLogin はログイン・登録に成功したユーザーのログインセッションを作成し、認証情報を発行してレスポンスを返します
+// セッションにはユーザーIDを保存し、ゲストカートがあればユーザーのカートへ移します
+func (h *authHandler) respondLogin(c *gin.Context, user *entity.User, status int, messageKey string) {
+	if user.Locale.IsValid() {
+		// ログインしたユーザーが設定した言語で応答する
+		c.Set("locale", user.Locale)
+	}
+	res := gin.H{
+		"message": translate(c, messageKey),
+		"user":    user,
+	}
+
+	login, err := h.sessionUseCase.Start(c.Request.Context(), user.ID, c.ClientIP(), c.Request.UserAgent())
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	if h.mode.UsesJWT() {
+		tokens, err := h.tokenUseCase.Issue(c.Request.Context(), user, login.ID)
+		if err != nil {
+			c.Error(err)
+			return
+		}
+		res["tokens"] = tokens
+	}
+
+	session := sessions.Default(c)
+	if h.mode.UsesSession() {
+		session.Set("user_id", user.ID)
+		session.Set("session_id", login.ID)
+		session.Set("authenticated_at", time.Now().Unix())
+	}
+	merge := h.mergeGuestCart(c, session, user.ID)
+	if err := session.Save(); err != nil {
+		c.Error(err)
+		return
+	}
+	if merge != nil {
+		res["cart_merge"] = merge
+	}
+	c.JSON(status, res)
+}
+
+// revokeTokens はリクエストに含まれるアクセストークンとリフレッシュトークンを失効させ、アクセストークンのクレームを返します
+// 期限切れや不正なアクセストークンは失効させる必要が無いため無視します
+func (h *authHandler) revokeTokens(c *gin.Context) (*entity.AccessTokenClaims, error) {
+	var claims *entity.AccessTokenClaims
+	if token := middleware.BearerToken(c); token != "" {
+		claims, _ = h.tokenUseCase.Authenticate(c.Request.Context(), token)
+	}
+	var req RefreshTokenRequest
+	_ = c.ShouldBindJSON(&req)
+	if claims == nil && req.RefreshToken == "" {
+		return nil, nil
+	}
+	return claims, h.tokenUseCase.Revoke(c.Request.Context(), claims, req.RefreshToken)
+}
+
+// forgetLogin はセッションからログイン情報を削除します (ゲストカートなどは残します)
+func forgetLogin(session sessions.Session) {
+	session.Delete("user_id")
+	session.Delete("session_id")
+	session.Delete("authenticated_at")
+}
+
+// mergeGuestCart はセッションのゲストカートをユーザーのカートにマージします
+// マージに失敗してもログイン自体は成功させ、ゲストカートはセッションに残します
+func (h *authHandler) mergeGuestCart(c *gin.Context, session sessions.Session, userID string) *usecase.CartMergeResult {
+	items := loadGuestCart(session)
+	if len(items) == 0 {
+		return nil
+	}
+
+	result, err := h.cartUseCase.MergeGuestCart(c.Request.Context(), userID, items)
+	if err != nil {
+		log.Printf("ゲストカートのマージに失敗しました (user_id=%s): %v", userID, err)
+		return nil
+	}
+	session.Delete(guestCartSessionKey)
+	return result
+}
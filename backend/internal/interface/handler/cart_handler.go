@@ -0,0 +1,245 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/gin-contrib/sessions"
+	"github.com/gin-gonic/gin"
+	"github.com/sotaheavymetal21/rabbit-cart/backend/internal/domain/entity"
+	"github.com/sotaheavymetal21/rabbit-cart/backend/internal/usecase"
+)
+
+type CartHandler interface {
+	GetCart(c *gin.Context)
+	AddItem(c *gin.Context)
+	SetItemQuantity(c *gin.Context)
+	RemoveItem(c *gin.Context)
+	ClearCart(c *gin.Context)
+
+	GetGuestCart(c *gin.Context)
+	AddGuestItem(c *gin.Context)
+	SetGuestItemQuantity(c *gin.Context)
+	RemoveGuestItem(c *gin.Context)
+	ClearGuestCart(c *gin.Context)
+}
+
+// guestCartSessionKey はゲストカートを保存するセッションのキーです
+const guestCartSessionKey = "guest_cart"
+
+type cartHandler struct {
+	useCase usecase.CartUseCase
+}
+
+// NewCartHandler は CartHandler の実装を生成します
+func NewCartHandler(u usecase.CartUseCase) CartHandler {
+	return &cartHandler{useCase: u}
+}
+
+// AddCartItemRequest はカートへの商品追加リクエストの構造体です
+type AddCartItemRequest struct {
+	ProductID string `json:"product_id" binding:"required"`
+	Quantity  int    `json:"quantity" binding:"required,min=1"`
+}
+
+// SetCartItemQuantityRequest はカート内の数量変更リクエストの構造体です
+type SetCartItemQuantityRequest struct {
+	Quantity *int `json:"quantity" binding:"required,min=0"`
+}
+
+// GetCart はログインユーザーのカートを取得するハンドラーです
+func (h *cartHandler) GetCart(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.Error(entity.ErrUnauthenticated)
+		return
+	}
+
+	cart, err := h.useCase.GetCart(c.Request.Context(), userID.(string))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, cart)
+}
+
+// AddItem はカートに商品を追加するハンドラーです
+func (h *cartHandler) AddItem(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.Error(entity.ErrUnauthenticated)
+		return
+	}
+
+	var req AddCartItemRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(bindError(err))
+		return
+	}
+
+	cart, err := h.useCase.AddItem(c.Request.Context(), userID.(string), req.ProductID, req.Quantity)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, cart)
+}
+
+// SetItemQuantity はカート内の商品の数量を変更するハンドラーです
+func (h *cartHandler) SetItemQuantity(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.Error(entity.ErrUnauthenticated)
+		return
+	}
+
+	var req SetCartItemQuantityRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(bindError(err))
+		return
+	}
+
+	cart, err := h.useCase.SetItemQuantity(c.Request.Context(), userID.(string), c.Param("product_id"), *req.Quantity)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, cart)
+}
+
+// RemoveItem はカートから商品を削除するハンドラーです
+func (h *cartHandler) RemoveItem(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.Error(entity.ErrUnauthenticated)
+		return
+	}
+
+	cart, err := h.useCase.RemoveItem(c.Request.Context(), userID.(string), c.Param("product_id"))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, cart)
+}
+
+// ClearCart はカートを空にするハンドラーです
+func (h *cartHandler) ClearCart(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.Error(entity.ErrUnauthenticated)
+		return
+	}
+
+	cart, err := h.useCase.ClearCart(c.Request.Context(), userID.(string))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, cart)
+}
+
+// GetGuestCart はセッションに保存されたゲストカートを取得するハンドラーです
+func (h *cartHandler) GetGuestCart(c *gin.Context) {
+	items := loadGuestCart(sessions.Default(c))
+
+	cart, err := h.useCase.GetGuestCart(c.Request.Context(), items)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, cart)
+}
+
+// AddGuestItem はゲストカートに商品を追加するハンドラーです
+func (h *cartHandler) AddGuestItem(c *gin.Context) {
+	var req AddCartItemRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(bindError(err))
+		return
+	}
+
+	session := sessions.Default(c)
+	items, err := h.useCase.AddGuestItem(c.Request.Context(), loadGuestCart(session), req.ProductID, req.Quantity)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	h.respondGuestCart(c, session, items)
+}
+
+// SetGuestItemQuantity はゲストカート内の商品の数量を変更するハンドラーです
+func (h *cartHandler) SetGuestItemQuantity(c *gin.Context) {
+	var req SetCartItemQuantityRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(bindError(err))
+		return
+	}
+
+	session := sessions.Default(c)
+	items, err := h.useCase.SetGuestItemQuantity(c.Request.Context(), loadGuestCart(session), c.Param("product_id"), *req.Quantity)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	h.respondGuestCart(c, session, items)
+}
+
+// RemoveGuestItem はゲストカートから商品を削除するハンドラーです
+func (h *cartHandler) RemoveGuestItem(c *gin.Context) {
+	session := sessions.Default(c)
+	items, err := h.useCase.SetGuestItemQuantity(c.Request.Context(), loadGuestCart(session), c.Param("product_id"), 0)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	h.respondGuestCart(c, session, items)
+}
+
+// ClearGuestCart はゲストカートを空にするハンドラーです
+func (h *cartHandler) ClearGuestCart(c *gin.Context) {
+	h.respondGuestCart(c, sessions.Default(c), nil)
+}
+
+// respondGuestCart はゲストカートをセッションに保存し、商品情報付きのカートを返します
+func (h *cartHandler) respondGuestCart(c *gin.Context, session sessions.Session, items []entity.GuestCartItem) {
+	if err := saveGuestCart(session, items); err != nil {
+		c.Error(err)
+		return
+	}
+
+	cart, err := h.useCase.GetGuestCart(c.Request.Context(), items)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, cart)
+}
+
+// loadGuestCart はセッションからゲストカートの明細を読み込みます
+// セッションの値が壊れている場合は空のカートとして扱います
+func loadGuestCart(session sessions.Session) []entity.GuestCartItem {
+	raw, ok := session.Get(guestCartSessionKey).(string)
+	if !ok {
+		return nil
+	}
+	var items []entity.GuestCartItem
+	if err := json.Unmarshal([]byte(raw), &items); err != nil {
+		return nil
+	}
+	return items
+}
+
+// saveGuestCart はゲストカートの明細をセッションに保存します（空の場合はキーを削除します）
+func saveGuestCart(session sessions.Session, items []entity.GuestCartItem) error {
+	if len(items) == 0 {
+		session.Delete(guestCartSessionKey)
+		return session.Save()
+	}
+	raw, err := json.Marshal(items)
+	if err != nil {
+		return err
+	}
+	session.Set(guestCartSessionKey, string(raw))
+	return session.Save()
+}
@@ -0,0 +1,82 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"strings"
+
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
+	"github.com/sotaheavymetal21/rabbit-cart/backend/internal/domain/entity"
+)
+
+// ハンドラーはエラーを c.Error で登録して処理を終え、レスポンスへの変換は middleware.ErrorHandler に任せます
+
+func init() {
+	// 入力エラーの項目名を構造体のフィールド名ではなく JSON・クエリパラメータの名前で返す
+	if v, ok := binding.Validator.Engine().(*validator.Validate); ok {
+		v.RegisterTagNameFunc(func(f reflect.StructField) string {
+			for _, tag := range []string{"json", "form"} {
+				name, _, _ := strings.Cut(f.Tag.Get(tag), ",")
+				if name == "-" {
+					return ""
+				}
+				if name != "" {
+					return name
+				}
+			}
+			return f.Name
+		})
+	}
+}
+
+// bindError はリクエストの読み込み・検証のエラーを項目ごとの理由を含む *entity.ValidationError に変換します
+// 項目の理由は code と args のみを設定し、メッセージは middleware.ErrorHandler がリクエストの言語で埋めます
+func bindError(err error) error {
+	var (
+		fields         []entity.FieldError
+		validationErrs validator.ValidationErrors
+		typeErr        *json.UnmarshalTypeError
+	)
+	switch {
+	case errors.As(err, &validationErrs):
+		for _, fe := range validationErrs {
+			code, args := validationReason(fe)
+			fields = append(fields, entity.FieldError{Field: fieldPath(fe), Code: code, Args: args})
+		}
+	case errors.As(err, &typeErr):
+		fields = append(fields, entity.FieldError{Field: typeErr.Field, Code: "invalid_type"})
+	}
+	return entity.NewValidationError("invalid_request", "入力内容が正しくありません", fields...)
+}
+
+// fieldPath は検証エラーの項目名を "address.postal_code" の形式で返します (先頭の構造体名は除きます)
+func fieldPath(fe validator.FieldError) string {
+	if _, path, ok := strings.Cut(fe.Namespace(), "."); ok {
+		return path
+	}
+	return fe.Field()
+}
+
+// validationReason は検証ルールに応じた入力エラーの理由の種類と、メッセージに埋め込む値を返します
+func validationReason(fe validator.FieldError) (string, []any) {
+	isString := fe.Kind() == reflect.String
+	switch fe.Tag() {
+	case "required", "email":
+		return fe.Tag(), nil
+	case "min", "gte":
+		if isString {
+			return "min_length", []any{fe.Param()}
+		}
+		return "min", []any{fe.Param()}
+	case "max", "lte":
+		if isString {
+			return "max_length", []any{fe.Param()}
+		}
+		return "max", []any{fe.Param()}
+	case "oneof":
+		return "oneof", []any{strings.ReplaceAll(fe.Param(), " ", ", ")}
+	}
+	return "invalid", nil
+}
@@ -0,0 +1,22 @@
+package handler
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/sotaheavymetal21/rabbit-cart/backend/internal/domain/entity"
+	"github.com/sotaheavymetal21/rabbit-cart/backend/internal/infrastructure/i18n"
+)
+
+// requestLocale はリクエストのメッセージの言語を返します (middleware.LocaleMiddleware が設定します)
+func requestLocale(c *gin.Context) entity.Locale {
+	if value, exists := c.Get("locale"); exists {
+		if locale, ok := value.(entity.Locale); ok {
+			return locale
+		}
+	}
+	return entity.DefaultLocale
+}
+
+// translate はメッセージカタログの key のメッセージをリクエストの言語で返します
+func translate(c *gin.Context, key string, args ...any) string {
+	return i18n.T(requestLocale(c), key, args...)
+}
@@ -4,6 +4,7 @@ import (
 	"net/http"
 
 	"github.com/gin-gonic/gin"
+	"github.com/sotaheavymetal21/rabbit-cart/backend/internal/domain/entity"
 	"github.com/sotaheavymetal21/rabbit-cart/backend/internal/usecase"
 )
 
@@ -11,6 +12,8 @@ type OrderHandler interface {
 	GetOrders(c *gin.Context)
 	GetOrder(c *gin.Context)
 	CreateOrder(c *gin.Context)
+	CancelOrder(c *gin.Context)
+	CompleteOrder(c *gin.Context)
 }
 
 type orderHandler struct {
@@ -25,13 +28,13 @@ func (h *orderHandler) GetOrders(c *gin.Context) {
 	// AuthMiddlewareでセットされたUserIDを取得
 	userID, exists := c.Get("userID")
 	if !exists {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "認証されていません"})
+		c.Error(entity.ErrUnauthenticated)
 		return
 	}
 
 	orders, err := h.useCase.GetOrdersByUserID(c.Request.Context(), userID.(string))
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "注文履歴の取得に失敗しました"})
+		c.Error(err)
 		return
 	}
 	c.JSON(http.StatusOK, orders)
@@ -45,14 +48,14 @@ func (h *orderHandler) GetOrder(c *gin.Context) {
 
 	order, err := h.useCase.GetOrderByID(c.Request.Context(), id)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "注文の取得に失敗しました"})
+		c.Error(err)
 		return
 	}
 
 	// 簡易的な権限チェック
 	userID, exists := c.Get("userID")
-	if !exists || order.UserID != userID.(string) {
-		c.JSON(http.StatusForbidden, gin.H{"error": "この注文を閲覧する権限がありません"})
+	if !exists || !order.IsOwnedBy(userID.(string)) {
+		c.Error(usecase.ErrOrderForbidden)
 		return
 	}
 
@@ -62,21 +65,65 @@ func (h *orderHandler) GetOrder(c *gin.Context) {
 func (h *orderHandler) CreateOrder(c *gin.Context) {
 	userID, exists := c.Get("userID")
 	if !exists {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "認証されていません"})
+		c.Error(entity.ErrUnauthenticated)
 		return
 	}
 
 	var input usecase.CreateOrderInput
 	if err := c.ShouldBindJSON(&input); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "入力データが不正です: " + err.Error()})
+		c.Error(bindError(err))
 		return
 	}
 
 	order, err := h.useCase.CreateOrder(c.Request.Context(), userID.(string), input)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "注文の作成に失敗しました: " + err.Error()})
+		c.Error(err)
 		return
 	}
 
 	c.JSON(http.StatusCreated, order)
 }
+
+// CancelOrderRequest は注文キャンセルリクエストの構造体です
+type CancelOrderRequest struct {
+	Reason string `json:"reason"`
+}
+
+func (h *orderHandler) CancelOrder(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.Error(entity.ErrUnauthenticated)
+		return
+	}
+
+	// 理由は任意のため、ボディが空の場合はそのまま進める
+	var req CancelOrderRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.Error(bindError(err))
+			return
+		}
+	}
+
+	order, err := h.useCase.CancelOrder(c.Request.Context(), c.Param("id"), userID.(string), req.Reason)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, order)
+}
+
+func (h *orderHandler) CompleteOrder(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.Error(entity.ErrUnauthenticated)
+		return
+	}
+
+	order, err := h.useCase.CompleteOrder(c.Request.Context(), c.Param("id"), userID.(string))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, order)
+}
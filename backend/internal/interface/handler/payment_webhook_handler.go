@@ -0,0 +1,42 @@
+package handler
+
+import (
+	"io"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sotaheavymetal21/rabbit-cart/backend/internal/infrastructure/payment"
+	"github.com/sotaheavymetal21/rabbit-cart/backend/internal/usecase"
+)
+
+// maxWebhookPayloadSize は Webhook のリクエストボディの上限です
+const maxWebhookPayloadSize = 1 << 20
+
+type PaymentWebhookHandler interface {
+	ReceivePaymentEvent(c *gin.Context)
+}
+
+type paymentWebhookHandler struct {
+	useCase usecase.PaymentWebhookUseCase
+}
+
+// NewPaymentWebhookHandler は PaymentWebhookHandler の実装を生成します
+func NewPaymentWebhookHandler(u usecase.PaymentWebhookUseCase) PaymentWebhookHandler {
+	return &paymentWebhookHandler{useCase: u}
+}
+
+// ReceivePaymentEvent は決済サービスからの Webhook を受け取るハンドラーです
+// 署名はボディそのものに対して検証するため、ボディは解釈せずにそのまま渡します
+func (h *paymentWebhookHandler) ReceivePaymentEvent(c *gin.Context) {
+	payload, err := io.ReadAll(http.MaxBytesReader(c.Writer, c.Request.Body, maxWebhookPayloadSize))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	if err := h.useCase.Receive(c.Request.Context(), payload, c.GetHeader(payment.SignatureHeader)); err != nil {
+		c.Error(err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
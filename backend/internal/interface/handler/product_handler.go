@@ -2,14 +2,18 @@ package handler
 
 import (
 	"net/http"
+	"strings"
 
 	"github.com/gin-gonic/gin"
+	"github.com/sotaheavymetal21/rabbit-cart/backend/internal/domain/entity"
+	"github.com/sotaheavymetal21/rabbit-cart/backend/internal/domain/repository"
 	"github.com/sotaheavymetal21/rabbit-cart/backend/internal/usecase"
 )
 
 type ProductHandler interface {
 	GetProducts(c *gin.Context)
 	GetProduct(c *gin.Context)
+	SearchProducts(c *gin.Context)
 }
 
 type productHandler struct {
@@ -21,14 +25,62 @@ func NewProductHandler(u usecase.ProductUseCase) ProductHandler {
 	return &productHandler{useCase: u}
 }
 
-// GetProducts は商品一覧を取得するハンドラーです
+// ProductListRequest は商品一覧のクエリパラメータの構造体です
+type ProductListRequest struct {
+	Keyword  string `form:"q"`
+	Category string `form:"category"`
+	MinPrice *int   `form:"min_price" binding:"omitempty,min=0"`
+	MaxPrice *int   `form:"max_price" binding:"omitempty,min=0"`
+	InStock  bool   `form:"in_stock"`
+	Sort     string `form:"sort" binding:"omitempty,oneof=newest price_asc price_desc name"`
+	Page     int    `form:"page" binding:"omitempty,min=1"`
+	PerPage  int    `form:"per_page" binding:"omitempty,min=1,max=100"`
+	Cursor   string `form:"cursor"`
+}
+
+// ProductListResponse は商品一覧のレスポンスの構造体です
+type ProductListResponse struct {
+	Products   []*entity.Product `json:"products"`
+	Total      int64             `json:"total"`
+	Page       int               `json:"page,omitempty"` // カーソル指定時は省略
+	PerPage    int               `json:"per_page"`
+	NextCursor string            `json:"next_cursor,omitempty"`
+	HasNext    bool              `json:"has_next"`
+}
+
+// GetProducts は商品一覧を検索・絞り込み・並び替えして取得するハンドラーです
 func (h *productHandler) GetProducts(c *gin.Context) {
-	products, err := h.useCase.GetAllProducts(c.Request.Context())
+	var req ProductListRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.Error(bindError(err))
+		return
+	}
+
+	query := repository.ProductQuery{
+		Keyword:     strings.TrimSpace(req.Keyword),
+		Category:    req.Category,
+		MinPrice:    req.MinPrice,
+		MaxPrice:    req.MaxPrice,
+		InStockOnly: req.InStock,
+		Sort:        repository.ProductSort(req.Sort),
+		Page:        req.Page,
+		PerPage:     req.PerPage,
+		Cursor:      req.Cursor,
+	}
+	page, err := h.useCase.SearchProducts(c.Request.Context(), query)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "商品の取得に失敗しました"})
+		c.Error(err)
 		return
 	}
-	c.JSON(http.StatusOK, products)
+
+	c.JSON(http.StatusOK, ProductListResponse{
+		Products:   page.Products,
+		Total:      page.Total,
+		Page:       page.Page,
+		PerPage:    page.PerPage,
+		NextCursor: page.NextCursor,
+		HasNext:    page.NextCursor != "",
+	})
 }
 
 // GetProduct は指定されたIDの商品を取得するハンドラーです
@@ -36,14 +88,33 @@ func (h *productHandler) GetProduct(c *gin.Context) {
 	id := c.Param("id")
 	product, err := h.useCase.GetProductByID(c.Request.Context(), id)
 	if err != nil {
-		// エラーの種類によってステータスコードを変えるべきだが、簡単のため500または404とする
-		// 実際にはリポジトリ層でエラー判定を行うのが望ましい
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "商品の取得に失敗しました"})
+		c.Error(err)
 		return
 	}
-	if product == nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "商品が見つかりません"})
+	c.JSON(http.StatusOK, product)
+}
+
+// ProductSearchRequest は全文検索のクエリパラメータの構造体です
+type ProductSearchRequest struct {
+	Keyword string `form:"q" binding:"required"`
+	Limit   int    `form:"limit" binding:"omitempty,min=1,max=100"`
+}
+
+// SearchProducts はキーワードで商品を全文検索し、関連度順に返すハンドラーです
+func (h *productHandler) SearchProducts(c *gin.Context) {
+	var req ProductSearchRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.Error(bindError(err))
 		return
 	}
-	c.JSON(http.StatusOK, product)
+
+	hits, err := h.useCase.FullTextSearch(c.Request.Context(), req.Keyword, req.Limit)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"hits":  hits,
+		"count": len(hits),
+	})
 }
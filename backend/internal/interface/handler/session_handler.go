@@ -0,0 +1,87 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-contrib/sessions"
+	"github.com/gin-gonic/gin"
+	"github.com/sotaheavymetal21/rabbit-cart/backend/internal/domain/entity"
+	"github.com/sotaheavymetal21/rabbit-cart/backend/internal/usecase"
+)
+
+type SessionHandler interface {
+	ListSessions(c *gin.Context)
+	RevokeSession(c *gin.Context)
+	RevokeAllSessions(c *gin.Context)
+}
+
+type sessionHandler struct {
+	useCase usecase.SessionUseCase
+}
+
+// NewSessionHandler は SessionHandler の実装を生成します
+func NewSessionHandler(u usecase.SessionUseCase) SessionHandler {
+	return &sessionHandler{useCase: u}
+}
+
+// ListSessions はログイン中のセッション (端末) の一覧を返すハンドラーです
+func (h *sessionHandler) ListSessions(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.Error(entity.ErrUnauthenticated)
+		return
+	}
+
+	list, err := h.useCase.List(c.Request.Context(), userID.(string), c.GetString("sessionID"))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"sessions": list})
+}
+
+// RevokeSession は指定したセッションをログアウトさせるハンドラーです
+// 現在のセッションを指定した場合は、このリクエストの Cookie のログイン情報も削除します
+func (h *sessionHandler) RevokeSession(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.Error(entity.ErrUnauthenticated)
+		return
+	}
+
+	id := c.Param("id")
+	if err := h.useCase.Revoke(c.Request.Context(), userID.(string), id); err != nil {
+		c.Error(err)
+		return
+	}
+
+	if id == c.GetString("sessionID") {
+		session := sessions.Default(c)
+		forgetLogin(session)
+		_ = session.Save()
+	}
+	c.JSON(http.StatusOK, gin.H{"message": translate(c, "session_logged_out")})
+}
+
+// RevokeAllSessions は現在のセッションを含む全てのセッションをログアウトさせるハンドラーです
+func (h *sessionHandler) RevokeAllSessions(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.Error(entity.ErrUnauthenticated)
+		return
+	}
+
+	count, err := h.useCase.RevokeAll(c.Request.Context(), userID.(string))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	session := sessions.Default(c)
+	forgetLogin(session)
+	_ = session.Save()
+	c.JSON(http.StatusOK, gin.H{
+		"message": translate(c, "all_sessions_logged_out"),
+		"revoked": count,
+	})
+}
@@ -0,0 +1,184 @@
+package handler
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-contrib/sessions"
+	"github.com/gin-gonic/gin"
+	"github.com/sotaheavymetal21/rabbit-cart/backend/internal/domain/entity"
+	"github.com/sotaheavymetal21/rabbit-cart/backend/internal/usecase"
+)
+
+type UserHandler interface {
+	GetProfile(c *gin.Context)
+	UpdateProfile(c *gin.Context)
+	ChangePassword(c *gin.Context)
+	ChangeEmail(c *gin.Context)
+	DeleteAccount(c *gin.Context)
+}
+
+type userHandler struct {
+	useCase      usecase.UserUseCase
+	tokenUseCase usecase.TokenUseCase
+	mode         entity.AuthMode
+}
+
+// NewUserHandler は UserHandler の実装を生成します
+// mode が JWT を含む場合、パスワード変更後に tokenUseCase で現在のセッションのトークンを発行し直します
+func NewUserHandler(u usecase.UserUseCase, tokenUseCase usecase.TokenUseCase, mode entity.AuthMode) UserHandler {
+	return &userHandler{useCase: u, tokenUseCase: tokenUseCase, mode: mode}
+}
+
+// ChangePasswordRequest はパスワード変更リクエストの構造体です
+type ChangePasswordRequest struct {
+	CurrentPassword string `json:"current_password"`
+	NewPassword     string `json:"new_password" binding:"required,min=6"`
+}
+
+// ChangeEmailRequest はメールアドレス変更リクエストの構造体です
+type ChangeEmailRequest struct {
+	Email    string `json:"email" binding:"required,email"`
+	Password string `json:"password"`
+}
+
+// DeleteAccountRequest は退会リクエストの構造体です
+type DeleteAccountRequest struct {
+	Password string `json:"password"`
+}
+
+// GetProfile はログインユーザーのプロフィールを返すハンドラーです
+func (h *userHandler) GetProfile(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.Error(entity.ErrUnauthenticated)
+		return
+	}
+
+	user, err := h.useCase.GetProfile(c.Request.Context(), userID.(string))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"user": user})
+}
+
+// UpdateProfile は表示名・電話番号・言語を更新するハンドラーです (指定した項目のみ更新します)
+func (h *userHandler) UpdateProfile(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.Error(entity.ErrUnauthenticated)
+		return
+	}
+
+	var input usecase.UpdateProfileInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.Error(bindError(err))
+		return
+	}
+
+	user, err := h.useCase.UpdateProfile(c.Request.Context(), userID.(string), input)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"message": translate(c, "profile_updated"),
+		"user":    user,
+	})
+}
+
+// ChangePassword は現在のパスワードを確認してパスワードを変更するハンドラーです
+// 他の端末のセッションはログアウトし、このリクエストのセッションは引き続き使えるようにします
+func (h *userHandler) ChangePassword(c *gin.Context) {
+	value, exists := c.Get("user")
+	if !exists {
+		c.Error(entity.ErrUnauthenticated)
+		return
+	}
+	user := value.(*entity.User)
+
+	var req ChangePasswordRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(bindError(err))
+		return
+	}
+
+	ctx := c.Request.Context()
+	sessionID := c.GetString("sessionID")
+	if err := h.useCase.ChangePassword(ctx, user.ID, sessionID, req.CurrentPassword, req.NewPassword); err != nil {
+		c.Error(err)
+		return
+	}
+
+	// パスワード変更前のログイン日時は無効になるため、このリクエストの認証情報を更新する
+	res := gin.H{"message": translate(c, "password_changed")}
+	session := sessions.Default(c)
+	if id, _ := session.Get("session_id").(string); id == sessionID {
+		session.Set("authenticated_at", time.Now().Unix())
+		if err := session.Save(); err != nil {
+			c.Error(err)
+			return
+		}
+	}
+	if h.mode.UsesJWT() {
+		if _, ok := c.Get("accessClaims"); ok {
+			tokens, err := h.tokenUseCase.Issue(ctx, user, sessionID)
+			if err != nil {
+				c.Error(err)
+				return
+			}
+			res["tokens"] = tokens
+		}
+	}
+	c.JSON(http.StatusOK, res)
+}
+
+// ChangeEmail は新しいメールアドレスに確認用のリンクを送信するハンドラーです
+// リンクから確認 (POST /auth/verify) が済むとメールアドレスが変更されます
+func (h *userHandler) ChangeEmail(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.Error(entity.ErrUnauthenticated)
+		return
+	}
+
+	var req ChangeEmailRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(bindError(err))
+		return
+	}
+
+	if err := h.useCase.RequestEmailChange(c.Request.Context(), userID.(string), req.Password, req.Email); err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusAccepted, gin.H{
+		"message": translate(c, "email_change_requested"),
+	})
+}
+
+// DeleteAccount は現在のパスワードを確認してアカウントを削除するハンドラーです
+func (h *userHandler) DeleteAccount(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.Error(entity.ErrUnauthenticated)
+		return
+	}
+
+	var req DeleteAccountRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(bindError(err))
+		return
+	}
+
+	if err := h.useCase.DeleteAccount(c.Request.Context(), userID.(string), req.Password); err != nil {
+		c.Error(err)
+		return
+	}
+
+	session := sessions.Default(c)
+	session.Clear()
+	_ = session.Save()
+	c.JSON(http.StatusOK, gin.H{"message": translate(c, "account_deleted")})
+}
@@ -14,13 +14,32 @@ import (
 func SetupRouter(
 	productHandler handler.ProductHandler,
 	authHandler handler.AuthHandler,
+	sessionHandler handler.SessionHandler,
+	userHandler handler.UserHandler,
+	addressHandler handler.AddressHandler,
 	orderHandler handler.OrderHandler,
+	cartHandler handler.CartHandler,
+	adminProductHandler handler.AdminProductHandler,
+	adminOrderHandler handler.AdminOrderHandler,
+	adminPaymentHandler handler.AdminPaymentHandler,
+	adminPromotionHandler handler.AdminPromotionHandler,
+	paymentWebhookHandler handler.PaymentWebhookHandler,
 	redisURL string,
 	sessionSecret string,
+	errorMiddleware gin.HandlerFunc,
+	localeMiddleware gin.HandlerFunc,
 	authMiddleware gin.HandlerFunc,
+	adminMiddleware gin.HandlerFunc,
+	adminOnlyMiddleware gin.HandlerFunc,
+	idempotencyMiddleware gin.HandlerFunc,
 ) *gin.Engine {
 	r := gin.Default()
 
+	// ハンドラー・ミドルウェアが c.Error で登録したエラーをレスポンスに変換する (全てのミドルウェアより先に登録する)
+	r.Use(errorMiddleware)
+	// Accept-Language からメッセージの言語を決める (ログインユーザーの設定は authMiddleware が反映する)
+	r.Use(localeMiddleware)
+
 	// Redis セッションストアの設定
 	// redisURL の形式: "host:port" (例: "redis:6379")
 	parts := strings.Split(redisURL, ":")
@@ -37,8 +56,8 @@ func SetupRouter(
 	r.Use(func(c *gin.Context) {
 		c.Writer.Header().Set("Access-Control-Allow-Origin", "http://localhost:3000")
 		c.Writer.Header().Set("Access-Control-Allow-Credentials", "true")
-		c.Writer.Header().Set("Access-Control-Allow-Headers", "Content-Type, Content-Length, Accept-Encoding, X-CSRF-Token, Authorization, accept, origin, Cache-Control, X-Requested-With")
-		c.Writer.Header().Set("Access-Control-Allow-Methods", "POST, OPTIONS, GET, PUT, DELETE")
+		c.Writer.Header().Set("Access-Control-Allow-Headers", "Content-Type, Content-Length, Accept-Encoding, X-CSRF-Token, Authorization, accept, origin, Cache-Control, X-Requested-With, Idempotency-Key")
+		c.Writer.Header().Set("Access-Control-Allow-Methods", "POST, OPTIONS, GET, PUT, PATCH, DELETE")
 
 		if c.Request.Method == "OPTIONS" {
 			c.AbortWithStatus(204)
@@ -64,13 +83,43 @@ func SetupRouter(
 			auth.POST("/register", authHandler.Register)
 			auth.POST("/login", authHandler.Login)
 			auth.POST("/logout", authHandler.Logout)
+			auth.POST("/token/refresh", authHandler.RefreshToken)
+			auth.POST("/password/forgot", authHandler.ForgotPassword)
+			auth.POST("/password/reset", authHandler.ResetPassword)
+			auth.POST("/verify", authHandler.VerifyEmail)
+			auth.POST("/verify/resend", authMiddleware, authHandler.ResendVerification)
+			auth.POST("/mfa/verify", authHandler.VerifyMFA)
+			auth.GET("/oidc/:provider/login", authHandler.OIDCLogin)
+			auth.GET("/oidc/:provider/callback", authHandler.OIDCCallback)
+			auth.POST("/mfa/totp/setup", authMiddleware, authHandler.SetupTOTP)
+			auth.POST("/mfa/totp/confirm", authMiddleware, authHandler.ConfirmTOTP)
+			auth.POST("/mfa/totp/disable", authMiddleware, authHandler.DisableTOTP)
 			auth.GET("/me", authMiddleware, authHandler.GetCurrentUser)
+			auth.GET("/sessions", authMiddleware, sessionHandler.ListSessions)
+			auth.DELETE("/sessions", authMiddleware, sessionHandler.RevokeAllSessions)
+			auth.DELETE("/sessions/:id", authMiddleware, sessionHandler.RevokeSession)
+		}
+
+		// ログインユーザーのアカウント管理エンドポイント (要認証)
+		me := v1.Group("/me")
+		me.Use(authMiddleware)
+		{
+			me.GET("", userHandler.GetProfile)
+			me.PATCH("", userHandler.UpdateProfile)
+			me.DELETE("", userHandler.DeleteAccount)
+			me.PUT("/password", userHandler.ChangePassword)
+			me.PUT("/email", userHandler.ChangeEmail)
+			me.GET("/addresses", addressHandler.ListAddresses)
+			me.POST("/addresses", addressHandler.CreateAddress)
+			me.PUT("/addresses/:id", addressHandler.UpdateAddress)
+			me.DELETE("/addresses/:id", addressHandler.DeleteAddress)
 		}
 
 		// 商品エンドポイント (認証不要)
 		products := v1.Group("/products")
 		{
 			products.GET("", productHandler.GetProducts)
+			products.GET("/search", productHandler.SearchProducts)
 			products.GET("/:id", productHandler.GetProduct)
 		}
 
@@ -80,7 +129,66 @@ func SetupRouter(
 		{
 			orders.GET("", orderHandler.GetOrders)
 			orders.GET("/:id", orderHandler.GetOrder)
-			orders.POST("", orderHandler.CreateOrder)
+			// Idempotency-Key ヘッダーによりダブルクリックや再送で注文が二重に作成されないようにする
+			orders.POST("", idempotencyMiddleware, orderHandler.CreateOrder)
+			orders.POST("/:id/cancel", orderHandler.CancelOrder)
+			orders.POST("/:id/complete", orderHandler.CompleteOrder)
+		}
+
+		// カートエンドポイント (要認証)
+		cart := v1.Group("/cart")
+		cart.Use(authMiddleware)
+		{
+			cart.GET("", cartHandler.GetCart)
+			cart.DELETE("", cartHandler.ClearCart)
+			cart.POST("/items", cartHandler.AddItem)
+			cart.PUT("/items/:product_id", cartHandler.SetItemQuantity)
+			cart.DELETE("/items/:product_id", cartHandler.RemoveItem)
+		}
+
+		// ゲストカートエンドポイント (認証不要、セッションに保存)
+		guestCart := v1.Group("/guest/cart")
+		{
+			guestCart.GET("", cartHandler.GetGuestCart)
+			guestCart.DELETE("", cartHandler.ClearGuestCart)
+			guestCart.POST("/items", cartHandler.AddGuestItem)
+			guestCart.PUT("/items/:product_id", cartHandler.SetGuestItemQuantity)
+			guestCart.DELETE("/items/:product_id", cartHandler.RemoveGuestItem)
+		}
+
+		// 決済サービスからの Webhook (署名で検証するため認証不要)
+		webhooks := v1.Group("/webhooks")
+		{
+			webhooks.POST("/payments", paymentWebhookHandler.ReceivePaymentEvent)
+		}
+
+		// 管理者エンドポイント (要認証・管理者権限)
+		// 金額や在庫をまとめて動かす操作 (一括取り込み・返金・決済イベントの再処理・クーポンの管理) は adminOnlyMiddleware で管理者のみに限定する
+		admin := v1.Group("/admin")
+		admin.Use(authMiddleware, adminMiddleware)
+		{
+			adminProducts := admin.Group("/products")
+			adminProducts.POST("", adminProductHandler.CreateProduct)
+			adminProducts.POST("/import", adminOnlyMiddleware, adminProductHandler.ImportProducts)
+			adminProducts.GET("/export", adminProductHandler.ExportProducts)
+			adminProducts.PUT("/:id", adminProductHandler.UpdateProduct)
+			adminProducts.DELETE("/:id", adminProductHandler.DeleteProduct)
+			adminProducts.POST("/:id/restock", adminProductHandler.RestockProduct)
+
+			adminOrders := admin.Group("/orders")
+			adminOrders.PUT("/:id/status", adminOrderHandler.UpdateOrderStatus)
+			adminOrders.POST("/:id/refunds", adminOnlyMiddleware, adminOrderHandler.RefundOrder)
+
+			adminPayments := admin.Group("/payments")
+			adminPayments.GET("/dead-letters", adminPaymentHandler.ListDeadLetters)
+			adminPayments.POST("/dead-letters/:id/redrive", adminOnlyMiddleware, adminPaymentHandler.RedriveDeadLetter)
+
+			adminPromotions := admin.Group("/promotions")
+			adminPromotions.GET("", adminPromotionHandler.ListPromotions)
+			adminPromotions.POST("", adminOnlyMiddleware, adminPromotionHandler.CreatePromotion)
+			adminPromotions.GET("/:id", adminPromotionHandler.GetPromotion)
+			adminPromotions.PUT("/:id", adminOnlyMiddleware, adminPromotionHandler.UpdatePromotion)
+			adminPromotions.DELETE("/:id", adminOnlyMiddleware, adminPromotionHandler.DeletePromotion)
 		}
 	}
 
@@ -0,0 +1,95 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+
+	"github.com/sotaheavymetal21/rabbit-cart/backend/internal/domain/entity"
+	"github.com/sotaheavymetal21/rabbit-cart/backend/internal/domain/repository"
+)
+
+var (
+	// ErrAddressNotFound は住所録に指定された住所が無い場合のエラーです
+	ErrAddressNotFound = entity.NewNotFoundError("address_not_found", "住所が見つかりません")
+	// ErrAddressRequired は注文時に配送先が指定されておらず、既定の配送先も無い場合のエラーです
+	ErrAddressRequired = entity.NewFieldError("address_required", "address_id", "配送先の住所を指定してください")
+)
+
+// AddressUseCase はユーザーの住所録に関するビジネスロジックを定義するインターフェースです
+type AddressUseCase interface {
+	// List はユーザーの住所を既定の配送先を先頭にして返します
+	List(ctx context.Context, userID string) ([]*entity.UserAddress, error)
+	// Create は住所を検証して住所録に追加します (最初の住所は既定の配送先になります)
+	// 住所が正しくない場合は *entity.InvalidAddressError を返します
+	Create(ctx context.Context, userID string, input AddressInput) (*entity.UserAddress, error)
+	// Update は住所録の住所を更新します
+	Update(ctx context.Context, userID, id string, input AddressInput) (*entity.UserAddress, error)
+	// Delete は住所録から住所を削除します
+	Delete(ctx context.Context, userID, id string) error
+}
+
+// AddressInput は住所録への登録・更新の入力です
+type AddressInput struct {
+	Address   entity.Address `json:"address"`
+	IsDefault bool           `json:"is_default"`
+}
+
+type addressUseCase struct {
+	addressRepo repository.AddressRepository
+}
+
+// NewAddressUseCase は AddressUseCase の実装を生成します
+func NewAddressUseCase(addressRepo repository.AddressRepository) AddressUseCase {
+	return &addressUseCase{addressRepo: addressRepo}
+}
+
+// List はユーザーの住所を返します
+func (u *addressUseCase) List(ctx context.Context, userID string) ([]*entity.UserAddress, error) {
+	return u.addressRepo.FindAllByUserID(ctx, userID)
+}
+
+// Create は住所を検証して住所録に追加します
+func (u *addressUseCase) Create(ctx context.Context, userID string, input AddressInput) (*entity.UserAddress, error) {
+	input.Address.Normalize()
+	if err := input.Address.Validate(); err != nil {
+		return nil, err
+	}
+
+	address := &entity.UserAddress{UserID: userID, Address: input.Address, IsDefault: input.IsDefault}
+	if err := u.addressRepo.Create(ctx, address); err != nil {
+		return nil, err
+	}
+	return address, nil
+}
+
+// Update は住所録の住所を更新します
+func (u *addressUseCase) Update(ctx context.Context, userID, id string, input AddressInput) (*entity.UserAddress, error) {
+	input.Address.Normalize()
+	if err := input.Address.Validate(); err != nil {
+		return nil, err
+	}
+
+	if err := u.addressRepo.Update(ctx, &entity.UserAddress{
+		ID:        id,
+		UserID:    userID,
+		Address:   input.Address,
+		IsDefault: input.IsDefault,
+	}); err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, ErrAddressNotFound
+		}
+		return nil, err
+	}
+	return u.addressRepo.FindByID(ctx, userID, id)
+}
+
+// Delete は住所録から住所を削除します
+func (u *addressUseCase) Delete(ctx context.Context, userID, id string) error {
+	if err := u.addressRepo.Delete(ctx, userID, id); err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return ErrAddressNotFound
+		}
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,220 @@
+package usecase
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base32"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/sotaheavymetal21/rabbit-cart/backend/internal/domain/entity"
+	"github.com/sotaheavymetal21/rabbit-cart/backend/internal/domain/repository"
+	"github.com/sotaheavymetal21/rabbit-cart/backend/pkg/totp"
+)
+
+const (
+	// totpIssuer は認証アプリに表示されるサービス名です
+	totpIssuer = "Rabbit Cart"
+	// totpSkew は時計のずれとして前後に許容する時間ステップの数です
+	totpSkew = 1
+	// mfaChallengeTTL はパスワード認証後にコードを入力できる時間です
+	mfaChallengeTTL = 5 * time.Minute
+	// mfaMaxAttempts は1回のログインでコードの入力に失敗できる回数です
+	mfaMaxAttempts = 5
+	// recoveryCodeCount は発行するリカバリーコードの数です
+	recoveryCodeCount = 10
+)
+
+var (
+	// ErrInvalidMFACode は二要素認証のコードが正しくない場合のエラーです
+	ErrInvalidMFACode = entity.NewFieldError("invalid_mfa_code", "code", "認証コードが正しくありません")
+	// ErrInvalidMFAToken はログイン途中の状態が不正・期限切れ・使用済みの場合のエラーです
+	ErrInvalidMFAToken = entity.NewUnauthorizedError("invalid_mfa_token", "認証の有効期限が切れました。もう一度ログインしてください")
+	// ErrMFAAlreadyEnabled は二要素認証が既に有効な場合のエラーです
+	ErrMFAAlreadyEnabled = entity.NewConflictError("mfa_already_enabled", "二要素認証は既に有効です")
+	// ErrMFANotEnabled は二要素認証が有効になっていない場合のエラーです
+	ErrMFANotEnabled = entity.NewConflictError("mfa_not_enabled", "二要素認証は有効になっていません")
+	// ErrMFASetupNotStarted は TOTP のシークレットを発行する前に確認しようとした場合のエラーです
+	ErrMFASetupNotStarted = entity.NewConflictError("mfa_setup_not_started", "先に二要素認証の設定を開始してください")
+)
+
+// InvalidMFACodeError はログイン時に二要素認証のコードが正しくなかった場合のエラーです
+// ログイン失敗として数えられるよう、対象ユーザーのメールアドレスを保持します
+type InvalidMFACodeError struct {
+	Email string
+}
+
+func (e *InvalidMFACodeError) Error() string {
+	return ErrInvalidMFACode.Error()
+}
+
+func (e *InvalidMFACodeError) Unwrap() error {
+	return ErrInvalidMFACode
+}
+
+// TOTPSetup は認証アプリに登録するための情報です
+type TOTPSetup struct {
+	Secret string `json:"secret"`
+	URI    string `json:"otpauth_uri"` // QR コードにして表示します
+}
+
+// VerifyMFA はログイン途中の状態と TOTP コード (またはリカバリーコード) を検証し、ログインを完了します
+func (u *authUseCase) VerifyMFA(ctx context.Context, mfaToken, code string) (*entity.User, error) {
+	challenge, err := u.mfaRepo.FindChallenge(ctx, hashToken(mfaToken))
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, ErrInvalidMFAToken
+		}
+		return nil, err
+	}
+	if challenge.UsedAt != nil || challenge.Attempts >= mfaMaxAttempts || time.Now().After(challenge.ExpiresAt) {
+		return nil, ErrInvalidMFAToken
+	}
+
+	user, err := u.userRepo.FindByID(ctx, challenge.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	ok, err := u.verifyMFACode(ctx, user, code)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		if _, err := u.mfaRepo.RecordChallengeFailure(ctx, challenge.ID); err != nil {
+			return nil, err
+		}
+		return nil, &InvalidMFACodeError{Email: user.Email}
+	}
+
+	if err := u.mfaRepo.ConsumeChallenge(ctx, challenge.ID); err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, ErrInvalidMFAToken
+		}
+		return nil, err
+	}
+	return user, nil
+}
+
+// SetupTOTP は TOTP のシークレットを発行します
+func (u *authUseCase) SetupTOTP(ctx context.Context, userID string) (*TOTPSetup, error) {
+	user, err := u.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if user.MFAEnabled() {
+		return nil, ErrMFAAlreadyEnabled
+	}
+
+	secret, err := totp.GenerateSecret()
+	if err != nil {
+		return nil, err
+	}
+	if err := u.mfaRepo.SetPendingTOTPSecret(ctx, userID, secret); err != nil {
+		return nil, err
+	}
+	return &TOTPSetup{Secret: secret, URI: totp.URI(totpIssuer, user.Email, secret)}, nil
+}
+
+// ConfirmTOTP はコードを確認して二要素認証を有効にし、リカバリーコードを返します
+// リカバリーコードはハッシュのみを保存するため、表示できるのはこの時だけです
+func (u *authUseCase) ConfirmTOTP(ctx context.Context, userID, code string) ([]string, error) {
+	user, err := u.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if user.MFAEnabled() {
+		return nil, ErrMFAAlreadyEnabled
+	}
+	if user.TOTPSecret == "" {
+		return nil, ErrMFASetupNotStarted
+	}
+
+	step, ok := totp.Validate(user.TOTPSecret, code, time.Now(), totpSkew)
+	if !ok {
+		return nil, ErrInvalidMFACode
+	}
+
+	codes := make([]string, 0, recoveryCodeCount)
+	records := make([]entity.MFARecoveryCode, 0, recoveryCodeCount)
+	for i := 0; i < recoveryCodeCount; i++ {
+		c, err := newRecoveryCode()
+		if err != nil {
+			return nil, err
+		}
+		codes = append(codes, c)
+		records = append(records, entity.MFARecoveryCode{UserID: userID, CodeHash: hashToken(normalizeRecoveryCode(c))})
+	}
+
+	if err := u.mfaRepo.EnableTOTP(ctx, userID, step, records); err != nil {
+		return nil, err
+	}
+	return codes, nil
+}
+
+// DisableTOTP はコードを確認して二要素認証を無効にします
+func (u *authUseCase) DisableTOTP(ctx context.Context, userID, code string) error {
+	user, err := u.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if !user.MFAEnabled() {
+		return ErrMFANotEnabled
+	}
+
+	ok, err := u.verifyMFACode(ctx, user, code)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return &InvalidMFACodeError{Email: user.Email}
+	}
+	return u.mfaRepo.DisableTOTP(ctx, userID)
+}
+
+// verifyMFACode は TOTP コードまたはリカバリーコードを検証します
+// 使用したコードは再利用できないよう記録します
+func (u *authUseCase) verifyMFACode(ctx context.Context, user *entity.User, code string) (bool, error) {
+	code = strings.TrimSpace(code)
+	if len(code) == totp.Digits {
+		step, ok := totp.Validate(user.TOTPSecret, code, time.Now(), totpSkew)
+		if !ok {
+			return false, nil
+		}
+		return u.mfaRepo.AdvanceTOTPStep(ctx, user.ID, step)
+	}
+	return u.mfaRepo.UseRecoveryCode(ctx, user.ID, hashToken(normalizeRecoveryCode(code)))
+}
+
+// createMFAChallenge はパスワード認証に成功したユーザーのログイン途中の状態を保存し、トークンを返します
+func (u *authUseCase) createMFAChallenge(ctx context.Context, userID string) (string, error) {
+	token, err := randomToken(32)
+	if err != nil {
+		return "", err
+	}
+	if err := u.mfaRepo.CreateChallenge(ctx, &entity.MFAChallenge{
+		UserID:    userID,
+		TokenHash: hashToken(token),
+		ExpiresAt: time.Now().Add(mfaChallengeTTL),
+	}); err != nil {
+		return "", err
+	}
+	return token, nil
+}
+
+// newRecoveryCode は "xxxxx-xxxxx" 形式のリカバリーコードを生成します
+func newRecoveryCode() (string, error) {
+	b := make([]byte, 7)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	s := strings.ToLower(base32.StdEncoding.WithPadding(base32.NoPadding).EncodeToString(b))[:10]
+	return s[:5] + "-" + s[5:], nil
+}
+
+// normalizeRecoveryCode は入力されたリカバリーコードの区切り文字と大文字小文字の違いを吸収します
+func normalizeRecoveryCode(code string) string {
+	code = strings.ToLower(code)
+	return strings.NewReplacer("-", "", " ", "").Replace(code)
+}
@@ -0,0 +1,169 @@
+package usecase
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/sotaheavymetal21/rabbit-cart/backend/internal/domain/entity"
+	"github.com/sotaheavymetal21/rabbit-cart/backend/internal/domain/repository"
+)
+
+// oidcLoginTTL は外部 ID プロバイダーでのログインを開始してから戻ってくるまでの有効期間です
+const oidcLoginTTL = 10 * time.Minute
+
+var (
+	// ErrOIDCProviderNotFound は指定された ID プロバイダーが設定されていない場合のエラーです
+	ErrOIDCProviderNotFound = entity.NewNotFoundError("oidc_provider_not_found", "指定されたログイン方法は利用できません")
+	// ErrInvalidOIDCState はログイン開始時の状態と一致しない (期限切れ・別のブラウザからのリクエストを含む) 場合のエラーです
+	ErrInvalidOIDCState = entity.NewValidationError("invalid_oidc_state", "ログインの有効期限が切れました。もう一度お試しください")
+	// ErrOIDCLoginFailed は ID プロバイダーとの通信や ID トークンの検証に失敗した場合のエラーです
+	ErrOIDCLoginFailed = entity.NewUnauthorizedError("oidc_login_failed", "外部サービスでのログインに失敗しました")
+	// ErrOIDCEmailRequired は ID プロバイダーからメールアドレスを取得できなかった場合のエラーです
+	ErrOIDCEmailRequired = entity.NewValidationError("oidc_email_required", "外部サービスからメールアドレスを取得できませんでした")
+	// ErrOIDCAccountExists は同じメールアドレスのユーザーが既に存在し、自動で紐付けられない場合のエラーです
+	ErrOIDCAccountExists = entity.NewConflictError("oidc_account_exists", "このメールアドレスは既に登録されています。メールアドレスとパスワードでログインしてください")
+)
+
+// OIDCLogin は外部 ID プロバイダーでのログインの開始結果です
+type OIDCLogin struct {
+	URL   string // ユーザーをリダイレクトする認可エンドポイントの URL
+	State *OIDCLoginState
+}
+
+// OIDCLoginState はログイン開始時に発行し、ID プロバイダーから戻ってきた際に照合する値です
+// ブラウザのセッションに保存し、別のブラウザで開始されたログインを受け付けないようにします
+type OIDCLoginState struct {
+	Provider     string
+	State        string
+	Nonce        string
+	CodeVerifier string // PKCE のコード検証値
+	ExpiresAt    time.Time
+}
+
+// BeginOIDCLogin は外部 ID プロバイダーでのログインを開始します
+func (u *authUseCase) BeginOIDCLogin(ctx context.Context, provider string) (*OIDCLogin, error) {
+	p, ok := u.oidcProviders[provider]
+	if !ok {
+		return nil, ErrOIDCProviderNotFound
+	}
+
+	state, err := randomToken(32)
+	if err != nil {
+		return nil, err
+	}
+	nonce, err := randomToken(32)
+	if err != nil {
+		return nil, err
+	}
+	verifier, err := randomToken(32)
+	if err != nil {
+		return nil, err
+	}
+
+	authURL, err := p.AuthCodeURL(ctx, state, nonce, codeChallenge(verifier))
+	if err != nil {
+		log.Printf("ID プロバイダー %s の認可 URL の作成に失敗しました: %v", provider, err)
+		return nil, ErrOIDCLoginFailed
+	}
+	return &OIDCLogin{
+		URL: authURL,
+		State: &OIDCLoginState{
+			Provider:     provider,
+			State:        state,
+			Nonce:        nonce,
+			CodeVerifier: verifier,
+			ExpiresAt:    time.Now().Add(oidcLoginTTL),
+		},
+	}, nil
+}
+
+// CompleteOIDCLogin は ID プロバイダーから戻ってきた state と認可コードを検証してログインします
+func (u *authUseCase) CompleteOIDCLogin(ctx context.Context, pending *OIDCLoginState, state, code string) (*LoginResult, error) {
+	if pending == nil || state == "" || code == "" || time.Now().After(pending.ExpiresAt) ||
+		subtle.ConstantTimeCompare([]byte(pending.State), []byte(state)) != 1 {
+		return nil, ErrInvalidOIDCState
+	}
+	p, ok := u.oidcProviders[pending.Provider]
+	if !ok {
+		return nil, ErrOIDCProviderNotFound
+	}
+
+	claims, err := p.Exchange(ctx, code, pending.CodeVerifier)
+	if err != nil {
+		log.Printf("ID プロバイダー %s でのログインに失敗しました: %v", pending.Provider, err)
+		return nil, ErrOIDCLoginFailed
+	}
+	// ID トークンの再利用を防ぐため、ログイン開始時に発行した nonce と照合する
+	if subtle.ConstantTimeCompare([]byte(pending.Nonce), []byte(claims.Nonce)) != 1 {
+		log.Printf("ID プロバイダー %s の ID トークンの nonce が一致しません", pending.Provider)
+		return nil, ErrOIDCLoginFailed
+	}
+
+	user, err := u.findOrCreateOIDCUser(ctx, pending.Provider, claims)
+	if err != nil {
+		return nil, err
+	}
+	return u.loginResult(ctx, user)
+}
+
+// findOrCreateOIDCUser は ID プロバイダーのアカウントに紐付いたユーザーを返します
+// 紐付けが無い場合は、同じメールアドレスのユーザーに紐付けるか、パスワードの無いユーザーを作成します
+func (u *authUseCase) findOrCreateOIDCUser(ctx context.Context, provider string, claims *entity.OIDCClaims) (*entity.User, error) {
+	identity, err := u.identityRepo.FindByProviderSubject(ctx, provider, claims.Subject)
+	if err == nil {
+		return u.userRepo.FindByID(ctx, identity.UserID)
+	}
+	if !errors.Is(err, repository.ErrNotFound) {
+		return nil, err
+	}
+
+	email := strings.TrimSpace(claims.Email)
+	if email == "" {
+		return nil, ErrOIDCEmailRequired
+	}
+	identity = &entity.UserIdentity{Provider: provider, Subject: claims.Subject, Email: email}
+
+	existing, err := u.userRepo.FindByEmail(ctx, email)
+	if err == nil {
+		// 自動で紐付けるのは、プロバイダーと Rabbit Cart の両方でメールアドレスが確認済みの場合のみ
+		// (他人のメールアドレスで事前に登録されたアカウントを乗っ取られないようにする)
+		if !claims.EmailVerified || !existing.IsVerified() {
+			return nil, ErrOIDCAccountExists
+		}
+		identity.UserID = existing.ID
+		if err := u.identityRepo.Create(ctx, identity); err != nil {
+			return nil, err
+		}
+		return existing, nil
+	}
+	if !errors.Is(err, repository.ErrNotFound) {
+		return nil, err
+	}
+
+	user := &entity.User{Email: email}
+	if claims.EmailVerified {
+		now := time.Now()
+		user.VerifiedAt = &now
+	}
+	if err := u.identityRepo.CreateWithUser(ctx, user, identity); err != nil {
+		return nil, err
+	}
+	if !user.IsVerified() {
+		if err := u.sendVerification(ctx, user); err != nil {
+			log.Printf("確認メールの送信に失敗しました (user_id=%s): %v", user.ID, err)
+		}
+	}
+	return user, nil
+}
+
+// codeChallenge は PKCE のコード検証値から S256 のコードチャレンジを計算します
+func codeChallenge(verifier string) string {
+	sum := sha256.Sum256([]byte(verifier))
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
@@ -0,0 +1,132 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/sotaheavymetal21/rabbit-cart/backend/internal/domain/entity"
+	domainrepository "github.com/sotaheavymetal21/rabbit-cart/backend/internal/domain/repository"
+	"github.com/sotaheavymetal21/rabbit-cart/backend/internal/infrastructure/oidc"
+	"github.com/sotaheavymetal21/rabbit-cart/backend/internal/infrastructure/oidc/oidctest"
+	"github.com/sotaheavymetal21/rabbit-cart/backend/internal/usecase"
+)
+
+// stubIdentityRepository は既存の紐付けだけを返す UserIdentityRepository です
+type stubIdentityRepository struct {
+	domainrepository.UserIdentityRepository
+	identities map[string]*entity.UserIdentity // sub → 紐付け
+}
+
+func (r *stubIdentityRepository) FindByProviderSubject(ctx context.Context, provider, subject string) (*entity.UserIdentity, error) {
+	identity, ok := r.identities[subject]
+	if !ok || identity.Provider != provider {
+		return nil, domainrepository.ErrNotFound
+	}
+	return identity, nil
+}
+
+type oidcFixture struct {
+	idp   *oidctest.Provider
+	auth  usecase.AuthUseCase
+	alice *entity.User
+}
+
+func newOIDCFixture(t *testing.T) *oidcFixture {
+	t.Helper()
+	idp := oidctest.New(t, "rabbit-cart-web")
+	provider, err := oidc.NewProvider(oidc.Config{
+		Name:        "mock",
+		Issuer:      idp.Issuer,
+		ClientID:    "rabbit-cart-web",
+		RedirectURL: oidctest.RedirectURL,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	alice := &entity.User{ID: "00000000-0000-4000-8000-00000000000a", Email: "alice@example.com", Role: entity.UserRoleCustomer}
+	users := &stubUserRepository{users: map[string]*entity.User{alice.ID: alice}}
+	identities := &stubIdentityRepository{identities: map[string]*entity.UserIdentity{
+		"mock-subject-1": {UserID: alice.ID, Provider: "mock", Subject: "mock-subject-1"},
+	}}
+	auth := usecase.NewAuthUseCase(users, nil, nil, nil, identities, nil, []domainrepository.OIDCProvider{provider}, usecase.AuthConfig{})
+	return &oidcFixture{idp: idp, auth: auth, alice: alice}
+}
+
+// begin はログインを開始し、ID プロバイダーで同意した後の認可コードと state を返します
+func (f *oidcFixture) begin(t *testing.T) (*usecase.OIDCLoginState, string, string) {
+	t.Helper()
+	login, err := f.auth.BeginOIDCLogin(context.Background(), "mock")
+	if err != nil {
+		t.Fatalf("BeginOIDCLogin: %v", err)
+	}
+	code, state := f.idp.Authorize(t, login.URL)
+	return login.State, code, state
+}
+
+func TestCompleteOIDCLogin(t *testing.T) {
+	f := newOIDCFixture(t)
+	pending, code, state := f.begin(t)
+
+	result, err := f.auth.CompleteOIDCLogin(context.Background(), pending, state, code)
+	if err != nil {
+		t.Fatalf("CompleteOIDCLogin: %v", err)
+	}
+	if result.User == nil || result.User.ID != f.alice.ID {
+		t.Errorf("User = %+v, want %s", result.User, f.alice.ID)
+	}
+}
+
+func TestCompleteOIDCLoginRejectsInvalidState(t *testing.T) {
+	tests := []struct {
+		name   string
+		modify func(pending *usecase.OIDCLoginState, state, code *string)
+	}{
+		{"state が一致しない", func(_ *usecase.OIDCLoginState, state, _ *string) { *state = "attacker-state" }},
+		{"state が空", func(_ *usecase.OIDCLoginState, state, _ *string) { *state = "" }},
+		{"認可コードが空", func(_ *usecase.OIDCLoginState, _, code *string) { *code = "" }},
+		{"ログインの有効期限切れ", func(pending *usecase.OIDCLoginState, _, _ *string) {
+			pending.ExpiresAt = time.Now().Add(-time.Second)
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newOIDCFixture(t)
+			pending, code, state := f.begin(t)
+			tt.modify(pending, &state, &code)
+			if _, err := f.auth.CompleteOIDCLogin(context.Background(), pending, state, code); !errors.Is(err, usecase.ErrInvalidOIDCState) {
+				t.Errorf("CompleteOIDCLogin = %v, want ErrInvalidOIDCState", err)
+			}
+		})
+	}
+
+	f := newOIDCFixture(t)
+	_, code, state := f.begin(t)
+	if _, err := f.auth.CompleteOIDCLogin(context.Background(), nil, state, code); !errors.Is(err, usecase.ErrInvalidOIDCState) {
+		t.Errorf("ログインを開始していない: CompleteOIDCLogin = %v, want ErrInvalidOIDCState", err)
+	}
+}
+
+func TestCompleteOIDCLoginRejectsNonceMismatch(t *testing.T) {
+	f := newOIDCFixture(t)
+	// 別のログインで発行された ID トークンの再利用
+	f.idp.Claims = func(c map[string]interface{}) { c["nonce"] = "nonce-of-another-login" }
+	pending, code, state := f.begin(t)
+
+	if _, err := f.auth.CompleteOIDCLogin(context.Background(), pending, state, code); !errors.Is(err, usecase.ErrOIDCLoginFailed) {
+		t.Errorf("CompleteOIDCLogin = %v, want ErrOIDCLoginFailed", err)
+	}
+}
+
+func TestCompleteOIDCLoginRejectsOtherBrowsersVerifier(t *testing.T) {
+	f := newOIDCFixture(t)
+	pending, code, state := f.begin(t)
+	// 別のブラウザで開始したログインのコード検証値では認可コードを交換できない
+	other, _, _ := f.begin(t)
+	pending.CodeVerifier = other.CodeVerifier
+
+	if _, err := f.auth.CompleteOIDCLogin(context.Background(), pending, state, code); !errors.Is(err, usecase.ErrOIDCLoginFailed) {
+		t.Errorf("CompleteOIDCLogin = %v, want ErrOIDCLoginFailed", err)
+	}
+}
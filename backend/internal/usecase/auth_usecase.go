@@ -3,26 +3,133 @@ package usecase
 import (
 	"context"
 	"errors"
+	"fmt"
+	"log"
+	"math"
+	"net/url"
+	"time"
 
 	"github.com/sotaheavymetal21/rabbit-cart/backend/internal/domain/entity"
 	"github.com/sotaheavymetal21/rabbit-cart/backend/internal/domain/repository"
 	"golang.org/x/crypto/bcrypt"
-	"gorm.io/gorm"
 )
 
+var (
+	// ErrInvalidCredentials はメールアドレスまたはパスワードが正しくない場合のエラーです
+	ErrInvalidCredentials = entity.NewUnauthorizedError("invalid_credentials", "メールアドレスまたはパスワードが正しくありません")
+	// ErrInvalidResetToken はパスワードリセット用のトークンが不正・期限切れ・使用済みの場合のエラーです
+	ErrInvalidResetToken = entity.NewFieldError("invalid_reset_token", "token", "パスワードリセット用のリンクが無効か、有効期限が切れています")
+	// ErrInvalidVerificationToken はメールアドレス確認用のトークンが不正・期限切れ・使用済みの場合のエラーです
+	ErrInvalidVerificationToken = entity.NewFieldError("invalid_verification_token", "token", "メールアドレス確認用のリンクが無効か、有効期限が切れています")
+	// ErrAlreadyVerified はメールアドレスが既に確認済みの場合のエラーです
+	ErrAlreadyVerified = entity.NewConflictError("already_verified", "メールアドレスは既に確認済みです")
+)
+
+// ThrottledError は短時間に同じ操作が繰り返された場合のエラーです
+type ThrottledError struct {
+	RetryAfter time.Duration // 再試行できるようになるまでの時間
+}
+
+func (e *ThrottledError) Error() string {
+	return fmt.Sprintf("しばらく時間をおいてから再度お試しください (%d 秒後に再試行できます)", e.RetryAfterSeconds())
+}
+
+// RetryAfterSeconds は再試行できるようになるまでの秒数 (切り上げ) を返します
+func (e *ThrottledError) RetryAfterSeconds() int {
+	return int(math.Ceil(e.RetryAfter.Seconds()))
+}
+
 // AuthUseCase は認証に関するビジネスロジックを定義するインターフェースです
 type AuthUseCase interface {
 	Register(ctx context.Context, email, password string) (*entity.User, error)
-	Login(ctx context.Context, email, password string) (*entity.User, error)
+	// Login はメールアドレスとパスワードを検証します
+	// 二要素認証が有効なユーザーの場合は LoginResult.MFAToken を返し、VerifyMFA でコードを検証するまでログインは完了しません
+	Login(ctx context.Context, email, password string) (*LoginResult, error)
+	// VerifyMFA はログイン途中の状態と TOTP コード (またはリカバリーコード) を検証し、ログインを完了します
+	// コードが正しくない場合は *InvalidMFACodeError を返します
+	VerifyMFA(ctx context.Context, mfaToken, code string) (*entity.User, error)
+	// SetupTOTP は TOTP のシークレットを発行します (ConfirmTOTP でコードを確認するまで有効になりません)
+	SetupTOTP(ctx context.Context, userID string) (*TOTPSetup, error)
+	// ConfirmTOTP はコードを確認して二要素認証を有効にし、リカバリーコードを返します
+	ConfirmTOTP(ctx context.Context, userID, code string) ([]string, error)
+	// DisableTOTP はコードを確認して二要素認証を無効にします
+	// コードが正しくない場合は *InvalidMFACodeError を返します (ログインと同じく失敗回数を数えてください)
+	DisableTOTP(ctx context.Context, userID, code string) error
+	// BeginOIDCLogin は外部 ID プロバイダーでのログインを開始し、リダイレクト先の URL と照合用の状態を返します
+	// 状態はログインを開始したブラウザのセッションに保存し、CompleteOIDCLogin に渡してください
+	BeginOIDCLogin(ctx context.Context, provider string) (*OIDCLogin, error)
+	// CompleteOIDCLogin は ID プロバイダーから戻ってきた state と認可コードを検証し、ユーザーを紐付け・作成してログインします
+	// 二要素認証が有効なユーザーの場合は Login と同じく LoginResult.MFAToken を返します
+	CompleteOIDCLogin(ctx context.Context, pending *OIDCLoginState, state, code string) (*LoginResult, error)
+	// RequestPasswordReset はパスワードリセット用のリンクをメールで送信します
+	// 登録されていないメールアドレスの場合も、登録の有無が分からないようエラーを返しません
+	RequestPasswordReset(ctx context.Context, email string) error
+	// ResetPassword はトークンを検証してパスワードを変更し、既存のセッションとトークンを全て無効にします
+	ResetPassword(ctx context.Context, token, newPassword string) error
+	// VerifyEmail はトークンを検証してユーザーのメールアドレスを確認済みにします
+	// メールアドレス変更の確認用のトークンの場合はメールアドレスを変更し、変更前のアドレスに通知します
+	VerifyEmail(ctx context.Context, token string) (*entity.User, error)
+	// ResendVerification はメールアドレス確認用のリンクを再送します
+	// 前回の送信から ResendInterval が経過していない場合は *ThrottledError を返します
+	ResendVerification(ctx context.Context, userID string) error
+}
+
+// AuthConfig はメールで送るリンクの URL と有効期間の設定です
+// URL には token クエリパラメータを付けてメールに記載します
+type AuthConfig struct {
+	PasswordResetURL string
+	PasswordResetTTL time.Duration
+
+	VerificationURL            string
+	VerificationTTL            time.Duration
+	VerificationResendInterval time.Duration // 確認メールを再送できる間隔
+}
+
+// LoginResult はログインの結果です
+// 二要素認証が必要な場合は User が nil になり、MFAToken に VerifyMFA で使うトークンが入ります
+type LoginResult struct {
+	User         *entity.User
+	MFAToken     string
+	MFAExpiresIn int // MFAToken の有効期間 (秒)
 }
 
 type authUseCase struct {
-	userRepo repository.UserRepository
+	userRepo         repository.UserRepository
+	resetRepo        repository.PasswordResetRepository
+	verificationRepo repository.EmailVerificationRepository
+	mfaRepo          repository.MFARepository
+	identityRepo     repository.UserIdentityRepository
+	mailer           repository.Mailer
+	oidcProviders    map[string]repository.OIDCProvider
+	config           AuthConfig
 }
 
 // NewAuthUseCase は AuthUseCase の実装を生成します
-func NewAuthUseCase(userRepo repository.UserRepository) AuthUseCase {
-	return &authUseCase{userRepo: userRepo}
+// oidcProviders はログインに使用できる外部 ID プロバイダーです (Name で区別します)
+func NewAuthUseCase(userRepo repository.UserRepository, resetRepo repository.PasswordResetRepository, verificationRepo repository.EmailVerificationRepository, mfaRepo repository.MFARepository, identityRepo repository.UserIdentityRepository, mailer repository.Mailer, oidcProviders []repository.OIDCProvider, config AuthConfig) AuthUseCase {
+	if config.PasswordResetTTL <= 0 {
+		config.PasswordResetTTL = 30 * time.Minute
+	}
+	if config.VerificationTTL <= 0 {
+		config.VerificationTTL = 24 * time.Hour
+	}
+	if config.VerificationResendInterval <= 0 {
+		config.VerificationResendInterval = time.Minute
+	}
+	providers := make(map[string]repository.OIDCProvider, len(oidcProviders))
+	for _, p := range oidcProviders {
+		providers[p.Name()] = p
+	}
+	return &authUseCase{
+		userRepo:         userRepo,
+		resetRepo:        resetRepo,
+		verificationRepo: verificationRepo,
+		mfaRepo:          mfaRepo,
+		identityRepo:     identityRepo,
+		mailer:           mailer,
+		oidcProviders:    providers,
+		config:           config,
+	}
 }
 
 // Register は新しいユーザーを登録します
@@ -30,9 +137,9 @@ func (u *authUseCase) Register(ctx context.Context, email, password string) (*en
 	// メールアドレスの重複チェック
 	_, err := u.userRepo.FindByEmail(ctx, email)
 	if err == nil {
-		return nil, errors.New("このメールアドレスは既に登録されています")
+		return nil, ErrEmailAlreadyInUse
 	}
-	if !errors.Is(err, gorm.ErrRecordNotFound) {
+	if !errors.Is(err, repository.ErrNotFound) {
 		return nil, err
 	}
 
@@ -42,9 +149,10 @@ func (u *authUseCase) Register(ctx context.Context, email, password string) (*en
 		return nil, err
 	}
 
+	passwordHash := string(hashedPassword)
 	user := &entity.User{
 		Email:        email,
-		PasswordHash: string(hashedPassword),
+		PasswordHash: &passwordHash,
 	}
 
 	// ユーザーの作成
@@ -52,24 +160,199 @@ func (u *authUseCase) Register(ctx context.Context, email, password string) (*en
 		return nil, err
 	}
 
+	// 確認メールの送信に失敗しても登録は成功させ、再送で対応できるようにする
+	if err := u.sendVerification(ctx, user); err != nil {
+		log.Printf("確認メールの送信に失敗しました (user_id=%s): %v", user.ID, err)
+	}
+
 	return user, nil
 }
 
 // Login はユーザーのログイン処理を行います
-func (u *authUseCase) Login(ctx context.Context, email, password string) (*entity.User, error) {
+func (u *authUseCase) Login(ctx context.Context, email, password string) (*LoginResult, error) {
 	// ユーザーの検索
 	user, err := u.userRepo.FindByEmail(ctx, email)
 	if err != nil {
-		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return nil, errors.New("メールアドレスまたはパスワードが正しくありません")
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, ErrInvalidCredentials
 		}
 		return nil, err
 	}
 
-	// パスワードの検証
-	if err := bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte(password)); err != nil {
-		return nil, errors.New("メールアドレスまたはパスワードが正しくありません")
+	// パスワードの検証 (外部 ID プロバイダーのみで登録したユーザーはパスワードでログインできない)
+	if !user.HasPassword() {
+		return nil, ErrInvalidCredentials
+	}
+	if err := bcrypt.CompareHashAndPassword([]byte(*user.PasswordHash), []byte(password)); err != nil {
+		return nil, ErrInvalidCredentials
+	}
+
+	return u.loginResult(ctx, user)
+}
+
+// loginResult は本人確認が済んだユーザーのログイン結果を返します
+// 二要素認証が有効な場合はコードの入力を待つ
+func (u *authUseCase) loginResult(ctx context.Context, user *entity.User) (*LoginResult, error) {
+	if user.MFAEnabled() {
+		token, err := u.createMFAChallenge(ctx, user.ID)
+		if err != nil {
+			return nil, err
+		}
+		return &LoginResult{MFAToken: token, MFAExpiresIn: int(mfaChallengeTTL.Seconds())}, nil
 	}
+	return &LoginResult{User: user}, nil
+}
 
-	return user, nil
+// RequestPasswordReset はパスワードリセット用のリンクをメールで送信します
+func (u *authUseCase) RequestPasswordReset(ctx context.Context, email string) error {
+	user, err := u.userRepo.FindByEmail(ctx, email)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil
+		}
+		return err
+	}
+
+	token, err := randomToken(32)
+	if err != nil {
+		return err
+	}
+	if err := u.resetRepo.Create(ctx, &entity.PasswordResetToken{
+		UserID:    user.ID,
+		TokenHash: hashToken(token),
+		ExpiresAt: time.Now().Add(u.config.PasswordResetTTL),
+	}); err != nil {
+		return err
+	}
+
+	link := u.config.PasswordResetURL + "?token=" + url.QueryEscape(token)
+	return u.mailer.Send(ctx, &entity.Mail{
+		To:      user.Email,
+		Subject: "【Rabbit Cart】パスワード再設定のご案内",
+		Body: fmt.Sprintf("パスワード再設定のリクエストを受け付けました。\n"+
+			"以下のリンクから %d 分以内に新しいパスワードを設定してください。\n\n%s\n\n"+
+			"このメールに心当たりが無い場合は、このまま破棄してください。\n",
+			int(u.config.PasswordResetTTL.Minutes()), link),
+	})
+}
+
+// ResetPassword はトークンを検証してパスワードを変更します
+func (u *authUseCase) ResetPassword(ctx context.Context, token, newPassword string) error {
+	reset, err := u.resetRepo.FindByHash(ctx, hashToken(token))
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return ErrInvalidResetToken
+		}
+		return err
+	}
+	if reset.UsedAt != nil || time.Now().After(reset.ExpiresAt) {
+		return ErrInvalidResetToken
+	}
+
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(newPassword), bcrypt.DefaultCost)
+	if err != nil {
+		return err
+	}
+
+	if err := u.resetRepo.Consume(ctx, reset, string(hashedPassword)); err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return ErrInvalidResetToken
+		}
+		return err
+	}
+	return nil
+}
+
+// VerifyEmail はトークンを検証してユーザーのメールアドレスを確認済みにします
+func (u *authUseCase) VerifyEmail(ctx context.Context, token string) (*entity.User, error) {
+	verification, err := u.verificationRepo.FindByHash(ctx, hashToken(token))
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, ErrInvalidVerificationToken
+		}
+		return nil, err
+	}
+	if verification.UsedAt != nil || time.Now().After(verification.ExpiresAt) {
+		return nil, ErrInvalidVerificationToken
+	}
+
+	// メールアドレス変更の確認の場合は、変更前のアドレスに通知するため先に取得しておく
+	var previous *entity.User
+	if verification.NewEmail != "" {
+		if _, err := u.userRepo.FindByEmail(ctx, verification.NewEmail); err == nil {
+			return nil, ErrEmailAlreadyInUse
+		} else if !errors.Is(err, repository.ErrNotFound) {
+			return nil, err
+		}
+		if previous, err = u.userRepo.FindByID(ctx, verification.UserID); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := u.verificationRepo.Consume(ctx, verification); err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, ErrInvalidVerificationToken
+		}
+		return nil, err
+	}
+
+	if previous != nil {
+		if err := u.mailer.Send(ctx, &entity.Mail{
+			To:      previous.Email,
+			Subject: "【Rabbit Cart】メールアドレスが変更されました",
+			Body: fmt.Sprintf("ログインに使うメールアドレスが %s に変更されました。\n\n"+
+				"この変更に心当たりが無い場合は、お問い合わせ窓口までご連絡ください。\n", verification.NewEmail),
+		}); err != nil {
+			log.Printf("メールアドレス変更の通知に失敗しました (user_id=%s): %v", previous.ID, err)
+		}
+	}
+	return u.userRepo.FindByID(ctx, verification.UserID)
+}
+
+// ResendVerification はメールアドレス確認用のリンクを再送します
+func (u *authUseCase) ResendVerification(ctx context.Context, userID string) error {
+	user, err := u.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if user.IsVerified() {
+		return ErrAlreadyVerified
+	}
+
+	latest, err := u.verificationRepo.FindLatestByUserID(ctx, userID)
+	if err != nil && !errors.Is(err, repository.ErrNotFound) {
+		return err
+	}
+	if latest != nil {
+		if wait := time.Until(latest.CreatedAt.Add(u.config.VerificationResendInterval)); wait > 0 {
+			return &ThrottledError{RetryAfter: wait}
+		}
+	}
+
+	return u.sendVerification(ctx, user)
+}
+
+// sendVerification は確認用のトークンを発行してメールで送信します
+func (u *authUseCase) sendVerification(ctx context.Context, user *entity.User) error {
+	token, err := randomToken(32)
+	if err != nil {
+		return err
+	}
+	if err := u.verificationRepo.Create(ctx, &entity.EmailVerificationToken{
+		UserID:    user.ID,
+		TokenHash: hashToken(token),
+		ExpiresAt: time.Now().Add(u.config.VerificationTTL),
+	}); err != nil {
+		return err
+	}
+
+	link := u.config.VerificationURL + "?token=" + url.QueryEscape(token)
+	return u.mailer.Send(ctx, &entity.Mail{
+		To:      user.Email,
+		Subject: "【Rabbit Cart】メールアドレスの確認",
+		Body: fmt.Sprintf("Rabbit Cart にご登録いただきありがとうございます。\n"+
+			"以下のリンクから %d 時間以内にメールアドレスの確認を完了してください。\n\n%s\n\n"+
+			"このメールに心当たりが無い場合は、このまま破棄してください。\n",
+			int(u.config.VerificationTTL.Hours()), link),
+	})
 }
@@ -0,0 +1,208 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/sotaheavymetal21/rabbit-cart/backend/internal/domain/entity"
+	domainrepository "github.com/sotaheavymetal21/rabbit-cart/backend/internal/domain/repository"
+	"github.com/sotaheavymetal21/rabbit-cart/backend/internal/infrastructure/mail"
+	"github.com/sotaheavymetal21/rabbit-cart/backend/internal/usecase"
+)
+
+// memoryUserRepository はメモリ上でユーザーを保持する UserRepository です
+type memoryUserRepository struct {
+	users         map[string]*entity.User
+	keptSessionID string // 最後のパスワード変更で残したセッション
+}
+
+func (r *memoryUserRepository) Create(ctx context.Context, user *entity.User) error {
+	user.ID = "user-" + user.Email
+	user.CreatedAt = time.Now()
+	r.users[user.ID] = user
+	return nil
+}
+
+func (r *memoryUserRepository) FindByEmail(ctx context.Context, email string) (*entity.User, error) {
+	for _, user := range r.users {
+		if user.Email == email {
+			return user, nil
+		}
+	}
+	return nil, domainrepository.ErrNotFound
+}
+
+func (r *memoryUserRepository) FindByID(ctx context.Context, id string) (*entity.User, error) {
+	user, ok := r.users[id]
+	if !ok {
+		return nil, domainrepository.ErrNotFound
+	}
+	return user, nil
+}
+
+func (r *memoryUserRepository) UpdateProfile(ctx context.Context, id, displayName, phone string, locale entity.Locale) error {
+	r.users[id].DisplayName, r.users[id].Phone, r.users[id].Locale = displayName, phone, locale
+	return nil
+}
+
+func (r *memoryUserRepository) UpdatePassword(ctx context.Context, id, passwordHash, keepSessionID string) error {
+	r.users[id].PasswordHash = &passwordHash
+	r.keptSessionID = keepSessionID
+	return nil
+}
+
+func (r *memoryUserRepository) Delete(ctx context.Context, id string) error {
+	delete(r.users, id)
+	return nil
+}
+
+// memoryVerificationRepository はメモリ上で確認用トークンを保持する EmailVerificationRepository です
+type memoryVerificationRepository struct {
+	users  *memoryUserRepository
+	tokens []*entity.EmailVerificationToken
+}
+
+func (r *memoryVerificationRepository) Create(ctx context.Context, token *entity.EmailVerificationToken) error {
+	token.CreatedAt = time.Now()
+	r.tokens = append(r.tokens, token)
+	return nil
+}
+
+func (r *memoryVerificationRepository) FindByHash(ctx context.Context, tokenHash string) (*entity.EmailVerificationToken, error) {
+	for _, token := range r.tokens {
+		if token.TokenHash == tokenHash {
+			copied := *token
+			return &copied, nil
+		}
+	}
+	return nil, domainrepository.ErrNotFound
+}
+
+func (r *memoryVerificationRepository) FindLatestByUserID(ctx context.Context, userID string) (*entity.EmailVerificationToken, error) {
+	for i := len(r.tokens) - 1; i >= 0; i-- {
+		if r.tokens[i].UserID == userID {
+			return r.tokens[i], nil
+		}
+	}
+	return nil, domainrepository.ErrNotFound
+}
+
+func (r *memoryVerificationRepository) Consume(ctx context.Context, token *entity.EmailVerificationToken) error {
+	for _, stored := range r.tokens {
+		if stored.TokenHash == token.TokenHash && stored.UsedAt == nil {
+			now := time.Now()
+			stored.UsedAt = &now
+			r.users.users[stored.UserID].VerifiedAt = &now
+			return nil
+		}
+	}
+	return domainrepository.ErrNotFound
+}
+
+var verificationLinkPattern = regexp.MustCompile(`token=(\S+)`)
+
+// linkToken はメール本文のリンクからトークンを取り出します
+func linkToken(t *testing.T, m entity.Mail) string {
+	t.Helper()
+	match := verificationLinkPattern.FindStringSubmatch(m.Body)
+	if match == nil {
+		t.Fatalf("メール本文にリンクがありません: %q", m.Body)
+	}
+	token, err := url.QueryUnescape(match[1])
+	if err != nil {
+		t.Fatal(err)
+	}
+	return token
+}
+
+func newVerificationFixture() (usecase.AuthUseCase, *memoryVerificationRepository, *mail.MemoryMailer) {
+	users := &memoryUserRepository{users: make(map[string]*entity.User)}
+	verifications := &memoryVerificationRepository{users: users}
+	mailer := mail.NewMemoryMailer()
+	auth := usecase.NewAuthUseCase(users, nil, verifications, nil, nil, mailer, nil, usecase.AuthConfig{
+		VerificationURL:            "http://localhost:3000/verify",
+		VerificationResendInterval: time.Minute,
+	})
+	return auth, verifications, mailer
+}
+
+func TestVerifyEmail(t *testing.T) {
+	auth, _, mailer := newVerificationFixture()
+	ctx := context.Background()
+
+	user, err := auth.Register(ctx, "new@example.com", "password123")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if user.IsVerified() {
+		t.Fatal("登録直後のユーザーが確認済みになっています")
+	}
+	sent := mailer.Sent()
+	if len(sent) != 1 || sent[0].To != "new@example.com" {
+		t.Fatalf("送信したメール = %+v, want new@example.com 宛の1通", sent)
+	}
+	token := linkToken(t, sent[0])
+
+	verified, err := auth.VerifyEmail(ctx, token)
+	if err != nil {
+		t.Fatalf("VerifyEmail: %v", err)
+	}
+	if !verified.IsVerified() {
+		t.Error("VerifyEmail 後もユーザーが未確認です")
+	}
+
+	// 使用済みのトークンと不明なトークンは使えない
+	for name, token := range map[string]string{"使用済み": token, "不明": "unknown"} {
+		if _, err := auth.VerifyEmail(ctx, token); !errors.Is(err, usecase.ErrInvalidVerificationToken) {
+			t.Errorf("%s: VerifyEmail = %v, want ErrInvalidVerificationToken", name, err)
+		}
+	}
+	if err := auth.ResendVerification(ctx, user.ID); !errors.Is(err, usecase.ErrAlreadyVerified) {
+		t.Errorf("ResendVerification = %v, want ErrAlreadyVerified", err)
+	}
+}
+
+func TestVerifyEmailRejectsExpiredToken(t *testing.T) {
+	auth, verifications, mailer := newVerificationFixture()
+	ctx := context.Background()
+
+	if _, err := auth.Register(ctx, "new@example.com", "password123"); err != nil {
+		t.Fatal(err)
+	}
+	verifications.tokens[0].ExpiresAt = time.Now().Add(-time.Second)
+
+	if _, err := auth.VerifyEmail(ctx, linkToken(t, mailer.Sent()[0])); !errors.Is(err, usecase.ErrInvalidVerificationToken) {
+		t.Errorf("VerifyEmail = %v, want ErrInvalidVerificationToken", err)
+	}
+}
+
+func TestResendVerificationThrottled(t *testing.T) {
+	auth, verifications, mailer := newVerificationFixture()
+	ctx := context.Background()
+
+	user, err := auth.Register(ctx, "new@example.com", "password123")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// 前回の送信から間隔が空いていなければ再送しない
+	var throttled *usecase.ThrottledError
+	if err := auth.ResendVerification(ctx, user.ID); !errors.As(err, &throttled) {
+		t.Fatalf("ResendVerification = %v, want ThrottledError", err)
+	}
+	if secs := throttled.RetryAfterSeconds(); secs < 1 || secs > 60 {
+		t.Errorf("RetryAfterSeconds = %d, want 1〜60", secs)
+	}
+
+	verifications.tokens[0].CreatedAt = time.Now().Add(-2 * time.Minute)
+	if err := auth.ResendVerification(ctx, user.ID); err != nil {
+		t.Fatalf("間隔が空いた後の ResendVerification: %v", err)
+	}
+	if n := len(mailer.Sent()); n != 2 {
+		t.Errorf("送信したメール = %d 通, want 2 通", n)
+	}
+}
@@ -0,0 +1,319 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/sotaheavymetal21/rabbit-cart/backend/internal/domain/entity"
+	"github.com/sotaheavymetal21/rabbit-cart/backend/internal/domain/repository"
+)
+
+var (
+	// ErrProductNotFound は指定された商品が存在しない場合のエラーです
+	ErrProductNotFound = entity.NewNotFoundError("product_not_found", "商品が見つかりません")
+	// ErrInvalidQuantity は数量が不正な場合のエラーです
+	ErrInvalidQuantity = entity.NewFieldError("invalid_quantity", "quantity", "数量は1以上を指定してください")
+)
+
+// CartUseCase はカートに関するビジネスロジックを定義するインターフェースです
+type CartUseCase interface {
+	GetCart(ctx context.Context, userID string) (*entity.Cart, error)
+	AddItem(ctx context.Context, userID, productID string, quantity int) (*entity.Cart, error)
+	SetItemQuantity(ctx context.Context, userID, productID string, quantity int) (*entity.Cart, error)
+	RemoveItem(ctx context.Context, userID, productID string) (*entity.Cart, error)
+	ClearCart(ctx context.Context, userID string) (*entity.Cart, error)
+
+	// GetGuestCart はセッションに保存されたゲストカートの明細から商品情報付きのカートを組み立てます
+	GetGuestCart(ctx context.Context, items []entity.GuestCartItem) (*entity.Cart, error)
+	// AddGuestItem はゲストカートに商品を追加し、更新後の明細を返します
+	AddGuestItem(ctx context.Context, items []entity.GuestCartItem, productID string, quantity int) ([]entity.GuestCartItem, error)
+	// SetGuestItemQuantity はゲストカート内の商品の数量を設定し、更新後の明細を返します（0 の場合は削除します）
+	SetGuestItemQuantity(ctx context.Context, items []entity.GuestCartItem, productID string, quantity int) ([]entity.GuestCartItem, error)
+	// MergeGuestCart はゲストカートの明細をユーザーのカートにマージし、調整が発生した明細を返します
+	MergeGuestCart(ctx context.Context, userID string, items []entity.GuestCartItem) (*CartMergeResult, error)
+}
+
+// CartMergePolicy はゲストカートをユーザーのカートにマージする際のルールです
+type CartMergePolicy struct {
+	Strategy     entity.CartMergeStrategy
+	ClampToStock bool // true の場合、マージ後の数量を在庫数までに抑えます
+}
+
+// CartMergeResult はマージ後のカートと調整が発生した明細の一覧です
+type CartMergeResult struct {
+	Cart        *entity.Cart                 `json:"cart"`
+	Adjustments []entity.CartMergeAdjustment `json:"adjustments"`
+}
+
+type cartUseCase struct {
+	cartRepo    repository.CartRepository
+	productRepo repository.ProductRepository
+	mergePolicy CartMergePolicy
+}
+
+// NewCartUseCase は CartUseCase の実装を生成します
+// mergePolicy.Strategy が空の場合は sum を使い、不明な値の場合はエラーを返します
+func NewCartUseCase(cartRepo repository.CartRepository, productRepo repository.ProductRepository, mergePolicy CartMergePolicy) (CartUseCase, error) {
+	if mergePolicy.Strategy == "" {
+		mergePolicy.Strategy = entity.CartMergeStrategySum
+	}
+	if !mergePolicy.Strategy.IsValid() {
+		return nil, fmt.Errorf("不明なマージ方法です: %s (sum, max)", mergePolicy.Strategy)
+	}
+	return &cartUseCase{
+		cartRepo:    cartRepo,
+		productRepo: productRepo,
+		mergePolicy: mergePolicy,
+	}, nil
+}
+
+// GetCart はユーザーのカートを取得します
+func (u *cartUseCase) GetCart(ctx context.Context, userID string) (*entity.Cart, error) {
+	cart, err := u.cartRepo.FindOrCreateByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	cart.CalculateTotal()
+	return cart, nil
+}
+
+// AddItem はカートに商品を追加します（既にある場合は数量を加算します）
+func (u *cartUseCase) AddItem(ctx context.Context, userID, productID string, quantity int) (*entity.Cart, error) {
+	if quantity <= 0 {
+		return nil, ErrInvalidQuantity
+	}
+
+	cart, err := u.cartRepo.FindOrCreateByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if item := cart.FindItem(productID); item != nil {
+		quantity += item.Quantity
+	}
+
+	if err := u.setQuantity(ctx, cart, productID, quantity); err != nil {
+		return nil, err
+	}
+	return u.GetCart(ctx, userID)
+}
+
+// SetItemQuantity はカート内の商品の数量を設定します（0 の場合は削除します）
+func (u *cartUseCase) SetItemQuantity(ctx context.Context, userID, productID string, quantity int) (*entity.Cart, error) {
+	if quantity < 0 {
+		return nil, ErrInvalidQuantity
+	}
+	if quantity == 0 {
+		return u.RemoveItem(ctx, userID, productID)
+	}
+
+	cart, err := u.cartRepo.FindOrCreateByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if err := u.setQuantity(ctx, cart, productID, quantity); err != nil {
+		return nil, err
+	}
+	return u.GetCart(ctx, userID)
+}
+
+// RemoveItem はカートから商品を削除します
+func (u *cartUseCase) RemoveItem(ctx context.Context, userID, productID string) (*entity.Cart, error) {
+	cart, err := u.cartRepo.FindOrCreateByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if err := u.cartRepo.RemoveItem(ctx, cart.ID, productID); err != nil {
+		return nil, err
+	}
+	return u.GetCart(ctx, userID)
+}
+
+// ClearCart はカートを空にします
+func (u *cartUseCase) ClearCart(ctx context.Context, userID string) (*entity.Cart, error) {
+	cart, err := u.cartRepo.FindOrCreateByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if err := u.cartRepo.Clear(ctx, cart.ID); err != nil {
+		return nil, err
+	}
+	return u.GetCart(ctx, userID)
+}
+
+// GetGuestCart はゲストカートの明細に商品情報を付与して返します
+// 削除された商品の明細は結果に含めません
+func (u *cartUseCase) GetGuestCart(ctx context.Context, items []entity.GuestCartItem) (*entity.Cart, error) {
+	cart := &entity.Cart{Items: []entity.CartItem{}}
+	for _, item := range items {
+		product, err := u.findProduct(ctx, item.ProductID)
+		if errors.Is(err, ErrProductNotFound) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		cart.Items = append(cart.Items, entity.CartItem{
+			ProductID: item.ProductID,
+			Quantity:  item.Quantity,
+			Product:   *product,
+		})
+	}
+	cart.CalculateTotal()
+	return cart, nil
+}
+
+// AddGuestItem はゲストカートに商品を追加します（既にある場合は数量を加算します）
+func (u *cartUseCase) AddGuestItem(ctx context.Context, items []entity.GuestCartItem, productID string, quantity int) ([]entity.GuestCartItem, error) {
+	if quantity <= 0 {
+		return nil, ErrInvalidQuantity
+	}
+	for _, item := range items {
+		if item.ProductID == productID {
+			quantity += item.Quantity
+		}
+	}
+	return u.SetGuestItemQuantity(ctx, items, productID, quantity)
+}
+
+// SetGuestItemQuantity はゲストカート内の商品の数量を設定します
+func (u *cartUseCase) SetGuestItemQuantity(ctx context.Context, items []entity.GuestCartItem, productID string, quantity int) ([]entity.GuestCartItem, error) {
+	if quantity < 0 {
+		return nil, ErrInvalidQuantity
+	}
+	if quantity > 0 {
+		if err := u.checkStock(ctx, productID, quantity); err != nil {
+			return nil, err
+		}
+	}
+
+	updated := make([]entity.GuestCartItem, 0, len(items)+1)
+	found := false
+	for _, item := range items {
+		if item.ProductID != productID {
+			updated = append(updated, item)
+			continue
+		}
+		found = true
+		if quantity > 0 {
+			updated = append(updated, entity.GuestCartItem{ProductID: productID, Quantity: quantity})
+		}
+	}
+	if !found && quantity > 0 {
+		updated = append(updated, entity.GuestCartItem{ProductID: productID, Quantity: quantity})
+	}
+	return updated, nil
+}
+
+// MergeGuestCart はゲストカートをユーザーのカートにマージします
+// 同じ商品がある場合は mergePolicy.Strategy に従って数量を決め、ClampToStock が有効なら在庫数までに抑えます
+func (u *cartUseCase) MergeGuestCart(ctx context.Context, userID string, items []entity.GuestCartItem) (*CartMergeResult, error) {
+	cart, err := u.cartRepo.FindOrCreateByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	adjustments := []entity.CartMergeAdjustment{}
+	for _, guestItem := range items {
+		if guestItem.Quantity <= 0 {
+			continue
+		}
+
+		adjustment := entity.CartMergeAdjustment{
+			ProductID:     guestItem.ProductID,
+			GuestQuantity: guestItem.Quantity,
+		}
+
+		product, err := u.findProduct(ctx, guestItem.ProductID)
+		if errors.Is(err, ErrProductNotFound) {
+			adjustment.Reasons = append(adjustment.Reasons, entity.CartMergeReasonNotFound)
+			adjustments = append(adjustments, adjustment)
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		adjustment.Name = product.Name
+
+		quantity := guestItem.Quantity
+		if userItem := cart.FindItem(guestItem.ProductID); userItem != nil {
+			adjustment.UserQuantity = userItem.Quantity
+			adjustment.Reasons = append(adjustment.Reasons, entity.CartMergeReasonConflict)
+			quantity = u.resolveConflict(userItem.Quantity, guestItem.Quantity)
+		}
+
+		if u.mergePolicy.ClampToStock && quantity > product.Stock {
+			quantity = product.Stock
+			if quantity == 0 {
+				adjustment.Reasons = append(adjustment.Reasons, entity.CartMergeReasonOutOfStock)
+			} else {
+				adjustment.Reasons = append(adjustment.Reasons, entity.CartMergeReasonClampedToStock)
+			}
+		}
+
+		if quantity > 0 {
+			if err := u.cartRepo.SetItemQuantity(ctx, cart.ID, guestItem.ProductID, quantity); err != nil {
+				return nil, err
+			}
+		} else if adjustment.UserQuantity > 0 {
+			if err := u.cartRepo.RemoveItem(ctx, cart.ID, guestItem.ProductID); err != nil {
+				return nil, err
+			}
+		}
+
+		if len(adjustment.Reasons) > 0 {
+			adjustment.Quantity = quantity
+			adjustments = append(adjustments, adjustment)
+		}
+	}
+
+	merged, err := u.GetCart(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	return &CartMergeResult{Cart: merged, Adjustments: adjustments}, nil
+}
+
+// resolveConflict は両方のカートにある商品の数量をマージ戦略に従って決定します
+func (u *cartUseCase) resolveConflict(userQuantity, guestQuantity int) int {
+	if u.mergePolicy.Strategy == entity.CartMergeStrategyMax {
+		return max(userQuantity, guestQuantity)
+	}
+	return userQuantity + guestQuantity
+}
+
+// setQuantity は商品の存在と在庫を確認してから数量を保存します
+func (u *cartUseCase) setQuantity(ctx context.Context, cart *entity.Cart, productID string, quantity int) error {
+	if err := u.checkStock(ctx, productID, quantity); err != nil {
+		return err
+	}
+	return u.cartRepo.SetItemQuantity(ctx, cart.ID, productID, quantity)
+}
+
+// checkStock は商品が存在し、指定された数量の在庫があるかを確認します
+func (u *cartUseCase) checkStock(ctx context.Context, productID string, quantity int) error {
+	product, err := u.findProduct(ctx, productID)
+	if err != nil {
+		return err
+	}
+	if product.Stock < quantity {
+		return &entity.InsufficientStockError{Shortages: []entity.StockShortage{{
+			ProductID: product.ID,
+			Name:      product.Name,
+			Requested: quantity,
+			Available: product.Stock,
+		}}}
+	}
+	return nil
+}
+
+func (u *cartUseCase) findProduct(ctx context.Context, productID string) (*entity.Product, error) {
+	product, err := u.productRepo.FindByID(ctx, productID)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, ErrProductNotFound
+		}
+		return nil, err
+	}
+	return product, nil
+}
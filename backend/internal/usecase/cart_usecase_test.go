@@ -0,0 +1,288 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/sotaheavymetal21/rabbit-cart/backend/internal/domain/entity"
+	domainrepository "github.com/sotaheavymetal21/rabbit-cart/backend/internal/domain/repository"
+	"github.com/sotaheavymetal21/rabbit-cart/backend/internal/usecase"
+)
+
+// stubProductRepository はメモリ上の商品を返す ProductRepository です
+type stubProductRepository struct {
+	domainrepository.ProductRepository
+	products map[string]*entity.Product
+}
+
+func (r *stubProductRepository) FindByID(ctx context.Context, id string) (*entity.Product, error) {
+	product, ok := r.products[id]
+	if !ok {
+		return nil, domainrepository.ErrNotFound
+	}
+	copied := *product
+	return &copied, nil
+}
+
+// stubCartRepository はメモリ上でユーザーごとのカートを保持する CartRepository です
+type stubCartRepository struct {
+	products *stubProductRepository
+	carts    map[string]map[string]int // ユーザーID → 商品ID → 数量
+	order    map[string][]string       // ユーザーID → 明細を追加した順の商品ID
+}
+
+func newStubCartRepository(products *stubProductRepository) *stubCartRepository {
+	return &stubCartRepository{
+		products: products,
+		carts:    make(map[string]map[string]int),
+		order:    make(map[string][]string),
+	}
+}
+
+// カートIDにはユーザーIDをそのまま使う
+func (r *stubCartRepository) FindOrCreateByUserID(ctx context.Context, userID string) (*entity.Cart, error) {
+	if _, ok := r.carts[userID]; !ok {
+		r.carts[userID] = make(map[string]int)
+	}
+	cart := &entity.Cart{ID: userID, UserID: userID, Items: []entity.CartItem{}}
+	for _, productID := range r.order[userID] {
+		quantity, ok := r.carts[userID][productID]
+		if !ok {
+			continue
+		}
+		item := entity.CartItem{CartID: userID, ProductID: productID, Quantity: quantity}
+		if product, ok := r.products.products[productID]; ok {
+			item.Product = *product
+		}
+		cart.Items = append(cart.Items, item)
+	}
+	return cart, nil
+}
+
+func (r *stubCartRepository) SetItemQuantity(ctx context.Context, cartID, productID string, quantity int) error {
+	if _, ok := r.carts[cartID]; !ok {
+		r.carts[cartID] = make(map[string]int)
+	}
+	if _, ok := r.carts[cartID][productID]; !ok {
+		r.order[cartID] = append(r.order[cartID], productID)
+	}
+	r.carts[cartID][productID] = quantity
+	return nil
+}
+
+func (r *stubCartRepository) RemoveItem(ctx context.Context, cartID, productID string) error {
+	delete(r.carts[cartID], productID)
+	return nil
+}
+
+func (r *stubCartRepository) Clear(ctx context.Context, cartID string) error {
+	r.carts[cartID] = make(map[string]int)
+	return nil
+}
+
+func newCartFixture(t *testing.T, policy usecase.CartMergePolicy) (usecase.CartUseCase, *stubCartRepository) {
+	t.Helper()
+	products := &stubProductRepository{products: map[string]*entity.Product{
+		"tee":      {ID: "tee", Name: "Tシャツ", Price: 3000, Stock: 5},
+		"socks":    {ID: "socks", Name: "靴下", Price: 800, Stock: 2},
+		"sold-out": {ID: "sold-out", Name: "完売した帽子", Price: 2500, Stock: 0},
+	}}
+	carts := newStubCartRepository(products)
+	cartUseCase, err := usecase.NewCartUseCase(carts, products, policy)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return cartUseCase, carts
+}
+
+func TestCartAddItemAccumulatesUpToStock(t *testing.T) {
+	carts, _ := newCartFixture(t, usecase.CartMergePolicy{})
+	ctx := context.Background()
+
+	if _, err := carts.AddItem(ctx, "user-1", "tee", 2); err != nil {
+		t.Fatal(err)
+	}
+	cart, err := carts.AddItem(ctx, "user-1", "tee", 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if item := cart.FindItem("tee"); item == nil || item.Quantity != 5 {
+		t.Fatalf("明細 = %+v, want 数量 5", item)
+	}
+	if cart.TotalAmount != 15000 {
+		t.Errorf("TotalAmount = %d, want 15000", cart.TotalAmount)
+	}
+
+	// 合算した数量が在庫を超える場合は追加しない
+	_, err = carts.AddItem(ctx, "user-1", "tee", 1)
+	var stockErr *entity.InsufficientStockError
+	if !errors.As(err, &stockErr) {
+		t.Fatalf("AddItem = %v, want InsufficientStockError", err)
+	}
+	if s := stockErr.Shortages[0]; s.Requested != 6 || s.Available != 5 {
+		t.Errorf("不足 = %+v, want 要求 6 / 在庫 5", s)
+	}
+	cart, err = carts.GetCart(ctx, "user-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if item := cart.FindItem("tee"); item == nil || item.Quantity != 5 {
+		t.Errorf("在庫不足の追加後の明細 = %+v, want 数量 5", item)
+	}
+}
+
+func TestCartSetItemQuantity(t *testing.T) {
+	tests := []struct {
+		name      string
+		productID string
+		quantity  int
+		want      int // 設定後の数量 (0 は明細なし)
+		wantErr   func(error) bool
+	}{
+		{name: "数量を変更", productID: "socks", quantity: 2, want: 2},
+		{name: "0 で削除", productID: "socks", quantity: 0, want: 0},
+		{name: "在庫を超える", productID: "socks", quantity: 3, want: 1, wantErr: func(err error) bool {
+			var stockErr *entity.InsufficientStockError
+			return errors.As(err, &stockErr)
+		}},
+		{name: "負の数量", productID: "socks", quantity: -1, want: 1, wantErr: func(err error) bool {
+			return errors.Is(err, usecase.ErrInvalidQuantity)
+		}},
+		{name: "存在しない商品", productID: "unknown", quantity: 1, want: 0, wantErr: func(err error) bool {
+			return errors.Is(err, usecase.ErrProductNotFound)
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			carts, _ := newCartFixture(t, usecase.CartMergePolicy{})
+			ctx := context.Background()
+			if _, err := carts.AddItem(ctx, "user-1", "socks", 1); err != nil {
+				t.Fatal(err)
+			}
+
+			_, err := carts.SetItemQuantity(ctx, "user-1", tt.productID, tt.quantity)
+			if tt.wantErr == nil && err != nil {
+				t.Fatalf("SetItemQuantity: %v", err)
+			}
+			if tt.wantErr != nil && !tt.wantErr(err) {
+				t.Fatalf("SetItemQuantity = %v", err)
+			}
+
+			cart, err := carts.GetCart(ctx, "user-1")
+			if err != nil {
+				t.Fatal(err)
+			}
+			got := 0
+			if item := cart.FindItem(tt.productID); item != nil {
+				got = item.Quantity
+			}
+			if got != tt.want {
+				t.Errorf("数量 = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewCartUseCaseRejectsUnknownMergeStrategy(t *testing.T) {
+	products := &stubProductRepository{}
+	if _, err := usecase.NewCartUseCase(newStubCartRepository(products), products, usecase.CartMergePolicy{Strategy: "min"}); err == nil {
+		t.Error("不明なマージ方法でエラーになりませんでした")
+	}
+}
+
+func TestCartAddGuestItemChecksStock(t *testing.T) {
+	carts, _ := newCartFixture(t, usecase.CartMergePolicy{})
+	ctx := context.Background()
+
+	items, err := carts.AddGuestItem(ctx, nil, "socks", 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if items, err = carts.AddGuestItem(ctx, items, "socks", 1); err != nil {
+		t.Fatal(err)
+	}
+	if len(items) != 1 || items[0].Quantity != 2 {
+		t.Fatalf("明細 = %+v, want 靴下 2 個", items)
+	}
+
+	_, err = carts.AddGuestItem(ctx, items, "socks", 1)
+	var stockErr *entity.InsufficientStockError
+	if !errors.As(err, &stockErr) {
+		t.Errorf("AddGuestItem = %v, want InsufficientStockError", err)
+	}
+}
+
+func TestCartMergeGuestCart(t *testing.T) {
+	guest := []entity.GuestCartItem{
+		{ProductID: "tee", Quantity: 4},
+		{ProductID: "socks", Quantity: 1},
+		{ProductID: "sold-out", Quantity: 1},
+		{ProductID: "deleted", Quantity: 1},
+	}
+	tests := []struct {
+		name        string
+		policy      usecase.CartMergePolicy
+		want        map[string]int // マージ後の数量
+		adjustments map[string][]entity.CartMergeReason
+	}{
+		{
+			name:   "合算",
+			policy: usecase.CartMergePolicy{Strategy: entity.CartMergeStrategySum},
+			want:   map[string]int{"tee": 7, "socks": 1, "sold-out": 1},
+			adjustments: map[string][]entity.CartMergeReason{
+				"tee":     {entity.CartMergeReasonConflict},
+				"deleted": {entity.CartMergeReasonNotFound},
+			},
+		},
+		{
+			name:   "多い方",
+			policy: usecase.CartMergePolicy{Strategy: entity.CartMergeStrategyMax},
+			want:   map[string]int{"tee": 4, "socks": 1, "sold-out": 1},
+			adjustments: map[string][]entity.CartMergeReason{
+				"tee":     {entity.CartMergeReasonConflict},
+				"deleted": {entity.CartMergeReasonNotFound},
+			},
+		},
+		{
+			name:   "在庫数まで",
+			policy: usecase.CartMergePolicy{Strategy: entity.CartMergeStrategySum, ClampToStock: true},
+			want:   map[string]int{"tee": 5, "socks": 1},
+			adjustments: map[string][]entity.CartMergeReason{
+				"tee":      {entity.CartMergeReasonConflict, entity.CartMergeReasonClampedToStock},
+				"sold-out": {entity.CartMergeReasonOutOfStock},
+				"deleted":  {entity.CartMergeReasonNotFound},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			carts, repo := newCartFixture(t, tt.policy)
+			ctx := context.Background()
+			// ログイン前からユーザーのカートに T シャツが 3 枚入っている
+			if err := repo.SetItemQuantity(ctx, "user-1", "tee", 3); err != nil {
+				t.Fatal(err)
+			}
+
+			result, err := carts.MergeGuestCart(ctx, "user-1", guest)
+			if err != nil {
+				t.Fatalf("MergeGuestCart: %v", err)
+			}
+			got := make(map[string]int)
+			for _, item := range result.Cart.Items {
+				got[item.ProductID] = item.Quantity
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("カート = %v, want %v", got, tt.want)
+			}
+			adjustments := make(map[string][]entity.CartMergeReason)
+			for _, a := range result.Adjustments {
+				adjustments[a.ProductID] = a.Reasons
+			}
+			if !reflect.DeepEqual(adjustments, tt.adjustments) {
+				t.Errorf("調整 = %v, want %v", adjustments, tt.adjustments)
+			}
+		})
+	}
+}
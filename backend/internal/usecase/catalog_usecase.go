@@ -0,0 +1,379 @@
+package usecase
+
+import (
+	"bufio"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/sotaheavymetal21/rabbit-cart/backend/internal/domain/entity"
+	"github.com/sotaheavymetal21/rabbit-cart/backend/internal/domain/repository"
+)
+
+const (
+	// catalogBatchSize は一括取り込み・書き出しで1度に処理する件数です
+	catalogBatchSize = 500
+	// maxCatalogImportRows は1回の取り込みで受け付ける最大行数です
+	maxCatalogImportRows = 10000
+)
+
+// CatalogFormat は商品カタログの入出力形式です
+type CatalogFormat string
+
+const (
+	CatalogFormatCSV   CatalogFormat = "csv"
+	CatalogFormatJSONL CatalogFormat = "jsonl"
+)
+
+var (
+	// ErrUnsupportedCatalogFormat は対応していない入出力形式が指定された場合のエラーです
+	ErrUnsupportedCatalogFormat = entity.NewFieldError("unsupported_catalog_format", "format", "対応していない形式です (csv または jsonl を指定してください)")
+	// ErrCatalogTooLarge は取り込み件数が上限を超えた場合のエラーです
+	ErrCatalogTooLarge = &entity.ValidationError{
+		Code:    "catalog_too_large",
+		Message: fmt.Sprintf("一度に取り込める商品は %d 件までです", maxCatalogImportRows),
+		Args:    []any{maxCatalogImportRows},
+	}
+	// ErrInvalidCatalogHeader は CSV のヘッダー行に必須の列が無い場合のエラーです
+	ErrInvalidCatalogHeader = entity.NewValidationError("invalid_catalog_header", "CSV のヘッダー行に必須の列がありません")
+
+	skuPattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9_-]{0,63}$`)
+)
+
+// catalogColumns は CSV の列の並びです（取り込み時はヘッダー名で列を判別します）
+var catalogColumns = []string{"sku", "name", "description", "price", "stock", "image_url", "category"}
+
+// CatalogRow は取り込み・書き出しの1行分の商品データです
+type CatalogRow struct {
+	SKU         string `json:"sku"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	Price       int    `json:"price"`
+	Stock       int    `json:"stock"`
+	ImageURL    string `json:"image_url"`
+	Category    string `json:"category"`
+}
+
+// CatalogImportOptions は取り込み時の動作を指定します
+type CatalogImportOptions struct {
+	// DryRun が true の場合は検証のみ行い、データベースには書き込みません
+	DryRun bool
+	// AllowPartial が true の場合はエラーの無い行だけを取り込みます
+	// false の場合は1行でもエラーがあれば何も取り込みません
+	AllowPartial bool
+}
+
+// CatalogRowError は取り込みに失敗した行の情報です
+type CatalogRowError struct {
+	Line    int    `json:"line"` // ファイル上の行番号 (CSV はヘッダー行を1行目とします)
+	SKU     string `json:"sku,omitempty"`
+	Field   string `json:"field,omitempty"`
+	Message string `json:"message"`
+}
+
+// CatalogImportReport は取り込み結果の報告です
+type CatalogImportReport struct {
+	Total    int               `json:"total"`
+	Created  int               `json:"created"`
+	Updated  int               `json:"updated"`
+	Failed   int               `json:"failed"`
+	DryRun   bool              `json:"dry_run"`
+	Imported bool              `json:"imported"` // データベースに書き込んだかどうか
+	Errors   []CatalogRowError `json:"errors"`
+}
+
+// CatalogUseCase は商品カタログの一括取り込み・書き出しを定義するインターフェースです
+type CatalogUseCase interface {
+	Import(ctx context.Context, format CatalogFormat, r io.Reader, opts CatalogImportOptions) (*CatalogImportReport, error)
+	Export(ctx context.Context, format CatalogFormat, w io.Writer) error
+}
+
+type catalogUseCase struct {
+	productRepo repository.ProductRepository
+	searcher    repository.ProductSearcher
+}
+
+// NewCatalogUseCase は CatalogUseCase の実装を生成します
+func NewCatalogUseCase(productRepo repository.ProductRepository, searcher repository.ProductSearcher) CatalogUseCase {
+	return &catalogUseCase{productRepo: productRepo, searcher: searcher}
+}
+
+// ParseCatalogFormat は文字列を CatalogFormat に変換します
+func ParseCatalogFormat(s string) (CatalogFormat, error) {
+	switch CatalogFormat(strings.ToLower(strings.TrimSpace(s))) {
+	case CatalogFormatCSV:
+		return CatalogFormatCSV, nil
+	case CatalogFormatJSONL, "ndjson", "json":
+		return CatalogFormatJSONL, nil
+	default:
+		return "", ErrUnsupportedCatalogFormat
+	}
+}
+
+// Import は CSV または JSONL の商品データを検証し、商品コードをキーに作成・更新します
+// stock 列は新規作成する商品の初期在庫です。既存の商品の在庫は変更しないため、補充は在庫補充 API で行います
+func (u *catalogUseCase) Import(ctx context.Context, format CatalogFormat, r io.Reader, opts CatalogImportOptions) (*CatalogImportReport, error) {
+	var rows []parsedCatalogRow
+	var err error
+	switch format {
+	case CatalogFormatCSV:
+		rows, err = parseCatalogCSV(r)
+	case CatalogFormatJSONL:
+		rows, err = parseCatalogJSONL(r)
+	default:
+		return nil, ErrUnsupportedCatalogFormat
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	report := &CatalogImportReport{Total: len(rows), DryRun: opts.DryRun, Errors: []CatalogRowError{}}
+	seen := make(map[string]int)
+	var products []*entity.Product
+	for _, row := range rows {
+		rowErrors := row.errors
+		if len(rowErrors) == 0 {
+			rowErrors = validateCatalogRow(row)
+		}
+		if line, ok := seen[row.SKU]; ok && row.SKU != "" {
+			rowErrors = append(rowErrors, CatalogRowError{
+				Line: row.line, SKU: row.SKU, Field: "sku",
+				Message: fmt.Sprintf("商品コードが %d 行目と重複しています", line),
+			})
+		}
+		if len(rowErrors) > 0 {
+			report.Failed++
+			report.Errors = append(report.Errors, rowErrors...)
+			continue
+		}
+		seen[row.SKU] = row.line
+		products = append(products, row.toProduct())
+	}
+
+	if opts.DryRun || len(products) == 0 || (report.Failed > 0 && !opts.AllowPartial) {
+		return report, nil
+	}
+
+	created, err := u.productRepo.UpsertBySKU(ctx, products, catalogBatchSize)
+	if err != nil {
+		return nil, err
+	}
+	report.Imported = true
+	report.Created = created
+	report.Updated = len(products) - created
+
+	for _, p := range products {
+		if err := u.searcher.Index(ctx, p); err != nil {
+			log.Printf("商品 %s の検索インデックスの更新に失敗しました: %v", p.ID, err)
+		}
+	}
+	return report, nil
+}
+
+// Export は全ての商品を指定された形式で w に書き出します
+// 商品は一定件数ずつ読み込みながら書き出すため、件数が多くてもメモリに全件を保持しません
+func (u *catalogUseCase) Export(ctx context.Context, format CatalogFormat, w io.Writer) error {
+	switch format {
+	case CatalogFormatCSV:
+		cw := csv.NewWriter(w)
+		if err := cw.Write(catalogColumns); err != nil {
+			return err
+		}
+		err := u.productRepo.FindInBatches(ctx, catalogBatchSize, func(products []*entity.Product) error {
+			for _, p := range products {
+				row := newCatalogRow(p)
+				if err := cw.Write([]string{
+					row.SKU, row.Name, row.Description,
+					strconv.Itoa(row.Price), strconv.Itoa(row.Stock),
+					row.ImageURL, row.Category,
+				}); err != nil {
+					return err
+				}
+			}
+			cw.Flush()
+			return cw.Error()
+		})
+		if err != nil {
+			return err
+		}
+		cw.Flush()
+		return cw.Error()
+	case CatalogFormatJSONL:
+		enc := json.NewEncoder(w)
+		enc.SetEscapeHTML(false)
+		return u.productRepo.FindInBatches(ctx, catalogBatchSize, func(products []*entity.Product) error {
+			for _, p := range products {
+				if err := enc.Encode(newCatalogRow(p)); err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+	default:
+		return ErrUnsupportedCatalogFormat
+	}
+}
+
+// parsedCatalogRow は読み込んだ行と、読み込み時点で見つかったエラーです
+type parsedCatalogRow struct {
+	CatalogRow
+	line   int
+	errors []CatalogRowError
+}
+
+func (r parsedCatalogRow) toProduct() *entity.Product {
+	return &entity.Product{
+		SKU:         r.SKU,
+		Name:        r.Name,
+		Description: r.Description,
+		Price:       r.Price,
+		Stock:       r.Stock,
+		ImageURL:    r.ImageURL,
+		Category:    r.Category,
+	}
+}
+
+func newCatalogRow(p *entity.Product) CatalogRow {
+	return CatalogRow{
+		SKU:         p.SKU,
+		Name:        p.Name,
+		Description: p.Description,
+		Price:       p.Price,
+		Stock:       p.Stock,
+		ImageURL:    p.ImageURL,
+		Category:    p.Category,
+	}
+}
+
+// parseCatalogCSV はヘッダー付きの CSV を読み込みます
+func parseCatalogCSV(r io.Reader) ([]parsedCatalogRow, error) {
+	cr := csv.NewReader(r)
+	cr.FieldsPerRecord = -1
+
+	header, err := cr.Read()
+	if err != nil {
+		return nil, ErrInvalidCatalogHeader
+	}
+	index := make(map[string]int)
+	for i, name := range header {
+		index[strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, "\ufeff")))] = i
+	}
+	for _, required := range []string{"sku", "name", "price", "stock", "category"} {
+		if _, ok := index[required]; !ok {
+			return nil, ErrInvalidCatalogHeader
+		}
+	}
+
+	var rows []parsedCatalogRow
+	for line := 2; ; line++ {
+		record, err := cr.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if len(rows) >= maxCatalogImportRows {
+			return nil, ErrCatalogTooLarge
+		}
+		row := parsedCatalogRow{line: line}
+		if err != nil {
+			row.errors = append(row.errors, CatalogRowError{Line: line, Message: "CSV の形式が正しくありません: " + err.Error()})
+			rows = append(rows, row)
+			continue
+		}
+
+		get := func(name string) string {
+			if i, ok := index[name]; ok && i < len(record) {
+				return strings.TrimSpace(record[i])
+			}
+			return ""
+		}
+		row.SKU = get("sku")
+		row.Name = get("name")
+		row.Description = get("description")
+		row.ImageURL = get("image_url")
+		row.Category = get("category")
+		for _, f := range []struct {
+			name string
+			dest *int
+		}{{"price", &row.Price}, {"stock", &row.Stock}} {
+			n, err := strconv.Atoi(get(f.name))
+			if err != nil {
+				row.errors = append(row.errors, CatalogRowError{Line: line, SKU: row.SKU, Field: f.name, Message: "整数で指定してください"})
+				continue
+			}
+			*f.dest = n
+		}
+		rows = append(rows, row)
+	}
+	return rows, nil
+}
+
+// parseCatalogJSONL は1行に1商品の JSON が並んだ形式を読み込みます（空行は無視します）
+func parseCatalogJSONL(r io.Reader) ([]parsedCatalogRow, error) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+
+	var rows []parsedCatalogRow
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" {
+			continue
+		}
+		if len(rows) >= maxCatalogImportRows {
+			return nil, ErrCatalogTooLarge
+		}
+		row := parsedCatalogRow{line: line}
+		if err := json.Unmarshal([]byte(text), &row.CatalogRow); err != nil {
+			row.errors = append(row.errors, CatalogRowError{Line: line, Message: "JSON の形式が正しくありません: " + err.Error()})
+		}
+		row.SKU = strings.TrimSpace(row.SKU)
+		row.Name = strings.TrimSpace(row.Name)
+		row.ImageURL = strings.TrimSpace(row.ImageURL)
+		row.Category = strings.TrimSpace(row.Category)
+		rows = append(rows, row)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return rows, nil
+}
+
+// validateCatalogRow は1行分の商品データを検証し、項目ごとのエラーを返します
+func validateCatalogRow(row parsedCatalogRow) []CatalogRowError {
+	var errs []CatalogRowError
+	add := func(field, message string) {
+		errs = append(errs, CatalogRowError{Line: row.line, SKU: row.SKU, Field: field, Message: message})
+	}
+
+	if !skuPattern.MatchString(row.SKU) {
+		add("sku", ErrInvalidSKU.Error())
+	}
+	if strings.TrimSpace(row.Name) == "" {
+		add("name", "商品名を入力してください")
+	} else if len([]rune(row.Name)) > 200 {
+		add("name", "商品名は200文字以内で入力してください")
+	}
+	if row.Price < 0 {
+		add("price", "価格は0以上で指定してください")
+	}
+	if row.Stock < 0 {
+		add("stock", "在庫数は0以上で指定してください")
+	}
+	if strings.TrimSpace(row.Category) == "" {
+		add("category", "カテゴリを入力してください")
+	} else if len([]rune(row.Category)) > 100 {
+		add("category", "カテゴリは100文字以内で入力してください")
+	}
+
+	input := ProductInput{ImageURL: row.ImageURL}
+	if err := validateProductInput(&input); err != nil {
+		add("image_url", err.Error())
+	}
+	return errs
+}
@@ -0,0 +1,136 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/sotaheavymetal21/rabbit-cart/backend/internal/domain/entity"
+	"github.com/sotaheavymetal21/rabbit-cart/backend/internal/infrastructure/search"
+	"github.com/sotaheavymetal21/rabbit-cart/backend/internal/usecase"
+)
+
+// upsertRecorder は UpsertBySKU に渡された商品を記録し、全て新規作成として扱う ProductRepository です
+type upsertRecorder struct {
+	stubProductRepository
+	upserted []*entity.Product
+}
+
+func (r *upsertRecorder) UpsertBySKU(ctx context.Context, products []*entity.Product, batchSize int) (int, error) {
+	r.upserted = append(r.upserted, products...)
+	return len(products), nil
+}
+
+func newCatalogFixture() (usecase.CatalogUseCase, *upsertRecorder) {
+	repo := &upsertRecorder{}
+	searcher := search.NewMemoryProductSearcher(func(ctx context.Context) ([]*entity.Product, error) { return nil, nil })
+	return usecase.NewCatalogUseCase(repo, searcher), repo
+}
+
+const catalogCSV = `sku,name,description,price,stock,image_url,category
+TEE-001,Tシャツ,綿100%,3000,10,,apparel
+TEE-002,,名前なし,3000,10,,apparel
+CAP-001,帽子,,abc,-1,,apparel
+-bad,靴下,,800,5,,apparel
+BAG-001,トートバッグ,,4200,3,javascript:alert(1),bags
+TEE-001,Tシャツ (重複),,3200,1,,apparel
+MUG-001,マグカップ,,1500,0,/images/mug.png,kitchen
+`
+
+func TestCatalogImportReportsRowErrors(t *testing.T) {
+	catalog, repo := newCatalogFixture()
+	report, err := catalog.Import(context.Background(), usecase.CatalogFormatCSV, strings.NewReader(catalogCSV), usecase.CatalogImportOptions{})
+	if err != nil {
+		t.Fatalf("Import: %v", err)
+	}
+
+	type fieldError struct {
+		Line  int
+		Field string
+	}
+	var got []fieldError
+	for _, e := range report.Errors {
+		got = append(got, fieldError{e.Line, e.Field})
+	}
+	want := []fieldError{
+		{3, "name"},
+		{4, "price"}, // 読み込めない値がある行は他の項目を検証しない
+		{5, "sku"},
+		{6, "image_url"},
+		{7, "sku"}, // 2 行目と重複
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("エラー = %v, want %v", got, want)
+	}
+	if report.Total != 7 || report.Failed != 5 {
+		t.Errorf("Total = %d, Failed = %d, want 7, 5", report.Total, report.Failed)
+	}
+	// エラーのある行がある場合は1件も取り込まない
+	if report.Imported || len(repo.upserted) != 0 {
+		t.Errorf("取り込んだ商品 = %d 件, want 0 件", len(repo.upserted))
+	}
+}
+
+func TestCatalogImportAllowPartial(t *testing.T) {
+	catalog, repo := newCatalogFixture()
+	report, err := catalog.Import(context.Background(), usecase.CatalogFormatCSV, strings.NewReader(catalogCSV), usecase.CatalogImportOptions{AllowPartial: true})
+	if err != nil {
+		t.Fatalf("Import: %v", err)
+	}
+	var skus []string
+	for _, p := range repo.upserted {
+		skus = append(skus, p.SKU)
+	}
+	if want := []string{"TEE-001", "MUG-001"}; !reflect.DeepEqual(skus, want) {
+		t.Errorf("取り込んだ商品 = %v, want %v", skus, want)
+	}
+	if repo.upserted[0].Name != "Tシャツ" {
+		t.Errorf("重複した商品コードは最初の行を取り込みます: %s", repo.upserted[0].Name)
+	}
+	if !report.Imported || report.Created != 2 {
+		t.Errorf("Imported = %v, Created = %d, want true, 2", report.Imported, report.Created)
+	}
+
+	// ドライランでは書き込まない
+	catalog, repo = newCatalogFixture()
+	report, err = catalog.Import(context.Background(), usecase.CatalogFormatCSV, strings.NewReader(catalogCSV), usecase.CatalogImportOptions{AllowPartial: true, DryRun: true})
+	if err != nil {
+		t.Fatalf("Import: %v", err)
+	}
+	if report.Imported || len(repo.upserted) != 0 {
+		t.Errorf("ドライランで %d 件取り込みました", len(repo.upserted))
+	}
+}
+
+func TestCatalogImportJSONL(t *testing.T) {
+	catalog, repo := newCatalogFixture()
+	input := `{"sku":"TEE-001","name":"Tシャツ","price":3000,"stock":10,"category":"apparel"}
+
+{"sku":"TEE-002","name":
+{"sku":"TEE-001","name":"Tシャツ","price":3000,"stock":10,"category":"apparel"}
+`
+	report, err := catalog.Import(context.Background(), usecase.CatalogFormatJSONL, strings.NewReader(input), usecase.CatalogImportOptions{AllowPartial: true})
+	if err != nil {
+		t.Fatalf("Import: %v", err)
+	}
+	if report.Total != 3 || report.Failed != 2 || len(repo.upserted) != 1 {
+		t.Errorf("Total = %d, Failed = %d, 取り込み = %d 件, want 3, 2, 1 件", report.Total, report.Failed, len(repo.upserted))
+	}
+	if len(report.Errors) < 2 || report.Errors[0].Line != 3 || report.Errors[len(report.Errors)-1].Line != 4 {
+		t.Errorf("エラー = %+v, want 3 行目と 4 行目", report.Errors)
+	}
+}
+
+func TestCatalogImportRejectsInvalidInput(t *testing.T) {
+	catalog, _ := newCatalogFixture()
+	ctx := context.Background()
+
+	if _, err := catalog.Import(ctx, usecase.CatalogFormatCSV, strings.NewReader("sku,name,price\nTEE-001,Tシャツ,3000\n"), usecase.CatalogImportOptions{}); !errors.Is(err, usecase.ErrInvalidCatalogHeader) {
+		t.Errorf("必須の列がない: Import = %v, want ErrInvalidCatalogHeader", err)
+	}
+	if _, err := catalog.Import(ctx, "xml", strings.NewReader(""), usecase.CatalogImportOptions{}); !errors.Is(err, usecase.ErrUnsupportedCatalogFormat) {
+		t.Errorf("未対応の形式: Import = %v, want ErrUnsupportedCatalogFormat", err)
+	}
+}
@@ -0,0 +1,131 @@
+package usecase
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"time"
+
+	"github.com/sotaheavymetal21/rabbit-cart/backend/internal/domain/entity"
+	"github.com/sotaheavymetal21/rabbit-cart/backend/internal/domain/repository"
+)
+
+// maxIdempotencyKeyLength は冪等キーの最大長です
+const maxIdempotencyKeyLength = 255
+
+var (
+	// ErrInvalidIdempotencyKey は冪等キーの形式が正しくない場合のエラーです
+	ErrInvalidIdempotencyKey = entity.NewFieldError("invalid_idempotency_key", "Idempotency-Key", "Idempotency-Key は255文字以内の英数字・記号で指定してください")
+	// ErrIdempotencyKeyInUse は同じ冪等キーのリクエストがまだ処理中の場合のエラーです
+	ErrIdempotencyKeyInUse = entity.NewConflictError("idempotency_key_in_use", "同じ Idempotency-Key のリクエストを処理中です。しばらくしてから再度お試しください")
+	// ErrIdempotencyKeyMismatch は冪等キーが以前と異なる内容のリクエストに使われた場合のエラーです
+	ErrIdempotencyKeyMismatch = entity.NewUnprocessableError("idempotency_key_mismatch", "この Idempotency-Key は異なる内容のリクエストで使用済みです")
+)
+
+// IdempotencyUseCase は Idempotency-Key ヘッダーによるリクエストの重複実行の防止に関するビジネスロジックを定義するインターフェースです
+type IdempotencyUseCase interface {
+	// Begin はユーザーの冪等キー key でリクエストの処理を開始します
+	// fingerprint はリクエストを識別する内容 (メソッド・パス・ボディ) で、同じキーの再送と内容が一致するかの確認に使います
+	// 同じ内容のリクエストが処理済みの場合は、保存したレスポンスを持つ (IsCompleted が true の) 冪等キーを返します
+	// 処理中の場合は ErrIdempotencyKeyInUse、内容が異なる場合は ErrIdempotencyKeyMismatch を返します
+	Begin(ctx context.Context, userID, key string, fingerprint []byte) (*entity.IdempotencyKey, error)
+	// Complete はリクエストの処理結果のレスポンスを保存し、以降の再送に返せるようにします
+	Complete(ctx context.Context, key *entity.IdempotencyKey, status int, body []byte) error
+	// Release は処理に失敗したリクエストの冪等キーを削除し、同じキーで再試行できるようにします
+	Release(ctx context.Context, key *entity.IdempotencyKey) error
+}
+
+// IdempotencyConfig は冪等キーの設定です
+type IdempotencyConfig struct {
+	TTL time.Duration // 冪等キーとレスポンスを保持する期間
+	// LockTimeout は処理中の冪等キーをロックしておく期間です
+	// 処理中にプロセスが停止して Complete も Release も呼ばれなかった場合、この期間が過ぎると同じキーで再試行できます
+	// リクエストの処理にかかる最大の時間より長くしてください
+	LockTimeout time.Duration
+}
+
+type idempotencyUseCase struct {
+	repo   repository.IdempotencyKeyRepository
+	config IdempotencyConfig
+}
+
+// NewIdempotencyUseCase は IdempotencyUseCase の実装を生成します
+func NewIdempotencyUseCase(repo repository.IdempotencyKeyRepository, config IdempotencyConfig) IdempotencyUseCase {
+	if config.TTL <= 0 {
+		config.TTL = 24 * time.Hour
+	}
+	if config.LockTimeout <= 0 {
+		config.LockTimeout = 2 * time.Minute
+	}
+	return &idempotencyUseCase{repo: repo, config: config}
+}
+
+// Begin はユーザーの冪等キー key でリクエストの処理を開始します
+// 冪等キーを処理中として先に保存することで、同時に再送されたリクエストも二重に処理しないようにします
+// 処理中の冪等キーの有効期限はロックの期限 (LockTimeout) とし、完了時に TTL まで延長します
+func (u *idempotencyUseCase) Begin(ctx context.Context, userID, key string, fingerprint []byte) (*entity.IdempotencyKey, error) {
+	if !isValidIdempotencyKey(key) {
+		return nil, ErrInvalidIdempotencyKey
+	}
+	sum := sha256.Sum256(fingerprint)
+	requestHash := hex.EncodeToString(sum[:])
+
+	record := &entity.IdempotencyKey{
+		UserID:      userID,
+		Key:         key,
+		RequestHash: requestHash,
+		ExpiresAt:   time.Now().Add(u.config.LockTimeout),
+	}
+	err := u.repo.Create(ctx, record)
+	if err == nil {
+		return record, nil
+	}
+	if !errors.Is(err, repository.ErrDuplicate) {
+		return nil, err
+	}
+
+	existing, err := u.repo.Find(ctx, userID, key)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			// 処理に失敗したリクエストが冪等キーを削除した直後
+			return nil, ErrIdempotencyKeyInUse
+		}
+		return nil, err
+	}
+	if existing.RequestHash != requestHash {
+		return nil, ErrIdempotencyKeyMismatch
+	}
+	if !existing.IsCompleted() {
+		return nil, ErrIdempotencyKeyInUse
+	}
+	return existing, nil
+}
+
+// Complete はリクエストの処理結果のレスポンスを保存し、冪等キーの有効期限を TTL まで延長します
+func (u *idempotencyUseCase) Complete(ctx context.Context, key *entity.IdempotencyKey, status int, body []byte) error {
+	expiresAt := time.Now().Add(u.config.TTL)
+	if err := u.repo.Complete(ctx, key.ID, status, body, expiresAt); err != nil {
+		return err
+	}
+	key.ResponseStatus, key.ResponseBody, key.ExpiresAt = status, body, expiresAt
+	return nil
+}
+
+// Release は処理に失敗したリクエストの冪等キーを削除します
+func (u *idempotencyUseCase) Release(ctx context.Context, key *entity.IdempotencyKey) error {
+	return u.repo.Delete(ctx, key.ID)
+}
+
+// isValidIdempotencyKey は冪等キーが空でなく、制御文字を含まない ASCII 文字列かを返します
+func isValidIdempotencyKey(key string) bool {
+	if key == "" || len(key) > maxIdempotencyKeyLength {
+		return false
+	}
+	for i := 0; i < len(key); i++ {
+		if key[i] < 0x20 || key[i] > 0x7e {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,163 @@
+package usecase
+
+import (
+	"context"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/sotaheavymetal21/rabbit-cart/backend/internal/domain/entity"
+	"github.com/sotaheavymetal21/rabbit-cart/backend/internal/domain/repository"
+)
+
+// LoginGuard はログインの総当たり攻撃を防ぐためのビジネスロジックを定義するインターフェースです
+// メールアドレス単位と接続元 IP 単位の両方で失敗回数を数え、一定回数を超えると待ち時間やロックアウトを課します
+type LoginGuard interface {
+	// Check はログインを試行してよいかを確認し、ロック中の場合は *ThrottledError を返します
+	Check(ctx context.Context, email, ip string) error
+	// RecordFailure はログインの失敗を記録します
+	// この失敗でロックされた場合は *ThrottledError を返します
+	RecordFailure(ctx context.Context, email, ip string) error
+	// RecordSuccess はログインの成功を記録し、メールアドレス単位の失敗回数をリセットします
+	RecordSuccess(ctx context.Context, email, ip string) error
+}
+
+// LoginGuardPolicy はログイン失敗時の待ち時間とロックアウトの設定です
+type LoginGuardPolicy struct {
+	Window time.Duration // 失敗回数を数える期間 (スライディングウィンドウ)
+
+	// DelayAfter 回目の失敗以降、失敗するたびに BaseDelay を倍にした待ち時間 (最大 MaxDelay) を課します
+	DelayAfter int
+	BaseDelay  time.Duration
+	MaxDelay   time.Duration
+
+	// 失敗回数が閾値に達すると LockoutDuration の間ロックアウトし、監査記録を残します
+	AccountLockoutThreshold int
+	IPLockoutThreshold      int
+	LockoutDuration         time.Duration
+}
+
+// DefaultLoginGuardPolicy は既定の設定です
+var DefaultLoginGuardPolicy = LoginGuardPolicy{
+	Window:                  15 * time.Minute,
+	DelayAfter:              3,
+	BaseDelay:               time.Second,
+	MaxDelay:                30 * time.Second,
+	AccountLockoutThreshold: 10,
+	IPLockoutThreshold:      50,
+	LockoutDuration:         15 * time.Minute,
+}
+
+type loginGuard struct {
+	store       repository.LoginAttemptStore
+	lockoutRepo repository.LoginLockoutRepository
+	policy      LoginGuardPolicy
+}
+
+// NewLoginGuard は LoginGuard の実装を生成します
+func NewLoginGuard(store repository.LoginAttemptStore, lockoutRepo repository.LoginLockoutRepository, policy LoginGuardPolicy) LoginGuard {
+	return &loginGuard{store: store, lockoutRepo: lockoutRepo, policy: policy}
+}
+
+// Check はログインを試行してよいかを確認します
+func (g *loginGuard) Check(ctx context.Context, email, ip string) error {
+	var wait time.Duration
+	for _, key := range []string{accountKey(email), ipKey(ip)} {
+		remaining, err := g.store.LockedFor(ctx, key)
+		if err != nil {
+			return err
+		}
+		wait = max(wait, remaining)
+	}
+	if wait > 0 {
+		return &ThrottledError{RetryAfter: wait}
+	}
+	return nil
+}
+
+// RecordFailure はログインの失敗を記録します
+func (g *loginGuard) RecordFailure(ctx context.Context, email, ip string) error {
+	accountFailures, err := g.store.RecordFailure(ctx, accountKey(email), g.policy.Window)
+	if err != nil {
+		return err
+	}
+	ipFailures, err := g.store.RecordFailure(ctx, ipKey(ip), g.policy.Window)
+	if err != nil {
+		return err
+	}
+
+	var wait time.Duration
+	if accountFailures >= g.policy.AccountLockoutThreshold {
+		if err := g.lockout(ctx, entity.LoginLockoutScopeAccount, accountKey(email), email, ip, accountFailures); err != nil {
+			return err
+		}
+		wait = g.policy.LockoutDuration
+	} else if delay := g.delay(accountFailures); delay > 0 {
+		if err := g.store.Lock(ctx, accountKey(email), delay); err != nil {
+			return err
+		}
+		wait = delay
+	}
+	if ipFailures >= g.policy.IPLockoutThreshold {
+		if err := g.lockout(ctx, entity.LoginLockoutScopeIP, ipKey(ip), email, ip, ipFailures); err != nil {
+			return err
+		}
+		wait = g.policy.LockoutDuration
+	}
+
+	if wait > 0 {
+		return &ThrottledError{RetryAfter: wait}
+	}
+	return nil
+}
+
+// RecordSuccess はログインの成功を記録します
+// IP 単位の失敗回数は、同じ IP から別のアカウントを狙う攻撃を検知するためリセットしません
+func (g *loginGuard) RecordSuccess(ctx context.Context, email, ip string) error {
+	return g.store.Reset(ctx, accountKey(email))
+}
+
+// delay は failures 回目の失敗に課す待ち時間を返します
+func (g *loginGuard) delay(failures int) time.Duration {
+	if g.policy.DelayAfter <= 0 || failures < g.policy.DelayAfter {
+		return 0
+	}
+	d := g.policy.BaseDelay
+	for i := g.policy.DelayAfter; i < failures && d < g.policy.MaxDelay; i++ {
+		d *= 2
+	}
+	return min(d, g.policy.MaxDelay)
+}
+
+// lockout は key をロックアウトし、監査記録を残します
+func (g *loginGuard) lockout(ctx context.Context, scope entity.LoginLockoutScope, key, email, ip string, failures int) error {
+	// 既にロックアウト中の場合は監査記録を重複させない
+	remaining, err := g.store.LockedFor(ctx, key)
+	if err != nil {
+		return err
+	}
+	if err := g.store.Lock(ctx, key, g.policy.LockoutDuration); err != nil {
+		return err
+	}
+	if remaining > g.policy.MaxDelay {
+		return nil
+	}
+
+	lockedUntil := time.Now().Add(g.policy.LockoutDuration)
+	log.Printf("ログイン失敗が続いたためロックアウトしました (scope=%s, ip=%s, failures=%d)", scope, ip, failures)
+	return g.lockoutRepo.Create(ctx, &entity.LoginLockout{
+		Scope:       scope,
+		Email:       email,
+		IPAddress:   ip,
+		Failures:    failures,
+		LockedUntil: lockedUntil,
+	})
+}
+
+func accountKey(email string) string {
+	return "account:" + strings.ToLower(strings.TrimSpace(email))
+}
+
+func ipKey(ip string) string {
+	return "ip:" + ip
+}
@@ -0,0 +1,132 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/sotaheavymetal21/rabbit-cart/backend/internal/domain/entity"
+	"github.com/sotaheavymetal21/rabbit-cart/backend/internal/infrastructure/throttle"
+	"github.com/sotaheavymetal21/rabbit-cart/backend/internal/usecase"
+)
+
+// lockoutRecorder はロックアウトの監査記録を保持する LoginLockoutRepository です
+type lockoutRecorder struct {
+	lockouts []*entity.LoginLockout
+}
+
+func (r *lockoutRecorder) Create(ctx context.Context, lockout *entity.LoginLockout) error {
+	r.lockouts = append(r.lockouts, lockout)
+	return nil
+}
+
+var testLoginGuardPolicy = usecase.LoginGuardPolicy{
+	Window:                  15 * time.Minute,
+	DelayAfter:              3,
+	BaseDelay:               time.Second,
+	MaxDelay:                4 * time.Second,
+	AccountLockoutThreshold: 6,
+	IPLockoutThreshold:      10,
+	LockoutDuration:         15 * time.Minute,
+}
+
+// retryAfter は err が *ThrottledError の場合に待ち時間を返します (それ以外は 0)
+func retryAfter(t *testing.T, err error) time.Duration {
+	t.Helper()
+	if err == nil {
+		return 0
+	}
+	var throttled *usecase.ThrottledError
+	if !errors.As(err, &throttled) {
+		t.Fatalf("err = %v, want ThrottledError", err)
+	}
+	return throttled.RetryAfter
+}
+
+func TestLoginGuardProgressiveDelayAndLockout(t *testing.T) {
+	lockouts := &lockoutRecorder{}
+	guard := usecase.NewLoginGuard(throttle.NewMemoryAttemptStore(), lockouts, testLoginGuardPolicy)
+	ctx := context.Background()
+	const email, ip = "victim@example.com", "198.51.100.7"
+
+	// 3回目の失敗から待ち時間が倍々に伸び、6回目でロックアウトされる
+	want := []time.Duration{0, 0, time.Second, 2 * time.Second, 4 * time.Second, 15 * time.Minute, 15 * time.Minute}
+	for i, w := range want {
+		if got := retryAfter(t, guard.RecordFailure(ctx, email, ip)); got != w {
+			t.Errorf("%d 回目の失敗: 待ち時間 = %v, want %v", i+1, got, w)
+		}
+	}
+
+	// ロック中は Check が Retry-After の秒数を返す
+	var throttled *usecase.ThrottledError
+	if err := guard.Check(ctx, "Victim@Example.com ", ip); !errors.As(err, &throttled) {
+		t.Fatalf("Check = %v, want ThrottledError", err)
+	}
+	if secs := throttled.RetryAfterSeconds(); secs != 900 {
+		t.Errorf("RetryAfterSeconds = %d, want 900", secs)
+	}
+
+	// 監査記録はロックアウトした時の1件だけ残る
+	if len(lockouts.lockouts) != 1 {
+		t.Fatalf("監査記録 = %d 件, want 1 件", len(lockouts.lockouts))
+	}
+	got := lockouts.lockouts[0]
+	if got.Scope != entity.LoginLockoutScopeAccount || got.Email != email || got.IPAddress != ip || got.Failures != 6 {
+		t.Errorf("監査記録 = %+v, want scope=account failures=6", got)
+	}
+	if until := time.Until(got.LockedUntil); until < 14*time.Minute || until > 15*time.Minute {
+		t.Errorf("LockedUntil までの時間 = %v, want 約 15 分", until)
+	}
+
+	// 別のアカウントは同じ IP からでもロックされていない
+	if err := guard.Check(ctx, "other@example.com", ip); err != nil {
+		t.Errorf("別のアカウントの Check = %v", err)
+	}
+}
+
+func TestLoginGuardSuccessResetsAccountFailures(t *testing.T) {
+	guard := usecase.NewLoginGuard(throttle.NewMemoryAttemptStore(), &lockoutRecorder{}, testLoginGuardPolicy)
+	ctx := context.Background()
+	const email, ip = "user@example.com", "198.51.100.7"
+
+	for range 2 {
+		if err := guard.RecordFailure(ctx, email, ip); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := guard.RecordSuccess(ctx, email, ip); err != nil {
+		t.Fatal(err)
+	}
+	// 成功で数え直すため、次の失敗では待ち時間が課されない
+	if err := guard.RecordFailure(ctx, email, ip); err != nil {
+		t.Errorf("成功後の RecordFailure = %v, want nil", err)
+	}
+}
+
+func TestLoginGuardIPLockout(t *testing.T) {
+	lockouts := &lockoutRecorder{}
+	guard := usecase.NewLoginGuard(throttle.NewMemoryAttemptStore(), lockouts, testLoginGuardPolicy)
+	ctx := context.Background()
+	const ip = "203.0.113.9"
+
+	// 同じ IP から毎回別のアカウントを狙うと IP 単位でロックアウトされる
+	emails := []string{"a@example.com", "b@example.com", "c@example.com", "d@example.com", "e@example.com"}
+	var err error
+	for i := range testLoginGuardPolicy.IPLockoutThreshold {
+		err = guard.RecordFailure(ctx, emails[i%len(emails)], ip)
+	}
+	if got := retryAfter(t, err); got != 15*time.Minute {
+		t.Errorf("閾値に達した失敗の待ち時間 = %v, want 15m", got)
+	}
+	if retryAfter(t, guard.Check(ctx, "new@example.com", ip)) == 0 {
+		t.Error("ロックアウトされた IP からのログインが許可されました")
+	}
+	if err := guard.Check(ctx, "new@example.com", "203.0.113.10"); err != nil {
+		t.Errorf("別の IP の Check = %v", err)
+	}
+
+	if len(lockouts.lockouts) != 1 || lockouts.lockouts[0].Scope != entity.LoginLockoutScopeIP || lockouts.lockouts[0].Failures != 10 {
+		t.Errorf("監査記録 = %+v, want scope=ip failures=10 の1件", lockouts.lockouts)
+	}
+}
@@ -3,21 +3,71 @@ package usecase
 import (
 	"context"
 	"errors"
+	"fmt"
+	"log"
+	"slices"
+	"time"
 
 	"github.com/sotaheavymetal21/rabbit-cart/backend/internal/domain/entity"
 	"github.com/sotaheavymetal21/rabbit-cart/backend/internal/domain/repository"
 )
 
+var (
+	// ErrOrderNotFound は注文が存在しない場合のエラーです
+	ErrOrderNotFound = entity.NewNotFoundError("order_not_found", "注文が見つかりません")
+	// ErrOrderForbidden は他のユーザーの注文を操作しようとした場合のエラーです
+	ErrOrderForbidden = entity.NewForbiddenError("order_forbidden", "この注文を操作する権限がありません")
+	// ErrEmailNotVerified はメールアドレス未確認のユーザーが注文しようとした場合のエラーです
+	ErrEmailNotVerified = entity.NewForbiddenError("email_not_verified", "注文するにはメールアドレスの確認を完了してください")
+	// ErrEmptyOrder は注文商品が1つも指定されていない場合のエラーです
+	ErrEmptyOrder = entity.NewFieldError("empty_order", "items", "注文商品が含まれていません")
+	// ErrPaymentNotAuthorized は決済の与信が完了していない注文を発送しようとした場合のエラーです
+	ErrPaymentNotAuthorized = entity.NewConflictError("payment_not_authorized", "決済の与信が完了していない注文は発送できません")
+	// ErrPaymentNotFound は決済サービスから通知された決済の記録が無い場合のエラーです
+	ErrPaymentNotFound = entity.NewNotFoundError("payment_not_found", "決済が見つかりません")
+	// ErrPaymentStatusConflict は決済サービスからの通知が決済の現在の状態と矛盾する場合のエラーです
+	ErrPaymentStatusConflict = entity.NewConflictError("payment_status_conflict", "決済の状態が通知の内容と矛盾しています")
+	// ErrInvalidCoupon は存在しないクーポンコードが指定された場合のエラーです
+	ErrInvalidCoupon = entity.NewFieldError("invalid_coupon", "coupon_code", "クーポンコードが正しくありません")
+	// ErrCouponNotAvailable は有効期間外または無効にされたクーポンが指定された場合のエラーです
+	ErrCouponNotAvailable = entity.NewFieldError("coupon_not_available", "coupon_code", "このクーポンは現在利用できません")
+	// ErrCouponNotApplicable はクーポンの対象商品が注文に含まれていない場合のエラーです
+	ErrCouponNotApplicable = entity.NewFieldError("coupon_not_applicable", "coupon_code", "クーポンの対象となる商品が含まれていません")
+)
+
+// paymentDeclinedReason は与信が拒否された注文をキャンセルする際に履歴に記録する理由です
+const paymentDeclinedReason = "決済が承認されませんでした"
+
+// captureFailedReason は売上を確定できなかった注文を発送前のステータスに戻す際に履歴に記録する理由です
+const captureFailedReason = "売上を確定できなかったため発送済みを取り消しました"
+
 // OrderUseCase は注文に関するビジネスロジックを定義するインターフェースです
 type OrderUseCase interface {
 	GetOrdersByUserID(ctx context.Context, userID string) ([]*entity.Order, error)
 	GetOrderByID(ctx context.Context, id string) (*entity.Order, error)
 	CreateOrder(ctx context.Context, userID string, input CreateOrderInput) (*entity.Order, error)
+	// TransitionStatus は注文ステータスを to へ遷移させ、actorID を変更者として履歴に記録します
+	TransitionStatus(ctx context.Context, orderID, actorID string, to entity.OrderStatus, reason string) (*entity.Order, error)
+	// CancelOrder は注文者本人による注文のキャンセルを行います（在庫は自動的に戻されます）
+	CancelOrder(ctx context.Context, orderID, userID, reason string) (*entity.Order, error)
+	// CompleteOrder は注文者本人による受け取り完了を記録します
+	CompleteOrder(ctx context.Context, orderID, userID string) (*entity.Order, error)
+	// ApplyPaymentEvent は決済サービスから通知された与信のイベントを決済と注文ステータスに反映します
+	// 反映済みのイベントを再度渡した場合は何もしません。返金のイベントは RefundUseCase.ApplyRefundEvent で反映します
+	ApplyPaymentEvent(ctx context.Context, event *entity.PaymentEvent) error
 }
 
 type CreateOrderInput struct {
-	Address string            `json:"address"`
-	Items   []CreateOrderItem `json:"items"`
+	// 配送先は AddressID (住所録の住所)、Address (直接入力) の順に使い、どちらも無い場合は既定の配送先を使います
+	AddressID string            `json:"address_id"`
+	Address   *entity.Address   `json:"address"`
+	Items     []CreateOrderItem `json:"items"`
+	// FromCart が true の場合は Items を無視し、サーバーに保存されたカートの内容で注文します
+	FromCart bool `json:"from_cart"`
+	// PaymentMethod はクライアントが決済サービスから取得した支払い方法のトークンです
+	PaymentMethod string `json:"payment_method"`
+	// CouponCode は適用するクーポンのコードです (省略可、大文字・小文字は区別しません)
+	CouponCode string `json:"coupon_code"`
 }
 
 type CreateOrderItem struct {
@@ -25,16 +75,36 @@ type CreateOrderItem struct {
 	Quantity  int    `json:"quantity"`
 }
 
+// OrderPolicy は注文を受け付ける条件です
+type OrderPolicy struct {
+	RequireVerifiedEmail bool // true の場合、メールアドレス未確認のユーザーは注文できません
+}
+
 type orderUseCase struct {
-	orderRepo   repository.OrderRepository
-	productRepo repository.ProductRepository
+	orderRepo     repository.OrderRepository
+	productRepo   repository.ProductRepository
+	cartRepo      repository.CartRepository
+	userRepo      repository.UserRepository
+	addressRepo   repository.AddressRepository
+	paymentRepo   repository.PaymentRepository
+	promotionRepo repository.PromotionRepository
+	gateway       repository.PaymentGateway
+	policy        OrderPolicy
 }
 
 // NewOrderUseCase は OrderUseCase の実装を生成します
-func NewOrderUseCase(orderRepo repository.OrderRepository, productRepo repository.ProductRepository) OrderUseCase {
+// 注文の作成時に gateway で代金の与信を行い、発送時に売上を確定、キャンセル時に与信を取り消します
+func NewOrderUseCase(orderRepo repository.OrderRepository, productRepo repository.ProductRepository, cartRepo repository.CartRepository, userRepo repository.UserRepository, addressRepo repository.AddressRepository, paymentRepo repository.PaymentRepository, promotionRepo repository.PromotionRepository, gateway repository.PaymentGateway, policy OrderPolicy) OrderUseCase {
 	return &orderUseCase{
-		orderRepo:   orderRepo,
-		productRepo: productRepo,
+		orderRepo:     orderRepo,
+		productRepo:   productRepo,
+		cartRepo:      cartRepo,
+		userRepo:      userRepo,
+		addressRepo:   addressRepo,
+		paymentRepo:   paymentRepo,
+		promotionRepo: promotionRepo,
+		gateway:       gateway,
+		policy:        policy,
 	}
 }
 
@@ -43,53 +113,492 @@ func (u *orderUseCase) GetOrdersByUserID(ctx context.Context, userID string) ([]
 }
 
 func (u *orderUseCase) GetOrderByID(ctx context.Context, id string) (*entity.Order, error) {
-	return u.orderRepo.FindByID(ctx, id)
+	return u.findOrder(ctx, id)
 }
 
 func (u *orderUseCase) CreateOrder(ctx context.Context, userID string, input CreateOrderInput) (*entity.Order, error) {
+	if u.policy.RequireVerifiedEmail {
+		user, err := u.userRepo.FindByID(ctx, userID)
+		if err != nil {
+			return nil, err
+		}
+		if !user.IsVerified() {
+			return nil, ErrEmailNotVerified
+		}
+	}
+
+	address, err := u.resolveAddress(ctx, userID, input)
+	if err != nil {
+		return nil, err
+	}
+
+	var cart *entity.Cart
+	if input.FromCart {
+		var err error
+		cart, err = u.cartRepo.FindOrCreateByUserID(ctx, userID)
+		if err != nil {
+			return nil, err
+		}
+		input.Items = nil
+		for _, item := range cart.Items {
+			input.Items = append(input.Items, CreateOrderItem{ProductID: item.ProductID, Quantity: item.Quantity})
+		}
+	}
+
 	if len(input.Items) == 0 {
-		return nil, errors.New("注文商品が含まれていません")
+		return nil, ErrEmptyOrder
 	}
 
-	var totalAmount int
+	// 同じ商品が複数行で指定された場合は数量を合算する
+	quantities := make(map[string]int)
+	var productIDs []string
+	for _, item := range input.Items {
+		if item.Quantity <= 0 {
+			return nil, ErrInvalidQuantity
+		}
+		if _, ok := quantities[item.ProductID]; !ok {
+			productIDs = append(productIDs, item.ProductID)
+		}
+		quantities[item.ProductID] += item.Quantity
+	}
+
+	var subtotal int
 	var orderItems []entity.OrderItem
+	var products []*entity.Product // orderItems と同じ順の商品 (クーポンの対象の判定に使う)
+	var shortages []entity.StockShortage
 
 	// 各商品の価格を取得して注文明細を作成
-	for _, item := range input.Items {
-		product, err := u.productRepo.FindByID(ctx, item.ProductID)
+	for _, productID := range productIDs {
+		quantity := quantities[productID]
+		product, err := u.productRepo.FindByID(ctx, productID)
 		if err != nil {
-			return nil, err // 商品が存在しない場合など
+			if errors.Is(err, repository.ErrNotFound) {
+				return nil, ErrProductNotFound
+			}
+			return nil, err
 		}
-		if product.Stock < item.Quantity {
-			return nil, errors.New("在庫不足の商品があります: " + product.Name)
+		// ここでの在庫チェックは事前確認のみ。確定的な引き当てはリポジトリのトランザクション内で行う
+		if product.Stock < quantity {
+			shortages = append(shortages, entity.StockShortage{
+				ProductID: product.ID,
+				Name:      product.Name,
+				Requested: quantity,
+				Available: product.Stock,
+			})
+			continue
 		}
 
 		price := product.Price
-		subtotal := price * item.Quantity
-		totalAmount += subtotal
+		subtotal += price * quantity
 
 		orderItems = append(orderItems, entity.OrderItem{
-			ProductID: item.ProductID,
-			Quantity:  item.Quantity,
+			ProductID: productID,
+			Quantity:  quantity,
 			Price:     price,
 		})
+		products = append(products, product)
+	}
+	if len(shortages) > 0 {
+		return nil, &entity.InsufficientStockError{Shortages: shortages}
 	}
 
-	// Address validation/serialization if needed. Input is already string (JSON from frontend).
-	// But Address in DB is just string for now.
-	// We might want to clear whitespace or validate it's valid JSON if we cared.
+	var discounts []entity.OrderDiscount
+	var discountAmount int
+	if input.CouponCode != "" {
+		discount, err := u.applyCoupon(ctx, input.CouponCode, orderItems, products)
+		if err != nil {
+			return nil, err
+		}
+		discounts = append(discounts, *discount)
+		discountAmount += discount.Amount
+	}
 
 	order := &entity.Order{
-		UserID:      userID,
-		TotalAmount: totalAmount,
-		Status:      entity.OrderStatusPending,
-		Address:     input.Address,
-		OrderItems:  orderItems,
+		UserID:         &userID,
+		SubtotalAmount: subtotal,
+		DiscountAmount: discountAmount,
+		TotalAmount:    subtotal - discountAmount,
+		Status:         entity.OrderStatusPending,
+		Address:        *address,
+		OrderItems:     orderItems,
+		Discounts:      discounts,
 	}
 
 	if err := u.orderRepo.Create(ctx, order); err != nil {
 		return nil, err
 	}
 
+	// 割引で支払い金額が 0 円になった注文は決済せずに支払い済みにする
+	if order.TotalAmount == 0 {
+		u.clearCart(ctx, order, cart)
+		return u.transition(ctx, order, userID, entity.OrderStatusPaid, "")
+	}
+
+	payment, err := u.authorizePayment(ctx, order, input.PaymentMethod)
+	if err != nil {
+		// 与信できなかった注文はキャンセルして引き当てた在庫とクーポンの利用回数を戻す
+		if _, cancelErr := u.transition(ctx, order, userID, entity.OrderStatusCancelled, paymentDeclinedReason); cancelErr != nil {
+			log.Printf("決済に失敗した注文 %s のキャンセルに失敗しました: %v", order.ID, cancelErr)
+		}
+		return nil, err
+	}
+
+	u.clearCart(ctx, order, cart)
+
+	// 本人認証が必要な場合は認証が完了するまで支払い待ちのままにする
+	if payment.Status == entity.PaymentStatusAuthorized {
+		return u.transition(ctx, order, userID, entity.OrderStatusPaid, "")
+	}
+	return u.orderRepo.FindByID(ctx, order.ID)
+}
+
+// clearCart はカートから注文した場合にカートを空にします
+// 注文は確定済みのため、カートのクリアに失敗しても注文自体は成功として扱う
+func (u *orderUseCase) clearCart(ctx context.Context, order *entity.Order, cart *entity.Cart) {
+	if cart == nil {
+		return
+	}
+	if err := u.cartRepo.Clear(ctx, cart.ID); err != nil {
+		log.Printf("注文 %s のカートのクリアに失敗しました: %v", order.ID, err)
+	}
+}
+
+func (u *orderUseCase) TransitionStatus(ctx context.Context, orderID, actorID string, to entity.OrderStatus, reason string) (*entity.Order, error) {
+	order, err := u.findOrder(ctx, orderID)
+	if err != nil {
+		return nil, err
+	}
+	return u.transition(ctx, order, actorID, to, reason)
+}
+
+func (u *orderUseCase) CancelOrder(ctx context.Context, orderID, userID, reason string) (*entity.Order, error) {
+	order, err := u.findOwnOrder(ctx, orderID, userID)
+	if err != nil {
+		return nil, err
+	}
+	return u.transition(ctx, order, userID, entity.OrderStatusCancelled, reason)
+}
+
+func (u *orderUseCase) CompleteOrder(ctx context.Context, orderID, userID string) (*entity.Order, error) {
+	order, err := u.findOwnOrder(ctx, orderID, userID)
+	if err != nil {
+		return nil, err
+	}
+	return u.transition(ctx, order, userID, entity.OrderStatusCompleted, "")
+}
+
+// transition は遷移表に従ってステータスを変更し、更新後の注文を返します
+// キャンセルへの遷移では引き当て済みの在庫を戻します
+// 決済サービスの呼び出し (売上確定・与信の取り消し) は条件付きの更新でステータスの変更を確定してから行うため、
+// 同じ注文を同時に遷移させても決済サービスを呼び出すのは変更に成功した1件だけです
+func (u *orderUseCase) transition(ctx context.Context, order *entity.Order, actorID string, to entity.OrderStatus, reason string) (*entity.Order, error) {
+	if !order.Status.CanTransitionTo(to) {
+		return nil, &entity.InvalidStatusTransitionError{From: order.Status, To: to}
+	}
+
+	history := &entity.OrderStatusHistory{
+		OrderID:    order.ID,
+		FromStatus: order.Status,
+		ToStatus:   to,
+		ChangedBy:  actorID,
+		Reason:     reason,
+	}
+	if err := u.orderRepo.UpdateStatus(ctx, history, to == entity.OrderStatusCancelled); err != nil {
+		return nil, err
+	}
+
+	switch to {
+	case entity.OrderStatusShipped:
+		if err := u.capturePayment(ctx, order); err != nil {
+			// 売上を確定できなかった場合はステータスを戻し、再度発送済みにできるようにする
+			u.revertTransition(ctx, history, captureFailedReason)
+			return nil, err
+		}
+	case entity.OrderStatusCancelled:
+		u.voidPayment(ctx, order)
+	}
+
+	return u.orderRepo.FindByID(ctx, order.ID)
+}
+
+// revertTransition は決済サービスの呼び出しに失敗した遷移を元のステータスに戻します (変更履歴にも記録します)
+// 戻せなかった場合は記録のみ残します
+func (u *orderUseCase) revertTransition(ctx context.Context, history *entity.OrderStatusHistory, reason string) {
+	revert := &entity.OrderStatusHistory{
+		OrderID:    history.OrderID,
+		FromStatus: history.ToStatus,
+		ToStatus:   history.FromStatus,
+		ChangedBy:  entity.SystemActorID,
+		Reason:     reason,
+	}
+	// リクエストが中断されていても戻せるように、キャンセルされないコンテキストで更新する
+	if err := u.orderRepo.UpdateStatus(context.WithoutCancel(ctx), revert, false); err != nil {
+		log.Printf("注文 %s のステータスを %s に戻せませんでした: %v", history.OrderID, history.FromStatus, err)
+	}
+}
+
+func (u *orderUseCase) findOrder(ctx context.Context, orderID string) (*entity.Order, error) {
+	order, err := u.orderRepo.FindByID(ctx, orderID)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, ErrOrderNotFound
+		}
+		return nil, err
+	}
+	return order, nil
+}
+
+func (u *orderUseCase) findOwnOrder(ctx context.Context, orderID, userID string) (*entity.Order, error) {
+	order, err := u.findOrder(ctx, orderID)
+	if err != nil {
+		return nil, err
+	}
+	if !order.IsOwnedBy(userID) {
+		return nil, ErrOrderForbidden
+	}
 	return order, nil
 }
+
+// resolveAddress は注文の配送先を決定します
+// 住所録の住所はこの時点の内容を注文に保存するため、後から住所録を変更しても注文の配送先は変わりません
+func (u *orderUseCase) resolveAddress(ctx context.Context, userID string, input CreateOrderInput) (*entity.Address, error) {
+	switch {
+	case input.AddressID != "":
+		saved, err := u.addressRepo.FindByID(ctx, userID, input.AddressID)
+		if err != nil {
+			if errors.Is(err, repository.ErrNotFound) {
+				return nil, ErrAddressNotFound
+			}
+			return nil, err
+		}
+		return &saved.Address, nil
+	case input.Address != nil:
+		address := *input.Address
+		address.Normalize()
+		if err := address.Validate(); err != nil {
+			return nil, err
+		}
+		return &address, nil
+	default:
+		saved, err := u.addressRepo.FindDefault(ctx, userID)
+		if err != nil {
+			if errors.Is(err, repository.ErrNotFound) {
+				return nil, ErrAddressRequired
+			}
+			return nil, err
+		}
+		return &saved.Address, nil
+	}
+}
+
+// authorizePayment は注文の代金の与信をゲートウェイに依頼し、結果を決済として記録します
+// 与信が拒否された場合は entity.ErrPaymentDeclined、ゲートウェイと通信できない場合は entity.ErrPaymentUnavailable を返します
+func (u *orderUseCase) authorizePayment(ctx context.Context, order *entity.Order, paymentMethod string) (*entity.Payment, error) {
+	payment := &entity.Payment{
+		OrderID:  order.ID,
+		Provider: u.gateway.Name(),
+		Amount:   order.TotalAmount,
+		Currency: entity.DefaultCurrency,
+		Status:   entity.PaymentStatusPending,
+	}
+	if err := u.paymentRepo.Create(ctx, payment); err != nil {
+		return nil, err
+	}
+
+	result, err := u.gateway.Authorize(ctx, &entity.PaymentAuthorization{
+		OrderID:       order.ID,
+		Amount:        payment.Amount,
+		Currency:      payment.Currency,
+		PaymentMethod: paymentMethod,
+	})
+	if err != nil {
+		log.Printf("注文 %s の与信に失敗しました: %v", order.ID, err)
+		payment.Status = entity.PaymentStatusFailed
+		payment.FailureCode = "gateway_error"
+		if err := u.paymentRepo.Update(ctx, payment); err != nil {
+			log.Printf("決済 %s の記録に失敗しました: %v", payment.ID, err)
+		}
+		return nil, entity.ErrPaymentUnavailable
+	}
+
+	payment.ApplyResult(result, time.Now())
+	if err := u.paymentRepo.Update(ctx, payment); err != nil {
+		return nil, err
+	}
+	if payment.Status == entity.PaymentStatusFailed {
+		return nil, entity.ErrPaymentDeclined
+	}
+	return payment, nil
+}
+
+// capturePayment は発送する注文の与信済みの決済の売上を確定します
+// 決済の記録が無い注文 (決済の導入前の注文) と売上確定済みの注文はそのまま発送できます
+func (u *orderUseCase) capturePayment(ctx context.Context, order *entity.Order) error {
+	payment, err := u.paymentRepo.FindLatestByOrderID(ctx, order.ID)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil
+		}
+		return err
+	}
+	switch payment.Status {
+	case entity.PaymentStatusCaptured:
+		return nil
+	case entity.PaymentStatusAuthorized:
+	default:
+		return ErrPaymentNotAuthorized
+	}
+
+	result, err := u.gateway.Capture(ctx, payment.ProviderPaymentID, payment.Amount)
+	if err != nil {
+		log.Printf("注文 %s の売上確定に失敗しました: %v", order.ID, err)
+		return entity.ErrPaymentUnavailable
+	}
+	payment.ApplyResult(result, time.Now())
+	if err := u.paymentRepo.Update(ctx, payment); err != nil {
+		return err
+	}
+	if payment.Status != entity.PaymentStatusCaptured {
+		return entity.ErrPaymentDeclined
+	}
+	return nil
+}
+
+// voidPayment はキャンセルする注文の売上確定前の与信を取り消します
+// 取り消せなかった与信は決済サービス側で期限切れになるため、記録のみ残してキャンセルは続行します
+func (u *orderUseCase) voidPayment(ctx context.Context, order *entity.Order) {
+	payment, err := u.paymentRepo.FindLatestByOrderID(ctx, order.ID)
+	if err != nil {
+		if !errors.Is(err, repository.ErrNotFound) {
+			log.Printf("注文 %s の決済の取得に失敗しました: %v", order.ID, err)
+		}
+		return
+	}
+	if payment.Status != entity.PaymentStatusAuthorized && payment.Status != entity.PaymentStatusRequiresAction {
+		return
+	}
+
+	result, err := u.gateway.Void(ctx, payment.ProviderPaymentID)
+	if err != nil {
+		log.Printf("注文 %s の与信の取り消しに失敗しました: %v", order.ID, err)
+		return
+	}
+	payment.ApplyResult(result, time.Now())
+	if err := u.paymentRepo.Update(ctx, payment); err != nil {
+		log.Printf("決済 %s の記録に失敗しました: %v", payment.ID, err)
+	}
+}
+
+// ApplyPaymentEvent は決済サービスから通知されたイベントを決済と注文ステータスに反映します
+// 与信の承認で支払い待ちの注文を支払い済みに、与信の拒否で注文をキャンセル (在庫を戻す) にします
+func (u *orderUseCase) ApplyPaymentEvent(ctx context.Context, event *entity.PaymentEvent) error {
+	payment, err := u.paymentRepo.FindByProviderPaymentID(ctx, event.Provider, event.ProviderPaymentID)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return ErrPaymentNotFound
+		}
+		return err
+	}
+	order, err := u.findOrder(ctx, payment.OrderID)
+	if err != nil {
+		return err
+	}
+
+	var (
+		from    []entity.PaymentStatus // イベントを反映できる決済の状態
+		to      entity.PaymentStatus
+		orderTo entity.OrderStatus
+		reason  string
+	)
+	switch event.Type {
+	case entity.PaymentEventAuthorized:
+		from, to, orderTo = []entity.PaymentStatus{entity.PaymentStatusPending, entity.PaymentStatusRequiresAction}, entity.PaymentStatusAuthorized, entity.OrderStatusPaid
+	case entity.PaymentEventFailed:
+		from, to, orderTo = []entity.PaymentStatus{entity.PaymentStatusPending, entity.PaymentStatusRequiresAction}, entity.PaymentStatusFailed, entity.OrderStatusCancelled
+		reason = paymentDeclinedReason
+	default:
+		// 対応していない種類のイベントは受け取るだけで何もしない
+		log.Printf("対応していない決済イベントです: %s (event_id=%s)", event.Type, event.EventID)
+		return nil
+	}
+
+	if payment.Status != to {
+		if !slices.Contains(from, payment.Status) {
+			return ErrPaymentStatusConflict
+		}
+		payment.ApplyResult(&entity.PaymentResult{Status: to, FailureCode: event.FailureCode}, time.Now())
+		if err := u.paymentRepo.Update(ctx, payment); err != nil {
+			return err
+		}
+	}
+
+	// 反映済みの場合や、既に先の状態に進んでいる場合は注文ステータスを変更しない
+	if !order.Status.CanTransitionTo(orderTo) {
+		return nil
+	}
+	_, err = u.transition(ctx, order, entity.SystemActorID, orderTo, reason)
+	return err
+}
+
+// applyCoupon はクーポンを確認して割引額を計算し、割引額を対象の明細に按分します
+// 利用回数の上限は注文の保存時にも確認するため、ここでの確認は事前確認のみです
+func (u *orderUseCase) applyCoupon(ctx context.Context, code string, items []entity.OrderItem, products []*entity.Product) (*entity.OrderDiscount, error) {
+	promotion, err := u.promotionRepo.FindByCode(ctx, entity.NormalizePromotionCode(code))
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, ErrInvalidCoupon
+		}
+		return nil, err
+	}
+	if !promotion.IsAvailableAt(time.Now()) {
+		return nil, ErrCouponNotAvailable
+	}
+	if promotion.UsageLimit > 0 && promotion.UsedCount >= promotion.UsageLimit {
+		return nil, entity.ErrPromotionUsageLimitReached
+	}
+
+	var eligible []int // 対象の明細の添字
+	var eligibleSubtotal int
+	for i := range items {
+		if promotion.AppliesTo(products[i]) {
+			eligible = append(eligible, i)
+			eligibleSubtotal += items[i].Price * items[i].Quantity
+		}
+	}
+	if len(eligible) == 0 {
+		return nil, ErrCouponNotApplicable
+	}
+	if eligibleSubtotal < promotion.MinSpend {
+		return nil, couponMinSpendError(promotion.MinSpend)
+	}
+
+	// 小計の比率で按分し、端数は最後の明細に寄せる
+	amount := promotion.DiscountFor(eligibleSubtotal)
+	remaining := amount
+	for n, i := range eligible {
+		share := amount * items[i].Price * items[i].Quantity / eligibleSubtotal
+		if n == len(eligible)-1 {
+			share = remaining
+		}
+		items[i].DiscountAmount = share
+		remaining -= share
+	}
+
+	return &entity.OrderDiscount{
+		PromotionID: promotion.ID,
+		Code:        promotion.Code,
+		Name:        promotion.Name,
+		Amount:      amount,
+	}, nil
+}
+
+// couponMinSpendError は対象商品の小計がクーポンの利用条件に満たない場合のエラーを返します
+func couponMinSpendError(minSpend int) error {
+	message := fmt.Sprintf("このクーポンは対象商品の合計が %d 円以上の場合に利用できます", minSpend)
+	return &entity.ValidationError{
+		Code:    "coupon_min_spend",
+		Message: message,
+		Fields:  []entity.FieldError{{Field: "coupon_code", Code: "coupon_min_spend", Message: message, Args: []any{minSpend}}},
+		Args:    []any{minSpend},
+	}
+}
@@ -0,0 +1,301 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/sotaheavymetal21/rabbit-cart/backend/internal/domain/entity"
+	domainrepository "github.com/sotaheavymetal21/rabbit-cart/backend/internal/domain/repository"
+	"github.com/sotaheavymetal21/rabbit-cart/backend/internal/infrastructure/database/dbtest"
+	"github.com/sotaheavymetal21/rabbit-cart/backend/internal/infrastructure/payment"
+	"github.com/sotaheavymetal21/rabbit-cart/backend/internal/infrastructure/repository"
+	"github.com/sotaheavymetal21/rabbit-cart/backend/internal/usecase"
+	"gorm.io/gorm"
+)
+
+// failingCaptureGateway は売上確定の呼び出し回数を数え、fail が true の場合は売上確定に失敗する決済ゲートウェイです
+type failingCaptureGateway struct {
+	domainrepository.PaymentGateway
+	fail     bool
+	captures atomic.Int32
+}
+
+func (g *failingCaptureGateway) Capture(ctx context.Context, providerPaymentID string, amount int) (*entity.PaymentResult, error) {
+	g.captures.Add(1)
+	if g.fail {
+		return nil, errors.New("フェイク決済ゲートウェイ: 通信に失敗しました")
+	}
+	return g.PaymentGateway.Capture(ctx, providerPaymentID, amount)
+}
+
+type orderFixture struct {
+	db          *gorm.DB
+	gateway     domainrepository.PaymentGateway
+	orders      usecase.OrderUseCase
+	orderRepo   domainrepository.OrderRepository
+	productRepo domainrepository.ProductRepository
+	user        *entity.User
+}
+
+func newOrderFixture(t *testing.T, gateway domainrepository.PaymentGateway) *orderFixture {
+	t.Helper()
+	db := dbtest.New(t)
+	productRepo := repository.NewProductRepository(db)
+	userRepo := repository.NewUserRepository(db)
+	orderRepo := repository.NewOrderRepository(db)
+	if gateway == nil {
+		fake, err := payment.NewFakeGateway(payment.FakeScenarioApprove, "")
+		if err != nil {
+			t.Fatal(err)
+		}
+		gateway = fake
+	}
+	orders := usecase.NewOrderUseCase(
+		orderRepo,
+		productRepo,
+		repository.NewCartRepository(db),
+		userRepo,
+		repository.NewAddressRepository(db),
+		repository.NewPaymentRepository(db),
+		repository.NewPromotionRepository(db),
+		gateway,
+		usecase.OrderPolicy{},
+	)
+
+	user := &entity.User{Email: "buyer@example.com", Role: entity.UserRoleCustomer}
+	if err := userRepo.Create(context.Background(), user); err != nil {
+		t.Fatal(err)
+	}
+	return &orderFixture{db: db, gateway: gateway, orders: orders, orderRepo: orderRepo, productRepo: productRepo, user: user}
+}
+
+// orderInput は商品を1個注文する入力を返します
+func orderInput(product *entity.Product) usecase.CreateOrderInput {
+	return usecase.CreateOrderInput{
+		Address: &entity.Address{
+			Name:       "山田 太郎",
+			PostalCode: "100-0001",
+			Prefecture: "東京都",
+			City:       "千代田区",
+			Line1:      "千代田1-1",
+			Phone:      "03-1234-5678",
+		},
+		Items: []usecase.CreateOrderItem{{ProductID: product.ID, Quantity: 1}},
+	}
+}
+
+// paidOrder は支払い済みの注文を作成します
+func (f *orderFixture) paidOrder(t *testing.T) *entity.Order {
+	t.Helper()
+	ctx := context.Background()
+	product := &entity.Product{Name: "限定スニーカー", Price: 12000, Stock: 10}
+	if err := f.productRepo.Create(ctx, product); err != nil {
+		t.Fatal(err)
+	}
+	order, err := f.orders.CreateOrder(ctx, f.user.ID, orderInput(product))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if order.Status != entity.OrderStatusPaid {
+		t.Fatalf("注文ステータス = %s, want %s", order.Status, entity.OrderStatusPaid)
+	}
+	return order
+}
+
+// 在庫 1 の商品に同時に注文が入っても、成功するのは 1 件だけで在庫がマイナスにならないことを確認する
+func TestCreateOrderConcurrentLastItem(t *testing.T) {
+	f := newOrderFixture(t, nil)
+	ctx := context.Background()
+
+	product := &entity.Product{Name: "限定スニーカー", Price: 12000, Stock: 1}
+	if err := f.productRepo.Create(ctx, product); err != nil {
+		t.Fatal(err)
+	}
+
+	const n = 20
+	input := orderInput(product)
+
+	var wg sync.WaitGroup
+	start := make(chan struct{})
+	errs := make([]error, n)
+	for i := range n {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			<-start
+			_, errs[i] = f.orders.CreateOrder(ctx, f.user.ID, input)
+		}()
+	}
+	close(start)
+	wg.Wait()
+
+	succeeded := 0
+	for i, err := range errs {
+		if err == nil {
+			succeeded++
+			continue
+		}
+		var stockErr *entity.InsufficientStockError
+		if !errors.As(err, &stockErr) {
+			t.Errorf("注文 %d: InsufficientStockError 以外のエラーです: %v", i, err)
+		}
+	}
+	if succeeded != 1 {
+		t.Errorf("成功した注文 = %d, want 1", succeeded)
+	}
+
+	got, err := f.productRepo.FindByID(ctx, product.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Stock != 0 {
+		t.Errorf("在庫 = %d, want 0", got.Stock)
+	}
+}
+
+// 同じ注文を同時に発送済みにしても、売上を確定するのはステータスの変更に成功した 1 件だけであることを確認する
+func TestTransitionStatusCapturesOnce(t *testing.T) {
+	fake, err := payment.NewFakeGateway(payment.FakeScenarioApprove, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	gateway := &failingCaptureGateway{PaymentGateway: fake}
+	f := newOrderFixture(t, gateway)
+	order := f.paidOrder(t)
+
+	const n = 10
+	var wg sync.WaitGroup
+	start := make(chan struct{})
+	errs := make([]error, n)
+	for i := range n {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			<-start
+			_, errs[i] = f.orders.TransitionStatus(context.Background(), order.ID, f.user.ID, entity.OrderStatusShipped, "")
+		}()
+	}
+	close(start)
+	wg.Wait()
+
+	succeeded := 0
+	for _, err := range errs {
+		if err == nil {
+			succeeded++
+		}
+	}
+	if succeeded != 1 {
+		t.Errorf("成功した遷移 = %d, want 1", succeeded)
+	}
+	if got := gateway.captures.Load(); got != 1 {
+		t.Errorf("売上確定の呼び出し = %d 回, want 1 回", got)
+	}
+}
+
+// 売上を確定できなかった場合は発送済みへの変更を取り消し、再度発送済みにできることを確認する
+func TestTransitionStatusRevertsWhenCaptureFails(t *testing.T) {
+	fake, err := payment.NewFakeGateway(payment.FakeScenarioApprove, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	gateway := &failingCaptureGateway{PaymentGateway: fake, fail: true}
+	f := newOrderFixture(t, gateway)
+	order := f.paidOrder(t)
+	ctx := context.Background()
+
+	if _, err := f.orders.TransitionStatus(ctx, order.ID, f.user.ID, entity.OrderStatusShipped, ""); !errors.Is(err, entity.ErrPaymentUnavailable) {
+		t.Fatalf("TransitionStatus = %v, want ErrPaymentUnavailable", err)
+	}
+	got, err := f.orderRepo.FindByID(ctx, order.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Status != entity.OrderStatusPaid {
+		t.Errorf("注文ステータス = %s, want %s", got.Status, entity.OrderStatusPaid)
+	}
+	// 取り消しも変更履歴に残る
+	last := got.StatusHistory[len(got.StatusHistory)-1]
+	if last.FromStatus != entity.OrderStatusShipped || last.ToStatus != entity.OrderStatusPaid {
+		t.Errorf("最後の変更履歴 = %s → %s, want shipped → paid", last.FromStatus, last.ToStatus)
+	}
+
+	gateway.fail = false
+	shipped, err := f.orders.TransitionStatus(ctx, order.ID, f.user.ID, entity.OrderStatusShipped, "")
+	if err != nil {
+		t.Fatalf("再度の TransitionStatus: %v", err)
+	}
+	if shipped.Status != entity.OrderStatusShipped {
+		t.Errorf("注文ステータス = %s, want %s", shipped.Status, entity.OrderStatusShipped)
+	}
+}
+
+// クーポンのユーザーごとの利用回数の上限は、キャンセルされた注文を除いて数えることを確認する
+func TestCreateOrderCouponPerUserLimit(t *testing.T) {
+	f := newOrderFixture(t, nil)
+	ctx := context.Background()
+	promotions := repository.NewPromotionRepository(f.db)
+
+	promotion := &entity.Promotion{
+		Code:          "WELCOME500",
+		Name:          "初回限定 500 円引き",
+		DiscountType:  entity.DiscountTypeFixedAmount,
+		DiscountValue: 500,
+		Scope:         entity.PromotionScopeAll,
+		Targets:       []string{},
+		UsageLimit:    3,
+		PerUserLimit:  1,
+		Active:        true,
+	}
+	if err := promotions.Create(ctx, promotion); err != nil {
+		t.Fatal(err)
+	}
+	product := &entity.Product{Name: "限定スニーカー", Price: 12000, Stock: 10}
+	if err := f.productRepo.Create(ctx, product); err != nil {
+		t.Fatal(err)
+	}
+	order := func(userID string) (*entity.Order, error) {
+		input := orderInput(product)
+		input.CouponCode = "welcome500"
+		return f.orders.CreateOrder(ctx, userID, input)
+	}
+
+	first, err := order(f.user.ID)
+	if err != nil {
+		t.Fatalf("1 回目の注文: %v", err)
+	}
+	if first.DiscountAmount != 500 || first.TotalAmount != 11500 {
+		t.Errorf("割引額・支払い金額 = %d・%d, want 500・11500", first.DiscountAmount, first.TotalAmount)
+	}
+	if _, err := order(f.user.ID); !errors.Is(err, entity.ErrPromotionPerUserLimitReached) {
+		t.Fatalf("2 回目の注文 = %v, want %v", err, entity.ErrPromotionPerUserLimitReached)
+	}
+
+	// 他のユーザーは利用できる
+	other := &entity.User{Email: "other@example.com", Role: entity.UserRoleCustomer}
+	if err := repository.NewUserRepository(f.db).Create(ctx, other); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := order(other.ID); err != nil {
+		t.Fatalf("他のユーザーの注文: %v", err)
+	}
+
+	// キャンセルした注文の利用は数えない
+	if _, err := f.orders.TransitionStatus(ctx, first.ID, f.user.ID, entity.OrderStatusCancelled, ""); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := order(f.user.ID); err != nil {
+		t.Fatalf("キャンセル後の注文: %v", err)
+	}
+
+	got, err := promotions.FindByID(ctx, promotion.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 拒否された注文とキャンセルされた注文は利用回数に含まない
+	if got.UsedCount != 2 {
+		t.Errorf("利用回数 = %d, want 2", got.UsedCount)
+	}
+}
@@ -0,0 +1,176 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"log"
+	"time"
+
+	"github.com/sotaheavymetal21/rabbit-cart/backend/internal/domain/entity"
+	"github.com/sotaheavymetal21/rabbit-cart/backend/internal/domain/repository"
+)
+
+// webhookProcessingTimeout は処理を開始したイベントが処理中とみなされる期間です
+// この期間を過ぎても処理済みにもデッドレターにもなっていないイベントは、処理中にプロセスが停止したものとして再送時に処理し直します
+const webhookProcessingTimeout = time.Minute
+
+var (
+	// ErrWebhookEventInProgress は同じイベントを処理中に再送された場合のエラーです (決済サービスに後で再送させる)
+	ErrWebhookEventInProgress = entity.NewConflictError("webhook_event_in_progress", "このイベントは処理中です。しばらくしてから再送してください")
+	// ErrDeadLetterNotFound はデッドレターが存在しない場合のエラーです
+	ErrDeadLetterNotFound = entity.NewNotFoundError("dead_letter_not_found", "デッドレターが見つかりません")
+	// ErrDeadLetterResolved は再処理に成功済みのデッドレターを再処理しようとした場合のエラーです
+	ErrDeadLetterResolved = entity.NewConflictError("dead_letter_resolved", "このイベントは再処理済みです")
+)
+
+// PaymentWebhookUseCase は決済サービスからの Webhook の受信に関するビジネスロジックを定義するインターフェースです
+type PaymentWebhookUseCase interface {
+	// Receive は署名を検証した Webhook のイベントを、まだ処理していない場合に一度だけ処理します
+	// 処理に失敗したイベントはデッドレターに記録し、エラーは返しません (決済サービスからの再送では再処理しません)
+	// 処理中のイベントが再送された場合は ErrWebhookEventInProgress を返します
+	Receive(ctx context.Context, payload []byte, signature string) error
+	// ListDeadLetters は再処理に成功していないデッドレターを古い順に返します
+	ListDeadLetters(ctx context.Context) ([]*entity.PaymentDeadLetter, error)
+	// Redrive はデッドレターのイベントを再処理し、結果を記録したデッドレターを返します
+	// 再処理に失敗した場合もエラーは返さず、LastError に失敗の理由を記録します
+	// 同じイベントを処理中の場合は ErrWebhookEventInProgress を返します
+	Redrive(ctx context.Context, deadLetterID string) (*entity.PaymentDeadLetter, error)
+}
+
+type paymentWebhookUseCase struct {
+	eventRepo     repository.PaymentEventRepository
+	verifier      repository.PaymentWebhookVerifier
+	orderUseCase  OrderUseCase
+	refundUseCase RefundUseCase
+}
+
+// NewPaymentWebhookUseCase は PaymentWebhookUseCase の実装を生成します
+func NewPaymentWebhookUseCase(eventRepo repository.PaymentEventRepository, verifier repository.PaymentWebhookVerifier, orderUseCase OrderUseCase, refundUseCase RefundUseCase) PaymentWebhookUseCase {
+	return &paymentWebhookUseCase{eventRepo: eventRepo, verifier: verifier, orderUseCase: orderUseCase, refundUseCase: refundUseCase}
+}
+
+// Receive は Webhook のイベントを処理します
+// イベントを先に保存し、同じイベントの再送や同時の受信を一意制約で弾くことで二重に処理しないようにします
+// 保存後に処理を終えられなかったイベント (処理済みでもデッドレターでもないもの) は、再送を受け取った際に処理し直します
+func (u *paymentWebhookUseCase) Receive(ctx context.Context, payload []byte, signature string) error {
+	now := time.Now()
+	event, err := u.verifier.Verify(payload, signature, now)
+	if err != nil {
+		return err
+	}
+	event.ClaimedAt = now
+	if err := u.eventRepo.Create(ctx, event); err != nil {
+		if errors.Is(err, repository.ErrDuplicate) {
+			return u.receiveAgain(ctx, event, now)
+		}
+		return err
+	}
+	return u.handle(ctx, event)
+}
+
+// receiveAgain は保存済みのイベントが再送された場合に、処理を終えていなければ処理し直します
+func (u *paymentWebhookUseCase) receiveAgain(ctx context.Context, received *entity.PaymentEvent, now time.Time) error {
+	event, err := u.eventRepo.FindByEventID(ctx, received.Provider, received.EventID)
+	if err != nil {
+		return err
+	}
+	if event.ProcessedAt != nil {
+		return nil
+	}
+	// デッドレターに記録したイベントは管理者が再処理する
+	if _, err := u.eventRepo.FindDeadLetterByEventID(ctx, event.ID); err == nil {
+		return nil
+	} else if !errors.Is(err, repository.ErrNotFound) {
+		return err
+	}
+
+	if now.Sub(event.ClaimedAt) < webhookProcessingTimeout {
+		return ErrWebhookEventInProgress
+	}
+	claimed, err := u.eventRepo.Claim(ctx, event.ID, event.ClaimedAt, now)
+	if err != nil {
+		return err
+	}
+	if !claimed {
+		return ErrWebhookEventInProgress
+	}
+	event.ClaimedAt = now
+	return u.handle(ctx, event)
+}
+
+// handle はイベントを処理し、失敗した場合はデッドレターに記録します
+func (u *paymentWebhookUseCase) handle(ctx context.Context, event *entity.PaymentEvent) error {
+	if err := u.process(ctx, event); err != nil {
+		log.Printf("決済イベント %s (%s) の処理に失敗しました: %v", event.EventID, event.Type, err)
+		deadLetter := &entity.PaymentDeadLetter{
+			PaymentEventID: event.ID,
+			LastError:      err.Error(),
+			Attempts:       1,
+		}
+		if err := u.eventRepo.CreateDeadLetter(ctx, deadLetter); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (u *paymentWebhookUseCase) ListDeadLetters(ctx context.Context) ([]*entity.PaymentDeadLetter, error) {
+	return u.eventRepo.ListUnresolvedDeadLetters(ctx)
+}
+
+func (u *paymentWebhookUseCase) Redrive(ctx context.Context, deadLetterID string) (*entity.PaymentDeadLetter, error) {
+	deadLetter, err := u.eventRepo.FindDeadLetter(ctx, deadLetterID)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, ErrDeadLetterNotFound
+		}
+		return nil, err
+	}
+	if deadLetter.IsResolved() {
+		return nil, ErrDeadLetterResolved
+	}
+
+	// 同じデッドレターの同時の再処理と二重に処理しないように、処理の開始を記録してから反映する
+	// 最後の結果の記録より後に処理を開始したものは、期限が切れるまで処理中とみなす
+	event := &deadLetter.Event
+	now := time.Now()
+	if event.ClaimedAt.After(deadLetter.UpdatedAt) && now.Sub(event.ClaimedAt) < webhookProcessingTimeout {
+		return nil, ErrWebhookEventInProgress
+	}
+	claimed, err := u.eventRepo.Claim(ctx, event.ID, event.ClaimedAt, now)
+	if err != nil {
+		return nil, err
+	}
+	if !claimed {
+		return nil, ErrWebhookEventInProgress
+	}
+	event.ClaimedAt = now
+
+	deadLetter.Attempts++
+	if err := u.process(ctx, event); err != nil {
+		deadLetter.LastError = err.Error()
+	} else {
+		now := time.Now()
+		deadLetter.ResolvedAt = &now
+		deadLetter.Event.ProcessedAt = &now
+	}
+	if err := u.eventRepo.UpdateDeadLetter(ctx, deadLetter); err != nil {
+		return nil, err
+	}
+	return deadLetter, nil
+}
+
+// process はイベントを注文に反映し、処理済みにします
+// 返金のイベントは管理画面からの返金と同じく返金として記録します
+func (u *paymentWebhookUseCase) process(ctx context.Context, event *entity.PaymentEvent) error {
+	var err error
+	if event.Type == entity.PaymentEventRefunded {
+		err = u.refundUseCase.ApplyRefundEvent(ctx, event)
+	} else {
+		err = u.orderUseCase.ApplyPaymentEvent(ctx, event)
+	}
+	if err != nil {
+		return err
+	}
+	return u.eventRepo.MarkProcessed(ctx, event.ID, time.Now())
+}
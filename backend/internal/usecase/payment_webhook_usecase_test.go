@@ -0,0 +1,360 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/sotaheavymetal21/rabbit-cart/backend/internal/domain/entity"
+	domainrepository "github.com/sotaheavymetal21/rabbit-cart/backend/internal/domain/repository"
+	"github.com/sotaheavymetal21/rabbit-cart/backend/internal/usecase"
+)
+
+// stubWebhookVerifier は署名を検証せずにペイロードをイベントIDとするイベントを返します
+type stubWebhookVerifier struct{}
+
+func (stubWebhookVerifier) Verify(payload []byte, signature string, now time.Time) (*entity.PaymentEvent, error) {
+	return &entity.PaymentEvent{
+		Provider:          "fake",
+		EventID:           string(payload),
+		Type:              entity.PaymentEventAuthorized,
+		ProviderPaymentID: "pay_1",
+		Payload:           string(payload),
+	}, nil
+}
+
+// stubPaymentEventRepository はメモリ上でイベントとデッドレターを保持する PaymentEventRepository です
+type stubPaymentEventRepository struct {
+	mu          sync.Mutex
+	events      map[string]*entity.PaymentEvent // イベントID → イベント
+	deadLetters map[string]*entity.PaymentDeadLetter
+}
+
+func newStubPaymentEventRepository() *stubPaymentEventRepository {
+	return &stubPaymentEventRepository{
+		events:      make(map[string]*entity.PaymentEvent),
+		deadLetters: make(map[string]*entity.PaymentDeadLetter),
+	}
+}
+
+func (r *stubPaymentEventRepository) Create(ctx context.Context, event *entity.PaymentEvent) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.events[event.EventID]; ok {
+		return domainrepository.ErrDuplicate
+	}
+	event.ID = fmt.Sprintf("event-%d", len(r.events)+1)
+	copied := *event
+	r.events[event.EventID] = &copied
+	return nil
+}
+
+func (r *stubPaymentEventRepository) FindByEventID(ctx context.Context, provider, eventID string) (*entity.PaymentEvent, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	event, ok := r.events[eventID]
+	if !ok || event.Provider != provider {
+		return nil, domainrepository.ErrNotFound
+	}
+	copied := *event
+	return &copied, nil
+}
+
+func (r *stubPaymentEventRepository) Claim(ctx context.Context, id string, claimedAt, now time.Time) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	event := r.byID(id)
+	if event == nil || event.ProcessedAt != nil || !event.ClaimedAt.Equal(claimedAt) {
+		return false, nil
+	}
+	event.ClaimedAt = now
+	return true, nil
+}
+
+func (r *stubPaymentEventRepository) MarkProcessed(ctx context.Context, id string, at time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if event := r.byID(id); event != nil {
+		event.ProcessedAt = &at
+	}
+	return nil
+}
+
+func (r *stubPaymentEventRepository) CreateDeadLetter(ctx context.Context, deadLetter *entity.PaymentDeadLetter) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.deadLetters[deadLetter.PaymentEventID]; ok {
+		return domainrepository.ErrDuplicate
+	}
+	deadLetter.ID = fmt.Sprintf("dead-letter-%d", len(r.deadLetters)+1)
+	deadLetter.CreatedAt = time.Now()
+	deadLetter.UpdatedAt = deadLetter.CreatedAt
+	copied := *deadLetter
+	r.deadLetters[deadLetter.PaymentEventID] = &copied
+	return nil
+}
+
+func (r *stubPaymentEventRepository) UpdateDeadLetter(ctx context.Context, deadLetter *entity.PaymentDeadLetter) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.deadLetters[deadLetter.PaymentEventID]; !ok {
+		return domainrepository.ErrNotFound
+	}
+	deadLetter.UpdatedAt = time.Now()
+	copied := *deadLetter
+	copied.Event = entity.PaymentEvent{}
+	r.deadLetters[deadLetter.PaymentEventID] = &copied
+	return nil
+}
+
+func (r *stubPaymentEventRepository) FindDeadLetter(ctx context.Context, id string) (*entity.PaymentDeadLetter, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, deadLetter := range r.deadLetters {
+		if deadLetter.ID == id {
+			copied := *deadLetter
+			copied.Event = *r.byID(deadLetter.PaymentEventID)
+			return &copied, nil
+		}
+	}
+	return nil, domainrepository.ErrNotFound
+}
+
+func (r *stubPaymentEventRepository) FindDeadLetterByEventID(ctx context.Context, paymentEventID string) (*entity.PaymentDeadLetter, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	deadLetter, ok := r.deadLetters[paymentEventID]
+	if !ok {
+		return nil, domainrepository.ErrNotFound
+	}
+	return deadLetter, nil
+}
+
+func (r *stubPaymentEventRepository) ListUnresolvedDeadLetters(ctx context.Context) ([]*entity.PaymentDeadLetter, error) {
+	return nil, nil
+}
+
+func (r *stubPaymentEventRepository) byID(id string) *entity.PaymentEvent {
+	for _, event := range r.events {
+		if event.ID == id {
+			return event
+		}
+	}
+	return nil
+}
+
+// stubOrderUseCase は ApplyPaymentEvent の呼び出し回数を数える OrderUseCase です
+type stubOrderUseCase struct {
+	usecase.OrderUseCase
+	mu      sync.Mutex
+	applied int
+	err     error
+}
+
+func (u *stubOrderUseCase) ApplyPaymentEvent(ctx context.Context, event *entity.PaymentEvent) error {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	u.applied++
+	return u.err
+}
+
+func TestPaymentWebhookReceiveProcessesOnce(t *testing.T) {
+	events := newStubPaymentEventRepository()
+	orders := &stubOrderUseCase{}
+	webhooks := usecase.NewPaymentWebhookUseCase(events, stubWebhookVerifier{}, orders, nil)
+	ctx := context.Background()
+
+	for i := range 3 {
+		if err := webhooks.Receive(ctx, []byte("evt_1"), ""); err != nil {
+			t.Fatalf("%d 回目の Receive: %v", i+1, err)
+		}
+	}
+	if orders.applied != 1 {
+		t.Errorf("イベントの反映 = %d 回, want 1 回", orders.applied)
+	}
+}
+
+// 保存後に処理を終えられなかったイベントは、再送を受け取った際に処理し直すことを確認する
+func TestPaymentWebhookReceiveRetriesUnfinishedEvent(t *testing.T) {
+	ctx := context.Background()
+	tests := []struct {
+		name      string
+		claimedAt time.Duration // 最後に処理を開始してからの経過時間
+		want      error
+		applied   int
+	}{
+		{"処理中にプロセスが停止した", 5 * time.Minute, nil, 1},
+		{"処理中", 10 * time.Second, usecase.ErrWebhookEventInProgress, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			events := newStubPaymentEventRepository()
+			orders := &stubOrderUseCase{}
+			webhooks := usecase.NewPaymentWebhookUseCase(events, stubWebhookVerifier{}, orders, nil)
+
+			// イベントの保存後、処理を終える前に停止した状態
+			saved, _ := stubWebhookVerifier{}.Verify([]byte("evt_1"), "", time.Now())
+			saved.ClaimedAt = time.Now().Add(-tt.claimedAt)
+			if err := events.Create(ctx, saved); err != nil {
+				t.Fatal(err)
+			}
+
+			if err := webhooks.Receive(ctx, []byte("evt_1"), ""); !errors.Is(err, tt.want) {
+				t.Fatalf("Receive = %v, want %v", err, tt.want)
+			}
+			if orders.applied != tt.applied {
+				t.Errorf("イベントの反映 = %d 回, want %d 回", orders.applied, tt.applied)
+			}
+			event, err := events.FindByEventID(ctx, "fake", "evt_1")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if processed := event.ProcessedAt != nil; processed != (tt.applied == 1) {
+				t.Errorf("処理済み = %v, want %v", processed, tt.applied == 1)
+			}
+		})
+	}
+}
+
+// デッドレターに記録したイベントは再送では処理し直さないことを確認する
+func TestPaymentWebhookReceiveSkipsDeadLetteredEvent(t *testing.T) {
+	events := newStubPaymentEventRepository()
+	orders := &stubOrderUseCase{err: errors.New("反映に失敗しました")}
+	webhooks := usecase.NewPaymentWebhookUseCase(events, stubWebhookVerifier{}, orders, nil)
+	ctx := context.Background()
+
+	if err := webhooks.Receive(ctx, []byte("evt_1"), ""); err != nil {
+		t.Fatalf("Receive: %v", err)
+	}
+	if len(events.deadLetters) != 1 {
+		t.Fatalf("デッドレター = %d 件, want 1 件", len(events.deadLetters))
+	}
+
+	// 処理中とみなす期間を過ぎてから再送された
+	for _, event := range events.events {
+		event.ClaimedAt = event.ClaimedAt.Add(-5 * time.Minute)
+	}
+	if err := webhooks.Receive(ctx, []byte("evt_1"), ""); err != nil {
+		t.Fatalf("再送の Receive: %v", err)
+	}
+	if orders.applied != 1 {
+		t.Errorf("イベントの反映 = %d 回, want 1 回", orders.applied)
+	}
+}
+
+// デッドレターの再処理は、失敗した場合は理由を記録し、成功した場合はイベントを処理済みにすることを確認する
+func TestPaymentWebhookRedrive(t *testing.T) {
+	events := newStubPaymentEventRepository()
+	orders := &stubOrderUseCase{err: errors.New("反映に失敗しました")}
+	webhooks := usecase.NewPaymentWebhookUseCase(events, stubWebhookVerifier{}, orders, nil)
+	ctx := context.Background()
+
+	if err := webhooks.Receive(ctx, []byte("evt_1"), ""); err != nil {
+		t.Fatalf("Receive: %v", err)
+	}
+	deadLetter, _ := events.FindDeadLetterByEventID(ctx, "event-1")
+	id := deadLetter.ID
+
+	orders.err = errors.New("注文が見つかりません")
+	deadLetter, err := webhooks.Redrive(ctx, id)
+	if err != nil {
+		t.Fatalf("Redrive: %v", err)
+	}
+	if deadLetter.IsResolved() || deadLetter.Attempts != 2 || deadLetter.LastError != "注文が見つかりません" {
+		t.Errorf("再処理に失敗したデッドレター = %+v", deadLetter)
+	}
+
+	// 失敗の直後でも、前回の結果を記録済みであれば再処理できる
+	orders.err = nil
+	deadLetter, err = webhooks.Redrive(ctx, id)
+	if err != nil {
+		t.Fatalf("Redrive: %v", err)
+	}
+	if !deadLetter.IsResolved() || deadLetter.Attempts != 3 {
+		t.Errorf("再処理に成功したデッドレター = %+v", deadLetter)
+	}
+	if event, _ := events.FindByEventID(ctx, "fake", "evt_1"); event.ProcessedAt == nil {
+		t.Error("再処理に成功したイベントが処理済みになっていません")
+	}
+
+	if _, err := webhooks.Redrive(ctx, id); !errors.Is(err, usecase.ErrDeadLetterResolved) {
+		t.Errorf("再処理済みの Redrive = %v, want %v", err, usecase.ErrDeadLetterResolved)
+	}
+	if orders.applied != 3 {
+		t.Errorf("イベントの反映 = %d 回, want 3 回", orders.applied)
+	}
+}
+
+// 同じデッドレターを同時に再処理しても、イベントを反映するのは一度だけであることを確認する
+func TestPaymentWebhookRedriveConcurrent(t *testing.T) {
+	events := newStubPaymentEventRepository()
+	orders := &stubOrderUseCase{err: errors.New("反映に失敗しました")}
+	webhooks := usecase.NewPaymentWebhookUseCase(events, stubWebhookVerifier{}, orders, nil)
+	ctx := context.Background()
+
+	if err := webhooks.Receive(ctx, []byte("evt_1"), ""); err != nil {
+		t.Fatalf("Receive: %v", err)
+	}
+	deadLetter, _ := events.FindDeadLetterByEventID(ctx, "event-1")
+	orders.err = nil
+
+	const n = 10
+	var wg sync.WaitGroup
+	start := make(chan struct{})
+	errs := make([]error, n)
+	for i := range n {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			<-start
+			_, errs[i] = webhooks.Redrive(ctx, deadLetter.ID)
+		}()
+	}
+	close(start)
+	wg.Wait()
+
+	succeeded := 0
+	for _, err := range errs {
+		switch {
+		case err == nil:
+			succeeded++
+		case errors.Is(err, usecase.ErrWebhookEventInProgress), errors.Is(err, usecase.ErrDeadLetterResolved):
+		default:
+			t.Errorf("Redrive = %v", err)
+		}
+	}
+	if succeeded != 1 {
+		t.Errorf("再処理の成功 = %d 件, want 1 件", succeeded)
+	}
+	// 受信時の 1 回と再処理の 1 回
+	if orders.applied != 2 {
+		t.Errorf("イベントの反映 = %d 回, want 2 回", orders.applied)
+	}
+}
+
+// 他の再処理が処理中のデッドレターは再処理しないことを確認する
+func TestPaymentWebhookRedriveInProgress(t *testing.T) {
+	events := newStubPaymentEventRepository()
+	orders := &stubOrderUseCase{err: errors.New("反映に失敗しました")}
+	webhooks := usecase.NewPaymentWebhookUseCase(events, stubWebhookVerifier{}, orders, nil)
+	ctx := context.Background()
+
+	if err := webhooks.Receive(ctx, []byte("evt_1"), ""); err != nil {
+		t.Fatalf("Receive: %v", err)
+	}
+	deadLetter, _ := events.FindDeadLetterByEventID(ctx, "event-1")
+	// 他の再処理がデッドレターの記録より後に処理を開始した状態
+	if claimed, _ := events.Claim(ctx, "event-1", events.byID("event-1").ClaimedAt, deadLetter.UpdatedAt.Add(time.Second)); !claimed {
+		t.Fatal("処理の開始を記録できません")
+	}
+
+	if _, err := webhooks.Redrive(ctx, deadLetter.ID); !errors.Is(err, usecase.ErrWebhookEventInProgress) {
+		t.Fatalf("Redrive = %v, want %v", err, usecase.ErrWebhookEventInProgress)
+	}
+	if orders.applied != 1 {
+		t.Errorf("イベントの反映 = %d 回, want 1 回", orders.applied)
+	}
+}
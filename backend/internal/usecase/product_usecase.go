@@ -2,24 +2,64 @@ package usecase
 
 import (
 	"context"
+	"errors"
+	"log"
+	"net/url"
+	"strings"
 
 	"github.com/sotaheavymetal21/rabbit-cart/backend/internal/domain/entity"
 	"github.com/sotaheavymetal21/rabbit-cart/backend/internal/domain/repository"
 )
 
+const (
+	defaultProductsPerPage = 20
+	maxProductsPerPage     = 100
+)
+
+var (
+	// ErrInvalidPriceRange は価格の下限が上限を上回っている場合のエラーです
+	ErrInvalidPriceRange = entity.NewFieldError("invalid_price_range", "min_price", "価格の下限が上限を上回っています")
+	// ErrEmptyKeyword は検索キーワードが空の場合のエラーです
+	ErrEmptyKeyword = entity.NewFieldError("empty_keyword", "q", "検索キーワードを入力してください")
+	// ErrInvalidImageURL は画像URLが http(s) の URL または / から始まるパスでない場合のエラーです
+	ErrInvalidImageURL = entity.NewFieldError("invalid_image_url", "image_url", "画像URLの形式が正しくありません")
+	// ErrInvalidSKU は商品コードの形式が正しくない場合のエラーです
+	ErrInvalidSKU = entity.NewFieldError("invalid_sku", "sku", "商品コードは英数字・ハイフン・アンダースコアの64文字以内で指定してください")
+)
+
+// ProductInput は商品の作成・更新時の入力です
+type ProductInput struct {
+	SKU         string // 省略可
+	Name        string
+	Description string
+	Price       int
+	Stock       int // 作成時の初期在庫 (更新時は使用しません)
+	ImageURL    string
+	Category    string
+}
+
 // ProductUseCase は商品に関するビジネスロジックを定義するインターフェースです
 type ProductUseCase interface {
 	GetAllProducts(ctx context.Context) ([]*entity.Product, error)
 	GetProductByID(ctx context.Context, id string) (*entity.Product, error)
+	SearchProducts(ctx context.Context, query repository.ProductQuery) (*repository.ProductPage, error)
+	FullTextSearch(ctx context.Context, keyword string, limit int) ([]repository.ProductSearchHit, error)
+
+	// 以下は管理者向けの商品管理操作です
+	CreateProduct(ctx context.Context, input ProductInput) (*entity.Product, error)
+	UpdateProduct(ctx context.Context, id string, input ProductInput) (*entity.Product, error)
+	DeleteProduct(ctx context.Context, id string) error
+	RestockProduct(ctx context.Context, id string, quantity int) (*entity.Product, error)
 }
 
 type productUseCase struct {
-	repo repository.ProductRepository
+	repo     repository.ProductRepository
+	searcher repository.ProductSearcher
 }
 
 // NewProductUseCase は ProductUseCase の実装を生成します
-func NewProductUseCase(repo repository.ProductRepository) ProductUseCase {
-	return &productUseCase{repo: repo}
+func NewProductUseCase(repo repository.ProductRepository, searcher repository.ProductSearcher) ProductUseCase {
+	return &productUseCase{repo: repo, searcher: searcher}
 }
 
 // GetAllProducts は全ての商品を取得します
@@ -29,5 +69,161 @@ func (u *productUseCase) GetAllProducts(ctx context.Context) ([]*entity.Product,
 
 // GetProductByID は指定されたIDの商品を取得します
 func (u *productUseCase) GetProductByID(ctx context.Context, id string) (*entity.Product, error) {
-	return u.repo.FindByID(ctx, id)
+	product, err := u.repo.FindByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, ErrProductNotFound
+		}
+		return nil, err
+	}
+	return product, nil
+}
+
+// SearchProducts は条件に一致する商品を1ページ分取得します
+// ページ番号・件数・並び順が未指定または範囲外の場合は既定値に補正します
+func (u *productUseCase) SearchProducts(ctx context.Context, query repository.ProductQuery) (*repository.ProductPage, error) {
+	if query.MinPrice != nil && query.MaxPrice != nil && *query.MinPrice > *query.MaxPrice {
+		return nil, ErrInvalidPriceRange
+	}
+
+	switch query.Sort {
+	case repository.ProductSortNewest, repository.ProductSortPriceAsc, repository.ProductSortPriceDesc, repository.ProductSortName:
+	default:
+		query.Sort = repository.ProductSortNewest
+	}
+	if query.Page < 1 {
+		query.Page = 1
+	}
+	if query.PerPage < 1 {
+		query.PerPage = defaultProductsPerPage
+	}
+	if query.PerPage > maxProductsPerPage {
+		query.PerPage = maxProductsPerPage
+	}
+
+	return u.repo.Search(ctx, query)
+}
+
+// FullTextSearch はキーワードに一致する商品を関連度順に取得します
+func (u *productUseCase) FullTextSearch(ctx context.Context, keyword string, limit int) ([]repository.ProductSearchHit, error) {
+	if strings.TrimSpace(keyword) == "" {
+		return nil, ErrEmptyKeyword
+	}
+	if limit < 1 {
+		limit = defaultProductsPerPage
+	}
+	if limit > maxProductsPerPage {
+		limit = maxProductsPerPage
+	}
+	return u.searcher.Search(ctx, keyword, limit)
+}
+
+// CreateProduct は商品を作成し、検索インデックスに登録します
+func (u *productUseCase) CreateProduct(ctx context.Context, input ProductInput) (*entity.Product, error) {
+	if err := validateProductInput(&input); err != nil {
+		return nil, err
+	}
+
+	product := &entity.Product{
+		SKU:         input.SKU,
+		Name:        input.Name,
+		Description: input.Description,
+		Price:       input.Price,
+		Stock:       input.Stock,
+		ImageURL:    input.ImageURL,
+		Category:    input.Category,
+	}
+	if err := u.repo.Create(ctx, product); err != nil {
+		return nil, err
+	}
+	u.reindex(ctx, product)
+	return product, nil
+}
+
+// UpdateProduct は商品情報を更新し、検索インデックスに反映します
+// 在庫は注文による引き当てと競合しないよう更新せず、RestockProduct で増減させます
+func (u *productUseCase) UpdateProduct(ctx context.Context, id string, input ProductInput) (*entity.Product, error) {
+	if err := validateProductInput(&input); err != nil {
+		return nil, err
+	}
+
+	product := &entity.Product{
+		ID:          id,
+		SKU:         input.SKU,
+		Name:        input.Name,
+		Description: input.Description,
+		Price:       input.Price,
+		ImageURL:    input.ImageURL,
+		Category:    input.Category,
+	}
+	if err := u.repo.Update(ctx, product); err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, ErrProductNotFound
+		}
+		return nil, err
+	}
+
+	updated, err := u.repo.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	u.reindex(ctx, updated)
+	return updated, nil
+}
+
+// DeleteProduct は商品を削除し、検索インデックスからも取り除きます
+func (u *productUseCase) DeleteProduct(ctx context.Context, id string) error {
+	if err := u.repo.Delete(ctx, id); err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return ErrProductNotFound
+		}
+		return err
+	}
+	if err := u.searcher.Remove(ctx, id); err != nil {
+		log.Printf("商品 %s の検索インデックスからの削除に失敗しました: %v", id, err)
+	}
+	return nil
+}
+
+// RestockProduct は商品の在庫を quantity だけ増やします
+func (u *productUseCase) RestockProduct(ctx context.Context, id string, quantity int) (*entity.Product, error) {
+	if quantity <= 0 {
+		return nil, ErrInvalidQuantity
+	}
+	product, err := u.repo.Restock(ctx, id, quantity)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, ErrProductNotFound
+		}
+		return nil, err
+	}
+	return product, nil
+}
+
+// reindex は商品を検索インデックスに反映します
+// 商品の保存自体は完了しているため、失敗してもログに残すだけにします（次回起動時の再構築で復旧します）
+func (u *productUseCase) reindex(ctx context.Context, product *entity.Product) {
+	if err := u.searcher.Index(ctx, product); err != nil {
+		log.Printf("商品 %s の検索インデックスの更新に失敗しました: %v", product.ID, err)
+	}
+}
+
+// validateProductInput は入力値を整形し、ハンドラーのバインディングでは確認できない項目を検証します
+func validateProductInput(input *ProductInput) error {
+	input.SKU = strings.TrimSpace(input.SKU)
+	input.Name = strings.TrimSpace(input.Name)
+	input.Category = strings.TrimSpace(input.Category)
+	input.ImageURL = strings.TrimSpace(input.ImageURL)
+
+	if input.SKU != "" && !skuPattern.MatchString(input.SKU) {
+		return ErrInvalidSKU
+	}
+	if input.ImageURL == "" || strings.HasPrefix(input.ImageURL, "/") {
+		return nil
+	}
+	parsed, err := url.Parse(input.ImageURL)
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+		return ErrInvalidImageURL
+	}
+	return nil
 }
@@ -0,0 +1,116 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/sotaheavymetal21/rabbit-cart/backend/internal/domain/entity"
+	"github.com/sotaheavymetal21/rabbit-cart/backend/internal/domain/repository"
+	"github.com/sotaheavymetal21/rabbit-cart/backend/internal/infrastructure/search"
+	"github.com/sotaheavymetal21/rabbit-cart/backend/internal/usecase"
+)
+
+// searchRecorder は Search に渡された検索条件を記録する ProductRepository です
+type searchRecorder struct {
+	stubProductRepository
+	query repository.ProductQuery
+}
+
+func (r *searchRecorder) Search(ctx context.Context, query repository.ProductQuery) (*repository.ProductPage, error) {
+	r.query = query
+	return &repository.ProductPage{}, nil
+}
+
+func TestSearchProductsNormalizesQuery(t *testing.T) {
+	tests := []struct {
+		name  string
+		query repository.ProductQuery
+		want  repository.ProductQuery
+	}{
+		{
+			name:  "未指定",
+			query: repository.ProductQuery{},
+			want:  repository.ProductQuery{Sort: repository.ProductSortNewest, Page: 1, PerPage: 20},
+		},
+		{
+			name:  "一覧にない並び順",
+			query: repository.ProductQuery{Sort: "stock", Page: 2, PerPage: 10},
+			want:  repository.ProductQuery{Sort: repository.ProductSortNewest, Page: 2, PerPage: 10},
+		},
+		{
+			name:  "件数の上限",
+			query: repository.ProductQuery{Sort: repository.ProductSortPriceDesc, PerPage: 1000},
+			want:  repository.ProductQuery{Sort: repository.ProductSortPriceDesc, Page: 1, PerPage: 100},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &searchRecorder{}
+			if _, err := usecase.NewProductUseCase(repo, nil).SearchProducts(context.Background(), tt.query); err != nil {
+				t.Fatal(err)
+			}
+			if repo.query != tt.want {
+				t.Errorf("検索条件 = %+v, want %+v", repo.query, tt.want)
+			}
+		})
+	}
+}
+
+func TestSearchProductsRejectsInvalidPriceRange(t *testing.T) {
+	minPrice, maxPrice := 2000, 1000
+	query := repository.ProductQuery{MinPrice: &minPrice, MaxPrice: &maxPrice}
+	if _, err := usecase.NewProductUseCase(&searchRecorder{}, nil).SearchProducts(context.Background(), query); !errors.Is(err, usecase.ErrInvalidPriceRange) {
+		t.Errorf("SearchProducts = %v, want ErrInvalidPriceRange", err)
+	}
+}
+
+// productUpdateRecorder は Update に渡された商品で保存済みの商品を上書きする ProductRepository です
+// Update と同じく在庫は上書きしません
+type productUpdateRecorder struct {
+	stubProductRepository
+	updated *entity.Product
+}
+
+func (r *productUpdateRecorder) Update(ctx context.Context, product *entity.Product) error {
+	r.updated = product
+	stored := r.products[product.ID]
+	stored.Name, stored.Description, stored.Price = product.Name, product.Description, product.Price
+	stored.ImageURL, stored.Category = product.ImageURL, product.Category
+	return nil
+}
+
+// 商品情報の更新で在庫を書き換えないことを確認する (在庫は注文による引き当てと補充でのみ増減する)
+func TestUpdateProductKeepsStock(t *testing.T) {
+	repo := &productUpdateRecorder{stubProductRepository: stubProductRepository{products: map[string]*entity.Product{
+		"tee": {ID: "tee", Name: "Tシャツ", Price: 3000, Stock: 5, Category: "apparel"},
+	}}}
+	searcher := search.NewMemoryProductSearcher(func(ctx context.Context) ([]*entity.Product, error) { return nil, nil })
+	products := usecase.NewProductUseCase(repo, searcher)
+
+	updated, err := products.UpdateProduct(context.Background(), "tee", usecase.ProductInput{
+		Name:     "  Tシャツ (白)  ",
+		Price:    3500,
+		Stock:    0,
+		Category: "apparel",
+	})
+	if err != nil {
+		t.Fatalf("UpdateProduct: %v", err)
+	}
+	if repo.updated.Stock != 0 {
+		t.Errorf("Update に渡した在庫 = %d, want 0 (未指定)", repo.updated.Stock)
+	}
+	if updated.Name != "Tシャツ (白)" || updated.Price != 3500 || updated.Stock != 5 {
+		t.Errorf("更新後の商品 = %+v, want 名前を整形・価格 3500・在庫 5", updated)
+	}
+}
+
+func TestCreateProductRejectsInvalidImageURL(t *testing.T) {
+	products := usecase.NewProductUseCase(&stubProductRepository{}, nil)
+	for _, imageURL := range []string{"javascript:alert(1)", "ftp://example.com/tee.png", "https://"} {
+		_, err := products.CreateProduct(context.Background(), usecase.ProductInput{Name: "Tシャツ", Category: "apparel", ImageURL: imageURL})
+		if !errors.Is(err, usecase.ErrInvalidImageURL) {
+			t.Errorf("%q: CreateProduct = %v, want ErrInvalidImageURL", imageURL, err)
+		}
+	}
+}
@@ -0,0 +1,138 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/sotaheavymetal21/rabbit-cart/backend/internal/domain/entity"
+	"github.com/sotaheavymetal21/rabbit-cart/backend/internal/domain/repository"
+)
+
+var (
+	// ErrPromotionNotFound はクーポンが存在しない場合のエラーです
+	ErrPromotionNotFound = entity.NewNotFoundError("promotion_not_found", "クーポンが見つかりません")
+	// ErrDuplicatePromotionCode はクーポンコードが他のクーポンと重複している場合のエラーです
+	ErrDuplicatePromotionCode = entity.NewConflictError("duplicate_promotion_code", "クーポンコードが他のクーポンと重複しています")
+)
+
+// PromotionInput はクーポンの作成・更新時の入力です
+type PromotionInput struct {
+	Code          string                `json:"code"`
+	Name          string                `json:"name"`
+	DiscountType  entity.DiscountType   `json:"discount_type"`
+	DiscountValue int                   `json:"discount_value"`
+	MaxDiscount   int                   `json:"max_discount"`
+	MinSpend      int                   `json:"min_spend"`
+	Scope         entity.PromotionScope `json:"scope"` // 省略した場合は all
+	Targets       []string              `json:"targets"`
+	UsageLimit    int                   `json:"usage_limit"`
+	PerUserLimit  int                   `json:"per_user_limit"`
+	StartsAt      *time.Time            `json:"starts_at"`
+	EndsAt        *time.Time            `json:"ends_at"`
+	Active        *bool                 `json:"active"` // 省略した場合は true
+}
+
+func (in *PromotionInput) toEntity() *entity.Promotion {
+	active := true
+	if in.Active != nil {
+		active = *in.Active
+	}
+	return &entity.Promotion{
+		Code:          in.Code,
+		Name:          in.Name,
+		DiscountType:  in.DiscountType,
+		DiscountValue: in.DiscountValue,
+		MaxDiscount:   in.MaxDiscount,
+		MinSpend:      in.MinSpend,
+		Scope:         in.Scope,
+		Targets:       in.Targets,
+		UsageLimit:    in.UsageLimit,
+		PerUserLimit:  in.PerUserLimit,
+		StartsAt:      in.StartsAt,
+		EndsAt:        in.EndsAt,
+		Active:        active,
+	}
+}
+
+// PromotionUseCase は管理者によるクーポンの管理に関するビジネスロジックを定義するインターフェースです
+type PromotionUseCase interface {
+	ListPromotions(ctx context.Context) ([]*entity.Promotion, error)
+	GetPromotion(ctx context.Context, id string) (*entity.Promotion, error)
+	// CreatePromotion はクーポンを検証して作成します (設定が正しくない場合は *entity.ValidationError を返します)
+	CreatePromotion(ctx context.Context, input PromotionInput) (*entity.Promotion, error)
+	// UpdatePromotion はクーポンの設定を更新します (利用回数は変わりません)
+	UpdatePromotion(ctx context.Context, id string, input PromotionInput) (*entity.Promotion, error)
+	// DeletePromotion はクーポンを削除します (注文で使用されたクーポンは削除できません)
+	DeletePromotion(ctx context.Context, id string) error
+}
+
+type promotionUseCase struct {
+	repo repository.PromotionRepository
+}
+
+// NewPromotionUseCase は PromotionUseCase の実装を生成します
+func NewPromotionUseCase(repo repository.PromotionRepository) PromotionUseCase {
+	return &promotionUseCase{repo: repo}
+}
+
+func (u *promotionUseCase) ListPromotions(ctx context.Context) ([]*entity.Promotion, error) {
+	return u.repo.FindAll(ctx)
+}
+
+func (u *promotionUseCase) GetPromotion(ctx context.Context, id string) (*entity.Promotion, error) {
+	promotion, err := u.repo.FindByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, ErrPromotionNotFound
+		}
+		return nil, err
+	}
+	return promotion, nil
+}
+
+func (u *promotionUseCase) CreatePromotion(ctx context.Context, input PromotionInput) (*entity.Promotion, error) {
+	promotion := input.toEntity()
+	promotion.Normalize()
+	if err := promotion.Validate(); err != nil {
+		return nil, err
+	}
+
+	if err := u.repo.Create(ctx, promotion); err != nil {
+		if errors.Is(err, repository.ErrDuplicate) {
+			return nil, ErrDuplicatePromotionCode
+		}
+		return nil, err
+	}
+	return promotion, nil
+}
+
+func (u *promotionUseCase) UpdatePromotion(ctx context.Context, id string, input PromotionInput) (*entity.Promotion, error) {
+	promotion := input.toEntity()
+	promotion.ID = id
+	promotion.Normalize()
+	if err := promotion.Validate(); err != nil {
+		return nil, err
+	}
+
+	if err := u.repo.Update(ctx, promotion); err != nil {
+		switch {
+		case errors.Is(err, repository.ErrNotFound):
+			return nil, ErrPromotionNotFound
+		case errors.Is(err, repository.ErrDuplicate):
+			return nil, ErrDuplicatePromotionCode
+		}
+		return nil, err
+	}
+	return u.GetPromotion(ctx, id)
+}
+
+func (u *promotionUseCase) DeletePromotion(ctx context.Context, id string) error {
+	if err := u.repo.Delete(ctx, id); err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return ErrPromotionNotFound
+		}
+		return err
+	}
+	return nil
+}
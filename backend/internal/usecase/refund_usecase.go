@@ -0,0 +1,286 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"log"
+
+	"github.com/sotaheavymetal21/rabbit-cart/backend/internal/domain/entity"
+	"github.com/sotaheavymetal21/rabbit-cart/backend/internal/domain/repository"
+)
+
+var (
+	// ErrPaymentNotCaptured は売上が確定していない (発送前の) 注文を返金しようとした場合のエラーです
+	ErrPaymentNotCaptured = entity.NewConflictError("payment_not_captured", "売上が確定していない注文は返金できません。発送前の注文はキャンセルしてください")
+	// ErrEmptyRefund は返金する金額が無い場合のエラーです
+	ErrEmptyRefund = entity.NewFieldError("empty_refund", "items", "返金する明細がありません")
+	// ErrRefundItemNotFound は注文に含まれない明細を返金しようとした場合のエラーです
+	ErrRefundItemNotFound = entity.NewFieldError("refund_item_not_found", "items", "注文明細が見つかりません")
+	// ErrRefundFailed は決済サービスで返金が拒否された場合のエラーです
+	ErrRefundFailed = entity.NewUnprocessableError("refund_failed", "決済サービスで返金できませんでした")
+	// ErrRefundAmountMismatch は決済サービスで行われた返金の金額を未返金の決済額・注文明細に割り当てられない場合のエラーです
+	ErrRefundAmountMismatch = entity.NewUnprocessableError("refund_amount_mismatch", "返金額が未返金の決済額と一致しません")
+)
+
+// RefundUseCase は注文の返金に関するビジネスロジックを定義するインターフェースです
+type RefundUseCase interface {
+	// RefundOrder は注文の全額または明細ごとの一部を返金し、返金後の注文を返します
+	// input.Items が空の場合は未返金の全ての明細を返金します。全額を返金した注文は返金済み (refunded) になります
+	RefundOrder(ctx context.Context, orderID, actorID string, input RefundInput) (*entity.Order, error)
+	// ApplyRefundEvent は決済サービスの管理画面などで行われた返金のイベントを記録し、全額が返金された注文を返金済みにします
+	// 決済サービスには返金を依頼せず、イベントの金額を未返金の明細に割り当てた記録を残します
+	// 金額を割り当てられない場合は ErrRefundAmountMismatch を返します。全額が返金済みの決済のイベントは何もしません
+	ApplyRefundEvent(ctx context.Context, event *entity.PaymentEvent) error
+}
+
+// RefundInput は返金の内容です
+type RefundInput struct {
+	Items   []RefundItemInput `json:"items"`
+	Reason  string            `json:"reason"`
+	Restock bool              `json:"restock"` // 返品された数量を在庫に戻すか
+}
+
+// RefundItemInput は注文明細ごとの返金の内容です
+type RefundItemInput struct {
+	OrderItemID string `json:"order_item_id"`
+	Quantity    int    `json:"quantity"` // 返品する数量 (0 の場合は返品を伴わない減額)
+	Amount      *int   `json:"amount"`   // 省略した場合は割引を按分した 単価 × 数量
+}
+
+// externalRefundReason は決済サービスの管理画面などで行われた返金を記録する際の理由です
+const externalRefundReason = "決済サービスで返金されました"
+
+type refundUseCase struct {
+	orderRepo    repository.OrderRepository
+	paymentRepo  repository.PaymentRepository
+	refundRepo   repository.RefundRepository
+	gateway      repository.PaymentGateway
+	orderUseCase OrderUseCase
+}
+
+// NewRefundUseCase は RefundUseCase の実装を生成します
+func NewRefundUseCase(orderRepo repository.OrderRepository, paymentRepo repository.PaymentRepository, refundRepo repository.RefundRepository, gateway repository.PaymentGateway, orderUseCase OrderUseCase) RefundUseCase {
+	return &refundUseCase{
+		orderRepo:    orderRepo,
+		paymentRepo:  paymentRepo,
+		refundRepo:   refundRepo,
+		gateway:      gateway,
+		orderUseCase: orderUseCase,
+	}
+}
+
+// RefundOrder は注文を返金します
+// 返金額を先に確保してから決済サービスに依頼し、失敗した場合は確保した返金額を戻します
+func (u *refundUseCase) RefundOrder(ctx context.Context, orderID, actorID string, input RefundInput) (*entity.Order, error) {
+	order, err := u.orderRepo.FindByID(ctx, orderID)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, ErrOrderNotFound
+		}
+		return nil, err
+	}
+	payment, err := u.paymentRepo.FindLatestByOrderID(ctx, order.ID)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, ErrPaymentNotCaptured
+		}
+		return nil, err
+	}
+	if payment.Status != entity.PaymentStatusCaptured {
+		return nil, ErrPaymentNotCaptured
+	}
+
+	items, err := buildRefundItems(order, input.Items)
+	if err != nil {
+		return nil, err
+	}
+	refund := &entity.Refund{
+		OrderID:   order.ID,
+		PaymentID: payment.ID,
+		Reason:    input.Reason,
+		Restock:   input.Restock,
+		CreatedBy: actorID,
+		Items:     items,
+	}
+	for _, item := range items {
+		refund.Amount += item.Amount
+	}
+	if refund.Amount <= 0 {
+		return nil, ErrEmptyRefund
+	}
+	if refund.Amount > payment.RefundableAmount() {
+		return nil, entity.ErrRefundExceedsCaptured
+	}
+
+	if err := u.refundRepo.Create(ctx, refund); err != nil {
+		return nil, err
+	}
+
+	result, err := u.gateway.Refund(ctx, payment.ProviderPaymentID, refund.Amount)
+	if err != nil || result.Status == entity.PaymentStatusFailed {
+		if err != nil {
+			log.Printf("注文 %s の返金に失敗しました: %v", order.ID, err)
+		}
+		if failErr := u.refundRepo.Fail(ctx, refund); failErr != nil {
+			log.Printf("返金 %s の取り消しの記録に失敗しました: %v", refund.ID, failErr)
+		}
+		if err != nil {
+			return nil, entity.ErrPaymentUnavailable
+		}
+		return nil, ErrRefundFailed
+	}
+
+	if err := u.refundRepo.Complete(ctx, refund, result.Status); err != nil {
+		// 決済サービスでは返金済みのため、記録の失敗は調査できるようにログに残す
+		log.Printf("注文 %s の返金 %s (%d 円) は完了しましたが、記録に失敗しました: %v", order.ID, refund.ID, refund.Amount, err)
+		return nil, err
+	}
+
+	if result.Status == entity.PaymentStatusRefunded && order.Status.CanTransitionTo(entity.OrderStatusRefunded) {
+		return u.orderUseCase.TransitionStatus(ctx, order.ID, actorID, entity.OrderStatusRefunded, input.Reason)
+	}
+	return u.orderRepo.FindByID(ctx, order.ID)
+}
+
+// ApplyRefundEvent は決済サービスで行われた返金を記録します
+// 管理画面からの返金と同じく返金と明細ごとの返金額を記録するため、以降の返金額の計算や注文の表示と矛盾しません
+// 同じイベントの再送は Webhook の受信時に除くため、ここでは全額が返金済みかどうかだけを確認します
+func (u *refundUseCase) ApplyRefundEvent(ctx context.Context, event *entity.PaymentEvent) error {
+	payment, err := u.paymentRepo.FindByProviderPaymentID(ctx, event.Provider, event.ProviderPaymentID)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return ErrPaymentNotFound
+		}
+		return err
+	}
+	order, err := u.orderRepo.FindByID(ctx, payment.OrderID)
+	if err != nil {
+		return err
+	}
+
+	switch payment.Status {
+	case entity.PaymentStatusRefunded:
+		// 返金は反映済み (注文ステータスの変更だけが失敗していた場合に備えて続行する)
+	case entity.PaymentStatusCaptured:
+		status, err := u.recordExternalRefund(ctx, order, payment, event.Amount)
+		if err != nil {
+			return err
+		}
+		if status != entity.PaymentStatusRefunded {
+			// 一部の返金では注文ステータスを変更しない
+			return nil
+		}
+	default:
+		return ErrPaymentStatusConflict
+	}
+
+	if !order.Status.CanTransitionTo(entity.OrderStatusRefunded) {
+		return nil
+	}
+	_, err = u.orderUseCase.TransitionStatus(ctx, order.ID, entity.SystemActorID, entity.OrderStatusRefunded, externalRefundReason)
+	return err
+}
+
+// recordExternalRefund は決済サービスで返金済みの amount を、未返金の明細に順に割り当てた返金として記録し、記録後の決済のステータスを返します
+func (u *refundUseCase) recordExternalRefund(ctx context.Context, order *entity.Order, payment *entity.Payment, amount int) (entity.PaymentStatus, error) {
+	if amount <= 0 || amount > payment.RefundableAmount() {
+		return "", ErrRefundAmountMismatch
+	}
+	items, ok := allocateRefundAmount(order, amount)
+	if !ok {
+		return "", ErrRefundAmountMismatch
+	}
+	refund := &entity.Refund{
+		OrderID:   order.ID,
+		PaymentID: payment.ID,
+		Amount:    amount,
+		Reason:    externalRefundReason,
+		CreatedBy: entity.SystemActorID,
+		Items:     items,
+	}
+	status := entity.PaymentStatusCaptured
+	if amount == payment.RefundableAmount() {
+		status = entity.PaymentStatusRefunded
+	}
+	if err := u.refundRepo.Create(ctx, refund); err != nil {
+		return "", err
+	}
+	if err := u.refundRepo.Complete(ctx, refund, status); err != nil {
+		return "", err
+	}
+	return status, nil
+}
+
+// allocateRefundAmount は amount を注文明細の順に未返金の金額まで割り当てます
+// 未返金の金額の全てを割り当てた明細は未返金の数量も返品したものとし、一部だけの明細は返品を伴わない減額とします
+// 明細の未返金の金額の合計が amount に足りない場合は false を返します
+func allocateRefundAmount(order *entity.Order, amount int) ([]entity.RefundItem, bool) {
+	var items []entity.RefundItem
+	for _, orderItem := range order.OrderItems {
+		if amount == 0 {
+			break
+		}
+		refundable := orderItem.RefundableAmount()
+		if refundable <= 0 {
+			continue
+		}
+		item := entity.RefundItem{OrderItemID: orderItem.ID, Amount: min(amount, refundable)}
+		if item.Amount == refundable {
+			item.Quantity = orderItem.RefundableQuantity()
+		}
+		items = append(items, item)
+		amount -= item.Amount
+	}
+	return items, amount == 0
+}
+
+// buildRefundItems は返金の内容から明細ごとの返金額を決めます
+// inputs が空の場合は未返金の全ての明細を、未返金の数量・金額の全てで返金します
+func buildRefundItems(order *entity.Order, inputs []RefundItemInput) ([]entity.RefundItem, error) {
+	orderItems := make(map[string]*entity.OrderItem, len(order.OrderItems))
+	for i := range order.OrderItems {
+		orderItems[order.OrderItems[i].ID] = &order.OrderItems[i]
+	}
+
+	if len(inputs) == 0 {
+		var items []entity.RefundItem
+		for _, orderItem := range order.OrderItems {
+			if orderItem.RefundableAmount() <= 0 {
+				continue
+			}
+			items = append(items, entity.RefundItem{
+				OrderItemID: orderItem.ID,
+				Quantity:    orderItem.RefundableQuantity(),
+				Amount:      orderItem.RefundableAmount(),
+			})
+		}
+		return items, nil
+	}
+
+	items := make([]entity.RefundItem, 0, len(inputs))
+	seen := make(map[string]bool, len(inputs))
+	for _, input := range inputs {
+		orderItem, ok := orderItems[input.OrderItemID]
+		if !ok || seen[input.OrderItemID] {
+			return nil, ErrRefundItemNotFound
+		}
+		seen[input.OrderItemID] = true
+
+		amount := orderItem.RefundAmountFor(input.Quantity)
+		if input.Amount != nil {
+			amount = *input.Amount
+		}
+		if input.Quantity < 0 || amount <= 0 {
+			return nil, ErrEmptyRefund
+		}
+		if input.Quantity > orderItem.RefundableQuantity() || amount > orderItem.RefundableAmount() {
+			return nil, entity.ErrRefundExceedsOrderItem
+		}
+		items = append(items, entity.RefundItem{
+			OrderItemID: orderItem.ID,
+			Quantity:    input.Quantity,
+			Amount:      amount,
+		})
+	}
+	return items, nil
+}
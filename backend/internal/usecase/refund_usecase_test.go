@@ -0,0 +1,156 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/sotaheavymetal21/rabbit-cart/backend/internal/domain/entity"
+	"github.com/sotaheavymetal21/rabbit-cart/backend/internal/infrastructure/repository"
+	"github.com/sotaheavymetal21/rabbit-cart/backend/internal/usecase"
+)
+
+// 決済サービスで行われた全額の返金が、管理画面からの返金と同じく返金と明細ごとの返金額として記録されることを確認する
+func TestApplyRefundEventRecordsRefund(t *testing.T) {
+	f := newOrderFixture(t, nil)
+	ctx := context.Background()
+	refunds := usecase.NewRefundUseCase(f.orderRepo, repository.NewPaymentRepository(f.db), repository.NewRefundRepository(f.db), f.gateway, f.orders)
+
+	order := f.paidOrder(t)
+	order, err := f.orders.TransitionStatus(ctx, order.ID, f.user.ID, entity.OrderStatusShipped, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	payment := order.Payments[len(order.Payments)-1]
+	event := &entity.PaymentEvent{
+		Provider:          payment.Provider,
+		EventID:           "evt_refunded_1",
+		Type:              entity.PaymentEventRefunded,
+		ProviderPaymentID: payment.ProviderPaymentID,
+		Amount:            payment.Amount,
+	}
+
+	// 再送で同じイベントを2回反映しても返金は1件だけ
+	for i := range 2 {
+		if err := refunds.ApplyRefundEvent(ctx, event); err != nil {
+			t.Fatalf("%d 回目の ApplyRefundEvent: %v", i+1, err)
+		}
+	}
+
+	got, err := f.orderRepo.FindByID(ctx, order.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Status != entity.OrderStatusRefunded {
+		t.Errorf("注文ステータス = %s, want %s", got.Status, entity.OrderStatusRefunded)
+	}
+	if len(got.Refunds) != 1 {
+		t.Fatalf("返金 = %d 件, want 1 件", len(got.Refunds))
+	}
+	if refund := got.Refunds[0]; refund.Amount != payment.Amount || refund.Status != entity.RefundStatusSucceeded || refund.CreatedBy != entity.SystemActorID {
+		t.Errorf("返金 = %+v, want 金額 %d・succeeded・システムによる返金", refund, payment.Amount)
+	}
+	for _, item := range got.OrderItems {
+		if item.RefundableAmount() != 0 || item.RefundableQuantity() != 0 {
+			t.Errorf("明細 %s に未返金の分が残っています (金額 %d, 数量 %d)", item.ID, item.RefundableAmount(), item.RefundableQuantity())
+		}
+	}
+	if last := got.Payments[len(got.Payments)-1]; last.Status != entity.PaymentStatusRefunded || last.RefundedAmount != last.Amount {
+		t.Errorf("決済 = %s (返金額 %d), want refunded (返金額 %d)", last.Status, last.RefundedAmount, last.Amount)
+	}
+}
+
+// 決済サービスで行われた一部の返金が、イベントの金額のとおりに明細へ割り当てて記録されることを確認する
+func TestApplyRefundEventPartial(t *testing.T) {
+	f := newOrderFixture(t, nil)
+	ctx := context.Background()
+	refunds := usecase.NewRefundUseCase(f.orderRepo, repository.NewPaymentRepository(f.db), repository.NewRefundRepository(f.db), f.gateway, f.orders)
+
+	tee := &entity.Product{Name: "Tシャツ", Price: 3000, Stock: 10}
+	hat := &entity.Product{Name: "キャップ", Price: 4000, Stock: 10}
+	for _, p := range []*entity.Product{tee, hat} {
+		if err := f.productRepo.Create(ctx, p); err != nil {
+			t.Fatal(err)
+		}
+	}
+	input := orderInput(tee)
+	input.Items = []usecase.CreateOrderItem{{ProductID: tee.ID, Quantity: 2}, {ProductID: hat.ID, Quantity: 1}}
+	order, err := f.orders.CreateOrder(ctx, f.user.ID, input)
+	if err != nil {
+		t.Fatal(err)
+	}
+	order, err = f.orders.TransitionStatus(ctx, order.ID, f.user.ID, entity.OrderStatusShipped, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	payment := order.Payments[len(order.Payments)-1]
+	event := func(id string, amount int) *entity.PaymentEvent {
+		return &entity.PaymentEvent{
+			Provider:          payment.Provider,
+			EventID:           id,
+			Type:              entity.PaymentEventRefunded,
+			ProviderPaymentID: payment.ProviderPaymentID,
+			Amount:            amount,
+		}
+	}
+
+	if err := refunds.ApplyRefundEvent(ctx, event("evt_partial_1", 7000)); err != nil {
+		t.Fatalf("ApplyRefundEvent: %v", err)
+	}
+	got, err := f.orderRepo.FindByID(ctx, order.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Status != entity.OrderStatusShipped {
+		t.Errorf("一部の返金後の注文ステータス = %s, want %s", got.Status, entity.OrderStatusShipped)
+	}
+	if len(got.Refunds) != 1 || got.Refunds[0].Amount != 7000 {
+		t.Fatalf("返金 = %+v, want 7000 円の返金 1 件", got.Refunds)
+	}
+	sum := 0
+	for _, item := range got.Refunds[0].Items {
+		sum += item.Amount
+	}
+	if sum != 7000 {
+		t.Errorf("明細ごとの返金額の合計 = %d, want 7000", sum)
+	}
+	// 未返金の金額の全てを返金した明細は返品、一部だけの明細は返品を伴わない減額になる
+	for _, item := range got.OrderItems {
+		if item.RefundedAmount == item.Price*item.Quantity && item.RefundedQuantity != item.Quantity ||
+			item.RefundedAmount < item.Price*item.Quantity && item.RefundedQuantity != 0 {
+			t.Errorf("明細 %s の返金 = 金額 %d・数量 %d (単価 %d × %d)", item.ID, item.RefundedAmount, item.RefundedQuantity, item.Price, item.Quantity)
+		}
+	}
+	if last := got.Payments[len(got.Payments)-1]; last.Status != entity.PaymentStatusCaptured || last.RefundedAmount != 7000 {
+		t.Errorf("決済 = %s (返金額 %d), want captured (返金額 7000)", last.Status, last.RefundedAmount)
+	}
+
+	// 未返金の残額 (3000 円) を超える返金は記録しない
+	for _, amount := range []int{0, 5000} {
+		if err := refunds.ApplyRefundEvent(ctx, event("evt_partial_invalid", amount)); !errors.Is(err, usecase.ErrRefundAmountMismatch) {
+			t.Errorf("%d 円の ApplyRefundEvent = %v, want %v", amount, err, usecase.ErrRefundAmountMismatch)
+		}
+	}
+
+	if err := refunds.ApplyRefundEvent(ctx, event("evt_partial_2", 3000)); err != nil {
+		t.Fatalf("ApplyRefundEvent: %v", err)
+	}
+	got, err = f.orderRepo.FindByID(ctx, order.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Status != entity.OrderStatusRefunded {
+		t.Errorf("全額の返金後の注文ステータス = %s, want %s", got.Status, entity.OrderStatusRefunded)
+	}
+	if len(got.Refunds) != 2 {
+		t.Errorf("返金 = %d 件, want 2 件", len(got.Refunds))
+	}
+	for _, item := range got.OrderItems {
+		if item.RefundableAmount() != 0 {
+			t.Errorf("明細 %s に未返金の金額 %d が残っています", item.ID, item.RefundableAmount())
+		}
+	}
+	if last := got.Payments[len(got.Payments)-1]; last.Status != entity.PaymentStatusRefunded || last.RefundedAmount != 10000 {
+		t.Errorf("決済 = %s (返金額 %d), want refunded (返金額 10000)", last.Status, last.RefundedAmount)
+	}
+}
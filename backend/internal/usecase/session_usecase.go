@@ -0,0 +1,163 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"time"
+
+	"github.com/sotaheavymetal21/rabbit-cart/backend/internal/domain/entity"
+	"github.com/sotaheavymetal21/rabbit-cart/backend/internal/domain/repository"
+)
+
+// ErrSessionNotFound は指定されたセッションが存在しない、または既に失効している場合のエラーです
+var ErrSessionNotFound = entity.NewNotFoundError("session_not_found", "セッションが見つかりません")
+
+// sessionTouchInterval は最終利用日時を更新する間隔です (リクエストごとの書き込みを避けるため)
+const sessionTouchInterval = time.Minute
+
+// maxUserAgentLength は保存する User-Agent の最大長です
+const maxUserAgentLength = 512
+
+// sessionIDPattern はセッションID (UUID) の形式です
+// 形式が異なる ID で検索するとデータベースがエラーを返すため、事前に弾きます
+var sessionIDPattern = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+
+// SessionUseCase はログインセッションの管理に関するビジネスロジックを定義するインターフェースです
+type SessionUseCase interface {
+	// Start はログインしたユーザーの新しいセッションを作成します
+	Start(ctx context.Context, userID, ip, userAgent string) (*entity.UserSession, error)
+	// Validate はセッションが userID のもので、失効していないことを確認し、最終利用日時を更新します
+	// 失効している場合は entity.ErrSessionRevoked を返します
+	Validate(ctx context.Context, sessionID, userID, ip string) error
+	// List はユーザーの有効なセッションを返します (currentID のセッションには Current を付けます)
+	List(ctx context.Context, userID, currentID string) ([]*entity.UserSession, error)
+	// Revoke はユーザーのセッションを失効させます
+	Revoke(ctx context.Context, userID, sessionID string) error
+	// RevokeAll はユーザーの全てのセッションを失効させ、失効させた数を返します
+	RevokeAll(ctx context.Context, userID string) (int64, error)
+}
+
+type sessionUseCase struct {
+	sessionRepo repository.SessionRepository
+}
+
+// NewSessionUseCase は SessionUseCase の実装を生成します
+func NewSessionUseCase(sessionRepo repository.SessionRepository) SessionUseCase {
+	return &sessionUseCase{sessionRepo: sessionRepo}
+}
+
+// Start はログインしたユーザーの新しいセッションを作成します
+func (u *sessionUseCase) Start(ctx context.Context, userID, ip, userAgent string) (*entity.UserSession, error) {
+	if len(userAgent) > maxUserAgentLength {
+		userAgent = userAgent[:maxUserAgentLength]
+	}
+	session := &entity.UserSession{
+		UserID:     userID,
+		Device:     deviceLabel(userAgent),
+		IPAddress:  ip,
+		UserAgent:  userAgent,
+		LastSeenAt: time.Now(),
+	}
+	if err := u.sessionRepo.Create(ctx, session); err != nil {
+		return nil, err
+	}
+	return session, nil
+}
+
+// Validate はセッションが有効であることを確認し、最終利用日時を更新します
+func (u *sessionUseCase) Validate(ctx context.Context, sessionID, userID, ip string) error {
+	if !sessionIDPattern.MatchString(sessionID) {
+		return entity.ErrSessionRevoked
+	}
+	session, err := u.sessionRepo.FindByID(ctx, sessionID)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return entity.ErrSessionRevoked
+		}
+		return err
+	}
+	if session.UserID != userID || session.RevokedAt != nil {
+		return entity.ErrSessionRevoked
+	}
+
+	now := time.Now()
+	if now.Sub(session.LastSeenAt) >= sessionTouchInterval || session.IPAddress != ip {
+		return u.sessionRepo.Touch(ctx, sessionID, ip, now)
+	}
+	return nil
+}
+
+// List はユーザーの有効なセッションを返します
+func (u *sessionUseCase) List(ctx context.Context, userID, currentID string) ([]*entity.UserSession, error) {
+	sessions, err := u.sessionRepo.FindActiveByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	for _, s := range sessions {
+		s.Current = s.ID == currentID
+	}
+	return sessions, nil
+}
+
+// Revoke はユーザーのセッションを失効させます
+func (u *sessionUseCase) Revoke(ctx context.Context, userID, sessionID string) error {
+	if !sessionIDPattern.MatchString(sessionID) {
+		return ErrSessionNotFound
+	}
+	revoked, err := u.sessionRepo.Revoke(ctx, userID, sessionID)
+	if err != nil {
+		return err
+	}
+	if !revoked {
+		return ErrSessionNotFound
+	}
+	return nil
+}
+
+// RevokeAll はユーザーの全てのセッションを失効させます
+func (u *sessionUseCase) RevokeAll(ctx context.Context, userID string) (int64, error) {
+	return u.sessionRepo.RevokeAllByUserID(ctx, userID)
+}
+
+var (
+	browserPatterns = []struct {
+		name    string
+		pattern *regexp.Regexp
+	}{
+		// Edge・Opera は Chrome を、Chrome は Safari を User-Agent に含むため、先に判定する
+		{"Edge", regexp.MustCompile(`Edg(e|A|iOS)?/`)},
+		{"Opera", regexp.MustCompile(`OPR/`)},
+		{"Firefox", regexp.MustCompile(`Firefox/|FxiOS/`)},
+		{"Chrome", regexp.MustCompile(`Chrome/|CriOS/`)},
+		{"Safari", regexp.MustCompile(`Safari/`)},
+	}
+	osPatterns = []struct {
+		name    string
+		pattern *regexp.Regexp
+	}{
+		{"iOS", regexp.MustCompile(`iPhone|iPad|iPod`)},
+		{"Android", regexp.MustCompile(`Android`)},
+		{"Windows", regexp.MustCompile(`Windows`)},
+		{"macOS", regexp.MustCompile(`Macintosh|Mac OS X`)},
+		{"Linux", regexp.MustCompile(`Linux`)},
+	}
+)
+
+// deviceLabel は User-Agent からセッション一覧に表示するブラウザと OS の名前を判定します
+func deviceLabel(userAgent string) string {
+	browser, os := "不明なブラウザ", "不明な OS"
+	for _, b := range browserPatterns {
+		if b.pattern.MatchString(userAgent) {
+			browser = b.name
+			break
+		}
+	}
+	for _, o := range osPatterns {
+		if o.pattern.MatchString(userAgent) {
+			os = o.name
+			break
+		}
+	}
+	return browser + " (" + os + ")"
+}
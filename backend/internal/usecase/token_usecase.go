@@ -0,0 +1,207 @@
+package usecase
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"time"
+
+	"github.com/sotaheavymetal21/rabbit-cart/backend/internal/domain/entity"
+	"github.com/sotaheavymetal21/rabbit-cart/backend/internal/domain/repository"
+)
+
+// TokenUseCase は JWT アクセストークンとリフレッシュトークンに関するビジネスロジックを定義するインターフェースです
+type TokenUseCase interface {
+	// Issue はログインセッション sessionID のユーザーに新しいトークンの組を発行します
+	// リフレッシュトークンの系列はセッションIDで識別し、セッションを失効させると系列のトークンも失効します
+	Issue(ctx context.Context, user *entity.User, sessionID string) (*entity.TokenPair, error)
+	// Refresh はリフレッシュトークンを使って新しいトークンの組を発行します
+	// 使用したリフレッシュトークンは失効し、失効済みのトークンが使われた場合は同じ系列のトークンを全て失効させます
+	Refresh(ctx context.Context, refreshToken string) (*entity.TokenPair, error)
+	// Authenticate はアクセストークンを検証し、クレームを返します
+	Authenticate(ctx context.Context, accessToken string) (*entity.AccessTokenClaims, error)
+	// Revoke はアクセストークンとリフレッシュトークンを失効させます (どちらも省略可能)
+	Revoke(ctx context.Context, claims *entity.AccessTokenClaims, refreshToken string) error
+}
+
+// TokenConfig はトークンの有効期間の設定です
+type TokenConfig struct {
+	AccessTTL  time.Duration
+	RefreshTTL time.Duration
+}
+
+type tokenUseCase struct {
+	tokenRepo repository.TokenRepository
+	userRepo  repository.UserRepository
+	signer    repository.TokenSigner
+	config    TokenConfig
+}
+
+// NewTokenUseCase は TokenUseCase の実装を生成します
+func NewTokenUseCase(tokenRepo repository.TokenRepository, userRepo repository.UserRepository, signer repository.TokenSigner, config TokenConfig) TokenUseCase {
+	if config.AccessTTL <= 0 {
+		config.AccessTTL = 15 * time.Minute
+	}
+	if config.RefreshTTL <= 0 {
+		config.RefreshTTL = 30 * 24 * time.Hour
+	}
+	return &tokenUseCase{
+		tokenRepo: tokenRepo,
+		userRepo:  userRepo,
+		signer:    signer,
+		config:    config,
+	}
+}
+
+// Issue はログインセッション sessionID のユーザーに新しいトークンの組を発行します
+func (u *tokenUseCase) Issue(ctx context.Context, user *entity.User, sessionID string) (*entity.TokenPair, error) {
+	refreshToken, record, err := u.newRefreshToken(user.ID, sessionID)
+	if err != nil {
+		return nil, err
+	}
+	if err := u.tokenRepo.CreateRefreshToken(ctx, record); err != nil {
+		return nil, err
+	}
+	return u.pair(user, sessionID, refreshToken)
+}
+
+// Refresh はリフレッシュトークンを使って新しいトークンの組を発行します
+func (u *tokenUseCase) Refresh(ctx context.Context, refreshToken string) (*entity.TokenPair, error) {
+	current, err := u.tokenRepo.FindRefreshToken(ctx, hashToken(refreshToken))
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, entity.ErrInvalidToken
+		}
+		return nil, err
+	}
+	if current.RevokedAt != nil {
+		return nil, u.revokeReused(ctx, current)
+	}
+	if time.Now().After(current.ExpiresAt) {
+		return nil, entity.ErrTokenExpired
+	}
+
+	user, err := u.userRepo.FindByID(ctx, current.UserID)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, entity.ErrInvalidToken
+		}
+		return nil, err
+	}
+
+	nextToken, next, err := u.newRefreshToken(user.ID, current.FamilyID)
+	if err != nil {
+		return nil, err
+	}
+	if err := u.tokenRepo.RotateRefreshToken(ctx, current, next); err != nil {
+		if errors.Is(err, entity.ErrRefreshTokenReused) {
+			return nil, u.revokeReused(ctx, current)
+		}
+		return nil, err
+	}
+	return u.pair(user, current.FamilyID, nextToken)
+}
+
+// Authenticate はアクセストークンを検証し、クレームを返します
+func (u *tokenUseCase) Authenticate(ctx context.Context, accessToken string) (*entity.AccessTokenClaims, error) {
+	claims, err := u.signer.Verify(accessToken)
+	if err != nil {
+		return nil, err
+	}
+	revoked, err := u.tokenRepo.IsAccessTokenRevoked(ctx, claims.ID)
+	if err != nil {
+		return nil, err
+	}
+	if revoked {
+		return nil, entity.ErrTokenRevoked
+	}
+	return claims, nil
+}
+
+// Revoke はアクセストークンとリフレッシュトークンを失効させます
+func (u *tokenUseCase) Revoke(ctx context.Context, claims *entity.AccessTokenClaims, refreshToken string) error {
+	if claims != nil {
+		if err := u.tokenRepo.RevokeAccessToken(ctx, claims.ID, claims.ExpiresAtTime()); err != nil {
+			return err
+		}
+	}
+	if refreshToken == "" {
+		return nil
+	}
+	current, err := u.tokenRepo.FindRefreshToken(ctx, hashToken(refreshToken))
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil
+		}
+		return err
+	}
+	// 他人のリフレッシュトークンは失効させない
+	if claims != nil && claims.Subject != current.UserID {
+		return nil
+	}
+	return u.tokenRepo.RevokeRefreshTokenFamily(ctx, current.FamilyID)
+}
+
+// revokeReused は使用済みのリフレッシュトークンが再利用された場合に、漏洩の可能性があるとみなして系列ごと失効させます
+func (u *tokenUseCase) revokeReused(ctx context.Context, token *entity.RefreshToken) error {
+	if err := u.tokenRepo.RevokeRefreshTokenFamily(ctx, token.FamilyID); err != nil {
+		return err
+	}
+	return entity.ErrRefreshTokenReused
+}
+
+func (u *tokenUseCase) newRefreshToken(userID, familyID string) (string, *entity.RefreshToken, error) {
+	token, err := randomToken(32)
+	if err != nil {
+		return "", nil, err
+	}
+	return token, &entity.RefreshToken{
+		UserID:    userID,
+		FamilyID:  familyID,
+		TokenHash: hashToken(token),
+		ExpiresAt: time.Now().Add(u.config.RefreshTTL),
+	}, nil
+}
+
+func (u *tokenUseCase) pair(user *entity.User, sessionID, refreshToken string) (*entity.TokenPair, error) {
+	jti, err := randomToken(16)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	accessToken, err := u.signer.Sign(&entity.AccessTokenClaims{
+		Subject:   user.ID,
+		ID:        jti,
+		SessionID: sessionID,
+		Role:      user.Role,
+		IssuedAt:  now.Unix(),
+		ExpiresAt: now.Add(u.config.AccessTTL).Unix(),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &entity.TokenPair{
+		AccessToken:  accessToken,
+		RefreshToken: refreshToken,
+		TokenType:    "Bearer",
+		ExpiresIn:    int(u.config.AccessTTL.Seconds()),
+	}, nil
+}
+
+// randomToken は n バイトの乱数を base64url で返します
+func randomToken(n int) (string, error) {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// hashToken はトークンを保存用の SHA-256 ハッシュに変換します
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
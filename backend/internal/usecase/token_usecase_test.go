@@ -0,0 +1,243 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/sotaheavymetal21/rabbit-cart/backend/internal/domain/entity"
+	domainrepository "github.com/sotaheavymetal21/rabbit-cart/backend/internal/domain/repository"
+	"github.com/sotaheavymetal21/rabbit-cart/backend/internal/infrastructure/jwt"
+	"github.com/sotaheavymetal21/rabbit-cart/backend/internal/infrastructure/repository"
+	"github.com/sotaheavymetal21/rabbit-cart/backend/internal/usecase"
+)
+
+// stubUserRepository は FindByID だけを実装した UserRepository です
+type stubUserRepository struct {
+	domainrepository.UserRepository
+	users map[string]*entity.User
+}
+
+func (r *stubUserRepository) FindByID(ctx context.Context, id string) (*entity.User, error) {
+	user, ok := r.users[id]
+	if !ok {
+		return nil, domainrepository.ErrNotFound
+	}
+	return user, nil
+}
+
+type tokenFixture struct {
+	tokens   usecase.TokenUseCase
+	sessions usecase.SessionUseCase
+	alice    *entity.User
+	bob      *entity.User
+}
+
+func newTokenFixture(t *testing.T) *tokenFixture {
+	t.Helper()
+	tokenRepo, sessionRepo := repository.NewMemoryTokenStore()
+	signer, err := jwt.NewHMACSigner(map[string][]byte{"k1": []byte("test-secret-0123456789abcdefghijklmn")}, "k1", "rabbit-cart")
+	if err != nil {
+		t.Fatal(err)
+	}
+	alice := &entity.User{ID: "00000000-0000-4000-8000-00000000000a", Role: entity.UserRoleCustomer}
+	bob := &entity.User{ID: "00000000-0000-4000-8000-00000000000b", Role: entity.UserRoleCustomer}
+	users := &stubUserRepository{users: map[string]*entity.User{alice.ID: alice, bob.ID: bob}}
+	return &tokenFixture{
+		tokens:   usecase.NewTokenUseCase(tokenRepo, users, signer, usecase.TokenConfig{AccessTTL: 15 * time.Minute, RefreshTTL: 24 * time.Hour}),
+		sessions: usecase.NewSessionUseCase(sessionRepo),
+		alice:    alice,
+		bob:      bob,
+	}
+}
+
+// login はセッションを開始してトークンを発行します
+func (f *tokenFixture) login(t *testing.T, user *entity.User) (*entity.UserSession, *entity.TokenPair) {
+	t.Helper()
+	ctx := context.Background()
+	session, err := f.sessions.Start(ctx, user.ID, "192.0.2.1", "Mozilla/5.0 (Macintosh) Safari/605.1.15")
+	if err != nil {
+		t.Fatal(err)
+	}
+	pair, err := f.tokens.Issue(ctx, user, session.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return session, pair
+}
+
+func TestTokenRefreshRotation(t *testing.T) {
+	f := newTokenFixture(t)
+	ctx := context.Background()
+	session, first := f.login(t, f.alice)
+
+	second, err := f.tokens.Refresh(ctx, first.RefreshToken)
+	if err != nil {
+		t.Fatalf("Refresh: %v", err)
+	}
+	if second.RefreshToken == first.RefreshToken || second.AccessToken == first.AccessToken {
+		t.Error("リフレッシュ後も同じトークンが返されました")
+	}
+
+	claims, err := f.tokens.Authenticate(ctx, second.AccessToken)
+	if err != nil {
+		t.Fatalf("Authenticate: %v", err)
+	}
+	if claims.Subject != f.alice.ID || claims.SessionID != session.ID {
+		t.Errorf("claims = %+v, want sub=%s sid=%s", claims, f.alice.ID, session.ID)
+	}
+
+	// 新しいリフレッシュトークンで続けてリフレッシュできる
+	if _, err := f.tokens.Refresh(ctx, second.RefreshToken); err != nil {
+		t.Errorf("2回目の Refresh: %v", err)
+	}
+}
+
+func TestTokenRefreshUnknownToken(t *testing.T) {
+	f := newTokenFixture(t)
+	if _, err := f.tokens.Refresh(context.Background(), "unknown"); !errors.Is(err, entity.ErrInvalidToken) {
+		t.Errorf("Refresh = %v, want ErrInvalidToken", err)
+	}
+}
+
+func TestTokenRefreshReuseRevokesFamily(t *testing.T) {
+	f := newTokenFixture(t)
+	ctx := context.Background()
+	_, first := f.login(t, f.alice)
+	_, other := f.login(t, f.alice) // 別の端末のログイン
+
+	second, err := f.tokens.Refresh(ctx, first.RefreshToken)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// 使用済みのトークンが再び使われたら漏洩とみなす
+	if _, err := f.tokens.Refresh(ctx, first.RefreshToken); !errors.Is(err, entity.ErrRefreshTokenReused) {
+		t.Fatalf("再利用: Refresh = %v, want ErrRefreshTokenReused", err)
+	}
+	// 同じ系列の最新のトークンも失効している
+	if _, err := f.tokens.Refresh(ctx, second.RefreshToken); !errors.Is(err, entity.ErrRefreshTokenReused) {
+		t.Errorf("系列の最新のトークン: Refresh = %v, want ErrRefreshTokenReused", err)
+	}
+	// 別の系列には影響しない
+	if _, err := f.tokens.Refresh(ctx, other.RefreshToken); err != nil {
+		t.Errorf("別の系列のトークン: Refresh = %v", err)
+	}
+}
+
+func TestTokenRefreshConcurrentUseSucceedsOnce(t *testing.T) {
+	f := newTokenFixture(t)
+	ctx := context.Background()
+	_, pair := f.login(t, f.alice)
+
+	const n = 10
+	var wg sync.WaitGroup
+	errs := make([]error, n)
+	for i := range n {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, errs[i] = f.tokens.Refresh(ctx, pair.RefreshToken)
+		}()
+	}
+	wg.Wait()
+
+	succeeded := 0
+	for _, err := range errs {
+		if err == nil {
+			succeeded++
+		} else if !errors.Is(err, entity.ErrRefreshTokenReused) {
+			t.Errorf("Refresh = %v, want ErrRefreshTokenReused", err)
+		}
+	}
+	if succeeded != 1 {
+		t.Errorf("成功したリフレッシュ = %d, want 1", succeeded)
+	}
+}
+
+func TestTokenRevoke(t *testing.T) {
+	f := newTokenFixture(t)
+	ctx := context.Background()
+	_, pair := f.login(t, f.alice)
+
+	claims, err := f.tokens.Authenticate(ctx, pair.AccessToken)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := f.tokens.Revoke(ctx, claims, pair.RefreshToken); err != nil {
+		t.Fatalf("Revoke: %v", err)
+	}
+	if _, err := f.tokens.Authenticate(ctx, pair.AccessToken); !errors.Is(err, entity.ErrTokenRevoked) {
+		t.Errorf("Authenticate = %v, want ErrTokenRevoked", err)
+	}
+	if _, err := f.tokens.Refresh(ctx, pair.RefreshToken); err == nil {
+		t.Error("ログアウト後のリフレッシュが成功しました")
+	}
+}
+
+func TestSessionRevokeAll(t *testing.T) {
+	f := newTokenFixture(t)
+	ctx := context.Background()
+	laptop, laptopPair := f.login(t, f.alice)
+	_, phonePair := f.login(t, f.alice)
+	bobSession, bobPair := f.login(t, f.bob)
+
+	count, err := f.sessions.RevokeAll(ctx, f.alice.ID)
+	if err != nil {
+		t.Fatalf("RevokeAll: %v", err)
+	}
+	if count != 2 {
+		t.Errorf("失効させたセッション = %d, want 2", count)
+	}
+
+	if err := f.sessions.Validate(ctx, laptop.ID, f.alice.ID, "192.0.2.1"); !errors.Is(err, entity.ErrSessionRevoked) {
+		t.Errorf("Validate = %v, want ErrSessionRevoked", err)
+	}
+	for name, pair := range map[string]*entity.TokenPair{"laptop": laptopPair, "phone": phonePair} {
+		if _, err := f.tokens.Refresh(ctx, pair.RefreshToken); err == nil {
+			t.Errorf("%s: 失効させたセッションのリフレッシュが成功しました", name)
+		}
+	}
+	sessions, err := f.sessions.List(ctx, f.alice.ID, laptop.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(sessions) != 0 {
+		t.Errorf("有効なセッション = %d 件, want 0 件", len(sessions))
+	}
+
+	// 他のユーザーには影響しない
+	if err := f.sessions.Validate(ctx, bobSession.ID, f.bob.ID, "192.0.2.1"); err != nil {
+		t.Errorf("他のユーザーの Validate = %v", err)
+	}
+	if _, err := f.tokens.Refresh(ctx, bobPair.RefreshToken); err != nil {
+		t.Errorf("他のユーザーの Refresh = %v", err)
+	}
+}
+
+func TestSessionRevokeOne(t *testing.T) {
+	f := newTokenFixture(t)
+	ctx := context.Background()
+	laptop, laptopPair := f.login(t, f.alice)
+	phone, phonePair := f.login(t, f.alice)
+
+	// 他のユーザーのセッションは失効させられない
+	if err := f.sessions.Revoke(ctx, f.bob.ID, laptop.ID); !errors.Is(err, usecase.ErrSessionNotFound) {
+		t.Errorf("他のユーザーの Revoke = %v, want ErrSessionNotFound", err)
+	}
+
+	if err := f.sessions.Revoke(ctx, f.alice.ID, laptop.ID); err != nil {
+		t.Fatalf("Revoke: %v", err)
+	}
+	if _, err := f.tokens.Refresh(ctx, laptopPair.RefreshToken); err == nil {
+		t.Error("失効させたセッションのリフレッシュが成功しました")
+	}
+	if err := f.sessions.Validate(ctx, phone.ID, f.alice.ID, "192.0.2.1"); err != nil {
+		t.Errorf("残りのセッションの Validate = %v", err)
+	}
+	if _, err := f.tokens.Refresh(ctx, phonePair.RefreshToken); err != nil {
+		t.Errorf("残りのセッションの Refresh = %v", err)
+	}
+}
@@ -0,0 +1,207 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/sotaheavymetal21/rabbit-cart/backend/internal/domain/entity"
+	"github.com/sotaheavymetal21/rabbit-cart/backend/internal/domain/repository"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// maxDisplayNameLength は表示名の最大文字数です
+const maxDisplayNameLength = 50
+
+// phonePattern は電話番号として受け付ける形式です (数字・ハイフン・括弧・先頭の +)
+var phonePattern = regexp.MustCompile(`^\+?[0-9()\- ]{6,20}$`)
+
+var (
+	// ErrIncorrectPassword は確認のために入力された現在のパスワードが正しくない場合のエラーです
+	ErrIncorrectPassword = entity.NewValidationError("incorrect_password", "現在のパスワードが正しくありません")
+	// ErrEmailAlreadyInUse は変更先のメールアドレスが既に使われている場合のエラーです
+	ErrEmailAlreadyInUse = entity.NewConflictError("email_already_in_use", "このメールアドレスは既に登録されています")
+	// ErrSameEmail は変更先のメールアドレスが現在と同じ場合のエラーです
+	ErrSameEmail = entity.NewFieldError("same_email", "email", "現在と同じメールアドレスです")
+	// ErrInvalidProfile はプロフィールの入力内容が正しくない場合のエラーです
+	ErrInvalidProfile = entity.NewValidationError("invalid_profile", "プロフィールの入力内容が正しくありません")
+	// ErrInvalidLocale は対応していない言語が指定された場合のエラーです
+	ErrInvalidLocale = entity.NewFieldError("invalid_locale", "locale", "対応していない言語です (ja または en を指定してください)")
+)
+
+// UserUseCase はログインユーザー自身のアカウント管理に関するビジネスロジックを定義するインターフェースです
+type UserUseCase interface {
+	// GetProfile はユーザーの情報を返します
+	GetProfile(ctx context.Context, userID string) (*entity.User, error)
+	// UpdateProfile は表示名・電話番号・言語を更新します (nil の項目は変更しません)
+	UpdateProfile(ctx context.Context, userID string, input UpdateProfileInput) (*entity.User, error)
+	// ChangePassword は現在のパスワードを確認して新しいパスワードに変更します
+	// パスワードが未設定のユーザー (外部 ID プロバイダーで登録) は現在のパスワードの確認を省略します
+	// currentSessionID 以外のログインセッションは全て失効します
+	ChangePassword(ctx context.Context, userID, currentSessionID, currentPassword, newPassword string) error
+	// RequestEmailChange は現在のパスワードを確認し、新しいメールアドレスに確認用のリンクを送信します
+	// リンクから確認が済むまでメールアドレスは変更されません
+	RequestEmailChange(ctx context.Context, userID, password, newEmail string) error
+	// DeleteAccount は現在のパスワードを確認してアカウントを削除します (注文は匿名化して残します)
+	DeleteAccount(ctx context.Context, userID, password string) error
+}
+
+// UpdateProfileInput はプロフィール更新の入力です
+type UpdateProfileInput struct {
+	DisplayName *string `json:"display_name"`
+	Phone       *string `json:"phone"`
+	Locale      *string `json:"locale"` // 空文字の場合は Accept-Language に従います
+}
+
+// UserConfig はメールアドレス変更の確認メールの設定です
+type UserConfig struct {
+	VerificationURL string
+	VerificationTTL time.Duration
+}
+
+type userUseCase struct {
+	userRepo         repository.UserRepository
+	verificationRepo repository.EmailVerificationRepository
+	mailer           repository.Mailer
+	config           UserConfig
+}
+
+// NewUserUseCase は UserUseCase の実装を生成します
+func NewUserUseCase(userRepo repository.UserRepository, verificationRepo repository.EmailVerificationRepository, mailer repository.Mailer, config UserConfig) UserUseCase {
+	if config.VerificationTTL <= 0 {
+		config.VerificationTTL = 24 * time.Hour
+	}
+	return &userUseCase{
+		userRepo:         userRepo,
+		verificationRepo: verificationRepo,
+		mailer:           mailer,
+		config:           config,
+	}
+}
+
+// GetProfile はユーザーの情報を返します
+func (u *userUseCase) GetProfile(ctx context.Context, userID string) (*entity.User, error) {
+	return u.userRepo.FindByID(ctx, userID)
+}
+
+// UpdateProfile は表示名・電話番号・言語を更新します
+func (u *userUseCase) UpdateProfile(ctx context.Context, userID string, input UpdateProfileInput) (*entity.User, error) {
+	user, err := u.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	displayName, phone, locale := user.DisplayName, user.Phone, user.Locale
+	if input.DisplayName != nil {
+		displayName = strings.TrimSpace(*input.DisplayName)
+		if utf8.RuneCountInString(displayName) > maxDisplayNameLength {
+			return nil, ErrInvalidProfile
+		}
+	}
+	if input.Phone != nil {
+		phone = strings.TrimSpace(*input.Phone)
+		if phone != "" && !phonePattern.MatchString(phone) {
+			return nil, ErrInvalidProfile
+		}
+	}
+	if input.Locale != nil {
+		locale = entity.Locale(strings.TrimSpace(*input.Locale))
+		if locale != "" && !locale.IsValid() {
+			return nil, ErrInvalidLocale
+		}
+	}
+
+	if err := u.userRepo.UpdateProfile(ctx, userID, displayName, phone, locale); err != nil {
+		return nil, err
+	}
+	user.DisplayName, user.Phone, user.Locale = displayName, phone, locale
+	return user, nil
+}
+
+// ChangePassword は現在のパスワードを確認して新しいパスワードに変更します
+func (u *userUseCase) ChangePassword(ctx context.Context, userID, currentSessionID, currentPassword, newPassword string) error {
+	user, err := u.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if err := confirmPassword(user, currentPassword); err != nil {
+		return err
+	}
+
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(newPassword), bcrypt.DefaultCost)
+	if err != nil {
+		return err
+	}
+	return u.userRepo.UpdatePassword(ctx, userID, string(hashedPassword), currentSessionID)
+}
+
+// RequestEmailChange は新しいメールアドレスに確認用のリンクを送信します
+func (u *userUseCase) RequestEmailChange(ctx context.Context, userID, password, newEmail string) error {
+	user, err := u.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if err := confirmPassword(user, password); err != nil {
+		return err
+	}
+	if strings.EqualFold(user.Email, newEmail) {
+		return ErrSameEmail
+	}
+	if _, err := u.userRepo.FindByEmail(ctx, newEmail); err == nil {
+		return ErrEmailAlreadyInUse
+	} else if !errors.Is(err, repository.ErrNotFound) {
+		return err
+	}
+
+	token, err := randomToken(32)
+	if err != nil {
+		return err
+	}
+	if err := u.verificationRepo.Create(ctx, &entity.EmailVerificationToken{
+		UserID:    user.ID,
+		NewEmail:  newEmail,
+		TokenHash: hashToken(token),
+		ExpiresAt: time.Now().Add(u.config.VerificationTTL),
+	}); err != nil {
+		return err
+	}
+
+	link := u.config.VerificationURL + "?token=" + url.QueryEscape(token)
+	return u.mailer.Send(ctx, &entity.Mail{
+		To:      newEmail,
+		Subject: "【Rabbit Cart】メールアドレス変更の確認",
+		Body: fmt.Sprintf("メールアドレスの変更のリクエストを受け付けました。\n"+
+			"以下のリンクから %d 時間以内に確認を完了すると、ログインに使うメールアドレスがこのアドレスに変更されます。\n\n%s\n\n"+
+			"このメールに心当たりが無い場合は、このまま破棄してください。\n",
+			int(u.config.VerificationTTL.Hours()), link),
+	})
+}
+
+// DeleteAccount は現在のパスワードを確認してアカウントを削除します
+func (u *userUseCase) DeleteAccount(ctx context.Context, userID, password string) error {
+	user, err := u.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if err := confirmPassword(user, password); err != nil {
+		return err
+	}
+	return u.userRepo.Delete(ctx, userID)
+}
+
+// confirmPassword は重要な操作の前に現在のパスワードを確認します
+// パスワードが未設定のユーザーはログインしていること自体を本人確認とみなします
+func confirmPassword(user *entity.User, password string) error {
+	if !user.HasPassword() {
+		return nil
+	}
+	if err := bcrypt.CompareHashAndPassword([]byte(*user.PasswordHash), []byte(password)); err != nil {
+		return ErrIncorrectPassword
+	}
+	return nil
+}
@@ -0,0 +1,181 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/sotaheavymetal21/rabbit-cart/backend/internal/domain/entity"
+	"github.com/sotaheavymetal21/rabbit-cart/backend/internal/infrastructure/mail"
+	"github.com/sotaheavymetal21/rabbit-cart/backend/internal/usecase"
+	"golang.org/x/crypto/bcrypt"
+)
+
+type accountFixture struct {
+	accounts      usecase.UserUseCase
+	users         *memoryUserRepository
+	verifications *memoryVerificationRepository
+	mailer        *mail.MemoryMailer
+	alice         *entity.User
+}
+
+func newAccountFixture(t *testing.T) *accountFixture {
+	t.Helper()
+	hash, err := bcrypt.GenerateFromPassword([]byte("current-password"), bcrypt.MinCost)
+	if err != nil {
+		t.Fatal(err)
+	}
+	passwordHash := string(hash)
+	alice := &entity.User{ID: "alice", Email: "alice@example.com", PasswordHash: &passwordHash, DisplayName: "アリス"}
+	users := &memoryUserRepository{users: map[string]*entity.User{
+		alice.ID: alice,
+		"bob":    {ID: "bob", Email: "bob@example.com"},
+	}}
+	verifications := &memoryVerificationRepository{users: users}
+	mailer := mail.NewMemoryMailer()
+	return &accountFixture{
+		accounts:      usecase.NewUserUseCase(users, verifications, mailer, usecase.UserConfig{VerificationURL: "http://localhost:3000/verify"}),
+		users:         users,
+		verifications: verifications,
+		mailer:        mailer,
+		alice:         alice,
+	}
+}
+
+func TestUpdateProfile(t *testing.T) {
+	ptr := func(s string) *string { return &s }
+	tests := []struct {
+		name      string
+		input     usecase.UpdateProfileInput
+		wantName  string
+		wantPhone string
+		wantErr   error
+	}{
+		{"前後の空白を除く", usecase.UpdateProfileInput{DisplayName: ptr("  うさぎ  "), Phone: ptr(" 03-1234-5678 ")}, "うさぎ", "03-1234-5678", nil},
+		{"nil の項目は変更しない", usecase.UpdateProfileInput{Phone: ptr("+81 90 1234 5678")}, "アリス", "+81 90 1234 5678", nil},
+		{"空文字で電話番号を消せる", usecase.UpdateProfileInput{Phone: ptr("")}, "アリス", "", nil},
+		{"表示名が長すぎる", usecase.UpdateProfileInput{DisplayName: ptr(strings.Repeat("あ", 51))}, "", "", usecase.ErrInvalidProfile},
+		{"電話番号の形式が正しくない", usecase.UpdateProfileInput{Phone: ptr("090-abcd-efgh")}, "", "", usecase.ErrInvalidProfile},
+		{"対応していない言語", usecase.UpdateProfileInput{Locale: ptr("fr")}, "", "", usecase.ErrInvalidLocale},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newAccountFixture(t)
+			user, err := f.accounts.UpdateProfile(context.Background(), f.alice.ID, tt.input)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("UpdateProfile = %v, want %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if user.DisplayName != tt.wantName || user.Phone != tt.wantPhone {
+				t.Errorf("プロフィール = (%q, %q), want (%q, %q)", user.DisplayName, user.Phone, tt.wantName, tt.wantPhone)
+			}
+		})
+	}
+}
+
+func TestUpdateProfileLocale(t *testing.T) {
+	f := newAccountFixture(t)
+	ctx := context.Background()
+	en, empty := " en ", ""
+
+	user, err := f.accounts.UpdateProfile(ctx, f.alice.ID, usecase.UpdateProfileInput{Locale: &en})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if user.Locale != entity.LocaleEnglish || user.DisplayName != "アリス" {
+		t.Errorf("言語 = %q, 表示名 = %q, want en, アリス", user.Locale, user.DisplayName)
+	}
+
+	// 空文字で Accept-Language に従う設定に戻す
+	user, err = f.accounts.UpdateProfile(ctx, f.alice.ID, usecase.UpdateProfileInput{Locale: &empty})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if user.Locale != "" {
+		t.Errorf("言語 = %q, want 空文字", user.Locale)
+	}
+}
+
+func TestChangePassword(t *testing.T) {
+	f := newAccountFixture(t)
+	ctx := context.Background()
+
+	if err := f.accounts.ChangePassword(ctx, f.alice.ID, "session-1", "wrong-password", "new-password"); !errors.Is(err, usecase.ErrIncorrectPassword) {
+		t.Fatalf("誤ったパスワード: ChangePassword = %v, want ErrIncorrectPassword", err)
+	}
+	if err := f.accounts.ChangePassword(ctx, f.alice.ID, "session-1", "current-password", "new-password"); err != nil {
+		t.Fatalf("ChangePassword: %v", err)
+	}
+	if err := bcrypt.CompareHashAndPassword([]byte(*f.alice.PasswordHash), []byte("new-password")); err != nil {
+		t.Error("新しいパスワードが保存されていません")
+	}
+	if f.users.keptSessionID != "session-1" {
+		t.Errorf("残したセッション = %q, want session-1", f.users.keptSessionID)
+	}
+
+	// 外部 ID プロバイダーで登録したユーザーは現在のパスワードを確認しない
+	if err := f.accounts.ChangePassword(ctx, "bob", "session-2", "", "first-password"); err != nil {
+		t.Errorf("パスワード未設定のユーザーの ChangePassword: %v", err)
+	}
+}
+
+func TestRequestEmailChange(t *testing.T) {
+	ctx := context.Background()
+	tests := []struct {
+		name     string
+		password string
+		newEmail string
+		wantErr  error
+	}{
+		{"誤ったパスワード", "wrong-password", "new@example.com", usecase.ErrIncorrectPassword},
+		{"現在と同じ", "current-password", "Alice@Example.com", usecase.ErrSameEmail},
+		{"他のユーザーが使用中", "current-password", "bob@example.com", usecase.ErrEmailAlreadyInUse},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newAccountFixture(t)
+			if err := f.accounts.RequestEmailChange(ctx, f.alice.ID, tt.password, tt.newEmail); !errors.Is(err, tt.wantErr) {
+				t.Errorf("RequestEmailChange = %v, want %v", err, tt.wantErr)
+			}
+			if len(f.mailer.Sent()) != 0 {
+				t.Error("エラーの場合に確認メールが送信されました")
+			}
+		})
+	}
+
+	// 確認が済むまでメールアドレスは変わらず、確認メールは新しいアドレスに届く
+	f := newAccountFixture(t)
+	if err := f.accounts.RequestEmailChange(ctx, f.alice.ID, "current-password", "new@example.com"); err != nil {
+		t.Fatalf("RequestEmailChange: %v", err)
+	}
+	if f.alice.Email != "alice@example.com" {
+		t.Errorf("確認前にメールアドレスが変更されました: %s", f.alice.Email)
+	}
+	if sent := f.mailer.Sent(); len(sent) != 1 || sent[0].To != "new@example.com" {
+		t.Errorf("送信したメール = %+v, want new@example.com 宛の1通", sent)
+	}
+	if len(f.verifications.tokens) != 1 || f.verifications.tokens[0].NewEmail != "new@example.com" {
+		t.Errorf("確認用トークン = %+v, want NewEmail=new@example.com", f.verifications.tokens)
+	}
+}
+
+func TestDeleteAccount(t *testing.T) {
+	f := newAccountFixture(t)
+	ctx := context.Background()
+
+	if err := f.accounts.DeleteAccount(ctx, f.alice.ID, "wrong-password"); !errors.Is(err, usecase.ErrIncorrectPassword) {
+		t.Fatalf("誤ったパスワード: DeleteAccount = %v, want ErrIncorrectPassword", err)
+	}
+	if _, ok := f.users.users[f.alice.ID]; !ok {
+		t.Fatal("誤ったパスワードでアカウントが削除されました")
+	}
+	if err := f.accounts.DeleteAccount(ctx, f.alice.ID, "current-password"); err != nil {
+		t.Fatalf("DeleteAccount: %v", err)
+	}
+	if _, ok := f.users.users[f.alice.ID]; ok {
+		t.Error("アカウントが削除されていません")
+	}
+}
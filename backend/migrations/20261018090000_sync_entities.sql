@@ -0,0 +1,85 @@
+-- AutoMigrate で作成されていたテーブルをマイグレーションに取り込みます
+-- AutoMigrate で構築済みのデータベースにも適用できるよう、IF NOT EXISTS を付けています
+
+-- Modify "users" table
+ALTER TABLE "users" ADD COLUMN IF NOT EXISTS "role" character varying(20) NOT NULL DEFAULT 'customer';
+
+-- Modify "products" table
+ALTER TABLE "products" ADD COLUMN IF NOT EXISTS "sku" character varying(64) NOT NULL DEFAULT '';
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_products_sku" ON "products" ("sku") WHERE (sku <> '');
+
+-- Create "orders" table
+CREATE TABLE IF NOT EXISTS "orders" (
+  "id" uuid NOT NULL DEFAULT gen_random_uuid(),
+  "user_id" uuid NOT NULL,
+  "total_amount" bigint NOT NULL,
+  "status" character varying(20) NOT NULL DEFAULT 'pending',
+  "address" jsonb NOT NULL,
+  "created_at" timestamptz NULL,
+  "updated_at" timestamptz NULL,
+  PRIMARY KEY ("id")
+);
+CREATE INDEX IF NOT EXISTS "idx_orders_user_id" ON "orders" ("user_id");
+
+-- Create "order_items" table
+CREATE TABLE IF NOT EXISTS "order_items" (
+  "id" uuid NOT NULL DEFAULT gen_random_uuid(),
+  "order_id" uuid NOT NULL,
+  "product_id" uuid NOT NULL,
+  "quantity" bigint NOT NULL,
+  "price" bigint NOT NULL,
+  "created_at" timestamptz NULL,
+  PRIMARY KEY ("id"),
+  CONSTRAINT "fk_orders_order_items" FOREIGN KEY ("order_id") REFERENCES "orders" ("id") ON UPDATE NO ACTION ON DELETE CASCADE,
+  CONSTRAINT "fk_order_items_product" FOREIGN KEY ("product_id") REFERENCES "products" ("id") ON UPDATE NO ACTION ON DELETE NO ACTION
+);
+CREATE INDEX IF NOT EXISTS "idx_order_items_order_id" ON "order_items" ("order_id");
+CREATE INDEX IF NOT EXISTS "idx_order_items_product_id" ON "order_items" ("product_id");
+
+-- Create "order_status_history" table
+CREATE TABLE IF NOT EXISTS "order_status_history" (
+  "id" uuid NOT NULL DEFAULT gen_random_uuid(),
+  "order_id" uuid NOT NULL,
+  "from_status" character varying(20) NOT NULL,
+  "to_status" character varying(20) NOT NULL,
+  "changed_by" uuid NOT NULL,
+  "reason" text NULL,
+  "created_at" timestamptz NULL,
+  PRIMARY KEY ("id"),
+  CONSTRAINT "fk_orders_status_history" FOREIGN KEY ("order_id") REFERENCES "orders" ("id") ON UPDATE NO ACTION ON DELETE CASCADE
+);
+CREATE INDEX IF NOT EXISTS "idx_order_status_history_order_id" ON "order_status_history" ("order_id");
+
+-- Create "carts" table
+CREATE TABLE IF NOT EXISTS "carts" (
+  "id" uuid NOT NULL DEFAULT gen_random_uuid(),
+  "user_id" uuid NOT NULL,
+  "created_at" timestamptz NULL,
+  "updated_at" timestamptz NULL,
+  PRIMARY KEY ("id")
+);
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_carts_user_id" ON "carts" ("user_id");
+
+-- Create "cart_items" table
+CREATE TABLE IF NOT EXISTS "cart_items" (
+  "id" uuid NOT NULL DEFAULT gen_random_uuid(),
+  "cart_id" uuid NOT NULL,
+  "product_id" uuid NOT NULL,
+  "quantity" bigint NOT NULL,
+  "created_at" timestamptz NULL,
+  "updated_at" timestamptz NULL,
+  PRIMARY KEY ("id"),
+  CONSTRAINT "fk_carts_items" FOREIGN KEY ("cart_id") REFERENCES "carts" ("id") ON UPDATE NO ACTION ON DELETE CASCADE,
+  CONSTRAINT "fk_cart_items_product" FOREIGN KEY ("product_id") REFERENCES "products" ("id") ON UPDATE NO ACTION ON DELETE CASCADE
+);
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_cart_items_cart_product" ON "cart_items" ("cart_id", "product_id");
+
+-- Create "product_search_documents" table
+CREATE TABLE IF NOT EXISTS "product_search_documents" (
+  "product_id" uuid NOT NULL,
+  "document" tsvector NOT NULL,
+  "updated_at" timestamptz NOT NULL DEFAULT now(),
+  PRIMARY KEY ("product_id"),
+  CONSTRAINT "fk_product_search_documents_product" FOREIGN KEY ("product_id") REFERENCES "products" ("id") ON UPDATE NO ACTION ON DELETE CASCADE
+);
+CREATE INDEX IF NOT EXISTS "idx_product_search_documents_document" ON "product_search_documents" USING gin ("document");
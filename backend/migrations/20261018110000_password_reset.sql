@@ -0,0 +1,18 @@
+-- Modify "users" table
+ALTER TABLE "users" ADD COLUMN "password_changed_at" timestamptz NULL;
+
+-- Create "password_reset_tokens" table
+CREATE TABLE "password_reset_tokens" (
+  "id" uuid NOT NULL DEFAULT gen_random_uuid(),
+  "user_id" uuid NOT NULL,
+  "token_hash" character varying(64) NOT NULL,
+  "expires_at" timestamptz NOT NULL,
+  "used_at" timestamptz NULL,
+  "created_at" timestamptz NULL,
+  PRIMARY KEY ("id"),
+  CONSTRAINT "fk_password_reset_tokens_user" FOREIGN KEY ("user_id") REFERENCES "users" ("id") ON UPDATE NO ACTION ON DELETE CASCADE
+);
+-- Create index "idx_password_reset_tokens_user_id" to table: "password_reset_tokens"
+CREATE INDEX "idx_password_reset_tokens_user_id" ON "password_reset_tokens" ("user_id");
+-- Create index "idx_password_reset_tokens_token_hash" to table: "password_reset_tokens"
+CREATE UNIQUE INDEX "idx_password_reset_tokens_token_hash" ON "password_reset_tokens" ("token_hash");
@@ -0,0 +1,20 @@
+-- Modify "users" table
+ALTER TABLE "users" ADD COLUMN "verified_at" timestamptz NULL;
+-- 確認機能の導入前に登録済みのユーザーは確認済みとして扱う
+UPDATE "users" SET "verified_at" = "created_at" WHERE "verified_at" IS NULL;
+
+-- Create "email_verification_tokens" table
+CREATE TABLE "email_verification_tokens" (
+  "id" uuid NOT NULL DEFAULT gen_random_uuid(),
+  "user_id" uuid NOT NULL,
+  "token_hash" character varying(64) NOT NULL,
+  "expires_at" timestamptz NOT NULL,
+  "used_at" timestamptz NULL,
+  "created_at" timestamptz NULL,
+  PRIMARY KEY ("id"),
+  CONSTRAINT "fk_email_verification_tokens_user" FOREIGN KEY ("user_id") REFERENCES "users" ("id") ON UPDATE NO ACTION ON DELETE CASCADE
+);
+-- Create index "idx_email_verification_tokens_user_id" to table: "email_verification_tokens"
+CREATE INDEX "idx_email_verification_tokens_user_id" ON "email_verification_tokens" ("user_id");
+-- Create index "idx_email_verification_tokens_token_hash" to table: "email_verification_tokens"
+CREATE UNIQUE INDEX "idx_email_verification_tokens_token_hash" ON "email_verification_tokens" ("token_hash");
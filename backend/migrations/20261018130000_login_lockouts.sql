@@ -0,0 +1,15 @@
+-- Create "login_lockouts" table
+CREATE TABLE "login_lockouts" (
+  "id" uuid NOT NULL DEFAULT gen_random_uuid(),
+  "scope" character varying(20) NOT NULL,
+  "email" text NOT NULL,
+  "ip_address" character varying(45) NOT NULL,
+  "failures" bigint NOT NULL,
+  "locked_until" timestamptz NOT NULL,
+  "created_at" timestamptz NULL,
+  PRIMARY KEY ("id")
+);
+-- Create index "idx_login_lockouts_email" to table: "login_lockouts"
+CREATE INDEX "idx_login_lockouts_email" ON "login_lockouts" ("email");
+-- Create index "idx_login_lockouts_ip_address" to table: "login_lockouts"
+CREATE INDEX "idx_login_lockouts_ip_address" ON "login_lockouts" ("ip_address");
@@ -0,0 +1,32 @@
+-- Modify "users" table
+ALTER TABLE "users" ADD COLUMN "totp_secret" text NOT NULL DEFAULT '', ADD COLUMN "totp_enabled_at" timestamptz NULL, ADD COLUMN "totp_last_step" bigint NOT NULL DEFAULT 0;
+
+-- Create "mfa_recovery_codes" table
+CREATE TABLE "mfa_recovery_codes" (
+  "id" uuid NOT NULL DEFAULT gen_random_uuid(),
+  "user_id" uuid NOT NULL,
+  "code_hash" character varying(64) NOT NULL,
+  "used_at" timestamptz NULL,
+  "created_at" timestamptz NULL,
+  PRIMARY KEY ("id"),
+  CONSTRAINT "fk_mfa_recovery_codes_user" FOREIGN KEY ("user_id") REFERENCES "users" ("id") ON UPDATE NO ACTION ON DELETE CASCADE
+);
+-- Create index "idx_mfa_recovery_codes_user_id" to table: "mfa_recovery_codes"
+CREATE INDEX "idx_mfa_recovery_codes_user_id" ON "mfa_recovery_codes" ("user_id");
+
+-- Create "mfa_challenges" table
+CREATE TABLE "mfa_challenges" (
+  "id" uuid NOT NULL DEFAULT gen_random_uuid(),
+  "user_id" uuid NOT NULL,
+  "token_hash" character varying(64) NOT NULL,
+  "attempts" bigint NOT NULL DEFAULT 0,
+  "expires_at" timestamptz NOT NULL,
+  "used_at" timestamptz NULL,
+  "created_at" timestamptz NULL,
+  PRIMARY KEY ("id"),
+  CONSTRAINT "fk_mfa_challenges_user" FOREIGN KEY ("user_id") REFERENCES "users" ("id") ON UPDATE NO ACTION ON DELETE CASCADE
+);
+-- Create index "idx_mfa_challenges_user_id" to table: "mfa_challenges"
+CREATE INDEX "idx_mfa_challenges_user_id" ON "mfa_challenges" ("user_id");
+-- Create index "idx_mfa_challenges_token_hash" to table: "mfa_challenges"
+CREATE UNIQUE INDEX "idx_mfa_challenges_token_hash" ON "mfa_challenges" ("token_hash");
@@ -0,0 +1,18 @@
+-- Create "user_sessions" table
+CREATE TABLE "user_sessions" (
+  "id" uuid NOT NULL DEFAULT gen_random_uuid(),
+  "user_id" uuid NOT NULL,
+  "device" text NOT NULL,
+  "ip_address" character varying(45) NOT NULL,
+  "user_agent" text NOT NULL,
+  "last_seen_at" timestamptz NOT NULL,
+  "revoked_at" timestamptz NULL,
+  "created_at" timestamptz NULL,
+  PRIMARY KEY ("id"),
+  CONSTRAINT "fk_user_sessions_user" FOREIGN KEY ("user_id") REFERENCES "users" ("id") ON UPDATE NO ACTION ON DELETE CASCADE
+);
+-- Create index "idx_user_sessions_user_id" to table: "user_sessions"
+CREATE INDEX "idx_user_sessions_user_id" ON "user_sessions" ("user_id");
+
+-- ログインセッションを持たない既存のリフレッシュトークンは失効させる (再ログインが必要)
+UPDATE "refresh_tokens" SET "revoked_at" = now() WHERE "revoked_at" IS NULL;
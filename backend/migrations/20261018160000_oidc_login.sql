@@ -0,0 +1,19 @@
+-- Modify "users" table
+ALTER TABLE "users" ALTER COLUMN "password_hash" DROP NOT NULL;
+
+-- Create "user_identities" table
+CREATE TABLE "user_identities" (
+  "id" uuid NOT NULL DEFAULT gen_random_uuid(),
+  "user_id" uuid NOT NULL,
+  "provider" character varying(50) NOT NULL,
+  "subject" character varying(255) NOT NULL,
+  "email" text NOT NULL,
+  "created_at" timestamptz NULL,
+  "updated_at" timestamptz NULL,
+  PRIMARY KEY ("id"),
+  CONSTRAINT "fk_user_identities_user" FOREIGN KEY ("user_id") REFERENCES "users" ("id") ON UPDATE NO ACTION ON DELETE CASCADE
+);
+-- Create index "idx_user_identities_user_id" to table: "user_identities"
+CREATE INDEX "idx_user_identities_user_id" ON "user_identities" ("user_id");
+-- Create index "idx_user_identities_provider_subject" to table: "user_identities"
+CREATE UNIQUE INDEX "idx_user_identities_provider_subject" ON "user_identities" ("provider", "subject");
@@ -0,0 +1,9 @@
+-- Modify "users" table
+ALTER TABLE "users" ADD COLUMN "display_name" character varying(50) NOT NULL DEFAULT '', ADD COLUMN "phone" character varying(20) NOT NULL DEFAULT '';
+
+-- Modify "email_verification_tokens" table
+ALTER TABLE "email_verification_tokens" ADD COLUMN "new_email" text NOT NULL DEFAULT '';
+
+-- Modify "orders" table
+-- 退会したユーザーの注文は user_id を NULL にして匿名化する
+ALTER TABLE "orders" ALTER COLUMN "user_id" DROP NOT NULL;
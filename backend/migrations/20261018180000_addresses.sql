@@ -0,0 +1,21 @@
+-- Create "addresses" table
+CREATE TABLE "addresses" (
+  "id" uuid NOT NULL DEFAULT gen_random_uuid(),
+  "user_id" uuid NOT NULL,
+  "name" character varying(100) NOT NULL,
+  "postal_code" character varying(8) NOT NULL,
+  "prefecture" character varying(10) NOT NULL,
+  "city" character varying(100) NOT NULL,
+  "line1" character varying(200) NOT NULL,
+  "line2" character varying(200) NOT NULL DEFAULT '',
+  "phone" character varying(20) NOT NULL,
+  "is_default" boolean NOT NULL DEFAULT false,
+  "created_at" timestamptz NULL,
+  "updated_at" timestamptz NULL,
+  PRIMARY KEY ("id"),
+  CONSTRAINT "fk_addresses_user" FOREIGN KEY ("user_id") REFERENCES "users" ("id") ON UPDATE NO ACTION ON DELETE CASCADE
+);
+-- Create index "idx_addresses_user_id" to table: "addresses"
+CREATE INDEX "idx_addresses_user_id" ON "addresses" ("user_id");
+-- Create index "idx_addresses_user_default" to table: "addresses"
+CREATE UNIQUE INDEX "idx_addresses_user_default" ON "addresses" ("user_id") WHERE is_default;
@@ -0,0 +1,17 @@
+-- Create "idempotency_keys" table
+CREATE TABLE "idempotency_keys" (
+  "id" uuid NOT NULL DEFAULT gen_random_uuid(),
+  "user_id" uuid NOT NULL,
+  "key" character varying(255) NOT NULL,
+  "request_hash" character varying(64) NOT NULL,
+  "response_status" bigint NOT NULL DEFAULT 0,
+  "response_body" bytea NULL,
+  "expires_at" timestamptz NOT NULL,
+  "created_at" timestamptz NULL,
+  PRIMARY KEY ("id"),
+  CONSTRAINT "fk_idempotency_keys_user" FOREIGN KEY ("user_id") REFERENCES "users" ("id") ON UPDATE NO ACTION ON DELETE CASCADE
+);
+-- Create index "idx_idempotency_keys_user_key" to table: "idempotency_keys"
+CREATE UNIQUE INDEX "idx_idempotency_keys_user_key" ON "idempotency_keys" ("user_id", "key");
+-- Create index "idx_idempotency_keys_expires_at" to table: "idempotency_keys"
+CREATE INDEX "idx_idempotency_keys_expires_at" ON "idempotency_keys" ("expires_at");
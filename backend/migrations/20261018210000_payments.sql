@@ -0,0 +1,23 @@
+-- Create "payments" table
+CREATE TABLE "payments" (
+  "id" uuid NOT NULL DEFAULT gen_random_uuid(),
+  "order_id" uuid NOT NULL,
+  "provider" character varying(50) NOT NULL,
+  "provider_payment_id" character varying(255) NOT NULL DEFAULT '',
+  "amount" bigint NOT NULL,
+  "currency" character varying(3) NOT NULL DEFAULT 'JPY',
+  "status" character varying(20) NOT NULL,
+  "next_action_url" text NOT NULL DEFAULT '',
+  "failure_code" character varying(50) NOT NULL DEFAULT '',
+  "authorized_at" timestamptz NULL,
+  "captured_at" timestamptz NULL,
+  "voided_at" timestamptz NULL,
+  "created_at" timestamptz NULL,
+  "updated_at" timestamptz NULL,
+  PRIMARY KEY ("id"),
+  CONSTRAINT "fk_orders_payments" FOREIGN KEY ("order_id") REFERENCES "orders" ("id") ON UPDATE NO ACTION ON DELETE CASCADE
+);
+-- Create index "idx_payments_order_id" to table: "payments"
+CREATE INDEX "idx_payments_order_id" ON "payments" ("order_id");
+-- Create index "idx_payments_provider_payment_id" to table: "payments"
+CREATE INDEX "idx_payments_provider_payment_id" ON "payments" ("provider_payment_id");
@@ -0,0 +1,30 @@
+-- Create "payment_events" table
+CREATE TABLE "payment_events" (
+  "id" uuid NOT NULL DEFAULT gen_random_uuid(),
+  "provider" character varying(50) NOT NULL,
+  "event_id" character varying(255) NOT NULL,
+  "type" character varying(50) NOT NULL,
+  "provider_payment_id" character varying(255) NOT NULL,
+  "failure_code" character varying(50) NOT NULL DEFAULT '',
+  "payload" text NOT NULL,
+  "processed_at" timestamptz NULL,
+  "claimed_at" timestamptz NOT NULL DEFAULT now(),
+  "created_at" timestamptz NULL,
+  PRIMARY KEY ("id")
+);
+-- Create index "idx_payment_events_provider_event" to table: "payment_events"
+CREATE UNIQUE INDEX "idx_payment_events_provider_event" ON "payment_events" ("provider", "event_id");
+-- Create "payment_dead_letters" table
+CREATE TABLE "payment_dead_letters" (
+  "id" uuid NOT NULL DEFAULT gen_random_uuid(),
+  "payment_event_id" uuid NOT NULL,
+  "last_error" text NOT NULL,
+  "attempts" bigint NOT NULL DEFAULT 1,
+  "resolved_at" timestamptz NULL,
+  "created_at" timestamptz NULL,
+  "updated_at" timestamptz NULL,
+  PRIMARY KEY ("id"),
+  CONSTRAINT "fk_payment_dead_letters_event" FOREIGN KEY ("payment_event_id") REFERENCES "payment_events" ("id") ON UPDATE NO ACTION ON DELETE CASCADE
+);
+-- Create index "idx_payment_dead_letters_payment_event_id" to table: "payment_dead_letters"
+CREATE UNIQUE INDEX "idx_payment_dead_letters_payment_event_id" ON "payment_dead_letters" ("payment_event_id");
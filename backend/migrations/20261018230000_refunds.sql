@@ -0,0 +1,40 @@
+-- Modify "order_items" table
+ALTER TABLE "order_items" ADD COLUMN "refunded_quantity" bigint NOT NULL DEFAULT 0, ADD COLUMN "refunded_amount" bigint NOT NULL DEFAULT 0;
+-- Modify "payment_events" table
+ALTER TABLE "payment_events" ADD COLUMN "amount" bigint NOT NULL DEFAULT 0;
+-- Modify "payments" table
+ALTER TABLE "payments" ADD COLUMN "refunded_amount" bigint NOT NULL DEFAULT 0;
+-- Create "refunds" table
+CREATE TABLE "refunds" (
+  "id" uuid NOT NULL DEFAULT gen_random_uuid(),
+  "order_id" uuid NOT NULL,
+  "payment_id" uuid NOT NULL,
+  "amount" bigint NOT NULL,
+  "reason" text NOT NULL DEFAULT '',
+  "restock" boolean NOT NULL DEFAULT false,
+  "status" character varying(20) NOT NULL,
+  "created_by" uuid NOT NULL,
+  "created_at" timestamptz NULL,
+  "updated_at" timestamptz NULL,
+  PRIMARY KEY ("id"),
+  CONSTRAINT "fk_orders_refunds" FOREIGN KEY ("order_id") REFERENCES "orders" ("id") ON UPDATE NO ACTION ON DELETE CASCADE
+);
+-- Create index "idx_refunds_order_id" to table: "refunds"
+CREATE INDEX "idx_refunds_order_id" ON "refunds" ("order_id");
+-- Create index "idx_refunds_payment_id" to table: "refunds"
+CREATE INDEX "idx_refunds_payment_id" ON "refunds" ("payment_id");
+-- Create "refund_items" table
+CREATE TABLE "refund_items" (
+  "id" uuid NOT NULL DEFAULT gen_random_uuid(),
+  "refund_id" uuid NOT NULL,
+  "order_item_id" uuid NOT NULL,
+  "quantity" bigint NOT NULL,
+  "amount" bigint NOT NULL,
+  "created_at" timestamptz NULL,
+  PRIMARY KEY ("id"),
+  CONSTRAINT "fk_refunds_items" FOREIGN KEY ("refund_id") REFERENCES "refunds" ("id") ON UPDATE NO ACTION ON DELETE CASCADE
+);
+-- Create index "idx_refund_items_refund_id" to table: "refund_items"
+CREATE INDEX "idx_refund_items_refund_id" ON "refund_items" ("refund_id");
+-- Create index "idx_refund_items_order_item_id" to table: "refund_items"
+CREATE INDEX "idx_refund_items_order_item_id" ON "refund_items" ("order_item_id");
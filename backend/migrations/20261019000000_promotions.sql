@@ -0,0 +1,45 @@
+-- Modify "orders" table
+ALTER TABLE "orders" ADD COLUMN "subtotal_amount" bigint NOT NULL DEFAULT 0, ADD COLUMN "discount_amount" bigint NOT NULL DEFAULT 0;
+-- Backfill "subtotal_amount" for orders placed before discounts existed
+UPDATE "orders" SET "subtotal_amount" = "total_amount";
+-- Modify "order_items" table
+ALTER TABLE "order_items" ADD COLUMN "discount_amount" bigint NOT NULL DEFAULT 0;
+-- Create "promotions" table
+CREATE TABLE "promotions" (
+  "id" uuid NOT NULL DEFAULT gen_random_uuid(),
+  "code" character varying(50) NOT NULL,
+  "name" character varying(100) NOT NULL,
+  "discount_type" character varying(20) NOT NULL,
+  "discount_value" bigint NOT NULL,
+  "max_discount" bigint NOT NULL DEFAULT 0,
+  "min_spend" bigint NOT NULL DEFAULT 0,
+  "scope" character varying(20) NOT NULL DEFAULT 'all',
+  "targets" jsonb NOT NULL,
+  "usage_limit" bigint NOT NULL DEFAULT 0,
+  "per_user_limit" bigint NOT NULL DEFAULT 0,
+  "used_count" bigint NOT NULL DEFAULT 0,
+  "starts_at" timestamptz NULL,
+  "ends_at" timestamptz NULL,
+  "active" boolean NOT NULL DEFAULT true,
+  "created_at" timestamptz NULL,
+  "updated_at" timestamptz NULL,
+  PRIMARY KEY ("id")
+);
+-- Create index "idx_promotions_code" to table: "promotions"
+CREATE UNIQUE INDEX "idx_promotions_code" ON "promotions" ("code");
+-- Create "order_discounts" table
+CREATE TABLE "order_discounts" (
+  "id" uuid NOT NULL DEFAULT gen_random_uuid(),
+  "order_id" uuid NOT NULL,
+  "promotion_id" uuid NOT NULL,
+  "code" character varying(50) NOT NULL,
+  "name" character varying(100) NOT NULL,
+  "amount" bigint NOT NULL,
+  "created_at" timestamptz NULL,
+  PRIMARY KEY ("id"),
+  CONSTRAINT "fk_orders_discounts" FOREIGN KEY ("order_id") REFERENCES "orders" ("id") ON UPDATE NO ACTION ON DELETE CASCADE
+);
+-- Create index "idx_order_discounts_order_id" to table: "order_discounts"
+CREATE INDEX "idx_order_discounts_order_id" ON "order_discounts" ("order_id");
+-- Create index "idx_order_discounts_promotion_id" to table: "order_discounts"
+CREATE INDEX "idx_order_discounts_promotion_id" ON "order_discounts" ("promotion_id");
@@ -0,0 +1,10 @@
+// Package migrations はデータベースのマイグレーションファイルを埋め込みます
+// ファイルは Atlas (atlas migrate diff) で生成し、atlas.sum と一緒にコミットしてください
+package migrations
+
+import "embed"
+
+// FS はマイグレーションファイル (*.sql) を保持します
+//
+//go:embed *.sql
+var FS embed.FS
@@ -3,6 +3,9 @@ package config
 import (
 	"log"
 	"os"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/joho/godotenv"
 )
@@ -12,6 +15,70 @@ type Config struct {
 	RedisURL      string
 	Port          string
 	SessionSecret string
+	// ゲストカートをログイン時にマージする際の設定
+	CartMergeStrategy     string
+	CartMergeClampToStock bool
+	// 認証方式 (session, jwt, both)
+	AuthMode string
+	// JWT の署名鍵 ("kid:secret" のカンマ区切り) と署名に使う鍵の kid
+	JWTKeys         string
+	JWTActiveKID    string
+	JWTIssuer       string
+	AccessTokenTTL  time.Duration
+	RefreshTokenTTL time.Duration
+	// メール送信の設定 (MailDriver: smtp, file, memory)
+	MailDriver   string
+	MailFrom     string
+	MailDir      string
+	SMTPAddr     string
+	SMTPUsername string
+	SMTPPassword string
+	// パスワードリセット画面の URL とリンクの有効期間
+	PasswordResetURL string
+	PasswordResetTTL time.Duration
+	// メールアドレス確認画面の URL、リンクの有効期間、確認メールを再送できる間隔
+	VerificationURL            string
+	VerificationTTL            time.Duration
+	VerificationResendInterval time.Duration
+	// メールアドレス未確認のユーザーの注文を拒否するか
+	RequireVerifiedEmailForOrder bool
+	// Idempotency-Key と処理結果のレスポンスを保持する期間・処理中のキーをロックしておく期間・期限切れのキーを掃除する間隔
+	// (処理中にプロセスが停止した場合は、ロックの期限が切れると同じキーで再試行できる)
+	IdempotencyKeyTTL        time.Duration
+	IdempotencyLockTimeout   time.Duration
+	IdempotencyPurgeInterval time.Duration
+	// 決済ゲートウェイ (fake) と、フェイクゲートウェイの既定のシナリオ (approve, decline, challenge, error) と本人認証画面の URL
+	// 決済ゲートウェイは必須で、フェイクゲートウェイは GIN_MODE=release では使用できない
+	PaymentGateway          string
+	FakePaymentScenario     string
+	FakePaymentChallengeURL string
+	// 決済の Webhook の署名鍵と、送信時刻のずれの許容範囲
+	PaymentWebhookSecret    string
+	PaymentWebhookTolerance time.Duration
+	// ログイン失敗回数の保存先 (redis, memory) とロックアウトの設定
+	LoginGuardBackend       string
+	LoginLockoutThreshold   int
+	LoginIPLockoutThreshold int
+	LoginLockoutDuration    time.Duration
+	// 管理者・スタッフに二要素認証を必須にするか
+	RequireAdminMFA bool
+	// 外部 ID プロバイダー (OpenID Connect) でのログインの設定
+	OIDCProviders []OIDCProviderConfig
+	// サーバー起動時に未適用のマイグレーションを適用するか
+	MigrateOnStartup bool
+	// サーバー起動時に商品の検索インデックスを作り直すか (通常は reindex-search サブコマンドで行います)
+	SearchReindexOnStartup bool
+}
+
+// OIDCProviderConfig は外部 ID プロバイダーごとの設定です
+// OIDC_PROVIDERS にプロバイダー名をカンマ区切りで指定し、名前ごとに OIDC_<NAME>_ISSUER などを設定します
+type OIDCProviderConfig struct {
+	Name         string
+	Issuer       string
+	ClientID     string
+	ClientSecret string
+	RedirectURL  string
+	Scopes       []string
 }
 
 func LoadConfig() *Config {
@@ -32,12 +99,121 @@ func LoadConfig() *Config {
 		RedisURL:      os.Getenv("REDIS_URL"),
 		Port:          getEnv("PORT", "8080"),
 		SessionSecret: sessionSecret,
+
+		CartMergeStrategy:     getEnv("CART_MERGE_STRATEGY", "sum"),
+		CartMergeClampToStock: getEnvBool("CART_MERGE_CLAMP_TO_STOCK", true),
+
+		AuthMode:        getEnv("AUTH_MODE", "session"),
+		JWTKeys:         os.Getenv("JWT_KEYS"),
+		JWTActiveKID:    os.Getenv("JWT_ACTIVE_KID"),
+		JWTIssuer:       getEnv("JWT_ISSUER", "rabbit-cart"),
+		AccessTokenTTL:  getEnvDuration("ACCESS_TOKEN_TTL", 15*time.Minute),
+		RefreshTokenTTL: getEnvDuration("REFRESH_TOKEN_TTL", 30*24*time.Hour),
+
+		MailDriver:   getEnv("MAIL_DRIVER", "file"),
+		MailFrom:     getEnv("MAIL_FROM", "no-reply@rabbit-cart.local"),
+		MailDir:      getEnv("MAIL_DIR", "tmp/mail"),
+		SMTPAddr:     os.Getenv("SMTP_ADDR"),
+		SMTPUsername: os.Getenv("SMTP_USERNAME"),
+		SMTPPassword: os.Getenv("SMTP_PASSWORD"),
+
+		PasswordResetURL: getEnv("PASSWORD_RESET_URL", "http://localhost:3000/password/reset"),
+		PasswordResetTTL: getEnvDuration("PASSWORD_RESET_TTL", 30*time.Minute),
+
+		VerificationURL:              getEnv("VERIFICATION_URL", "http://localhost:3000/verify"),
+		VerificationTTL:              getEnvDuration("VERIFICATION_TTL", 24*time.Hour),
+		VerificationResendInterval:   getEnvDuration("VERIFICATION_RESEND_INTERVAL", time.Minute),
+		RequireVerifiedEmailForOrder: getEnvBool("REQUIRE_VERIFIED_EMAIL_FOR_ORDER", false),
+
+		IdempotencyKeyTTL:        getEnvDuration("IDEMPOTENCY_KEY_TTL", 24*time.Hour),
+		IdempotencyLockTimeout:   getEnvDuration("IDEMPOTENCY_LOCK_TIMEOUT", 2*time.Minute),
+		IdempotencyPurgeInterval: getEnvDuration("IDEMPOTENCY_PURGE_INTERVAL", time.Hour),
+
+		PaymentGateway:          os.Getenv("PAYMENT_GATEWAY"),
+		FakePaymentScenario:     getEnv("FAKE_PAYMENT_SCENARIO", "approve"),
+		FakePaymentChallengeURL: getEnv("FAKE_PAYMENT_CHALLENGE_URL", "http://localhost:3000/payments/challenge"),
+		PaymentWebhookSecret:    os.Getenv("PAYMENT_WEBHOOK_SECRET"),
+		PaymentWebhookTolerance: getEnvDuration("PAYMENT_WEBHOOK_TOLERANCE", 5*time.Minute),
+
+		LoginGuardBackend:       getEnv("LOGIN_GUARD_BACKEND", "redis"),
+		LoginLockoutThreshold:   getEnvInt("LOGIN_LOCKOUT_THRESHOLD", 10),
+		LoginIPLockoutThreshold: getEnvInt("LOGIN_IP_LOCKOUT_THRESHOLD", 50),
+		LoginLockoutDuration:    getEnvDuration("LOGIN_LOCKOUT_DURATION", 15*time.Minute),
+
+		RequireAdminMFA: getEnvBool("REQUIRE_ADMIN_MFA", false),
+
+		OIDCProviders: loadOIDCProviders(os.Getenv("OIDC_PROVIDERS")),
+
+		MigrateOnStartup:       getEnvBool("MIGRATE_ON_STARTUP", true),
+		SearchReindexOnStartup: getEnvBool("SEARCH_REINDEX_ON_STARTUP", false),
 	}
 }
 
+// loadOIDCProviders は names (カンマ区切り) の各プロバイダーの設定を環境変数から読み込みます
+// 例: OIDC_PROVIDERS=google の場合は OIDC_GOOGLE_ISSUER, OIDC_GOOGLE_CLIENT_ID, OIDC_GOOGLE_CLIENT_SECRET,
+// OIDC_GOOGLE_REDIRECT_URL, OIDC_GOOGLE_SCOPES (スペース区切り、省略時は "openid email profile") を読み込みます
+func loadOIDCProviders(names string) []OIDCProviderConfig {
+	var providers []OIDCProviderConfig
+	for _, name := range strings.Split(names, ",") {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+		prefix := "OIDC_" + strings.ToUpper(strings.ReplaceAll(name, "-", "_")) + "_"
+		providers = append(providers, OIDCProviderConfig{
+			Name:         name,
+			Issuer:       os.Getenv(prefix + "ISSUER"),
+			ClientID:     os.Getenv(prefix + "CLIENT_ID"),
+			ClientSecret: os.Getenv(prefix + "CLIENT_SECRET"),
+			RedirectURL:  os.Getenv(prefix + "REDIRECT_URL"),
+			Scopes:       strings.Fields(getEnv(prefix+"SCOPES", "openid email profile")),
+		})
+	}
+	return providers
+}
+
 func getEnv(key, fallback string) string {
 	if value, ok := os.LookupEnv(key); ok {
 		return value
 	}
 	return fallback
 }
+
+func getEnvBool(key string, fallback bool) bool {
+	value, ok := os.LookupEnv(key)
+	if !ok {
+		return fallback
+	}
+	b, err := strconv.ParseBool(value)
+	if err != nil {
+		log.Printf("%s の値が不正なため既定値 %t を使用します", key, fallback)
+		return fallback
+	}
+	return b
+}
+
+func getEnvInt(key string, fallback int) int {
+	value, ok := os.LookupEnv(key)
+	if !ok {
+		return fallback
+	}
+	n, err := strconv.Atoi(value)
+	if err != nil || n <= 0 {
+		log.Printf("%s の値が不正なため既定値 %d を使用します", key, fallback)
+		return fallback
+	}
+	return n
+}
+
+func getEnvDuration(key string, fallback time.Duration) time.Duration {
+	value, ok := os.LookupEnv(key)
+	if !ok {
+		return fallback
+	}
+	d, err := time.ParseDuration(value)
+	if err != nil || d <= 0 {
+		log.Printf("%s の値が不正なため既定値 %s を使用します", key, fallback)
+		return fallback
+	}
+	return d
+}
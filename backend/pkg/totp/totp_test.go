@@ -0,0 +1,89 @@
+package totp
+
+import (
+	"encoding/base32"
+	"strings"
+	"testing"
+	"time"
+)
+
+// rfc6238Secret は RFC 6238 付録 B の SHA-1 用のシークレット ("12345678901234567890") です
+var rfc6238Secret = base32.StdEncoding.WithPadding(base32.NoPadding).EncodeToString([]byte("12345678901234567890"))
+
+// RFC 6238 付録 B のテストベクター (SHA-1)
+// RFC のコードは8桁のため、6桁のコードはその下6桁になります
+var rfc6238Vectors = []struct {
+	unix int64
+	code string // 8桁
+}{
+	{59, "94287082"},
+	{1111111109, "07081804"},
+	{1111111111, "14050471"},
+	{1234567890, "89005924"},
+	{2000000000, "69279037"},
+	{20000000000, "65353130"},
+}
+
+func TestCodeRFC6238(t *testing.T) {
+	for _, v := range rfc6238Vectors {
+		step := Step(time.Unix(v.unix, 0))
+		got, err := Code(rfc6238Secret, step)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if want := v.code[len(v.code)-Digits:]; got != want {
+			t.Errorf("T=%d: Code = %s, want %s", v.unix, got, want)
+		}
+	}
+}
+
+func TestCodeAcceptsLowercaseSecret(t *testing.T) {
+	got, err := Code(strings.ToLower(rfc6238Secret), Step(time.Unix(59, 0)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != "287082" {
+		t.Errorf("Code = %s, want 287082", got)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	now := time.Unix(1111111111, 0)
+	current := Step(now)
+	code := func(step int64) string {
+		c, err := Code(rfc6238Secret, step)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return c
+	}
+
+	tests := []struct {
+		name     string
+		code     string
+		wantStep int64
+		wantOK   bool
+	}{
+		{"現在のステップ", code(current), current, true},
+		{"1つ前のステップ", code(current - 1), current - 1, true},
+		{"1つ後のステップ", code(current + 1), current + 1, true},
+		{"許容範囲外", code(current - 2), 0, false},
+		{"前後の空白", " " + code(current) + " ", current, true},
+		{"桁数が違う", "12345", 0, false},
+		{"誤ったコード", "000000", 0, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			step, ok := Validate(rfc6238Secret, tt.code, now, 1)
+			if ok != tt.wantOK || step != tt.wantStep {
+				t.Errorf("Validate(%q) = (%d, %v), want (%d, %v)", tt.code, step, ok, tt.wantStep, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestValidateRejectsInvalidSecret(t *testing.T) {
+	if _, ok := Validate("not base32!", "123456", time.Now(), 1); ok {
+		t.Error("不正なシークレットでコードを受け付けました")
+	}
+}
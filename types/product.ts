@@ -9,3 +9,12 @@ export interface Product {
   created_at: string;
   updated_at: string;
 }
+
+export interface ProductListResponse {
+  products: Product[];
+  total: number;
+  page?: number;
+  per_page: number;
+  next_cursor?: string;
+  has_next: boolean;
+}